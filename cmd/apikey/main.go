@@ -0,0 +1,117 @@
+// Command apikey issues, rotates, revokes and lists API keys directly in the database.
+// It is also how the first admin key is bootstrapped.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"transaction-service/config"
+
+	"transaction-service/internal/interactor"
+	"transaction-service/internal/usecase"
+)
+
+const usage = `usage:
+  apikey issue -name NAME -scopes SCOPE[,SCOPE...] [-wallets ID[,ID...]]
+  apikey rotate -id ID
+  apikey revoke -id ID
+  apikey list
+
+scopes: wallets:read, transfers:write, admin`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	i, err := interactor.NewInteractor(config.Get())
+	if err != nil {
+		fatal("Failed to configure service", "error", err)
+	}
+
+	err = run(context.Background(), i.NewAPIKeyUsecase(), os.Args[1], os.Args[2:])
+	i.Close()
+	if err != nil {
+		fatal("Command failed", "command", os.Args[1], "error", err)
+	}
+}
+
+// fatal logs an error that ends the command and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+func run(ctx context.Context, keys usecase.APIKeyUsecase, cmd string, args []string) error {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+
+	switch cmd {
+	case "issue":
+		name := fs.String("name", "", "label of the client the key is issued to")
+		scopes := fs.String("scopes", "", "comma separated scopes")
+		wallets := fs.String("wallets", "", "comma separated wallet IDs the key is bound to")
+		_ = fs.Parse(args)
+
+		key, err := keys.IssueKey(ctx, *name, splitList(*scopes), splitList(*wallets))
+		if err != nil {
+			return err
+		}
+		return printJSON(key)
+
+	case "rotate":
+		id := fs.String("id", "", "key ID")
+		_ = fs.Parse(args)
+
+		key, err := keys.RotateKey(ctx, *id)
+		if err != nil {
+			return err
+		}
+		return printJSON(key)
+
+	case "revoke":
+		id := fs.String("id", "", "key ID")
+		_ = fs.Parse(args)
+
+		if err := keys.RevokeKey(ctx, *id); err != nil {
+			return err
+		}
+		fmt.Printf("revoked %s\n", *id)
+		return nil
+
+	case "list":
+		_ = fs.Parse(args)
+
+		list, err := keys.ListKeys(ctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(list)
+
+	default:
+		return fmt.Errorf("unknown command %q\n%s", cmd, usage)
+	}
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
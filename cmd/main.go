@@ -2,60 +2,291 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"transaction-service/config"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo"
-	_ "github.com/lib/pq"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
 
+	"transaction-service/internal/infrastructure/jwtauth"
+	"transaction-service/internal/infrastructure/logging"
+	"transaction-service/internal/infrastructure/ratelimit"
+	"transaction-service/internal/infrastructure/tracing"
 	"transaction-service/internal/interactor"
+	"transaction-service/internal/presenter/grpc/pb"
+	"transaction-service/internal/presenter/grpc/server"
 	"transaction-service/internal/presenter/http/middleware"
 	"transaction-service/internal/presenter/http/router"
 )
 
 func main() {
-	dbHost := config.Get().DBHost
-	dbPort := config.Get().DBPort
-	dbUser := config.Get().DBUser
-	dbPassword := config.Get().DBPassword
-	dbName := config.Get().DBName
-	sslMode := config.Get().SSLMode
-
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		dbHost, dbPort, dbUser, dbPassword, dbName, sslMode,
-	)
+	// Cancelled on SIGTERM/SIGINT, which starts the graceful shutdown below.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg := config.Get()
+	logger, err := logging.New(os.Stdout, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		fatal("Failed to configure logging", "error", err)
+	}
+	slog.SetDefault(logger)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(ctx, os.Args[2:]))
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:     cfg.TracingExporter,
+		OTLPEndpoint: cfg.OTLPEndpoint,
+		OTLPInsecure: cfg.OTLPInsecure,
+		SampleRatio:  cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		fatal("Failed to configure tracing", "error", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Error("Failed to flush spans", "error", err)
+		}
+	}()
 
-	db, err := sqlx.Connect("postgres", dsn)
+	i, err := interactor.NewInteractor(cfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatal("Failed to configure service", "error", err)
 	}
 	defer func() {
-		if err := db.Close(); err != nil {
-			log.Printf("Failed to close database connection: %v", err)
+		if err := i.Close(); err != nil {
+			slog.Error("Failed to close database connection", "error", err)
 		}
 	}()
 
-	i := interactor.NewInteractor(db)
+	// Replicas starting together queue on the migration lock; the first applies the
+	// pending migrations and the rest find nothing left to do.
+	if cfg.AutoMigrate && cfg.DBDriver != interactor.DriverMemory {
+		m, err := i.NewMigrator()
+		if err != nil {
+			fatal("Failed to configure migrations", "error", err)
+		}
+		if _, err := m.Up(ctx); err != nil {
+			fatal("Failed to migrate database", "error", err)
+		}
+	}
 
-	ctx := context.Background()
 	if err := i.InitializeService(ctx); err != nil {
-		log.Fatalf("failed to initialize service: %v", err)
+		fatal("failed to initialize service", "error", err)
+	}
+
+	// Background workers outlive the request context and are stopped after the servers drain.
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		if err := i.NewTransactionListener().Run(workerCtx); err != nil {
+			slog.Error("Transaction listener stopped", "error", err)
+		}
+	}()
+	if listener := i.NewWalletCacheListener(); listener != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if err := listener.Run(workerCtx); err != nil {
+				slog.Error("Wallet cache listener stopped", "error", err)
+			}
+		}()
+	}
+	if healthCheck := i.NewReplicaHealthCheck(); healthCheck != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if err := healthCheck.Run(workerCtx); err != nil {
+				slog.Error("Replica health check stopped", "error", err)
+			}
+		}()
+	}
+	if maintenance := i.NewPartitionMaintenance(); maintenance != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if err := maintenance.Run(workerCtx); err != nil {
+				slog.Error("Partition maintenance stopped", "error", err)
+			}
+		}()
+	}
+	if checkpointer := i.NewChainCheckpointer(); checkpointer != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if err := checkpointer.Run(workerCtx); err != nil {
+				slog.Error("Chain checkpointer stopped", "error", err)
+			}
+		}()
+	}
+	if reloader := i.NewKeyReloader(); reloader != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if err := reloader.Run(workerCtx); err != nil {
+				slog.Error("Signing key reloader stopped", "error", err)
+			}
+		}()
+	}
+	if reloader := i.NewRiskRulesReloader(); reloader != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if err := reloader.Run(workerCtx); err != nil {
+				slog.Error("Risk rules reloader stopped", "error", err)
+			}
+		}()
+	}
+	if refresher := i.NewWatchlistRefresher(); refresher != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if err := refresher.Run(workerCtx); err != nil {
+				slog.Error("Watchlist refresher stopped", "error", err)
+			}
+		}()
 	}
 
 	h := i.NewAppHandler()
 
 	e := echo.New()
+	e.HideBanner = true
+
+	auth := middleware.NewAnonymousAuth()
+	grpcAuth := server.NewAnonymousAuth()
+	if config.Get().AuthEnabled {
+		var tokens middleware.Authenticator
+		if jwksFile := config.Get().JWKSFile; jwksFile != "" {
+			if len(config.Get().JWTIssuers) == 0 {
+				fatal("jwt_issuers must be set when jwks_file is configured")
+			}
+			keys, err := jwtauth.LoadKeySet(jwksFile)
+			if err != nil {
+				fatal("Failed to load JWKS", "error", err)
+			}
+			verifier := jwtauth.NewVerifier(keys, config.Get().JWTIssuers, config.Get().JWTAudience)
+			tokens = i.NewTokenService(verifier)
+		}
+		auth = middleware.NewAuth(i.NewAPIKeyService(), tokens)
+		grpcAuth = server.NewAuth(i.NewAPIKeyService(), tokens)
+	} else {
+		slog.Warn("Authentication is disabled, every request and call is treated as admin")
+	}
+
+	routes := router.Middleware{Auth: auth}
+	var store ratelimit.Store
+	if cfg.RateLimitEnabled {
+		store, err = i.NewRateLimitStore(cfg.RateLimitStore)
+		if err != nil {
+			fatal("Failed to configure rate limiting", "error", err)
+		}
+		key, err := middleware.RateLimitKeyByName(cfg.RateLimitKey)
+		if err != nil {
+			fatal("Failed to configure rate limiting", "error", err)
+		}
+		routes.IPLimit = middleware.NewRateLimit(store, "ip", ratelimit.Limit{Rate: cfg.IPRateLimit, Burst: cfg.IPBurst}, middleware.RateLimitByIP)
+		routes.ReadLimit = middleware.NewRateLimit(store, "read", ratelimit.Limit{Rate: cfg.ReadRateLimit, Burst: cfg.ReadBurst}, key)
+		routes.WriteLimit = middleware.NewRateLimit(store, "write", ratelimit.Limit{Rate: cfg.WriteRateLimit, Burst: cfg.WriteBurst}, key)
+	}
+
+	router.NewRouter(e, h, routes)
+
+	// Outermost, so recovered panics are counted as 500s.
+	e.Use(middleware.NewMetrics(i.NewMetrics()))
+	e.Use(middleware.NewTracing())
 
-	router.NewRouter(e, h)
 	middleware.NewMiddleware(e)
 
+	grpcPort := config.Get().GRPCPort
+	lis, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		fatal("Failed to listen on gRPC port", "error", err)
+	}
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(server.UnaryRequestID, grpcAuth.Unary),
+		grpc.ChainStreamInterceptor(server.StreamRequestID, grpcAuth.Stream),
+	)
+	pb.RegisterTransactionServiceServer(grpcServer, i.NewGRPCServer())
+
+	go func() {
+		slog.Info("Starting gRPC server", "port", grpcPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			slog.Error("gRPC server stopped", "error", err)
+			stop()
+		}
+	}()
+
 	port := config.Get().APPPort
 
-	log.Printf("Starting server on port %s", port)
-	if err := e.Start(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	go func() {
+		slog.Info("Starting HTTP server", "port", port)
+		if err := e.Start(":" + port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Failed to start server", "error", err)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	stop() // A second signal kills the process immediately.
+	slog.Info("Shutting down, draining requests", "timeout", cfg.ShutdownTimeout)
+
+	// Fail readiness first so load balancers stop routing here, and end open
+	// transaction streams, which would otherwise hold the drain until the timeout.
+	i.NewHealthService().MarkShuttingDown()
+	i.NewTransactionBroker().Close()
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelDrain()
+
+	var servers sync.WaitGroup
+	servers.Add(2)
+	go func() {
+		defer servers.Done()
+		if err := e.Shutdown(drainCtx); err != nil {
+			slog.Error("HTTP server did not drain in time", "error", err)
+		}
+	}()
+	go func() {
+		defer servers.Done()
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-drainCtx.Done():
+			slog.Warn("gRPC server did not drain in time, closing remaining calls")
+			grpcServer.Stop()
+		}
+	}()
+	servers.Wait()
+
+	stopWorkers()
+	workers.Wait()
+	if store != nil {
+		if err := store.Close(); err != nil {
+			slog.Error("Failed to stop rate limit store", "error", err)
+		}
 	}
+	slog.Info("Server stopped")
+}
+
+// fatal logs an error that prevents the server from starting and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
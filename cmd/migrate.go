@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+	"transaction-service/config"
+
+	"transaction-service/internal/infrastructure/migrator"
+	"transaction-service/internal/interactor"
+)
+
+const migrateUsage = `usage:
+  transaction-service migrate up       apply every pending migration
+  transaction-service migrate down     roll back the newest migration
+  transaction-service migrate status   list migrations and whether they are applied
+  transaction-service migrate version  print the schema version of the database`
+
+// runMigrate runs the migrate subcommand and returns the exit code: 0 on success,
+// 1 when the command fails and 2 on a usage error.
+func runMigrate(ctx context.Context, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	switch args[0] {
+	case "up", "down", "status", "version":
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	i, err := interactor.NewInteractor(config.Get())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer i.Close()
+
+	m, err := i.NewMigrator()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := migrate(ctx, m, args[0]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func migrate(ctx context.Context, m *migrator.Migrator, cmd string) error {
+	switch cmd {
+	case "up":
+		applied, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		for _, version := range applied {
+			fmt.Printf("applied %d\n", version)
+		}
+
+	case "down":
+		version, err := m.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("rolled back %d\n", version)
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tNAME")
+		for _, status := range statuses {
+			state, appliedAt := "pending", "-"
+			if status.Applied {
+				state, appliedAt = "applied", status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, state, appliedAt, status.Name)
+		}
+		return w.Flush()
+
+	case "version":
+		version, err := m.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println(version)
+	}
+	return nil
+}
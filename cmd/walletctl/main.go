@@ -0,0 +1,967 @@
+// Command walletctl administers wallets and transfers directly in the database,
+// through the same usecases as the server. It reads the server configuration, so
+// it runs against whichever storage the server is configured with.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"transaction-service/config"
+
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/infrastructure/logging"
+	"transaction-service/internal/interactor"
+	"transaction-service/internal/usecase"
+)
+
+const usage = `usage: walletctl [-o table|json|csv] COMMAND [FLAGS]
+
+commands:
+  wallet create [-owner SUBJECT]
+  wallet list
+  wallet show -id ID
+  wallet close -id ID -reason TEXT
+  wallet status -id ID -status active|frozen|debit_blocked|closed -reason TEXT
+  wallet status-history -id ID
+  balance -id ID
+  send -from ID -to ID -amount AMOUNT
+  mint -to ID -amount AMOUNT -reason TEXT
+  burn -from ID -amount AMOUNT -reason TEXT
+  supply
+  history [-wallet ID] [-since TIME] [-until TIME] [-min AMOUNT] [-max AMOUNT] [-before SEQ] [-limit N] [-archived]
+  reconcile
+  chain verify
+  chain checkpoint
+  chain checkpoints [-limit N]
+  risk decisions [-wallet ID] [-action allow|review|deny] [-since TIME] [-until TIME] [-limit N]
+  sanctions cases [-wallet ID] [-status open|confirmed|false_positive] [-limit N]
+  sanctions resolve -id ID -status confirmed|false_positive -note TEXT
+  sanctions whitelist [-wallet ID]
+  sanctions whitelist-add -wallet ID -entry TEXT -reason TEXT
+  sanctions whitelist-remove -wallet ID -entry TEXT -reason TEXT
+  limits show -id ID
+  limits tiers
+  limits tier -name NAME [-max-transfer AMOUNT] [-daily AMOUNT] [-monthly AMOUNT] [-hourly N]
+  limits set -id ID [-tier NAME] [-max-transfer AMOUNT] [-daily AMOUNT] [-monthly AMOUNT] [-hourly N]
+  seed [-wallets N] [-balance AMOUNT] [-transfers N] [-max-amount AMOUNT]
+
+AMOUNT is in units with up to two decimals. Limits left out cap nothing, or take
+the limit of the tier for a wallet. TIME is RFC 3339 or YYYY-MM-DD (UTC).
+
+exit codes:
+  0  success
+  1  error
+  2  invalid usage
+  3  wallet, sanctions case, whitelist entry or limit tier not found
+  4  invalid argument
+  5  insufficient funds
+  6  conflict: concurrent modification, a wallet that is not empty, a status change not
+     allowed or a case already resolved
+  7  reconcile or supply found mismatched balances, or chain verify a broken link
+  8  transfer denied by the risk rules, sanctions screening, spending limits or the
+     status of a wallet`
+
+// Exit codes, documented in usage.
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitNotFound
+	exitInvalid
+	exitInsufficientFunds
+	exitConflict
+	exitMismatch
+	exitDenied
+)
+
+// errUsage marks errors in the command line.
+var errUsage = errors.New("invalid usage")
+
+// errMismatch is returned by reconcile when balances disagree with the log.
+var errMismatch = errors.New("balances do not match the transaction log")
+
+// errBrokenChain is returned by chain verify when the hash chain is broken.
+var errBrokenChain = errors.New("transaction hash chain is broken")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("walletctl", flag.ContinueOnError)
+	global.SetOutput(io.Discard)
+	format := global.String("o", formatTable, "output format: table, json or csv")
+	if err := global.Parse(args); err != nil || global.NArg() == 0 {
+		fmt.Fprintln(stderr, usage)
+		return exitUsage
+	}
+	switch *format {
+	case formatTable, formatJSON, formatCSV:
+	default:
+		fmt.Fprintf(stderr, "unknown output format %q\n\n%s\n", *format, usage)
+		return exitUsage
+	}
+
+	cmd, err := parseCommand(global.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n\n%s\n", err, usage)
+		return exitUsage
+	}
+
+	// The service logs every wallet and transfer at info, which would drown the output.
+	logger, err := logging.New(stderr, "warn", "text")
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	slog.SetDefault(logger)
+
+	i, err := interactor.NewInteractor(config.Get())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	defer i.Close()
+
+	c := &cli{
+		wallets:      i.NewWalletUsecase(),
+		transactions: i.NewTransactionUsecase(),
+		chain:        i.NewChainUsecase(),
+		risk:         i.NewRiskUsecase(),
+		sanctions:    i.NewSanctionsUsecase(),
+		limits:       i.NewLimitUsecase(),
+		statuses:     i.NewStatusUsecase(),
+	}
+	// Whoever can run the CLI can read its database credentials, so it is trusted.
+	res, err := cmd(model.WithTrustedCaller(ctx, "walletctl"), c)
+	if res != nil {
+		if err := printResult(stdout, *format, *res); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitCode(err)
+	}
+	return exitOK
+}
+
+// exitCode maps domain errors to the exit codes documented in usage.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case errors.Is(err, model.ErrWalletNotFound),
+		errors.Is(err, model.ErrCaseNotFound),
+		errors.Is(err, model.ErrNotWhitelisted),
+		errors.Is(err, model.ErrTierNotFound):
+		return exitNotFound
+	case errors.Is(err, model.ErrInvalidWalletID),
+		errors.Is(err, model.ErrInvalidAmount),
+		errors.Is(err, model.ErrSameWallet),
+		errors.Is(err, model.ErrInvalidCursor),
+		errors.Is(err, model.ErrInvalidFilter),
+		errors.Is(err, model.ErrSystemWallet),
+		errors.Is(err, model.ErrReasonRequired),
+		errors.Is(err, model.ErrNameRequired),
+		errors.Is(err, model.ErrInvalidResolution),
+		errors.Is(err, model.ErrEntryRequired),
+		errors.Is(err, model.ErrInvalidLimits),
+		errors.Is(err, model.ErrInvalidStatus):
+		return exitInvalid
+	case errors.Is(err, model.ErrInsufficientFunds):
+		return exitInsufficientFunds
+	case errors.Is(err, model.ErrConflict),
+		errors.Is(err, model.ErrWalletNotEmpty),
+		errors.Is(err, model.ErrCaseResolved),
+		errors.Is(err, model.ErrStatusTransition):
+		return exitConflict
+	case errors.Is(err, errMismatch),
+		errors.Is(err, errBrokenChain):
+		return exitMismatch
+	case errors.Is(err, model.ErrTransferDenied),
+		errors.Is(err, model.ErrSanctionsHit),
+		errors.Is(err, model.ErrLimitExceeded),
+		errors.Is(err, model.ErrWalletStatus):
+		return exitDenied
+	default:
+		return exitError
+	}
+}
+
+// command runs against the usecases. It may return a result along with an error,
+// as reconcile does when it finds mismatches.
+type command func(ctx context.Context, c *cli) (*result, error)
+
+// parseCommand parses the command and its flags before anything is opened, so
+// usage errors do not need a database.
+func parseCommand(args []string) (command, error) {
+	name, args := args[0], args[1:]
+	if name == "wallet" || name == "chain" || name == "risk" || name == "sanctions" || name == "limits" {
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%w: %s needs a subcommand", errUsage, name)
+		}
+		name, args = name+" "+args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	parse := func() error {
+		if err := fs.Parse(args); err != nil {
+			return fmt.Errorf("%w: %s: %v", errUsage, name, err)
+		}
+		if fs.NArg() > 0 {
+			return fmt.Errorf("%w: %s: unexpected argument %q", errUsage, name, fs.Arg(0))
+		}
+		return nil
+	}
+	required := func(flagName, value string) error {
+		if value == "" {
+			return fmt.Errorf("%w: %s needs -%s", errUsage, name, flagName)
+		}
+		return nil
+	}
+
+	switch name {
+	case "wallet create":
+		owner := fs.String("owner", "", "subject of the end user owning the wallet")
+		ownerName := fs.String("owner-name", "", "legal name of the owner, screened against the sanctions lists")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) { return c.createWallet(ctx, *owner, *ownerName) }, nil
+
+	case "wallet list":
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) { return c.listWallets(ctx) }, nil
+
+	case "wallet show", "wallet status-history", "balance":
+		id := fs.String("id", "", "wallet ID")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		if err := required("id", *id); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) {
+			switch name {
+			case "wallet show":
+				return c.showWallet(ctx, *id)
+			case "wallet status-history":
+				return c.statusHistory(ctx, *id)
+			default:
+				return c.balance(ctx, *id)
+			}
+		}, nil
+
+	case "wallet close", "wallet status":
+		id := fs.String("id", "", "wallet ID")
+		status := string(model.WalletClosed)
+		if name == "wallet status" {
+			fs.StringVar(&status, "status", "", "active, frozen, debit_blocked or closed")
+		}
+		reason := fs.String("reason", "", "why the status changes, recorded in its history")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		if err := errors.Join(required("id", *id), required("status", status), required("reason", *reason)); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) {
+			change, err := c.statuses.SetWalletStatus(ctx, *id, status, *reason)
+			if err != nil {
+				return nil, err
+			}
+			return statusChangesResult(change), nil
+		}, nil
+
+	case "send":
+		from := fs.String("from", "", "wallet to debit")
+		to := fs.String("to", "", "wallet to credit")
+		amount := fs.Float64("amount", 0, "amount in units")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		if err := errors.Join(required("from", *from), required("to", *to)); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) { return c.send(ctx, *from, *to, *amount) }, nil
+
+	case "mint", "burn":
+		wallet, walletFlag := fs.String("to", "", "wallet to credit"), "to"
+		if name == "burn" {
+			wallet, walletFlag = fs.String("from", "", "wallet to debit"), "from"
+		}
+		amount := fs.Float64("amount", 0, "amount in units")
+		reason := fs.String("reason", "", "reason recorded on the transaction")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		if err := errors.Join(required(walletFlag, *wallet), required("reason", *reason)); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) {
+			if name == "mint" {
+				return c.mint(ctx, *wallet, *amount, *reason)
+			}
+			return c.burn(ctx, *wallet, *amount, *reason)
+		}, nil
+
+	case "supply":
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) { return c.supply(ctx) }, nil
+
+	case "history":
+		var query usecase.HistoryQuery
+		var since, until string
+		fs.StringVar(&query.WalletID, "wallet", "", "only transfers from or to this wallet")
+		fs.StringVar(&since, "since", "", "only transfers at or after this time")
+		fs.StringVar(&until, "until", "", "only transfers before this time")
+		fs.Float64Var(&query.MinAmount, "min", 0, "only transfers of at least this amount")
+		fs.Float64Var(&query.MaxAmount, "max", 0, "only transfers of at most this amount")
+		fs.Int64Var(&query.BeforeSeq, "before", 0, "only transfers older than this sequence number, for the next page")
+		fs.IntVar(&query.Limit, "limit", usecase.DefaultHistoryLimit, "maximum number of transfers")
+		fs.BoolVar(&query.IncludeArchived, "archived", false, "also search the archived months")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		var err error
+		if query.Since, err = parseTime("since", since); err != nil {
+			return nil, err
+		}
+		if query.Until, err = parseTime("until", until); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) { return c.history(ctx, query) }, nil
+
+	case "reconcile":
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) { return c.reconcile(ctx) }, nil
+
+	case "chain verify", "chain checkpoint":
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) {
+			if name == "chain verify" {
+				return c.verifyChain(ctx)
+			}
+			return c.checkpoint(ctx)
+		}, nil
+
+	case "chain checkpoints":
+		limit := fs.Int("limit", usecase.DefaultCheckpointLimit, "maximum number of checkpoints")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) { return c.checkpoints(ctx, *limit) }, nil
+
+	case "risk decisions":
+		var query usecase.DecisionQuery
+		var since, until string
+		fs.StringVar(&query.WalletID, "wallet", "", "only transfers from or to this wallet")
+		fs.StringVar(&query.Action, "action", "", "only decisions with this outcome")
+		fs.StringVar(&since, "since", "", "only decisions at or after this time")
+		fs.StringVar(&until, "until", "", "only decisions before this time")
+		fs.IntVar(&query.Limit, "limit", usecase.DefaultDecisionLimit, "maximum number of decisions")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		var err error
+		if query.Since, err = parseTime("since", since); err != nil {
+			return nil, err
+		}
+		if query.Until, err = parseTime("until", until); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) { return c.decisions(ctx, query) }, nil
+
+	case "sanctions cases":
+		var query usecase.CaseQuery
+		fs.StringVar(&query.WalletID, "wallet", "", "only transfers from or to this wallet")
+		fs.StringVar(&query.Status, "status", "", "only cases with this status")
+		fs.IntVar(&query.Limit, "limit", usecase.DefaultCaseLimit, "maximum number of cases")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) { return c.cases(ctx, query) }, nil
+
+	case "sanctions resolve":
+		id := fs.String("id", "", "case ID")
+		status := fs.String("status", "", "confirmed or false_positive")
+		note := fs.String("note", "", "why the case is resolved so")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		if *id == "" || *status == "" || *note == "" {
+			return nil, fmt.Errorf("%w: sanctions resolve needs -id, -status and -note", errUsage)
+		}
+		return func(ctx context.Context, c *cli) (*result, error) {
+			resolved, err := c.sanctions.ResolveCase(ctx, *id, *status, *note)
+			if err != nil {
+				return nil, err
+			}
+			return casesResult(resolved), nil
+		}, nil
+
+	case "sanctions whitelist":
+		wallet := fs.String("wallet", "", "only entries cleared for this wallet")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) { return c.whitelist(ctx, *wallet) }, nil
+
+	case "sanctions whitelist-add", "sanctions whitelist-remove":
+		wallet := fs.String("wallet", "", "wallet ID")
+		entry := fs.String("entry", "", "watchlist entry, as the screening hit reports it")
+		reason := fs.String("reason", "", "why the entry is added or removed")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		if *wallet == "" || *entry == "" || *reason == "" {
+			return nil, fmt.Errorf("%w: %s needs -wallet, -entry and -reason", errUsage, name)
+		}
+		return func(ctx context.Context, c *cli) (*result, error) {
+			if name == "sanctions whitelist-remove" {
+				if err := c.sanctions.RemoveFromWhitelist(ctx, *wallet, *entry, *reason); err != nil {
+					return nil, err
+				}
+				return c.whitelist(ctx, *wallet)
+			}
+			added, err := c.sanctions.AddToWhitelist(ctx, *wallet, *entry, *reason)
+			if err != nil {
+				return nil, err
+			}
+			return whitelistResult(added), nil
+		}, nil
+
+	case "limits show":
+		id := fs.String("id", "", "wallet ID")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		if err := required("id", *id); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) { return c.allowance(ctx, *id) }, nil
+
+	case "limits tiers":
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) { return c.tiers(ctx) }, nil
+
+	case "limits tier":
+		tier := fs.String("name", "", "tier name")
+		limits := limitFlags(fs)
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		if err := required("name", *tier); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) {
+			saved, err := c.limits.SetTier(ctx, *tier, limits())
+			if err != nil {
+				return nil, err
+			}
+			return tiersResult(saved), nil
+		}, nil
+
+	case "limits set":
+		id := fs.String("id", "", "wallet ID")
+		tier := fs.String("tier", "", "tier of the wallet, the default one when empty")
+		limits := limitFlags(fs)
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		if err := required("id", *id); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *cli) (*result, error) {
+			saved, err := c.limits.SetWalletLimits(ctx, *id, *tier, limits())
+			if err != nil {
+				return nil, err
+			}
+			return limitRowsResult([]string{"WALLET", "TIER"}, [][]string{{saved.WalletID, saved.Tier}}, saved,
+				[]usecase.LimitsDTO{saved.Limits}), nil
+		}, nil
+
+	case "seed":
+		wallets := fs.Int("wallets", 10, "number of wallets to create")
+		balance := fs.Float64("balance", 1, "amount minted into each wallet")
+		transfers := fs.Int("transfers", 100, "number of random transfers between them")
+		maxAmount := fs.Float64("max-amount", 0.5, "largest amount of a transfer")
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		if *wallets < 2 && *transfers > 0 {
+			return nil, fmt.Errorf("%w: transfers need at least two wallets", errUsage)
+		}
+		if *balance < 0.01 && *transfers > 0 {
+			return nil, fmt.Errorf("%w: transfers need a -balance of at least 0.01", errUsage)
+		}
+		if *maxAmount < 0.01 {
+			return nil, fmt.Errorf("%w: -max-amount must be at least 0.01", errUsage)
+		}
+		return func(ctx context.Context, c *cli) (*result, error) {
+			return c.seed(ctx, *wallets, *balance, *transfers, *maxAmount)
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown command %q", errUsage, name)
+	}
+}
+
+// limitFlags defines the flags of spending limits on fs. The function it returns
+// reads them once fs is parsed, leaving out the flags not given.
+func limitFlags(fs *flag.FlagSet) func() usecase.LimitsDTO {
+	maxTransfer := fs.Float64("max-transfer", 0, "largest single transfer")
+	daily := fs.Float64("daily", 0, "total sent per day")
+	monthly := fs.Float64("monthly", 0, "total sent per month")
+	hourly := fs.Int("hourly", 0, "transfers per hour")
+	return func() usecase.LimitsDTO {
+		var limits usecase.LimitsDTO
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "max-transfer":
+				limits.MaxTransfer = maxTransfer
+			case "daily":
+				limits.DailyTotal = daily
+			case "monthly":
+				limits.MonthlyTotal = monthly
+			case "hourly":
+				limits.HourlyCount = hourly
+			}
+		})
+		return limits
+	}
+}
+
+// parseTime parses an RFC 3339 time or a date, which stands for midnight UTC.
+func parseTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: -%s must be RFC 3339 or YYYY-MM-DD, got %q", errUsage, name, value)
+	}
+	return t, nil
+}
+
+type cli struct {
+	wallets      usecase.WalletUsecase
+	transactions usecase.TransactionUsecase
+	chain        usecase.ChainUsecase
+	risk         usecase.RiskUsecase
+	sanctions    usecase.SanctionsUsecase
+	limits       usecase.LimitUsecase
+	statuses     usecase.StatusUsecase
+}
+
+// walletView is a wallet as walletctl prints it, with its balance in units.
+type walletView struct {
+	ID        string  `json:"id"`
+	Balance   float64 `json:"balance"`
+	OwnerID   string  `json:"owner_id,omitempty"`
+	OwnerName string  `json:"owner_name,omitempty"`
+	Status    string  `json:"status"`
+}
+
+func walletsResult(wallets ...*model.Wallet) *result {
+	views := make([]walletView, len(wallets))
+	rows := make([][]string, len(wallets))
+	for n, w := range wallets {
+		views[n] = walletView{
+			ID: w.ID.String(), Balance: float64(w.Amount) / 100,
+			OwnerID: w.OwnerID, OwnerName: w.OwnerName, Status: string(w.Status),
+		}
+		rows[n] = []string{views[n].ID, formatAmount(views[n].Balance), views[n].OwnerID, views[n].Status}
+	}
+	return &result{value: views, header: []string{"ID", "BALANCE", "OWNER", "STATUS"}, rows: rows}
+}
+
+func (c *cli) createWallet(ctx context.Context, owner, ownerName string) (*result, error) {
+	wallet, err := c.wallets.CreateWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if owner != "" || ownerName != "" {
+		if err := c.wallets.SetWalletOwner(ctx, wallet.ID.String(), owner, ownerName); err != nil {
+			return nil, err
+		}
+		wallet.OwnerID, wallet.OwnerName = owner, ownerName
+	}
+	return walletsResult(wallet), nil
+}
+
+func (c *cli) listWallets(ctx context.Context) (*result, error) {
+	wallets, err := c.wallets.GetAllWallets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return walletsResult(wallets...), nil
+}
+
+func (c *cli) showWallet(ctx context.Context, id string) (*result, error) {
+	wallet, err := c.wallets.GetWallet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return walletsResult(wallet), nil
+}
+
+func statusChangesResult(changes ...usecase.StatusChangeDTO) *result {
+	rows := make([][]string, len(changes))
+	for n, c := range changes {
+		rows[n] = []string{c.CreatedAt, c.WalletID, c.From, c.To, c.Reason, c.Actor, c.RequestID}
+	}
+	return &result{
+		value:  changes,
+		header: []string{"CREATED AT", "WALLET", "FROM", "TO", "REASON", "ACTOR", "REQUEST ID"},
+		rows:   rows,
+	}
+}
+
+func (c *cli) statusHistory(ctx context.Context, id string) (*result, error) {
+	changes, err := c.statuses.GetStatusHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return statusChangesResult(changes...), nil
+}
+
+func (c *cli) balance(ctx context.Context, id string) (*result, error) {
+	balance, err := c.wallets.GetBalance(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &result{
+		value:  map[string]any{"id": id, "balance": balance},
+		header: []string{"ID", "BALANCE"},
+		rows:   [][]string{{id, formatAmount(balance)}},
+	}, nil
+}
+
+func (c *cli) send(ctx context.Context, from, to string, amount float64) (*result, error) {
+	transfer, err := c.wallets.SendMoney(ctx, from, to, amount)
+	if err != nil {
+		return nil, err
+	}
+	t := transfer.Transaction
+	return &result{
+		value:  transfer,
+		header: []string{"ID", "FROM", "TO", "AMOUNT", "SEQ"},
+		rows:   [][]string{{t.ID, t.From, t.To, formatAmount(t.Amount), strconv.FormatInt(t.Seq, 10)}},
+	}, nil
+}
+
+func (c *cli) mint(ctx context.Context, to string, amount float64, reason string) (*result, error) {
+	if err := c.wallets.Mint(ctx, to, amount, reason); err != nil {
+		return nil, err
+	}
+	return &result{
+		value:  map[string]any{"to": to, "amount": amount, "reason": reason, "status": "success"},
+		header: []string{"TO", "AMOUNT", "REASON", "STATUS"},
+		rows:   [][]string{{to, formatAmount(amount), reason, "success"}},
+	}, nil
+}
+
+func (c *cli) burn(ctx context.Context, from string, amount float64, reason string) (*result, error) {
+	if err := c.wallets.Burn(ctx, from, amount, reason); err != nil {
+		return nil, err
+	}
+	return &result{
+		value:  map[string]any{"from": from, "amount": amount, "reason": reason, "status": "success"},
+		header: []string{"FROM", "AMOUNT", "REASON", "STATUS"},
+		rows:   [][]string{{from, formatAmount(amount), reason, "success"}},
+	}, nil
+}
+
+// supply reports the money supply and fails with errMismatch when the money in
+// circulation differs from the sum of user wallets.
+func (c *cli) supply(ctx context.Context) (*result, error) {
+	supply, err := c.wallets.GetSupply(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &result{
+		value:  supply,
+		header: []string{"ISSUED", "BURNED", "CIRCULATING", "WALLET TOTAL", "BALANCED"},
+		rows: [][]string{{
+			formatAmount(supply.Issued), formatAmount(supply.Burned), formatAmount(supply.Circulating),
+			formatAmount(supply.WalletTotal), strconv.FormatBool(supply.Balanced),
+		}},
+	}
+	if !supply.Balanced {
+		return res, errMismatch
+	}
+	return res, nil
+}
+
+func (c *cli) history(ctx context.Context, query usecase.HistoryQuery) (*result, error) {
+	transactions, err := c.transactions.GetHistory(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]string, len(transactions))
+	for n, t := range transactions {
+		rows[n] = []string{strconv.FormatInt(t.Seq, 10), t.CreatedAt, t.From, t.To, formatAmount(t.Amount), t.RequestID, t.Reason}
+	}
+	return &result{
+		value:  transactions,
+		header: []string{"SEQ", "CREATED AT", "FROM", "TO", "AMOUNT", "REQUEST ID", "REASON"},
+		rows:   rows,
+	}, nil
+}
+
+func (c *cli) reconcile(ctx context.Context) (*result, error) {
+	report, err := c.wallets.Reconcile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]string, len(report.Mismatches))
+	for n, m := range report.Mismatches {
+		rows[n] = []string{m.WalletID, formatAmount(m.Balance), formatAmount(m.Expected)}
+	}
+	res := &result{
+		value: report,
+		summary: fmt.Sprintf("%d wallets, %d transactions, total balance %s, %d mismatched",
+			report.Wallets, report.Transactions, formatAmount(report.TotalAmount), len(report.Mismatches)),
+		header: []string{"WALLET", "BALANCE", "EXPECTED"},
+		rows:   rows,
+	}
+	if len(report.Mismatches) > 0 {
+		return res, errMismatch
+	}
+	return res, nil
+}
+
+// verifyChain walks the hash chain and fails with errBrokenChain at the first
+// broken link.
+func (c *cli) verifyChain(ctx context.Context) (*result, error) {
+	report, err := c.chain.VerifyChain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &result{
+		value: report,
+		summary: fmt.Sprintf("%d transactions verified, %d unchained, %d checkpoints matched, head seq %d",
+			report.Checked, report.Unchained, report.Checkpoints, report.Head.Seq),
+		header: []string{"SEQ", "TRANSACTION", "PROBLEM", "EXPECTED", "ACTUAL"},
+	}
+	if b := report.Broken; b != nil {
+		res.rows = [][]string{{strconv.FormatInt(b.Seq, 10), b.TransactionID, b.Problem, b.Expected, b.Actual}}
+		return res, errBrokenChain
+	}
+	return res, nil
+}
+
+func checkpointsResult(checkpoints ...usecase.CheckpointDTO) *result {
+	rows := make([][]string, len(checkpoints))
+	for n, cp := range checkpoints {
+		rows[n] = []string{strconv.FormatInt(cp.Seq, 10), strconv.FormatInt(cp.Count, 10), cp.Hash, cp.CreatedAt, cp.KeyID, cp.Signature}
+	}
+	return &result{
+		value:  checkpoints,
+		header: []string{"SEQ", "COUNT", "HASH", "CREATED AT", "KEY ID", "SIGNATURE"},
+		rows:   rows,
+	}
+}
+
+func (c *cli) checkpoint(ctx context.Context) (*result, error) {
+	checkpoint, err := c.chain.CreateCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return checkpointsResult(*checkpoint), nil
+}
+
+func (c *cli) checkpoints(ctx context.Context, limit int) (*result, error) {
+	checkpoints, err := c.chain.GetCheckpoints(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return checkpointsResult(checkpoints...), nil
+}
+
+// decisions lists risk decisions with the rules that fired, one per line.
+func (c *cli) decisions(ctx context.Context, query usecase.DecisionQuery) (*result, error) {
+	decisions, err := c.risk.GetDecisions(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]string, len(decisions))
+	for n, d := range decisions {
+		fired := make([]string, len(d.Fired))
+		for k, f := range d.Fired {
+			fired[k] = fmt.Sprintf("%s (%s): %s", f.Rule, f.Action, f.Reason)
+		}
+		rows[n] = []string{d.ID, d.CreatedAt, d.From, d.To, formatAmount(d.Amount), d.Action, strings.Join(fired, "; ")}
+	}
+	return &result{
+		value:  decisions,
+		header: []string{"ID", "CREATED AT", "FROM", "TO", "AMOUNT", "ACTION", "RULES"},
+		rows:   rows,
+	}, nil
+}
+
+func (c *cli) cases(ctx context.Context, query usecase.CaseQuery) (*result, error) {
+	cases, err := c.sanctions.GetCases(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return casesResult(cases...), nil
+}
+
+// casesResult lists sanctions cases with their hits, one case per line.
+func casesResult(cases ...usecase.SanctionsCaseDTO) *result {
+	rows := make([][]string, len(cases))
+	for n, sc := range cases {
+		hits := make([]string, len(sc.Hits))
+		for k, h := range sc.Hits {
+			hits[k] = fmt.Sprintf("%s %s %q in %s (%.2f)", h.Party, h.Kind, h.Entry, h.List, h.Score)
+		}
+		rows[n] = []string{sc.ID, sc.CreatedAt, sc.From, sc.To, formatAmount(sc.Amount), sc.Status, strings.Join(hits, "; ")}
+	}
+	return &result{
+		value:  cases,
+		header: []string{"ID", "CREATED AT", "FROM", "TO", "AMOUNT", "STATUS", "HITS"},
+		rows:   rows,
+	}
+}
+
+func (c *cli) whitelist(ctx context.Context, walletID string) (*result, error) {
+	entries, err := c.sanctions.GetWhitelist(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	return whitelistResult(entries...), nil
+}
+
+func whitelistResult(entries ...usecase.WhitelistEntryDTO) *result {
+	rows := make([][]string, len(entries))
+	for n, e := range entries {
+		rows[n] = []string{e.WalletID, e.Entry, e.CaseID, e.Reason, e.CreatedAt}
+	}
+	return &result{value: entries, header: []string{"WALLET", "ENTRY", "CASE", "REASON", "CREATED AT"}, rows: rows}
+}
+
+// allowance shows the limits of a wallet with what it used and has left of each.
+func (c *cli) allowance(ctx context.Context, walletID string) (*result, error) {
+	allowance, err := c.limits.GetAllowance(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	used := usecase.LimitsDTO{
+		DailyTotal:   &allowance.Used.DailyTotal,
+		MonthlyTotal: &allowance.Used.MonthlyTotal,
+		HourlyCount:  &allowance.Used.HourlyCount,
+	}
+	return limitRowsResult([]string{"TIER", "OF"},
+		[][]string{{allowance.Tier, "limit"}, {allowance.Tier, "used"}, {allowance.Tier, "remaining"}},
+		allowance, []usecase.LimitsDTO{allowance.Limits, used, allowance.Remaining}), nil
+}
+
+func (c *cli) tiers(ctx context.Context) (*result, error) {
+	tiers, err := c.limits.GetTiers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tiersResult(tiers...), nil
+}
+
+func tiersResult(tiers ...usecase.TierLimitsDTO) *result {
+	keys := make([][]string, len(tiers))
+	limits := make([]usecase.LimitsDTO, len(tiers))
+	for n, tier := range tiers {
+		keys[n], limits[n] = []string{tier.Name}, tier.Limits
+	}
+	return limitRowsResult([]string{"TIER"}, keys, tiers, limits)
+}
+
+// limitRowsResult prints a row of limits after the key columns of each row, "-"
+// where nothing is capped.
+func limitRowsResult(keyHeader []string, keys [][]string, value any, limits []usecase.LimitsDTO) *result {
+	amount := func(limit *float64) string {
+		if limit == nil {
+			return "-"
+		}
+		return formatAmount(*limit)
+	}
+	rows := make([][]string, len(limits))
+	for n, l := range limits {
+		count := "-"
+		if l.HourlyCount != nil {
+			count = strconv.Itoa(*l.HourlyCount)
+		}
+		rows[n] = append(slices.Clone(keys[n]), amount(l.MaxTransfer), amount(l.DailyTotal), amount(l.MonthlyTotal), count)
+	}
+	header := append(slices.Clone(keyHeader), "MAX TRANSFER", "DAILY", "MONTHLY", "HOURLY COUNT")
+	return &result{value: value, header: header, rows: rows}
+}
+
+// seed creates wallets, mints balance into each and makes random transfers between
+// them, for development and load testing. Transfers never exceed the balance of the
+// sender.
+func (c *cli) seed(ctx context.Context, count int, balance float64, transfers int, maxAmount float64) (*result, error) {
+	wallets := make([]*model.Wallet, count)
+	for n := range wallets {
+		wallet, err := c.wallets.CreateWallet(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if balance > 0 {
+			if err := c.wallets.Mint(ctx, wallet.ID.String(), balance, "seed"); err != nil {
+				return nil, err
+			}
+			wallet.Amount = int(math.Round(balance * 100))
+		}
+		wallets[n] = wallet
+	}
+
+	// Money only moves between the new wallets, so some of them always have funds.
+	maxCents := int(math.Round(maxAmount * 100))
+	for made := 0; made < transfers; {
+		sender := rand.N(count)
+		from, to := wallets[sender], wallets[(sender+1+rand.N(count-1))%count]
+		if from.Amount == 0 {
+			continue
+		}
+		cents := 1 + rand.N(min(maxCents, from.Amount))
+		if _, err := c.wallets.SendMoney(ctx, from.ID.String(), to.ID.String(), float64(cents)/100); err != nil {
+			return nil, err
+		}
+		from.Amount -= cents
+		to.Amount += cents
+		made++
+	}
+	return walletsResult(wallets...), nil
+}
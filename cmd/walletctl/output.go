@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats selected with -o.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// result is the output of a command: value is printed as JSON, header and rows
+// as a table or CSV. summary is printed above the table only.
+type result struct {
+	value   any
+	summary string
+	header  []string
+	rows    [][]string
+}
+
+func printResult(w io.Writer, format string, r result) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r.value)
+
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(r.header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(r.rows); err != nil {
+			return err
+		}
+		return cw.Error()
+
+	default:
+		if r.summary != "" {
+			fmt.Fprintln(w, r.summary)
+			if len(r.rows) == 0 {
+				return nil
+			}
+			fmt.Fprintln(w)
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(r.header, "\t"))
+		for _, row := range r.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+// formatAmount formats an amount in units with two decimals.
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// formatCents formats an amount in cents as units.
+func formatCents(cents int) string {
+	return formatAmount(float64(cents) / 100)
+}
@@ -1,3 +1,5 @@
+database_driver = "postgres"
+# database_dsn = "./data/wallets.db"  # with database_driver = "sqlite"
 database_host = "localhost"
 database_port = "5434"
 database_user = "postgres"
@@ -5,3 +7,45 @@ database_password = "postgres"
 database_name = "transaction_service"
 database_sslmode = "disable"
 app_port = "8080"
+grpc_port = "9090"
+# database_replica_dsns = ["host=replica-1 port=5432 user=postgres password=postgres dbname=transaction_service sslmode=disable"]
+database_replica_check_interval = "5s"
+balance_cache_ttl = "30s"
+balance_cache_entries = 100000
+transaction_partitions_ahead = 3
+transaction_retention_months = 0
+transaction_archive_dir = "archive"
+transaction_maintenance_interval = "1h"
+# signing_key_file = "./signing.pem"
+signing_key_reload_interval = "1m"
+chain_checkpoint_interval = "1h"
+# risk_rules_file = "./risk_rules.hcl"
+risk_rules_reload_interval = "30s"
+# sanctions_list_files = ["./sanctions/ofac.xml", "./sanctions/blocklist.csv"]
+sanctions_refresh_interval = "1h"
+sanctions_name_threshold = 0.85
+auto_migrate = false
+locking_strategy = "pessimistic"
+transfer_max_attempts = 5
+transfer_retry_backoff = "10ms"
+log_level = "info"
+log_format = "json"
+shutdown_timeout = "30s"
+auth_enabled = true
+# jwks_file = "./jwks.json"
+# jwt_issuers = ["https://auth.example.com/"]
+# jwt_audience = "transaction-service"
+rate_limit_enabled = true
+rate_limit_key = "api_key"
+rate_limit_store = "memory"
+read_rate_limit = 50
+read_burst = 100
+write_rate_limit = 10
+write_burst = 20
+ip_rate_limit = 100
+ip_burst = 200
+
+tracing_exporter = "none"
+otlp_endpoint = "localhost:4317"
+otlp_insecure = true
+tracing_sample_ratio = 1
@@ -1,13 +1,21 @@
 package config
 
 import (
+	"fmt"
 	"github.com/cristalhq/aconfig"
 	"github.com/cristalhq/aconfig/aconfighcl"
-	"log"
+	"log/slog"
 	"sync"
+	"time"
 )
 
 type Config struct {
+	// DBDriver selects the storage: postgres, sqlite for deployments without a
+	// database server, or memory for local development and tests, which keeps
+	// everything in process and loses it on exit. DBDSN overrides the connection
+	// settings below; for sqlite it is the database file, e.g. "data/wallets.db".
+	DBDriver   string `hcl:"database_driver" env:"DBDRIVER" default:"postgres"`
+	DBDSN      string `hcl:"database_dsn" env:"DBDSN"`
 	DBHost     string `hcl:"database_host" env:"DBHOST" default:"postgres"`
 	DBPort     string `hcl:"database_port" env:"DBPORT" default:"5434"`
 	DBUser     string `hcl:"database_user" env:"DBUSER" default:"postgres"`
@@ -15,6 +23,113 @@ type Config struct {
 	DBName     string `hcl:"database_name" env:"DBNAME" default:"transaction_service"`
 	SSLMode    string `hcl:"database_sslmode" env:"SSLMODE" default:"disable"`
 	APPPort    string `hcl:"app_port" env:"PORT" default:"8080"`
+	GRPCPort   string `hcl:"grpc_port" env:"GRPC_PORT" default:"9090"`
+
+	// DBReplicaDSNs are Postgres read replicas. Reads that tolerate replication lag
+	// are spread over the replicas passing a health check every
+	// DBReplicaCheckInterval, and go to the primary when none does.
+	DBReplicaDSNs          []string      `hcl:"database_replica_dsns" env:"DBREPLICA_DSNS"`
+	DBReplicaCheckInterval time.Duration `hcl:"database_replica_check_interval" env:"DBREPLICA_CHECK_INTERVAL" default:"5s"`
+
+	// BalanceCacheTTL is how long a wallet read by ID is cached, bounding staleness
+	// should an invalidation be missed; 0 disables the cache. At most
+	// BalanceCacheEntries wallets and invalidation tombstones are kept.
+	BalanceCacheTTL     time.Duration `hcl:"balance_cache_ttl" env:"BALANCE_CACHE_TTL" default:"30s"`
+	BalanceCacheEntries int           `hcl:"balance_cache_entries" env:"BALANCE_CACHE_ENTRIES" default:"100000"`
+
+	// The transactions table of Postgres is partitioned by month. Every
+	// TransactionMaintenanceInterval partitions are created TransactionPartitionsAhead
+	// months ahead, and months older than TransactionRetentionMonths are written to
+	// TransactionArchiveDir and detached; 0 keeps every month. The archive is
+	// searched by the transaction history of every driver.
+	TransactionPartitionsAhead     int           `hcl:"transaction_partitions_ahead" env:"TRANSACTION_PARTITIONS_AHEAD" default:"3"`
+	TransactionRetentionMonths     int           `hcl:"transaction_retention_months" env:"TRANSACTION_RETENTION_MONTHS" default:"0"`
+	TransactionArchiveDir          string        `hcl:"transaction_archive_dir" env:"TRANSACTION_ARCHIVE_DIR" default:"archive"`
+	TransactionMaintenanceInterval time.Duration `hcl:"transaction_maintenance_interval" env:"TRANSACTION_MAINTENANCE_INTERVAL" default:"1h"`
+
+	// Every transaction is linked into a hash chain. With SigningKeyFile, an Ed25519
+	// private key in PEM, a signed checkpoint of the chain head is stored every
+	// ChainCheckpointInterval and transfers return signed receipts. The file is
+	// reread every SigningKeyReloadInterval once it changes, so the key can be
+	// rotated; keys after the first in it are published for verification only.
+	SigningKeyFile           string        `hcl:"signing_key_file" env:"SIGNING_KEY_FILE"`
+	SigningKeyReloadInterval time.Duration `hcl:"signing_key_reload_interval" env:"SIGNING_KEY_RELOAD_INTERVAL" default:"1m"`
+	ChainCheckpointInterval  time.Duration `hcl:"chain_checkpoint_interval" env:"CHAIN_CHECKPOINT_INTERVAL" default:"1h"`
+
+	// With RiskRulesFile, transfers are checked against the risk rules in it before
+	// they are executed, and every decision is recorded. The file is reread every
+	// RiskRulesReloadInterval once it changes.
+	RiskRulesFile           string        `hcl:"risk_rules_file" env:"RISK_RULES_FILE"`
+	RiskRulesReloadInterval time.Duration `hcl:"risk_rules_reload_interval" env:"RISK_RULES_RELOAD_INTERVAL" default:"30s"`
+
+	// With SanctionsListFiles, CSV or XML watchlists, the wallets and owners of both
+	// parties of a transfer are screened before it is executed; a hit blocks the
+	// transfer and opens a case. Owners match listed names whose similarity, from 0
+	// to 1, is at least SanctionsNameThreshold. The files are reread every
+	// SanctionsRefreshInterval.
+	SanctionsListFiles       []string      `hcl:"sanctions_list_files" env:"SANCTIONS_LIST_FILES"`
+	SanctionsRefreshInterval time.Duration `hcl:"sanctions_refresh_interval" env:"SANCTIONS_REFRESH_INTERVAL" default:"1h"`
+	SanctionsNameThreshold   float64       `hcl:"sanctions_name_threshold" env:"SANCTIONS_NAME_THRESHOLD" default:"0.85"`
+
+	// AutoMigrate applies pending migrations at startup. On Postgres replicas take
+	// an advisory lock first, so only one of them migrates.
+	AutoMigrate bool `hcl:"auto_migrate" env:"AUTO_MIGRATE" default:"false"`
+
+	// LogLevel is one of debug, info, warn and error; LogFormat is json or text.
+	LogLevel  string `hcl:"log_level" env:"LOG_LEVEL" default:"info"`
+	LogFormat string `hcl:"log_format" env:"LOG_FORMAT" default:"json"`
+
+	// LockingStrategy is pessimistic (per-wallet locks in this process) or optimistic
+	// (no locks, retry on version conflicts). Either way a transfer that conflicts with
+	// another, including serialization failures and deadlocks, is attempted up to
+	// TransferMaxAttempts times with jittered exponential backoff from TransferRetryBackoff.
+	LockingStrategy      string        `hcl:"locking_strategy" env:"LOCKING_STRATEGY" default:"pessimistic"`
+	TransferMaxAttempts  int           `hcl:"transfer_max_attempts" env:"TRANSFER_MAX_ATTEMPTS" default:"5"`
+	TransferRetryBackoff time.Duration `hcl:"transfer_retry_backoff" env:"TRANSFER_RETRY_BACKOFF" default:"10ms"`
+
+	// ShutdownTimeout bounds how long in-flight requests may drain after SIGTERM.
+	ShutdownTimeout time.Duration `hcl:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" default:"30s"`
+
+	// AuthEnabled requires an API key on every request. Disable only for local development.
+	AuthEnabled bool `hcl:"auth_enabled" env:"AUTH_ENABLED" default:"true"`
+
+	// JWKSFile enables bearer token authentication for end users. Tokens must be
+	// signed by a key from this file and issued by one of JWTIssuers.
+	JWKSFile    string   `hcl:"jwks_file" env:"JWKS_FILE"`
+	JWTIssuers  []string `hcl:"jwt_issuers" env:"JWT_ISSUERS"`
+	JWTAudience string   `hcl:"jwt_audience" env:"JWT_AUDIENCE"`
+
+	// Token bucket rate limits, in requests per second with the given burst, counted
+	// per api_key, ip or wallet. The postgres store shares limits across replicas.
+	// Every request is also limited per client IP before it is authenticated.
+	RateLimitEnabled bool    `hcl:"rate_limit_enabled" env:"RATE_LIMIT_ENABLED" default:"true"`
+	RateLimitKey     string  `hcl:"rate_limit_key" env:"RATE_LIMIT_KEY" default:"api_key"`
+	RateLimitStore   string  `hcl:"rate_limit_store" env:"RATE_LIMIT_STORE" default:"memory"`
+	ReadRateLimit    float64 `hcl:"read_rate_limit" env:"READ_RATE_LIMIT" default:"50"`
+	ReadBurst        int     `hcl:"read_burst" env:"READ_BURST" default:"100"`
+	WriteRateLimit   float64 `hcl:"write_rate_limit" env:"WRITE_RATE_LIMIT" default:"10"`
+	WriteBurst       int     `hcl:"write_burst" env:"WRITE_BURST" default:"20"`
+	IPRateLimit      float64 `hcl:"ip_rate_limit" env:"IP_RATE_LIMIT" default:"100"`
+	IPBurst          int     `hcl:"ip_burst" env:"IP_BURST" default:"200"`
+
+	// Span export: none, stdout (local runs) or otlp (OTLP/gRPC collector at OTLPEndpoint).
+	// W3C traceparent headers are propagated regardless of the exporter.
+	TracingExporter    string  `hcl:"tracing_exporter" env:"TRACING_EXPORTER" default:"none"`
+	OTLPEndpoint       string  `hcl:"otlp_endpoint" env:"OTLP_ENDPOINT" default:"localhost:4317"`
+	OTLPInsecure       bool    `hcl:"otlp_insecure" env:"OTLP_INSECURE" default:"true"`
+	TracingSampleRatio float64 `hcl:"tracing_sample_ratio" env:"TRACING_SAMPLE_RATIO" default:"1"`
+}
+
+// DSN returns DBDSN if set, or the Postgres connection string built from the
+// database settings.
+func (c Config) DSN() string {
+	if c.DBDSN != "" {
+		return c.DBDSN
+	}
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		c.DBHost, c.DBPort, c.DBUser, c.DBPassword, c.DBName, c.SSLMode,
+	)
 }
 
 var (
@@ -26,6 +141,8 @@ func Get() Config {
 	once.Do(func() {
 		loader := aconfig.LoaderFor(&cfg, aconfig.Config{
 			EnvPrefix: "NFB",
+			// Flags belong to the binaries (e.g. the apikey subcommands), not to the config.
+			SkipFlags: true,
 			Files:     []string{"./config.hcl", "./config.local.hcl"},
 			FileDecoders: map[string]aconfig.FileDecoder{
 				".hcl": aconfighcl.New(),
@@ -33,7 +150,7 @@ func Get() Config {
 		})
 
 		if err := loader.Load(); err != nil {
-			log.Printf("[ERROR] failed to load config: %v", err)
+			slog.Error("failed to load config", "error", err)
 		}
 
 	})
@@ -0,0 +1,30 @@
+// Package model defines the core data models used in the transaction service.
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey is a credential issued to an API client. Only a hash of its secret is stored.
+type APIKey struct {
+	ID         uuid.UUID   // Unique identifier, also embedded in the key itself
+	Name       string      // Human readable label of the client
+	SecretHash []byte      // SHA-256 of the key secret
+	Scopes     []Scope     // Permissions granted to the key
+	WalletIDs  []uuid.UUID // Wallets the key is bound to; empty means any wallet
+	CreatedAt  time.Time   // When the key was issued
+	RotatedAt  *time.Time  // When the secret was last replaced, if ever
+	RevokedAt  *time.Time  // When the key was revoked, if it was
+}
+
+// Principal returns the identity requests authenticated with the key act as.
+func (k *APIKey) Principal() *Principal {
+	return &Principal{
+		Subject:    "apikey:" + k.ID.String(),
+		Scopes:     k.Scopes,
+		AllWallets: len(k.WalletIDs) == 0,
+		WalletIDs:  k.WalletIDs,
+	}
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChainHash returns the hash linking a transaction into the transaction log: the
+// hex SHA-256 of the JSON object below, whose fields are encoded in this order
+// with created_at in RFC 3339 with nanoseconds, in UTC. Any edit to a transaction
+// changes its hash, and so the previous hash of the transaction after it.
+//
+//	{"prev_hash":"…","id":"…","from":"…","to":"…","amount":100,"created_at":"…","seq":1,"request_id":"…","reason":"…"}
+func ChainHash(prevHash string, t Transaction) string {
+	content, _ := json.Marshal(struct {
+		PrevHash  string    `json:"prev_hash"`
+		ID        uuid.UUID `json:"id"`
+		From      string    `json:"from"`
+		To        string    `json:"to"`
+		Amount    int       `json:"amount"`
+		CreatedAt string    `json:"created_at"`
+		Seq       int64     `json:"seq"`
+		RequestID string    `json:"request_id"`
+		Reason    string    `json:"reason"`
+	}{prevHash, t.ID, t.From, t.To, t.Amount, t.CreatedAt.UTC().Format(time.RFC3339Nano), t.Seq, t.RequestID, t.Reason})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Link sets the chain hashes of a transaction following the one hashed prevHash.
+// The transaction must carry its final Seq and CreatedAt.
+func (t *Transaction) Link(prevHash string) {
+	t.PrevHash = prevHash
+	t.Hash = ChainHash(prevHash, *t)
+}
+
+// ChainHead is the last link of the hash chain.
+type ChainHead struct {
+	Seq   int64  // Sequence number of the last chained transaction, 0 before the first
+	Hash  string // Hash of that transaction
+	Count int64  // Number of transactions chained so far
+}
+
+// Checkpoint is a signed statement of the chain head at a point in time. Held
+// outside the service, it proves the log up to Seq has not been rewritten since.
+type Checkpoint struct {
+	ChainHead
+	CreatedAt time.Time
+	KeyID     string // ID of the key the checkpoint is signed with
+	Signature []byte // Ed25519 signature of SignedPayload
+}
+
+// SignedPayload returns the bytes a checkpoint signature covers: the JSON object
+// below, with fields in this order and created_at in RFC 3339 with nanoseconds,
+// in UTC.
+//
+//	{"seq":42,"count":42,"hash":"…","created_at":"…","key_id":"…"}
+func (c Checkpoint) SignedPayload() []byte {
+	payload, _ := json.Marshal(struct {
+		Seq       int64  `json:"seq"`
+		Count     int64  `json:"count"`
+		Hash      string `json:"hash"`
+		CreatedAt string `json:"created_at"`
+		KeyID     string `json:"key_id"`
+	}{c.Seq, c.Count, c.Hash, c.CreatedAt.UTC().Format(time.RFC3339Nano), c.KeyID})
+	return payload
+}
+
+// ChainVerification reports a walk of the hash chain.
+type ChainVerification struct {
+	Checked     int         // Number of chained transactions verified
+	Unchained   int         // Transactions created before the chain began, not verified
+	Checkpoints int         // Number of checkpoints the chain was compared with
+	Head        ChainHead   // Head of the chain when the walk started
+	Broken      *BrokenLink // First link found broken, nil if the chain is intact
+}
+
+// BrokenLink is a transaction whose chain hashes do not add up.
+type BrokenLink struct {
+	Seq           int64
+	TransactionID uuid.UUID
+	Problem       string // What does not match
+	Expected      string // Hash the chain calls for
+	Actual        string // Hash found
+}
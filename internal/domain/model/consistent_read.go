@@ -0,0 +1,17 @@
+package model
+
+import "context"
+
+type consistentReadKey struct{}
+
+// WithConsistentRead returns a copy of ctx whose reads must see every committed
+// write, so storage with read replicas serves them from the primary.
+func WithConsistentRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, consistentReadKey{}, true)
+}
+
+// ConsistentReadFromContext reports whether ctx asks for consistent reads.
+func ConsistentReadFromContext(ctx context.Context) bool {
+	consistent, _ := ctx.Value(consistentReadKey{}).(bool)
+	return consistent
+}
@@ -0,0 +1,39 @@
+// Package model defines the core data models used in the transaction service.
+package model
+
+import "errors"
+
+// Domain errors shared by every layer. Callers wrap them with context and
+// presenters match them with errors.Is to pick a status code.
+var (
+	ErrWalletNotFound    = errors.New("wallet not found")
+	ErrInvalidWalletID   = errors.New("invalid wallet ID")
+	ErrInvalidAmount     = errors.New("invalid amount")
+	ErrSameWallet        = errors.New("cannot send money to the same wallet")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrUnauthenticated   = errors.New("unauthenticated")
+	ErrForbidden         = errors.New("forbidden")
+	ErrAPIKeyNotFound    = errors.New("api key not found")
+	ErrInvalidScope      = errors.New("invalid scope")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrInvalidFilter     = errors.New("invalid filter")
+	ErrConflict          = errors.New("concurrent modification")
+	ErrWalletNotEmpty    = errors.New("wallet balance is not zero")
+	ErrSystemWallet      = errors.New("not allowed on the mint wallet")
+	ErrReasonRequired    = errors.New("reason is required")
+	ErrNameRequired      = errors.New("name is required")
+	ErrSigningDisabled   = errors.New("no signing key is configured")
+	ErrTransferDenied    = errors.New("transfer denied by risk rules")
+	ErrSanctionsHit      = errors.New("transfer blocked by sanctions screening")
+	ErrCaseNotFound      = errors.New("sanctions case not found")
+	ErrCaseResolved      = errors.New("sanctions case is already resolved")
+	ErrInvalidResolution = errors.New("invalid case resolution")
+	ErrNotWhitelisted    = errors.New("whitelist entry not found")
+	ErrEntryRequired     = errors.New("watchlist entry is required")
+	ErrLimitExceeded     = errors.New("spending limit exceeded")
+	ErrTierNotFound      = errors.New("limit tier not found")
+	ErrInvalidLimits     = errors.New("invalid limits")
+	ErrInvalidStatus     = errors.New("invalid wallet status")
+	ErrStatusTransition  = errors.New("wallet status transition not allowed")
+	ErrWalletStatus      = errors.New("wallet status does not allow the transfer")
+)
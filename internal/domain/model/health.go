@@ -0,0 +1,16 @@
+package model
+
+// Readiness checks reported by the health service.
+const (
+	CheckDatabase       = "database"
+	CheckMigrations     = "migrations"
+	CheckInitialization = "initialization"
+	CheckShutdown       = "shutdown"
+)
+
+// HealthCheck is the result of one readiness check.
+type HealthCheck struct {
+	Name    string
+	Healthy bool
+	Detail  string // Why the check failed, or what it found
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTier holds the limits of wallets not assigned to a tier. Until it is
+// saved, such wallets have no limits of their tier.
+const DefaultTier = "default"
+
+// Limits caps what a wallet sends with SendMoney. A nil field caps nothing. The
+// totals and the count include every transfer sent from the wallet.
+type Limits struct {
+	MaxTransfer  *int // Largest single transfer, in cents
+	DailyTotal   *int // Sent per calendar day in UTC, in cents
+	MonthlyTotal *int // Sent per calendar month in UTC, in cents
+	HourlyCount  *int // Transfers within the last hour
+}
+
+// Validate reports a negative limit.
+func (l Limits) Validate() error {
+	for name, limit := range map[string]*int{
+		"max_transfer":  l.MaxTransfer,
+		"daily_total":   l.DailyTotal,
+		"monthly_total": l.MonthlyTotal,
+		"hourly_count":  l.HourlyCount,
+	} {
+		if limit != nil && *limit < 0 {
+			return fmt.Errorf("%w: %s must not be negative", ErrInvalidLimits, name)
+		}
+	}
+	return nil
+}
+
+// Over returns base with the limits set in l replacing its own.
+func (l Limits) Over(base Limits) Limits {
+	pick := func(override, fallback *int) *int {
+		if override != nil {
+			return override
+		}
+		return fallback
+	}
+	return Limits{
+		MaxTransfer:  pick(l.MaxTransfer, base.MaxTransfer),
+		DailyTotal:   pick(l.DailyTotal, base.DailyTotal),
+		MonthlyTotal: pick(l.MonthlyTotal, base.MonthlyTotal),
+		HourlyCount:  pick(l.HourlyCount, base.HourlyCount),
+	}
+}
+
+// TierLimits are the limits shared by the wallets of a tier.
+type TierLimits struct {
+	Name      string
+	Limits    Limits
+	UpdatedAt time.Time
+}
+
+// WalletLimits assigns a wallet to a tier and overrides limits of the tier for it.
+type WalletLimits struct {
+	WalletID  uuid.UUID
+	Tier      string // DefaultTier when empty
+	Limits    Limits // Nil fields take the limit of the tier
+	UpdatedAt time.Time
+}
+
+// TierName returns the tier the wallet is in.
+func (w WalletLimits) TierName() string {
+	if w.Tier == "" {
+		return DefaultTier
+	}
+	return w.Tier
+}
+
+// SpendingUsage is what a wallet has sent within the windows of its limits.
+type SpendingUsage struct {
+	DailyTotal   int // Sent today, in cents
+	MonthlyTotal int // Sent this month, in cents
+	HourlyCount  int // Transfers within the last hour
+}
+
+// Allowance is what a wallet may still send under its limits.
+type Allowance struct {
+	WalletID uuid.UUID
+	Tier     string
+	Limits   Limits // The limits of the tier with the overrides of the wallet
+	Used     SpendingUsage
+}
+
+// Remaining returns what is left of each limit, nil where nothing is capped.
+// MaxTransfer is the largest transfer the wallet may send now.
+func (a Allowance) Remaining() Limits {
+	left := func(limit *int, used int) *int {
+		if limit == nil {
+			return nil
+		}
+		rest := max(*limit-used, 0)
+		return &rest
+	}
+	remaining := Limits{
+		DailyTotal:   left(a.Limits.DailyTotal, a.Used.DailyTotal),
+		MonthlyTotal: left(a.Limits.MonthlyTotal, a.Used.MonthlyTotal),
+		HourlyCount:  left(a.Limits.HourlyCount, a.Used.HourlyCount),
+	}
+	for _, limit := range []*int{a.Limits.MaxTransfer, remaining.DailyTotal, remaining.MonthlyTotal} {
+		if limit != nil && (remaining.MaxTransfer == nil || *limit < *remaining.MaxTransfer) {
+			remaining.MaxTransfer = limit
+		}
+	}
+	if remaining.HourlyCount != nil && *remaining.HourlyCount == 0 {
+		zero := 0
+		remaining.MaxTransfer = &zero
+	}
+	return remaining
+}
+
+// Check fails with ErrLimitExceeded, naming the limit, when sending amount would go
+// over one of the limits.
+func (a Allowance) Check(amount int) error {
+	l, used := a.Limits, a.Used
+	switch {
+	case l.MaxTransfer != nil && amount > *l.MaxTransfer:
+		return fmt.Errorf("%w: at most %d cents per transfer", ErrLimitExceeded, *l.MaxTransfer)
+	case l.HourlyCount != nil && used.HourlyCount >= *l.HourlyCount:
+		return fmt.Errorf("%w: at most %d transfers per hour", ErrLimitExceeded, *l.HourlyCount)
+	case l.DailyTotal != nil && used.DailyTotal+amount > *l.DailyTotal:
+		return fmt.Errorf("%w: at most %d cents per day, %d sent today", ErrLimitExceeded, *l.DailyTotal, used.DailyTotal)
+	case l.MonthlyTotal != nil && used.MonthlyTotal+amount > *l.MonthlyTotal:
+		return fmt.Errorf("%w: at most %d cents per month, %d sent this month", ErrLimitExceeded, *l.MonthlyTotal, used.MonthlyTotal)
+	}
+	return nil
+}
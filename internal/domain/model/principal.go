@@ -0,0 +1,86 @@
+// Package model defines the core data models used in the transaction service.
+package model
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Scope is a permission granted to an API caller.
+type Scope string
+
+const (
+	ScopeWalletsRead    Scope = "wallets:read"    // Read balances, wallets and transactions
+	ScopeTransfersWrite Scope = "transfers:write" // Send money
+	ScopeAdmin          Scope = "admin"           // Everything, including key management
+)
+
+// ParseScope validates a scope name.
+func ParseScope(s string) (Scope, bool) {
+	switch scope := Scope(s); scope {
+	case ScopeWalletsRead, ScopeTransfersWrite, ScopeAdmin:
+		return scope, true
+	}
+	return "", false
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject    string      // Who the caller is, e.g. the API key ID or token subject
+	Scopes     []Scope     // Permissions granted to the caller
+	AllWallets bool        // The caller may act on any wallet
+	WalletIDs  []uuid.UUID // Wallets the caller may act on when AllWallets is false
+}
+
+// HasScope reports whether the principal was granted the scope. Admin implies every scope.
+func (p *Principal) HasScope(scope Scope) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+// CanAccessWallet reports whether the principal may act on the wallet.
+func (p *Principal) CanAccessWallet(id uuid.UUID) bool {
+	return p.AllWallets || p.HasScope(ScopeAdmin) || slices.Contains(p.WalletIDs, id)
+}
+
+// VisibleWalletIDs returns the wallets the principal is limited to, or nil when it
+// may access every wallet. Queries filter by it rather than trimming their results.
+func (p *Principal) VisibleWalletIDs() []string {
+	if p.AllWallets || p.HasScope(ScopeAdmin) {
+		return nil
+	}
+	ids := make([]string, len(p.WalletIDs))
+	for i, id := range p.WalletIDs {
+		ids[i] = id.String()
+	}
+	return ids
+}
+
+// CanSeeTransaction reports whether either side of the transaction is accessible to the principal.
+func (p *Principal) CanSeeTransaction(t Transaction) bool {
+	from, _ := uuid.Parse(t.From)
+	to, _ := uuid.Parse(t.To)
+	return p.CanAccessWallet(from) || p.CanAccessWallet(to)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// WithTrustedCaller returns a copy of ctx marking the call as coming from a trusted
+// in-process caller, such as the walletctl CLI, named by subject. Trusted callers
+// may do anything; the APIs never mark calls this way.
+func WithTrustedCaller(ctx context.Context, subject string) context.Context {
+	return WithPrincipal(ctx, &Principal{Subject: subject, Scopes: []Scope{ScopeAdmin}, AllWallets: true})
+}
+
+// PrincipalFromContext returns the principal stored in ctx. The usecases refuse
+// calls without one.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Receipt is a signed statement that a transfer was committed. Anyone holding the
+// published public key of KeyID can verify it without calling the service.
+type Receipt struct {
+	TransactionID uuid.UUID
+	From          string
+	To            string
+	Amount        int // In cents
+	CreatedAt     time.Time
+	Seq           int64
+	Hash          string // Chain hash of the transaction
+	IssuedAt      time.Time
+	KeyID         string // ID of the key the receipt is signed with
+	Signature     []byte // Ed25519 signature of SignedPayload
+}
+
+// NewReceipt returns the unsigned receipt of a committed transaction.
+func NewReceipt(t Transaction) Receipt {
+	return Receipt{
+		TransactionID: t.ID,
+		From:          t.From,
+		To:            t.To,
+		Amount:        t.Amount,
+		CreatedAt:     t.CreatedAt,
+		Seq:           t.Seq,
+		Hash:          t.Hash,
+	}
+}
+
+// SignedPayload returns the bytes a receipt signature covers: the JSON object
+// below, with fields in this order and both times in RFC 3339 with nanoseconds,
+// in UTC. The receipt served to clients is this object plus its signature.
+//
+//	{"transaction_id":"…","from":"…","to":"…","amount_cents":100,"created_at":"…","seq":1,"hash":"…","issued_at":"…","key_id":"…"}
+func (r Receipt) SignedPayload() []byte {
+	payload, _ := json.Marshal(struct {
+		TransactionID uuid.UUID `json:"transaction_id"`
+		From          string    `json:"from"`
+		To            string    `json:"to"`
+		Amount        int       `json:"amount_cents"`
+		CreatedAt     string    `json:"created_at"`
+		Seq           int64     `json:"seq"`
+		Hash          string    `json:"hash"`
+		IssuedAt      string    `json:"issued_at"`
+		KeyID         string    `json:"key_id"`
+	}{
+		r.TransactionID, r.From, r.To, r.Amount, r.CreatedAt.UTC().Format(time.RFC3339Nano),
+		r.Seq, r.Hash, r.IssuedAt.UTC().Format(time.RFC3339Nano), r.KeyID,
+	})
+	return payload
+}
+
+// PublicKey is a key the signatures of the service are verified with.
+type PublicKey struct {
+	ID      string
+	Key     ed25519.PublicKey
+	Current bool // Whether new signatures are made with it; retired keys only verify
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RiskAction is the outcome of a risk rule, and of a risk decision: the most
+// severe outcome of the rules that fired.
+type RiskAction string
+
+const (
+	RiskAllow  RiskAction = "allow"  // The transfer goes ahead
+	RiskReview RiskAction = "review" // The transfer goes ahead, flagged for review
+	RiskDeny   RiskAction = "deny"   // The transfer is rejected with ErrTransferDenied
+)
+
+// Severity orders actions from allow to deny.
+func (a RiskAction) Severity() int {
+	switch a {
+	case RiskReview:
+		return 1
+	case RiskDeny:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// Valid reports whether a is one of the actions above.
+func (a RiskAction) Valid() bool {
+	return a == RiskAllow || a == RiskReview || a == RiskDeny
+}
+
+// Risk rule types.
+const (
+	// RuleAmount fires on transfers of at least MinAmount.
+	RuleAmount = "amount"
+
+	// RuleVelocity fires when the sender would make more than MaxCount transfers, or
+	// send more than MaxAmount, within Window. Either limit may be left zero.
+	RuleVelocity = "velocity"
+
+	// RuleNewCounterparty fires when the sender has never sent money to the
+	// receiver, on transfers of at least MinAmount.
+	RuleNewCounterparty = "new_counterparty"
+
+	// RuleBlocklist fires when either wallet is one of Wallets.
+	RuleBlocklist = "blocklist"
+)
+
+// RiskRule is a check a transfer is evaluated against before it is executed.
+type RiskRule struct {
+	Name      string
+	Type      string     // One of the rule types above
+	Action    RiskAction // Outcome when the rule fires
+	MinAmount int        // In cents
+	Window    time.Duration
+	MaxCount  int
+	MaxAmount int // In cents
+	Wallets   []string
+}
+
+// Validate reports a rule that could never be evaluated.
+func (r RiskRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("rule has no name")
+	}
+	if !r.Action.Valid() {
+		return fmt.Errorf("rule %s: unknown action %q", r.Name, r.Action)
+	}
+	switch r.Type {
+	case RuleAmount:
+		if r.MinAmount <= 0 {
+			return fmt.Errorf("rule %s: min_amount must be positive", r.Name)
+		}
+	case RuleVelocity:
+		if r.Window <= 0 {
+			return fmt.Errorf("rule %s: window must be positive", r.Name)
+		}
+		if r.MaxCount <= 0 && r.MaxAmount <= 0 {
+			return fmt.Errorf("rule %s: needs max_count or max_amount", r.Name)
+		}
+	case RuleNewCounterparty:
+	case RuleBlocklist:
+		if len(r.Wallets) == 0 {
+			return fmt.Errorf("rule %s: blocklist has no wallets", r.Name)
+		}
+		for _, wallet := range r.Wallets {
+			if _, err := uuid.Parse(wallet); err != nil {
+				return fmt.Errorf("rule %s: %w %q", r.Name, ErrInvalidWalletID, wallet)
+			}
+		}
+	default:
+		return fmt.Errorf("rule %s: unknown type %q", r.Name, r.Type)
+	}
+	return nil
+}
+
+// Blocks reports whether a blocklist rule lists the wallet.
+func (r RiskRule) Blocks(walletID uuid.UUID) bool {
+	return slices.ContainsFunc(r.Wallets, func(wallet string) bool {
+		id, err := uuid.Parse(wallet)
+		return err == nil && id == walletID
+	})
+}
+
+// FiredRule is a rule that fired for a transfer, and why.
+type FiredRule struct {
+	Rule   string     `json:"rule"`
+	Action RiskAction `json:"action"`
+	Reason string     `json:"reason"`
+}
+
+// RiskDecision records the evaluation of a transfer against the risk rules.
+type RiskDecision struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	From      string
+	To        string
+	Amount    int // In cents
+	Action    RiskAction
+	Fired     []FiredRule // Rules that fired; empty when the transfer was allowed outright
+	RequestID string      // ID of the request that asked for the transfer, if any
+}
+
+// RiskDecisionFilter selects risk decisions. Zero fields match everything.
+type RiskDecisionFilter struct {
+	WalletID string     // Only transfers from or to this wallet
+	Action   RiskAction // Only decisions with this outcome
+	Since    time.Time  // Only decisions made at or after this time
+	Until    time.Time  // Only decisions made before this time
+	Limit    int        // Maximum number of decisions returned
+}
+
+// Matches reports whether the decision passes the filter. Limit is not applied.
+func (f RiskDecisionFilter) Matches(d RiskDecision) bool {
+	return (f.WalletID == "" || d.From == f.WalletID || d.To == f.WalletID) &&
+		(f.Action == "" || d.Action == f.Action) &&
+		(f.Since.IsZero() || !d.CreatedAt.Before(f.Since)) &&
+		(f.Until.IsZero() || d.CreatedAt.Before(f.Until))
+}
+
+// TransferVelocity counts the transfers a wallet sent within a window.
+type TransferVelocity struct {
+	Count  int
+	Amount int64 // In cents
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of watchlist entries.
+const (
+	WatchWallet = "wallet" // A wallet ID, matched exactly
+	WatchName   = "name"   // A name, matched fuzzily against the wallet owner's name
+)
+
+// Parties of a transfer a screening hit is about.
+const (
+	PartySender   = "sender"
+	PartyReceiver = "receiver"
+)
+
+// ScreeningHit is a wallet matching a watchlist entry.
+type ScreeningHit struct {
+	List      string  `json:"list" doc:"Name of the watchlist file, without its extension."`
+	Kind      string  `json:"kind" enums:"wallet,name"`
+	Entry     string  `json:"entry" doc:"The listed wallet ID or name."`
+	Reference string  `json:"reference,omitempty" doc:"ID of the entry in the list, if it has one."`
+	Party     string  `json:"party" enums:"sender,receiver"`
+	WalletID  string  `json:"wallet_id" format:"uuid"`
+	Matched   string  `json:"matched" doc:"Wallet ID or owner name that matched the entry."`
+	Score     float64 `json:"score" doc:"Similarity of a name match, 1 for an exact match."`
+}
+
+// CaseStatus is the state of a sanctions case.
+type CaseStatus string
+
+const (
+	CaseOpen          CaseStatus = "open"           // Waiting for review
+	CaseConfirmed     CaseStatus = "confirmed"      // The hits are genuine
+	CaseFalsePositive CaseStatus = "false_positive" // The hits are wrong and were whitelisted
+)
+
+// Valid reports whether s is one of the statuses above.
+func (s CaseStatus) Valid() bool {
+	return s == CaseOpen || s == CaseConfirmed || s == CaseFalsePositive
+}
+
+// SanctionsCase is opened for a transfer blocked by screening hits.
+type SanctionsCase struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	From       string
+	To         string
+	Amount     int // In cents
+	Hits       []ScreeningHit
+	Status     CaseStatus
+	Note       string    // Left by the reviewer who resolved the case
+	ResolvedAt time.Time // Zero while the case is open
+	RequestID  string    // ID of the request that asked for the transfer, if any
+}
+
+// CaseFilter selects sanctions cases. Zero fields match everything.
+type CaseFilter struct {
+	WalletID string     // Only transfers from or to this wallet
+	Status   CaseStatus // Only cases in this state
+	Limit    int        // Maximum number of cases returned
+}
+
+// Matches reports whether the case passes the filter. Limit is not applied.
+func (f CaseFilter) Matches(c SanctionsCase) bool {
+	return (f.WalletID == "" || c.From == f.WalletID || c.To == f.WalletID) &&
+		(f.Status == "" || c.Status == f.Status)
+}
+
+// WhitelistEntry clears a wallet of a watchlist entry it was wrongly matched
+// with. The entry no longer hits the wallet on any list.
+type WhitelistEntry struct {
+	WalletID  string
+	Entry     string    // The listed wallet ID or name
+	CaseID    uuid.UUID // Case resolved as a false positive, uuid.Nil for an entry added by hand
+	Reason    string    // Why the entry was added by hand
+	Actor     string    // Subject of the principal that added it by hand, empty without one
+	CreatedAt time.Time
+}
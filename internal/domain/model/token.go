@@ -0,0 +1,9 @@
+// Package model defines the core data models used in the transaction service.
+package model
+
+// TokenClaims are the claims of a verified bearer token that matter to the service.
+type TokenClaims struct {
+	Subject string  // Owner the token was issued to, matched against Wallet.OwnerID
+	Scopes  []Scope // Scopes listed in the token; empty means the end-user defaults
+	Admin   bool    // The token grants access to every wallet
+}
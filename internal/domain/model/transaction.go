@@ -3,6 +3,7 @@ package model
 
 import (
 	"github.com/google/uuid"
+	"slices"
 	"time"
 )
 
@@ -13,4 +14,50 @@ type Transaction struct {
 	To        string    // Wallet ID of the receiver
 	Amount    int       // Transaction amount in cents
 	CreatedAt time.Time // Timestamp of when the transaction was created
+	Seq       int64     // Monotonic position in the transaction log, assigned by storage
+	RequestID string    // ID of the request that created the transaction, if any
+	Reason    string    // Why money was minted or burned, empty for transfers between users
+	PrevHash  string    // Hash of the previous transaction in the chain, empty for the first
+	Hash      string    // ChainHash of the transaction, empty if created before the chain began
+}
+
+// TransferTotals sums the transfers of one wallet.
+type TransferTotals struct {
+	Sent     int64 // Sum of transfers from the wallet, in cents
+	Received int64 // Sum of transfers to the wallet, in cents
+}
+
+// TransactionFilter narrows a transaction feed down to the transfers of interest.
+type TransactionFilter struct {
+	WalletID string // When set, only transactions sent from or to this wallet match
+}
+
+// Matches reports whether the transaction passes the filter.
+func (f TransactionFilter) Matches(t Transaction) bool {
+	return f.WalletID == "" || t.From == f.WalletID || t.To == f.WalletID
+}
+
+// HistoryFilter selects transactions from the log. Zero fields match everything.
+type HistoryFilter struct {
+	WalletID  string    // Only transactions sent from or to this wallet
+	WalletIDs []string  // When not nil, only transactions sent from or to one of these wallets
+	Since     time.Time // Only transactions created at or after this time
+	Until     time.Time // Only transactions created before this time
+	MinAmount int       // Only transactions of at least this many cents
+	MaxAmount int       // Only transactions of at most this many cents
+	BeforeSeq int64     // Only transactions older than this sequence number, for paging
+	Limit     int       // Maximum number of transactions returned
+
+	IncludeArchived bool // Also search months moved to the archive once the log runs out
+}
+
+// Matches reports whether the transaction passes the filter. Limit is not applied.
+func (f HistoryFilter) Matches(t Transaction) bool {
+	return (f.WalletID == "" || t.From == f.WalletID || t.To == f.WalletID) &&
+		(f.WalletIDs == nil || slices.Contains(f.WalletIDs, t.From) || slices.Contains(f.WalletIDs, t.To)) &&
+		(f.Since.IsZero() || !t.CreatedAt.Before(f.Since)) &&
+		(f.Until.IsZero() || t.CreatedAt.Before(f.Until)) &&
+		(f.MinAmount == 0 || t.Amount >= f.MinAmount) &&
+		(f.MaxAmount == 0 || t.Amount <= f.MaxAmount) &&
+		(f.BeforeSeq == 0 || t.Seq < f.BeforeSeq)
 }
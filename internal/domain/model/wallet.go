@@ -1,10 +1,112 @@
 // Package model defines the core data models used in the transaction service.
 package model
 
-import "github.com/google/uuid"
+import (
+	"fmt"
+	"time"
 
-// Wallet represents a digital wallet with a unique ID and a balance.
+	"github.com/google/uuid"
+)
+
+// MintWalletID identifies the system wallet money is issued from and burned into.
+// It may go negative: its balance is the negative of the circulating supply.
+var MintWalletID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Wallet represents a digital wallet with a unique ID and a balance. The owner is
+// personal data and left out of its JSON; admins read it as a WalletOwnerDTO.
 type Wallet struct {
-	ID     uuid.UUID // Unique identifier for the wallet
-	Amount int       // Current balance in the wallet, in cents
+	ID        uuid.UUID    // Unique identifier for the wallet
+	Amount    int          `doc:"Balance in cents."`
+	OwnerID   string       `json:"-"` // Subject of the end user owning the wallet, empty if unassigned
+	OwnerName string       `json:"-"` // Legal name of the owner, screened against the sanctions lists
+	Status    WalletStatus `enums:"active,frozen,debit_blocked,closed"`
+	Version   int64        `json:"-"` // Incremented on every update, for optimistic concurrency
+	System    bool         `json:"-"` // Set on the mint wallet, which is left out of listings
+}
+
+// WalletStatus is the lifecycle state of a wallet. It limits the transfers wallet
+// holders make with SendMoney; mints and burns only stop at closed wallets.
+//
+// Frozen wallets refuse credits as well as debits, which goes beyond blocking
+// debits alone: a freeze holds the wallet as it is while fraud or legal review it,
+// and debit_blocked is the status that keeps taking credits.
+type WalletStatus string
+
+const (
+	WalletActive       WalletStatus = "active"        // Sends and receives
+	WalletFrozen       WalletStatus = "frozen"        // Neither sends nor receives
+	WalletDebitBlocked WalletStatus = "debit_blocked" // Receives but does not send
+	WalletClosed       WalletStatus = "closed"        // Empty and retired for good
+)
+
+// ParseWalletStatus returns the status named s.
+func ParseWalletStatus(s string) (WalletStatus, error) {
+	switch status := WalletStatus(s); status {
+	case WalletActive, WalletFrozen, WalletDebitBlocked, WalletClosed:
+		return status, nil
+	default:
+		return "", fmt.Errorf("%w %q: want active, frozen, debit_blocked or closed", ErrInvalidStatus, s)
+	}
+}
+
+// CanSend reports whether a wallet holder may debit a wallet in this status.
+func (s WalletStatus) CanSend() bool {
+	return s == WalletActive
+}
+
+// CanReceive reports whether a wallet holder may credit a wallet in this status.
+// Frozen wallets may not be credited; see WalletStatus.
+func (s WalletStatus) CanReceive() bool {
+	return s == WalletActive || s == WalletDebitBlocked
+}
+
+// CanBecome reports whether a wallet may change from s to status. Any status but
+// closed may change to any other; closed is final.
+func (s WalletStatus) CanBecome(status WalletStatus) bool {
+	return s != WalletClosed && s != status
+}
+
+// StatusChange records a change of the status of a wallet.
+type StatusChange struct {
+	ID        uuid.UUID
+	WalletID  uuid.UUID
+	From      WalletStatus
+	To        WalletStatus
+	Reason    string
+	Actor     string // Subject of the principal that made the change, empty without one
+	RequestID string // X-Request-ID of the request that made the change, if any
+	CreatedAt time.Time
+}
+
+// WalletSummary aggregates every user wallet.
+type WalletSummary struct {
+	Count       int   // Number of wallets
+	TotalAmount int64 // Sum of all balances, in cents
+}
+
+// Supply reports the money issued and burned by the mint wallet.
+type Supply struct {
+	Issued      int64 // Sum of transfers from the mint wallet, in cents
+	Burned      int64 // Sum of transfers to the mint wallet, in cents
+	WalletTotal int64 // Sum of the balances of user wallets, in cents
+}
+
+// Circulating returns the money issued and not burned, in cents.
+func (s Supply) Circulating() int64 {
+	return s.Issued - s.Burned
+}
+
+// Reconciliation compares every wallet balance with the transaction log.
+type Reconciliation struct {
+	Wallets      int               // Number of wallets checked
+	Transactions int               // Number of transactions replayed
+	TotalAmount  int64             // Sum of all balances, in cents; zero as the mint is included
+	Mismatches   []BalanceMismatch // Wallets whose balance disagrees with the log
+}
+
+// BalanceMismatch is a wallet whose balance differs from the one its transactions add up to.
+type BalanceMismatch struct {
+	WalletID uuid.UUID
+	Balance  int // Stored balance, in cents
+	Expected int // Incoming minus outgoing transfers, in cents
 }
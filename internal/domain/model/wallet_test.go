@@ -0,0 +1,23 @@
+package model
+
+import "testing"
+
+func TestWalletStatusTransfers(t *testing.T) {
+	tests := []struct {
+		status     WalletStatus
+		send, recv bool
+	}{
+		{WalletActive, true, true},
+		{WalletFrozen, false, false},
+		{WalletDebitBlocked, false, true},
+		{WalletClosed, false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.status.CanSend(); got != tt.send {
+			t.Errorf("%s.CanSend() = %v, want %v", tt.status, got, tt.send)
+		}
+		if got := tt.status.CanReceive(); got != tt.recv {
+			t.Errorf("%s.CanReceive() = %v, want %v", tt.status, got, tt.recv)
+		}
+	}
+}
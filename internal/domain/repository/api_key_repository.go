@@ -0,0 +1,26 @@
+// Package repository defines interfaces for interacting with persistent storage.
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"transaction-service/internal/domain/model"
+)
+
+// APIKeyRepository defines methods for managing API keys in the database.
+type APIKeyRepository interface {
+	// Create stores a newly issued API key.
+	Create(ctx context.Context, key *model.APIKey) error
+
+	// FetchByID retrieves an API key, revoked or not, by its ID.
+	FetchByID(ctx context.Context, id uuid.UUID) (*model.APIKey, error)
+
+	// FetchAll returns every API key, newest first.
+	FetchAll(ctx context.Context) ([]*model.APIKey, error)
+
+	// UpdateSecret replaces the secret hash of an active key.
+	UpdateSecret(ctx context.Context, id uuid.UUID, secretHash []byte) error
+
+	// Revoke marks a key as revoked so it no longer authenticates.
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"transaction-service/internal/domain/model"
+)
+
+// ChainRepository reads the hash chain of the transaction log, which
+// TransactionRepository.Create extends, and keeps its signed checkpoints.
+type ChainRepository interface {
+	// Head returns the last link of the chain.
+	Head(ctx context.Context) (model.ChainHead, error)
+
+	// ArchivedHead returns the last link of the chain in archived months, with
+	// Count the chained transactions archived. It is zero while none are.
+	ArchivedHead(ctx context.Context) (model.ChainHead, error)
+
+	// SaveCheckpoint stores a checkpoint. Saving a second checkpoint of the same
+	// Seq does nothing.
+	SaveCheckpoint(ctx context.Context, checkpoint model.Checkpoint) error
+
+	// Checkpoints retrieves up to limit checkpoints, newest first.
+	Checkpoints(ctx context.Context, limit int) ([]model.Checkpoint, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "context"
+
+// HealthRepository reports the state of the database.
+type HealthRepository interface {
+	// Ping checks that the database is reachable.
+	Ping(ctx context.Context) error
+
+	// SchemaVersion returns the version of the newest applied migration.
+	SchemaVersion(ctx context.Context) (int64, error)
+}
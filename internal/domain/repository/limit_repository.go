@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"transaction-service/internal/domain/model"
+
+	"github.com/google/uuid"
+)
+
+// LimitRepository keeps the spending limits of tiers and wallets.
+type LimitRepository interface {
+	// SaveTier creates a tier or replaces its limits.
+	SaveTier(ctx context.Context, tier model.TierLimits) error
+
+	// FetchTier returns a tier by its name, or model.ErrTierNotFound.
+	FetchTier(ctx context.Context, name string) (model.TierLimits, error)
+
+	// Tiers returns every tier, by name.
+	Tiers(ctx context.Context) ([]model.TierLimits, error)
+
+	// SaveWalletLimits replaces the tier and overrides of a wallet.
+	SaveWalletLimits(ctx context.Context, limits model.WalletLimits) error
+
+	// FetchWalletLimits returns the tier and overrides of a wallet. A wallet never
+	// saved has an empty tier and no overrides.
+	FetchWalletLimits(ctx context.Context, walletID uuid.UUID) (model.WalletLimits, error)
+}
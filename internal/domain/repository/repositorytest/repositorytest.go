@@ -0,0 +1,1241 @@
+// Package repositorytest is a conformance suite for implementations of the
+// repository interfaces. Every storage backend runs it, so the service behaves the
+// same whichever one is configured.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// Backend is a set of repositories sharing one storage.
+type Backend struct {
+	Wallets      repository.WalletRepository
+	Transactions repository.TransactionRepository
+	APIKeys      repository.APIKeyRepository
+	Chain        repository.ChainRepository
+	Risk         repository.RiskRepository
+	Sanctions    repository.SanctionsRepository
+	Limits       repository.LimitRepository
+	Statuses     repository.StatusRepository
+	Transactor   repository.Transactor
+}
+
+// Run runs the suite. newBackend is called once per test and must return empty storage.
+func Run(t *testing.T, newBackend func(t *testing.T) Backend) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, b Backend)
+	}{
+		{"WalletCreateAndFetch", testWalletCreateAndFetch},
+		{"WalletUpdateChecksVersion", testWalletUpdateChecksVersion},
+		{"WalletOwners", testWalletOwners},
+		{"WalletDeleteAndSummarize", testWalletDeleteAndSummarize},
+		{"SystemWallet", testSystemWallet},
+		{"ServiceInitialized", testServiceInitialized},
+		{"TransactionLog", testTransactionLog},
+		{"TransactionHistory", testTransactionHistory},
+		{"TransactionTotals", testTransactionTotals},
+		{"TransferVelocity", testTransferVelocity},
+		{"ConcurrentVelocityLimit", testConcurrentVelocityLimit},
+		{"TransactionChain", testTransactionChain},
+		{"ChainCheckpoints", testChainCheckpoints},
+		{"TransactionCommit", testTransactionCommit},
+		{"TransactionRollback", testTransactionRollback},
+		{"TransactionIsolation", testTransactionIsolation},
+		{"NestedTransaction", testNestedTransaction},
+		{"APIKeys", testAPIKeys},
+		{"RiskDecisions", testRiskDecisions},
+		{"SanctionsCases", testSanctionsCases},
+		{"SanctionsWhitelist", testSanctionsWhitelist},
+		{"Limits", testLimits},
+		{"WalletStatus", testWalletStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newBackend(t))
+		})
+	}
+}
+
+func testWalletCreateAndFetch(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	id := createWallet(t, b)
+	wallet, err := b.Wallets.FetchByID(ctx, id)
+	if err != nil {
+		t.Fatalf("FetchByID: %v", err)
+	}
+	if wallet.ID != id || wallet.Amount != 0 || wallet.OwnerID != "" || wallet.Status != model.WalletActive || wallet.Version != 0 || wallet.System {
+		t.Errorf("new wallet = %+v, want ID %s, amount 0, no owner, active, version 0, not system", wallet, id)
+	}
+
+	if _, err := b.Wallets.FetchByID(ctx, uuid.Nil); !errors.Is(err, model.ErrInvalidWalletID) {
+		t.Errorf("FetchByID(nil) error = %v, want %v", err, model.ErrInvalidWalletID)
+	}
+	if _, err := b.Wallets.FetchByID(ctx, uuid.New()); !errors.Is(err, model.ErrWalletNotFound) {
+		t.Errorf("FetchByID(unknown) error = %v, want %v", err, model.ErrWalletNotFound)
+	}
+}
+
+func testWalletUpdateChecksVersion(t *testing.T, b Backend) {
+	ctx := context.Background()
+	id := createWallet(t, b)
+
+	first := fetchWallet(t, b, id)
+	stale := fetchWallet(t, b, id)
+
+	first.Amount = 70
+	updated, err := b.Wallets.Update(ctx, first)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Version != 1 {
+		t.Errorf("Update returned version %d, want 1", updated.Version)
+	}
+	if got := fetchWallet(t, b, id); got.Amount != 70 || got.Version != 1 {
+		t.Errorf("after Update wallet = %+v, want amount 70 at version 1", got)
+	}
+
+	stale.Amount = 10
+	if _, err := b.Wallets.Update(ctx, stale); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("Update(stale) error = %v, want %v", err, model.ErrConflict)
+	}
+	if got := fetchWallet(t, b, id); got.Amount != 70 {
+		t.Errorf("stale Update changed the balance to %d", got.Amount)
+	}
+
+	missing := &model.Wallet{ID: uuid.New(), Amount: 1}
+	if _, err := b.Wallets.Update(ctx, missing); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("Update(unknown) error = %v, want %v", err, model.ErrConflict)
+	}
+}
+
+func testWalletOwners(t *testing.T, b Backend) {
+	ctx := context.Background()
+	owned, other := createWallet(t, b), createWallet(t, b)
+
+	if err := b.Wallets.SetOwner(ctx, owned, "user:1", "Ivan Petrov"); err != nil {
+		t.Fatalf("SetOwner: %v", err)
+	}
+	wallets, err := b.Wallets.FetchByOwner(ctx, "user:1")
+	if err != nil {
+		t.Fatalf("FetchByOwner: %v", err)
+	}
+	if len(wallets) != 1 || wallets[0].ID != owned || wallets[0].OwnerID != "user:1" || wallets[0].OwnerName != "Ivan Petrov" {
+		t.Errorf("FetchByOwner = %+v, want only wallet %s", wallets, owned)
+	}
+	if wallets, err := b.Wallets.FetchByOwner(ctx, "user:2"); err != nil || len(wallets) != 0 {
+		t.Errorf("FetchByOwner(no wallets) = %+v, %v, want none", wallets, err)
+	}
+
+	if got := fetchWallet(t, b, owned); got.OwnerName != "Ivan Petrov" {
+		t.Errorf("FetchByID owner name = %q, want %q", got.OwnerName, "Ivan Petrov")
+	}
+
+	if err := b.Wallets.SetOwner(ctx, owned, "", ""); err != nil {
+		t.Fatalf("SetOwner(unassign): %v", err)
+	}
+	if got := fetchWallet(t, b, owned); got.OwnerID != "" || got.OwnerName != "" {
+		t.Errorf("unassigned wallet has owner %q named %q", got.OwnerID, got.OwnerName)
+	}
+	if got := fetchWallet(t, b, other); got.OwnerID != "" {
+		t.Errorf("SetOwner changed another wallet's owner to %q", got.OwnerID)
+	}
+
+	if err := b.Wallets.SetOwner(ctx, uuid.New(), "user:1", ""); !errors.Is(err, model.ErrWalletNotFound) {
+		t.Errorf("SetOwner(unknown) error = %v, want %v", err, model.ErrWalletNotFound)
+	}
+}
+
+func testWalletDeleteAndSummarize(t *testing.T, b Backend) {
+	ctx := context.Background()
+	kept, deleted := createWallet(t, b), createWallet(t, b)
+
+	wallet := fetchWallet(t, b, kept)
+	wallet.Amount = 250
+	if _, err := b.Wallets.Update(ctx, wallet); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	if err := b.Wallets.Delete(ctx, deleted); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := b.Wallets.FetchByID(ctx, deleted); !errors.Is(err, model.ErrWalletNotFound) {
+		t.Errorf("FetchByID(deleted) error = %v, want %v", err, model.ErrWalletNotFound)
+	}
+
+	wallets, err := b.Wallets.FetchAll(ctx)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if len(wallets) != 1 || wallets[0].ID != kept {
+		t.Errorf("FetchAll = %+v, want only wallet %s", wallets, kept)
+	}
+
+	summary, err := b.Wallets.Summarize(ctx)
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if want := (model.WalletSummary{Count: 1, TotalAmount: 250}); summary != want {
+		t.Errorf("Summarize = %+v, want %+v", summary, want)
+	}
+}
+
+func testSystemWallet(t *testing.T, b Backend) {
+	ctx := context.Background()
+	user := createWallet(t, b)
+
+	// Creating it twice is fine: migrations may have created it already.
+	for range 2 {
+		if err := b.Wallets.CreateSystem(ctx, model.MintWalletID); err != nil {
+			t.Fatalf("CreateSystem: %v", err)
+		}
+	}
+	mint := fetchWallet(t, b, model.MintWalletID)
+	if !mint.System || mint.Amount != 0 {
+		t.Errorf("system wallet = %+v, want a system wallet with amount 0", mint)
+	}
+
+	// A system wallet may go negative.
+	mint.Amount = -500
+	if _, err := b.Wallets.Update(ctx, mint); err != nil {
+		t.Fatalf("Update(system): %v", err)
+	}
+	wallet := fetchWallet(t, b, user)
+	wallet.Amount = 500
+	if _, err := b.Wallets.Update(ctx, wallet); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := b.Wallets.SetOwner(ctx, user, "user:1", ""); err != nil {
+		t.Fatalf("SetOwner: %v", err)
+	}
+
+	wallets, err := b.Wallets.FetchAll(ctx)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if len(wallets) != 1 || wallets[0].ID != user {
+		t.Errorf("FetchAll = %+v, want only user wallet %s", wallets, user)
+	}
+	summary, err := b.Wallets.Summarize(ctx)
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if want := (model.WalletSummary{Count: 1, TotalAmount: 500}); summary != want {
+		t.Errorf("Summarize = %+v, want %+v", summary, want)
+	}
+}
+
+func testServiceInitialized(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	if initialized, err := b.Wallets.IsServiceInitialized(ctx); err != nil || initialized {
+		t.Fatalf("IsServiceInitialized on empty storage = %v, %v, want false", initialized, err)
+	}
+	if err := b.Wallets.SetServiceInitialized(ctx); err != nil {
+		t.Fatalf("SetServiceInitialized: %v", err)
+	}
+	if initialized, err := b.Wallets.IsServiceInitialized(ctx); err != nil || !initialized {
+		t.Errorf("IsServiceInitialized = %v, %v, want true", initialized, err)
+	}
+}
+
+func testTransactionLog(t *testing.T, b Backend) {
+	ctx := context.Background()
+	alice, bob, carol := uuid.NewString(), uuid.NewString(), uuid.NewString()
+
+	created := []*model.Transaction{
+		{ID: uuid.New(), From: alice, To: bob, Amount: 10, RequestID: "req-1"},
+		{ID: uuid.New(), From: bob, To: carol, Amount: 20},
+		{ID: uuid.New(), From: carol, To: alice, Amount: 30},
+	}
+	for _, transaction := range created {
+		id, err := b.Transactions.Create(ctx, transaction)
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if id != transaction.ID {
+			t.Errorf("Create returned ID %s, want %s", id, transaction.ID)
+		}
+	}
+	if _, err := b.Transactions.Create(ctx, nil); err == nil {
+		t.Error("Create(nil) succeeded")
+	}
+
+	all, err := b.Transactions.GetTransactionsAfter(ctx, 0, model.TransactionFilter{}, 10)
+	if err != nil {
+		t.Fatalf("GetTransactionsAfter: %v", err)
+	}
+	if len(all) != len(created) {
+		t.Fatalf("GetTransactionsAfter returned %d transactions, want %d", len(all), len(created))
+	}
+	for n, transaction := range all {
+		want := created[n]
+		if transaction.ID != want.ID || transaction.From != want.From || transaction.To != want.To ||
+			transaction.Amount != want.Amount || transaction.RequestID != want.RequestID {
+			t.Errorf("transaction %d = %+v, want %+v", n, transaction, *want)
+		}
+		if transaction.CreatedAt.IsZero() {
+			t.Errorf("transaction %d has no creation time", n)
+		}
+		if n > 0 && transaction.Seq <= all[n-1].Seq {
+			t.Errorf("sequence numbers not increasing: %d after %d", transaction.Seq, all[n-1].Seq)
+		}
+	}
+
+	after, err := b.Transactions.GetTransactionsAfter(ctx, all[0].Seq, model.TransactionFilter{}, 1)
+	if err != nil {
+		t.Fatalf("GetTransactionsAfter(seq, limit): %v", err)
+	}
+	if len(after) != 1 || after[0].ID != created[1].ID {
+		t.Errorf("GetTransactionsAfter(first seq, 1) = %+v, want the second transaction", after)
+	}
+
+	filtered, err := b.Transactions.GetTransactionsAfter(ctx, 0, model.TransactionFilter{WalletID: alice}, 10)
+	if err != nil {
+		t.Fatalf("GetTransactionsAfter(filter): %v", err)
+	}
+	if len(filtered) != 2 || filtered[0].ID != created[0].ID || filtered[1].ID != created[2].ID {
+		t.Errorf("GetTransactionsAfter(wallet filter) = %+v, want the first and third transactions", filtered)
+	}
+
+	recent, err := b.Transactions.GetTransactions(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetTransactions: %v", err)
+	}
+	if len(recent) != len(created) {
+		t.Fatalf("GetTransactions returned %d transactions, want %d", len(recent), len(created))
+	}
+	for n := 1; n < len(recent); n++ {
+		if recent[n].CreatedAt.After(recent[n-1].CreatedAt) {
+			t.Errorf("GetTransactions is not newest first: %v after %v", recent[n].CreatedAt, recent[n-1].CreatedAt)
+		}
+	}
+
+	// The wallet filter is applied before the limit, so it never trims a page.
+	owned, err := b.Transactions.GetTransactions(ctx, []string{carol})
+	if err != nil {
+		t.Fatalf("GetTransactions(wallets): %v", err)
+	}
+	seen := make(map[uuid.UUID]bool)
+	for _, transaction := range owned {
+		seen[transaction.ID] = true
+	}
+	if len(owned) != 2 || !seen[created[1].ID] || !seen[created[2].ID] {
+		t.Errorf("GetTransactions(carol) = %+v, want the second and third transactions", owned)
+	}
+	if none, err := b.Transactions.GetTransactions(ctx, []string{}); err != nil || len(none) != 0 {
+		t.Errorf("GetTransactions(no wallets) = %+v, %v, want none", none, err)
+	}
+}
+
+func testTransactionChain(t *testing.T, b Backend) {
+	ctx := context.Background()
+	alice, bob := uuid.NewString(), uuid.NewString()
+
+	if head, err := b.Chain.Head(ctx); err != nil || head != (model.ChainHead{}) {
+		t.Errorf("Head of an empty log = %+v, %v; want the zero head", head, err)
+	}
+
+	first := &model.Transaction{ID: uuid.New(), From: alice, To: bob, Amount: 10, Reason: "rent"}
+	if _, err := b.Transactions.Create(ctx, first); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if first.Seq == 0 || first.CreatedAt.IsZero() || first.PrevHash != "" || first.Hash != model.ChainHash("", *first) {
+		t.Errorf("created transaction = %+v, want its seq, creation time and the first link of the chain", *first)
+	}
+
+	// A rolled back transaction leaves the chain as it was.
+	errAbort := errors.New("abort")
+	err := b.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := b.Transactions.Create(ctx, &model.Transaction{ID: uuid.New(), From: bob, To: alice, Amount: 99}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithinTransaction error = %v, want %v", err, errAbort)
+	}
+	for _, amount := range []int{20, 30} {
+		if _, err := b.Transactions.Create(ctx, &model.Transaction{ID: uuid.New(), From: bob, To: alice, Amount: amount}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	all, err := b.Transactions.GetTransactionsAfter(ctx, 0, model.TransactionFilter{}, 10)
+	if err != nil {
+		t.Fatalf("GetTransactionsAfter: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("GetTransactionsAfter returned %d transactions, want 3", len(all))
+	}
+	if all[0].Hash != first.Hash {
+		t.Errorf("stored hash %q, want the %q set by Create", all[0].Hash, first.Hash)
+	}
+	for n, transaction := range all {
+		prev := ""
+		if n > 0 {
+			prev = all[n-1].Hash
+		}
+		if transaction.PrevHash != prev {
+			t.Errorf("transaction %d links to %q, want %q", n, transaction.PrevHash, prev)
+		}
+		if want := model.ChainHash(prev, transaction); transaction.Hash != want {
+			t.Errorf("transaction %d hash = %q, want %q", n, transaction.Hash, want)
+		}
+	}
+
+	head, err := b.Chain.Head(ctx)
+	if err != nil {
+		t.Fatalf("Head: %v", err)
+	}
+	last := all[len(all)-1]
+	if want := (model.ChainHead{Seq: last.Seq, Hash: last.Hash, Count: 3}); head != want {
+		t.Errorf("Head = %+v, want %+v", head, want)
+	}
+}
+
+func testChainCheckpoints(t *testing.T, b Backend) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	checkpoint := func(seq int64, keyID string) model.Checkpoint {
+		return model.Checkpoint{
+			ChainHead: model.ChainHead{Seq: seq, Hash: fmt.Sprintf("hash-%d", seq), Count: seq},
+			CreatedAt: now.Add(time.Duration(seq) * time.Second), KeyID: keyID, Signature: []byte{byte(seq), 1, 2},
+		}
+	}
+	for _, seq := range []int64{1, 5, 9} {
+		if err := b.Chain.SaveCheckpoint(ctx, checkpoint(seq, "key-1")); err != nil {
+			t.Fatalf("SaveCheckpoint: %v", err)
+		}
+	}
+	if err := b.Chain.SaveCheckpoint(ctx, checkpoint(5, "key-2")); err != nil {
+		t.Errorf("SaveCheckpoint of a saved seq: %v", err)
+	}
+
+	checkpoints, err := b.Chain.Checkpoints(ctx, 2)
+	if err != nil {
+		t.Fatalf("Checkpoints: %v", err)
+	}
+	if len(checkpoints) != 2 {
+		t.Fatalf("Checkpoints(2) returned %d checkpoints, want 2", len(checkpoints))
+	}
+	for n, seq := range []int64{9, 5} {
+		got, want := checkpoints[n], checkpoint(seq, "key-1")
+		if got.ChainHead != want.ChainHead || !got.CreatedAt.Equal(want.CreatedAt) ||
+			got.KeyID != want.KeyID || string(got.Signature) != string(want.Signature) {
+			t.Errorf("checkpoint %d = %+v, want %+v", n, got, want)
+		}
+	}
+}
+
+func testTransactionHistory(t *testing.T, b Backend) {
+	ctx := context.Background()
+	alice, bob, carol := uuid.NewString(), uuid.NewString(), uuid.NewString()
+
+	created := []*model.Transaction{
+		{ID: uuid.New(), From: alice, To: bob, Amount: 10},
+		{ID: uuid.New(), From: bob, To: carol, Amount: 20},
+		{ID: uuid.New(), From: carol, To: alice, Amount: 30},
+		{ID: uuid.New(), From: alice, To: carol, Amount: 40},
+	}
+	for _, transaction := range created {
+		transaction.CreatedAt = time.Now()
+		if _, err := b.Transactions.Create(ctx, transaction); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	// Times are a day apart from now, so the zone the storage records them in does not matter.
+	now := time.Now()
+	tests := []struct {
+		name   string
+		filter model.HistoryFilter
+		want   []int // Indexes into created, newest first
+	}{
+		{"all", model.HistoryFilter{Limit: 10}, []int{3, 2, 1, 0}},
+		{"limit", model.HistoryFilter{Limit: 2}, []int{3, 2}},
+		{"wallet", model.HistoryFilter{WalletID: alice, Limit: 10}, []int{3, 2, 0}},
+		{"amount range", model.HistoryFilter{MinAmount: 20, MaxAmount: 30, Limit: 10}, []int{2, 1}},
+		{"time range", model.HistoryFilter{Since: now.Add(-24 * time.Hour), Until: now.Add(24 * time.Hour), Limit: 10}, []int{3, 2, 1, 0}},
+		{"since", model.HistoryFilter{Since: now.Add(24 * time.Hour), Limit: 10}, nil},
+		{"until", model.HistoryFilter{Until: now.Add(-24 * time.Hour), Limit: 10}, nil},
+		{"wallet and amount", model.HistoryFilter{WalletID: carol, MinAmount: 30, Limit: 10}, []int{3, 2}},
+		{"wallets", model.HistoryFilter{WalletIDs: []string{bob}, Limit: 10}, []int{1, 0}},
+		{"no wallets", model.HistoryFilter{WalletIDs: []string{}, Limit: 10}, nil},
+		{"wallets and limit", model.HistoryFilter{WalletIDs: []string{alice, bob}, Limit: 3}, []int{3, 2, 1}},
+	}
+	for _, tt := range tests {
+		found, err := b.Transactions.FindTransactions(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("FindTransactions(%s): %v", tt.name, err)
+		}
+		if len(found) != len(tt.want) {
+			t.Errorf("FindTransactions(%s) returned %d transactions, want %d", tt.name, len(found), len(tt.want))
+			continue
+		}
+		for n, transaction := range found {
+			if want := created[tt.want[n]]; transaction.ID != want.ID {
+				t.Errorf("FindTransactions(%s)[%d] = %+v, want %+v", tt.name, n, transaction, *want)
+			}
+		}
+	}
+
+	// Paging by sequence number walks the whole log.
+	var paged []model.Transaction
+	filter := model.HistoryFilter{Limit: 3}
+	for {
+		page, err := b.Transactions.FindTransactions(ctx, filter)
+		if err != nil {
+			t.Fatalf("FindTransactions(page): %v", err)
+		}
+		paged = append(paged, page...)
+		if len(page) < filter.Limit {
+			break
+		}
+		filter.BeforeSeq = page[len(page)-1].Seq
+	}
+	if len(paged) != len(created) || paged[0].ID != created[3].ID || paged[len(paged)-1].ID != created[0].ID {
+		t.Errorf("paging returned %+v, want every transaction newest first", paged)
+	}
+}
+
+func testTransactionTotals(t *testing.T, b Backend) {
+	ctx := context.Background()
+	mint, alice, bob := model.MintWalletID.String(), uuid.NewString(), uuid.NewString()
+
+	for _, transaction := range []*model.Transaction{
+		{ID: uuid.New(), From: mint, To: alice, Amount: 300, Reason: "grant"},
+		{ID: uuid.New(), From: mint, To: bob, Amount: 200, Reason: "grant"},
+		{ID: uuid.New(), From: alice, To: bob, Amount: 50},
+		{ID: uuid.New(), From: bob, To: mint, Amount: 70, Reason: "refund"},
+	} {
+		if _, err := b.Transactions.Create(ctx, transaction); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	totals, err := b.Transactions.Totals(ctx, mint)
+	if err != nil {
+		t.Fatalf("Totals: %v", err)
+	}
+	if want := (model.TransferTotals{Sent: 500, Received: 70}); totals != want {
+		t.Errorf("Totals(mint) = %+v, want %+v", totals, want)
+	}
+	if totals, err := b.Transactions.Totals(ctx, uuid.NewString()); err != nil || totals != (model.TransferTotals{}) {
+		t.Errorf("Totals(no transfers) = %+v, %v, want zero", totals, err)
+	}
+	if archived, err := b.Transactions.ArchivedTotals(ctx); err != nil || len(archived) != 0 {
+		t.Errorf("ArchivedTotals = %v, %v, want none before anything is archived", archived, err)
+	}
+
+	history, err := b.Transactions.FindTransactions(ctx, model.HistoryFilter{WalletID: bob, Limit: 10})
+	if err != nil {
+		t.Fatalf("FindTransactions: %v", err)
+	}
+	reasons := make([]string, len(history))
+	for n, transaction := range history {
+		reasons[n] = transaction.Reason
+	}
+	if fmt.Sprint(reasons) != fmt.Sprint([]string{"refund", "", "grant"}) {
+		t.Errorf("reasons = %q, want refund, none, grant", reasons)
+	}
+}
+
+func testTransferVelocity(t *testing.T, b Backend) {
+	ctx := context.Background()
+	alice, bob, carol := uuid.NewString(), uuid.NewString(), uuid.NewString()
+
+	for _, transaction := range []*model.Transaction{
+		{ID: uuid.New(), From: alice, To: bob, Amount: 100},
+		{ID: uuid.New(), From: alice, To: bob, Amount: 250},
+		{ID: uuid.New(), From: bob, To: carol, Amount: 40},
+	} {
+		if _, err := b.Transactions.Create(ctx, transaction); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	velocity, err := b.Transactions.Velocity(ctx, alice, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Velocity: %v", err)
+	}
+	if want := (model.TransferVelocity{Count: 2, Amount: 350}); velocity != want {
+		t.Errorf("Velocity(alice, last hour) = %+v, want %+v", velocity, want)
+	}
+	if velocity, err := b.Transactions.Velocity(ctx, alice, time.Now().Add(time.Hour)); err != nil || velocity != (model.TransferVelocity{}) {
+		t.Errorf("Velocity(alice, future) = %+v, %v, want zero", velocity, err)
+	}
+	if velocity, err := b.Transactions.Velocity(ctx, carol, time.Time{}); err != nil || velocity != (model.TransferVelocity{}) {
+		t.Errorf("Velocity(receiver only) = %+v, %v, want zero", velocity, err)
+	}
+
+	for _, tt := range []struct {
+		from, to string
+		want     bool
+	}{
+		{alice, bob, true},
+		{bob, alice, false},
+		{bob, carol, true},
+		{alice, carol, false},
+	} {
+		if got, err := b.Transactions.HasTransferred(ctx, tt.from, tt.to); err != nil || got != tt.want {
+			t.Errorf("HasTransferred(%s, %s) = %v, %v, want %v", tt.from, tt.to, got, err, tt.want)
+		}
+	}
+}
+
+// testConcurrentVelocityLimit sends from one wallet in parallel the way the wallet
+// service does: read the sender, then its velocity, then update it version
+// checked, retrying on a conflict. However the transfers interleave, each one that
+// commits must have counted every transfer committed before it, so none goes over
+// the limit.
+func testConcurrentVelocityLimit(t *testing.T, b Backend) {
+	ctx := context.Background()
+	sender, receiver := createWallet(t, b), uuid.NewString()
+	err := b.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		wallet, err := b.Wallets.FetchByID(ctx, sender)
+		if err != nil {
+			return err
+		}
+		wallet.Amount = 10_000
+		_, err = b.Wallets.Update(ctx, wallet)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("fund sender: %v", err)
+	}
+
+	const limit, amount, senders = 500, 100, 10
+	errLimit := errors.New("limit exceeded")
+	since := time.Now().Add(-time.Hour)
+	send := func() error {
+		return b.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			wallet, err := b.Wallets.FetchByID(ctx, sender)
+			if err != nil {
+				return err
+			}
+			velocity, err := b.Transactions.Velocity(ctx, sender.String(), since)
+			if err != nil {
+				return err
+			}
+			if velocity.Amount+amount > limit {
+				return errLimit
+			}
+			wallet.Amount -= amount
+			if _, err := b.Wallets.Update(ctx, wallet); err != nil {
+				return err
+			}
+			_, err = b.Transactions.Create(ctx, &model.Transaction{ID: uuid.New(), From: sender.String(), To: receiver, Amount: amount})
+			return err
+		})
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan error, senders)
+	for range senders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if err := send(); !errors.Is(err, model.ErrConflict) {
+					results <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	var committed int
+	for err := range results {
+		switch {
+		case err == nil:
+			committed++
+		case !errors.Is(err, errLimit):
+			t.Errorf("transfer: %v", err)
+		}
+	}
+	if committed != limit/amount {
+		t.Errorf("%d transfers committed, want %d", committed, limit/amount)
+	}
+	velocity, err := b.Transactions.Velocity(ctx, sender.String(), since)
+	if err != nil {
+		t.Fatalf("Velocity: %v", err)
+	}
+	if velocity.Amount > limit {
+		t.Errorf("sent %d cents, over the limit of %d", velocity.Amount, limit)
+	}
+	if got := fetchWallet(t, b, sender); got.Amount != 10_000-int(velocity.Amount) {
+		t.Errorf("sender balance = %d, want %d", got.Amount, 10_000-velocity.Amount)
+	}
+}
+
+func testTransactionCommit(t *testing.T, b Backend) {
+	ctx := context.Background()
+	id := createWallet(t, b)
+	transaction := &model.Transaction{ID: uuid.New(), From: uuid.NewString(), To: id.String(), Amount: 40}
+
+	err := b.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		wallet, err := b.Wallets.FetchByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		wallet.Amount += 40
+		if _, err := b.Wallets.Update(ctx, wallet); err != nil {
+			return err
+		}
+		// Reads in the transaction see its own writes.
+		if got, err := b.Wallets.FetchByID(ctx, id); err != nil || got.Amount != 40 {
+			return fmt.Errorf("read in transaction = %+v, %v, want amount 40", got, err)
+		}
+		_, err = b.Transactions.Create(ctx, transaction)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithinTransaction: %v", err)
+	}
+
+	if got := fetchWallet(t, b, id); got.Amount != 40 || got.Version != 1 {
+		t.Errorf("committed wallet = %+v, want amount 40 at version 1", got)
+	}
+	if n := countTransactions(t, b); n != 1 {
+		t.Errorf("%d transactions after commit, want 1", n)
+	}
+}
+
+func testTransactionRollback(t *testing.T, b Backend) {
+	ctx := context.Background()
+	id := createWallet(t, b)
+	errAbort := errors.New("abort")
+
+	var created uuid.UUID
+	err := b.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		wallet, err := b.Wallets.FetchByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		wallet.Amount = 100
+		if _, err := b.Wallets.Update(ctx, wallet); err != nil {
+			return err
+		}
+		if created, err = b.Wallets.Create(ctx); err != nil {
+			return err
+		}
+		if err := b.Wallets.SetServiceInitialized(ctx); err != nil {
+			return err
+		}
+		transaction := &model.Transaction{ID: uuid.New(), From: id.String(), To: created.String(), Amount: 100}
+		if _, err := b.Transactions.Create(ctx, transaction); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithinTransaction error = %v, want %v", err, errAbort)
+	}
+
+	if got := fetchWallet(t, b, id); got.Amount != 0 || got.Version != 0 {
+		t.Errorf("rolled back wallet = %+v, want amount 0 at version 0", got)
+	}
+	if _, err := b.Wallets.FetchByID(ctx, created); !errors.Is(err, model.ErrWalletNotFound) {
+		t.Errorf("wallet created in rolled back transaction: FetchByID error = %v, want %v", err, model.ErrWalletNotFound)
+	}
+	if initialized, err := b.Wallets.IsServiceInitialized(ctx); err != nil || initialized {
+		t.Errorf("IsServiceInitialized after rollback = %v, %v, want false", initialized, err)
+	}
+	if n := countTransactions(t, b); n != 0 {
+		t.Errorf("%d transactions after rollback, want 0", n)
+	}
+}
+
+func testTransactionIsolation(t *testing.T, b Backend) {
+	ctx := context.Background()
+	id := createWallet(t, b)
+
+	written := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			wallet, err := b.Wallets.FetchByID(ctx, id)
+			if err != nil {
+				close(written)
+				return err
+			}
+			wallet.Amount = 1
+			_, err = b.Wallets.Update(ctx, wallet)
+			close(written)
+			<-release
+			return err
+		})
+	}()
+
+	<-written
+	readCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	wallet, err := b.Wallets.FetchByID(readCtx, id)
+	close(release)
+	if err != nil {
+		t.Fatalf("FetchByID during open transaction: %v", err)
+	}
+	if wallet.Amount != 0 {
+		t.Errorf("uncommitted balance visible outside the transaction: %d", wallet.Amount)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("WithinTransaction: %v", err)
+	}
+	if got := fetchWallet(t, b, id); got.Amount != 1 {
+		t.Errorf("balance after commit = %d, want 1", got.Amount)
+	}
+}
+
+func testNestedTransaction(t *testing.T, b Backend) {
+	ctx := context.Background()
+	id := createWallet(t, b)
+	errAbort := errors.New("abort")
+
+	err := b.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := b.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			wallet, err := b.Wallets.FetchByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			wallet.Amount = 5
+			_, err = b.Wallets.Update(ctx, wallet)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		// The outer transaction sees the inner write and undoes it by failing.
+		if got, err := b.Wallets.FetchByID(ctx, id); err != nil || got.Amount != 5 {
+			return fmt.Errorf("read after nested transaction = %+v, %v, want amount 5", got, err)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithinTransaction error = %v, want %v", err, errAbort)
+	}
+	if got := fetchWallet(t, b, id); got.Amount != 0 {
+		t.Errorf("nested write survived the outer rollback: amount %d", got.Amount)
+	}
+}
+
+func testAPIKeys(t *testing.T, b Backend) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	older := &model.APIKey{
+		ID:         uuid.New(),
+		Name:       "older",
+		SecretHash: []byte("hash-1"),
+		Scopes:     []model.Scope{model.ScopeWalletsRead},
+		CreatedAt:  now.Add(-time.Hour),
+	}
+	newer := &model.APIKey{
+		ID:         uuid.New(),
+		Name:       "newer",
+		SecretHash: []byte("hash-2"),
+		Scopes:     []model.Scope{model.ScopeWalletsRead, model.ScopeTransfersWrite},
+		WalletIDs:  []uuid.UUID{uuid.New()},
+		CreatedAt:  now,
+	}
+	for _, key := range []*model.APIKey{older, newer} {
+		if err := b.APIKeys.Create(ctx, key); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	got, err := b.APIKeys.FetchByID(ctx, newer.ID)
+	if err != nil {
+		t.Fatalf("FetchByID: %v", err)
+	}
+	if got.Name != newer.Name || string(got.SecretHash) != "hash-2" || len(got.Scopes) != 2 ||
+		len(got.WalletIDs) != 1 || got.WalletIDs[0] != newer.WalletIDs[0] || !got.CreatedAt.Equal(newer.CreatedAt) {
+		t.Errorf("FetchByID = %+v, want %+v", got, newer)
+	}
+	if _, err := b.APIKeys.FetchByID(ctx, uuid.New()); !errors.Is(err, model.ErrAPIKeyNotFound) {
+		t.Errorf("FetchByID(unknown) error = %v, want %v", err, model.ErrAPIKeyNotFound)
+	}
+
+	keys, err := b.APIKeys.FetchAll(ctx)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID != newer.ID || keys[1].ID != older.ID {
+		t.Errorf("FetchAll = %+v, want newest first", keys)
+	}
+
+	if err := b.APIKeys.UpdateSecret(ctx, older.ID, []byte("hash-3")); err != nil {
+		t.Fatalf("UpdateSecret: %v", err)
+	}
+	if got, err := b.APIKeys.FetchByID(ctx, older.ID); err != nil || string(got.SecretHash) != "hash-3" || got.RotatedAt == nil {
+		t.Errorf("after UpdateSecret key = %+v, %v, want new hash and rotation time", got, err)
+	}
+
+	if err := b.APIKeys.Revoke(ctx, older.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if got, err := b.APIKeys.FetchByID(ctx, older.ID); err != nil || got.RevokedAt == nil {
+		t.Errorf("revoked key = %+v, %v, want revocation time", got, err)
+	}
+	if err := b.APIKeys.Revoke(ctx, older.ID); !errors.Is(err, model.ErrAPIKeyNotFound) {
+		t.Errorf("Revoke(revoked) error = %v, want %v", err, model.ErrAPIKeyNotFound)
+	}
+	if err := b.APIKeys.UpdateSecret(ctx, older.ID, []byte("hash-4")); !errors.Is(err, model.ErrAPIKeyNotFound) {
+		t.Errorf("UpdateSecret(revoked) error = %v, want %v", err, model.ErrAPIKeyNotFound)
+	}
+}
+
+func testRiskDecisions(t *testing.T, b Backend) {
+	ctx := context.Background()
+	alice, bob := uuid.NewString(), uuid.NewString()
+	start := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+
+	saved := []model.RiskDecision{
+		{ID: uuid.New(), CreatedAt: start, From: alice, To: bob, Amount: 100, Action: model.RiskAllow, Fired: []model.FiredRule{}},
+		{ID: uuid.New(), CreatedAt: start.Add(time.Minute), From: bob, To: alice, Amount: 5000, Action: model.RiskReview,
+			Fired: []model.FiredRule{{Rule: "large", Action: model.RiskReview, Reason: "amount 5000 is at least 5000"}}, RequestID: "req-1"},
+		{ID: uuid.New(), CreatedAt: start.Add(2 * time.Minute), From: alice, To: uuid.NewString(), Amount: 70, Action: model.RiskDeny,
+			Fired: []model.FiredRule{
+				{Rule: "blocked", Action: model.RiskDeny, Reason: "wallet is blocklisted"},
+				{Rule: "new", Action: model.RiskReview, Reason: "first transfer to the wallet"},
+			}},
+	}
+	for _, decision := range saved {
+		if err := b.Risk.SaveDecision(ctx, decision); err != nil {
+			t.Fatalf("SaveDecision: %v", err)
+		}
+	}
+
+	all, err := b.Risk.FindDecisions(ctx, model.RiskDecisionFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("FindDecisions: %v", err)
+	}
+	if len(all) != len(saved) {
+		t.Fatalf("FindDecisions returned %d decisions, want %d", len(all), len(saved))
+	}
+	for n, decision := range all {
+		want := saved[len(saved)-1-n]
+		if decision.ID != want.ID || !decision.CreatedAt.Equal(want.CreatedAt) || decision.From != want.From ||
+			decision.To != want.To || decision.Amount != want.Amount || decision.Action != want.Action ||
+			decision.RequestID != want.RequestID || fmt.Sprint(decision.Fired) != fmt.Sprint(want.Fired) {
+			t.Errorf("decision %d = %+v, want %+v", n, decision, want)
+		}
+	}
+
+	for _, tt := range []struct {
+		name   string
+		filter model.RiskDecisionFilter
+		want   []uuid.UUID
+	}{
+		{"wallet", model.RiskDecisionFilter{WalletID: bob, Limit: 10}, []uuid.UUID{saved[1].ID, saved[0].ID}},
+		{"action", model.RiskDecisionFilter{Action: model.RiskDeny, Limit: 10}, []uuid.UUID{saved[2].ID}},
+		{"period", model.RiskDecisionFilter{Since: start.Add(time.Minute), Until: start.Add(2 * time.Minute), Limit: 10}, []uuid.UUID{saved[1].ID}},
+		{"limit", model.RiskDecisionFilter{WalletID: alice, Limit: 1}, []uuid.UUID{saved[2].ID}},
+	} {
+		decisions, err := b.Risk.FindDecisions(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("FindDecisions(%s): %v", tt.name, err)
+		}
+		ids := make([]uuid.UUID, len(decisions))
+		for n, decision := range decisions {
+			ids[n] = decision.ID
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(tt.want) {
+			t.Errorf("FindDecisions(%s) = %v, want %v", tt.name, ids, tt.want)
+		}
+	}
+}
+
+func testSanctionsCases(t *testing.T, b Backend) {
+	ctx := context.Background()
+	alice, bob := uuid.NewString(), uuid.NewString()
+	start := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+
+	hit := model.ScreeningHit{
+		List: "ofac", Kind: model.WatchName, Entry: "Ivan Petrov", Reference: "SDN-1", Party: model.PartyReceiver,
+		WalletID: bob, Matched: "ivan.petrov", Score: 0.93,
+	}
+	older := model.SanctionsCase{ID: uuid.New(), CreatedAt: start, From: alice, To: bob, Amount: 500,
+		Hits: []model.ScreeningHit{hit}, Status: model.CaseOpen, RequestID: "req-1"}
+	newer := model.SanctionsCase{ID: uuid.New(), CreatedAt: start.Add(time.Minute), From: bob, To: uuid.NewString(), Amount: 70,
+		Hits:   []model.ScreeningHit{{List: "local", Kind: model.WatchWallet, Entry: bob, Party: model.PartySender, WalletID: bob, Matched: bob, Score: 1}},
+		Status: model.CaseOpen}
+	for _, c := range []model.SanctionsCase{older, newer} {
+		if err := b.Sanctions.CreateCase(ctx, c); err != nil {
+			t.Fatalf("CreateCase: %v", err)
+		}
+	}
+
+	fetched, err := b.Sanctions.FetchCase(ctx, older.ID)
+	if err != nil {
+		t.Fatalf("FetchCase: %v", err)
+	}
+	if fetched.ID != older.ID || !fetched.CreatedAt.Equal(older.CreatedAt) || fetched.From != alice || fetched.To != bob ||
+		fetched.Amount != 500 || fetched.Status != model.CaseOpen || fetched.RequestID != "req-1" ||
+		!fetched.ResolvedAt.IsZero() || fmt.Sprint(fetched.Hits) != fmt.Sprint(older.Hits) {
+		t.Errorf("FetchCase = %+v, want %+v", fetched, older)
+	}
+	if _, err := b.Sanctions.FetchCase(ctx, uuid.New()); !errors.Is(err, model.ErrCaseNotFound) {
+		t.Errorf("FetchCase(unknown) error = %v, want %v", err, model.ErrCaseNotFound)
+	}
+
+	resolvedAt := start.Add(2 * time.Minute)
+	if err := b.Sanctions.ResolveCase(ctx, older.ID, model.CaseConfirmed, "genuine match", resolvedAt); err != nil {
+		t.Fatalf("ResolveCase: %v", err)
+	}
+	if err := b.Sanctions.ResolveCase(ctx, older.ID, model.CaseFalsePositive, "", resolvedAt); !errors.Is(err, model.ErrCaseResolved) {
+		t.Errorf("ResolveCase(resolved) error = %v, want %v", err, model.ErrCaseResolved)
+	}
+	if err := b.Sanctions.ResolveCase(ctx, uuid.New(), model.CaseConfirmed, "", resolvedAt); !errors.Is(err, model.ErrCaseNotFound) {
+		t.Errorf("ResolveCase(unknown) error = %v, want %v", err, model.ErrCaseNotFound)
+	}
+	fetched, err = b.Sanctions.FetchCase(ctx, older.ID)
+	if err != nil || fetched.Status != model.CaseConfirmed || fetched.Note != "genuine match" || !fetched.ResolvedAt.Equal(resolvedAt) {
+		t.Errorf("FetchCase(resolved) = %+v, %v, want it confirmed with the note and time", fetched, err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		filter model.CaseFilter
+		want   []uuid.UUID
+	}{
+		{"all", model.CaseFilter{Limit: 10}, []uuid.UUID{newer.ID, older.ID}},
+		{"wallet", model.CaseFilter{WalletID: alice, Limit: 10}, []uuid.UUID{older.ID}},
+		{"status", model.CaseFilter{Status: model.CaseOpen, Limit: 10}, []uuid.UUID{newer.ID}},
+		{"limit", model.CaseFilter{WalletID: bob, Limit: 1}, []uuid.UUID{newer.ID}},
+	} {
+		cases, err := b.Sanctions.FindCases(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("FindCases(%s): %v", tt.name, err)
+		}
+		ids := make([]uuid.UUID, len(cases))
+		for n, c := range cases {
+			ids[n] = c.ID
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(tt.want) {
+			t.Errorf("FindCases(%s) = %v, want %v", tt.name, ids, tt.want)
+		}
+	}
+}
+
+func testSanctionsWhitelist(t *testing.T, b Backend) {
+	ctx := context.Background()
+	alice, bob := uuid.NewString(), uuid.NewString()
+	c := model.SanctionsCase{ID: uuid.New(), CreatedAt: time.Now(), From: alice, To: bob, Amount: 1, Status: model.CaseOpen}
+	if err := b.Sanctions.CreateCase(ctx, c); err != nil {
+		t.Fatalf("CreateCase: %v", err)
+	}
+
+	now := time.Now().Truncate(time.Millisecond)
+	err := b.Sanctions.AddToWhitelist(ctx,
+		model.WhitelistEntry{WalletID: alice, Entry: "Ivan Petrov", CaseID: c.ID, CreatedAt: now},
+		model.WhitelistEntry{WalletID: bob, Entry: "Ivan Petrov", CaseID: c.ID, CreatedAt: now.Add(time.Second)},
+	)
+	if err != nil {
+		t.Fatalf("AddToWhitelist: %v", err)
+	}
+	// Whitelisting a pair again keeps the first entry.
+	err = b.Sanctions.AddToWhitelist(ctx, model.WhitelistEntry{WalletID: alice, Entry: "Ivan Petrov", CaseID: c.ID, CreatedAt: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("AddToWhitelist(again): %v", err)
+	}
+
+	all, err := b.Sanctions.Whitelist(ctx, "")
+	if err != nil {
+		t.Fatalf("Whitelist: %v", err)
+	}
+	if len(all) != 2 || all[0].WalletID != alice || all[1].WalletID != bob || !all[0].CreatedAt.Equal(now) || all[0].CaseID != c.ID {
+		t.Errorf("Whitelist = %+v, want the entries of alice and bob, oldest first", all)
+	}
+	if entries, err := b.Sanctions.Whitelist(ctx, bob); err != nil || len(entries) != 1 || entries[0].Entry != "Ivan Petrov" {
+		t.Errorf("Whitelist(bob) = %+v, %v, want one entry", entries, err)
+	}
+	if entries, err := b.Sanctions.Whitelist(ctx, uuid.NewString()); err != nil || len(entries) != 0 {
+		t.Errorf("Whitelist(other wallet) = %+v, %v, want none", entries, err)
+	}
+
+	// Entries added by hand have no case.
+	byHand := model.WhitelistEntry{WalletID: bob, Entry: "Petr Ivanov", Reason: "known customer", Actor: "admin", CreatedAt: now.Add(time.Hour)}
+	if err := b.Sanctions.AddToWhitelist(ctx, byHand); err != nil {
+		t.Fatalf("AddToWhitelist(by hand): %v", err)
+	}
+	entries, err := b.Sanctions.Whitelist(ctx, bob)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Whitelist(bob) = %+v, %v, want two entries", entries, err)
+	}
+	if got := entries[1]; got.Entry != byHand.Entry || got.CaseID != uuid.Nil || got.Reason != byHand.Reason || got.Actor != byHand.Actor {
+		t.Errorf("entry added by hand = %+v, want %+v", got, byHand)
+	}
+
+	if err := b.Sanctions.RemoveFromWhitelist(ctx, bob, "Ivan Petrov"); err != nil {
+		t.Fatalf("RemoveFromWhitelist: %v", err)
+	}
+	if entries, err := b.Sanctions.Whitelist(ctx, bob); err != nil || len(entries) != 1 || entries[0].Entry != byHand.Entry {
+		t.Errorf("Whitelist(bob) after removal = %+v, %v, want the entry added by hand", entries, err)
+	}
+	if entries, err := b.Sanctions.Whitelist(ctx, alice); err != nil || len(entries) != 1 {
+		t.Errorf("RemoveFromWhitelist removed the entry of another wallet: %+v, %v", entries, err)
+	}
+	if err := b.Sanctions.RemoveFromWhitelist(ctx, bob, "Ivan Petrov"); !errors.Is(err, model.ErrNotWhitelisted) {
+		t.Errorf("RemoveFromWhitelist(again) error = %v, want %v", err, model.ErrNotWhitelisted)
+	}
+}
+
+func testLimits(t *testing.T, b Backend) {
+	ctx := context.Background()
+	limit := func(n int) *int { return &n }
+	now := time.Now().Truncate(time.Millisecond)
+
+	if _, err := b.Limits.FetchTier(ctx, model.DefaultTier); !errors.Is(err, model.ErrTierNotFound) {
+		t.Errorf("FetchTier(unsaved) error = %v, want ErrTierNotFound", err)
+	}
+	premium := model.TierLimits{Name: "premium", Limits: model.Limits{DailyTotal: limit(100000)}, UpdatedAt: now}
+	basic := model.TierLimits{Name: "basic", Limits: model.Limits{MaxTransfer: limit(500), HourlyCount: limit(3)}, UpdatedAt: now}
+	for _, tier := range []model.TierLimits{premium, basic} {
+		if err := b.Limits.SaveTier(ctx, tier); err != nil {
+			t.Fatalf("SaveTier(%s): %v", tier.Name, err)
+		}
+	}
+	// Saving a tier again replaces its limits, unset ones included.
+	basic.Limits = model.Limits{MonthlyTotal: limit(0)}
+	basic.UpdatedAt = now.Add(time.Minute)
+	if err := b.Limits.SaveTier(ctx, basic); err != nil {
+		t.Fatalf("SaveTier(basic again): %v", err)
+	}
+
+	tiers, err := b.Limits.Tiers(ctx)
+	if err != nil {
+		t.Fatalf("Tiers: %v", err)
+	}
+	if len(tiers) != 2 || tiers[0].Name != "basic" || tiers[1].Name != "premium" {
+		t.Fatalf("Tiers = %+v, want basic and premium", tiers)
+	}
+	got := tiers[0]
+	if got.Limits.MaxTransfer != nil || got.Limits.HourlyCount != nil || got.Limits.MonthlyTotal == nil || *got.Limits.MonthlyTotal != 0 ||
+		!got.UpdatedAt.Equal(basic.UpdatedAt) {
+		t.Errorf("basic tier = %+v, want only a zero monthly total", got)
+	}
+	if got, err := b.Limits.FetchTier(ctx, "premium"); err != nil || got.Limits.DailyTotal == nil || *got.Limits.DailyTotal != 100000 {
+		t.Errorf("FetchTier(premium) = %+v, %v", got, err)
+	}
+
+	wallet := createWallet(t, b)
+	if got, err := b.Limits.FetchWalletLimits(ctx, wallet); err != nil || got.WalletID != wallet || got.Tier != "" || got.Limits != (model.Limits{}) {
+		t.Errorf("FetchWalletLimits(unsaved) = %+v, %v, want no tier and no overrides", got, err)
+	}
+	saved := model.WalletLimits{WalletID: wallet, Tier: "premium", Limits: model.Limits{MaxTransfer: limit(2500)}, UpdatedAt: now}
+	if err := b.Limits.SaveWalletLimits(ctx, saved); err != nil {
+		t.Fatalf("SaveWalletLimits: %v", err)
+	}
+	got2, err := b.Limits.FetchWalletLimits(ctx, wallet)
+	if err != nil || got2.Tier != "premium" || got2.Limits.MaxTransfer == nil || *got2.Limits.MaxTransfer != 2500 || got2.Limits.DailyTotal != nil {
+		t.Errorf("FetchWalletLimits = %+v, %v, want premium with a max transfer of 2500", got2, err)
+	}
+	saved = model.WalletLimits{WalletID: wallet, UpdatedAt: now.Add(time.Minute)}
+	if err := b.Limits.SaveWalletLimits(ctx, saved); err != nil {
+		t.Fatalf("SaveWalletLimits(again): %v", err)
+	}
+	if got, err := b.Limits.FetchWalletLimits(ctx, wallet); err != nil || got.Tier != "" || got.Limits != (model.Limits{}) {
+		t.Errorf("FetchWalletLimits after clearing = %+v, %v, want no tier and no overrides", got, err)
+	}
+}
+
+func testWalletStatus(t *testing.T, b Backend) {
+	ctx := context.Background()
+	id, other := createWallet(t, b), createWallet(t, b)
+	now := time.Now().Truncate(time.Millisecond)
+
+	wallet := fetchWallet(t, b, id)
+	wallet.Status = model.WalletFrozen
+	if _, err := b.Wallets.Update(ctx, wallet); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := fetchWallet(t, b, id); got.Status != model.WalletFrozen || got.Version != 1 {
+		t.Errorf("after Update wallet = %+v, want frozen at version 1", got)
+	}
+	wallets, err := b.Wallets.FetchAll(ctx)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	for _, w := range wallets {
+		if want := map[uuid.UUID]model.WalletStatus{id: model.WalletFrozen, other: model.WalletActive}[w.ID]; w.Status != want {
+			t.Errorf("FetchAll lists wallet %s as %q, want %q", w.ID, w.Status, want)
+		}
+	}
+
+	changes := []model.StatusChange{
+		{ID: uuid.New(), WalletID: id, From: model.WalletActive, To: model.WalletFrozen, Reason: "court order",
+			Actor: "admin", RequestID: "req-1", CreatedAt: now.Add(-time.Minute)},
+		{ID: uuid.New(), WalletID: id, From: model.WalletFrozen, To: model.WalletActive, Reason: "order lifted",
+			CreatedAt: now},
+		{ID: uuid.New(), WalletID: other, From: model.WalletActive, To: model.WalletClosed, Reason: "customer request",
+			CreatedAt: now},
+	}
+	for _, change := range changes {
+		if err := b.Statuses.SaveChange(ctx, change); err != nil {
+			t.Fatalf("SaveChange: %v", err)
+		}
+	}
+
+	got, err := b.Statuses.FetchChanges(ctx, id)
+	if err != nil {
+		t.Fatalf("FetchChanges: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("FetchChanges = %+v, want the two changes of wallet %s", got, id)
+	}
+	for n, want := range changes[:2] {
+		if !got[n].CreatedAt.Equal(want.CreatedAt) {
+			t.Errorf("change %d created at %s, want %s", n, got[n].CreatedAt, want.CreatedAt)
+		}
+		got[n].CreatedAt = want.CreatedAt
+		if got[n] != want {
+			t.Errorf("change %d = %+v, want %+v", n, got[n], want)
+		}
+	}
+	if got, err := b.Statuses.FetchChanges(ctx, uuid.New()); err != nil || len(got) != 0 {
+		t.Errorf("FetchChanges(no changes) = %+v, %v, want none", got, err)
+	}
+}
+
+func createWallet(t *testing.T, b Backend) uuid.UUID {
+	t.Helper()
+	id, err := b.Wallets.Create(context.Background())
+	if err != nil {
+		t.Fatalf("Create wallet: %v", err)
+	}
+	return id
+}
+
+func fetchWallet(t *testing.T, b Backend, id uuid.UUID) *model.Wallet {
+	t.Helper()
+	wallet, err := b.Wallets.FetchByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("FetchByID(%s): %v", id, err)
+	}
+	return wallet
+}
+
+func countTransactions(t *testing.T, b Backend) int {
+	t.Helper()
+	transactions, err := b.Transactions.GetTransactionsAfter(context.Background(), 0, model.TransactionFilter{}, 1000)
+	if err != nil {
+		t.Fatalf("GetTransactionsAfter: %v", err)
+	}
+	return len(transactions)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"transaction-service/internal/domain/model"
+)
+
+// RiskRepository keeps the log of risk decisions made on transfers.
+type RiskRepository interface {
+	// SaveDecision stores a decision.
+	SaveDecision(ctx context.Context, decision model.RiskDecision) error
+
+	// FindDecisions retrieves up to filter.Limit decisions matching the filter,
+	// newest first.
+	FindDecisions(ctx context.Context, filter model.RiskDecisionFilter) ([]model.RiskDecision, error)
+}
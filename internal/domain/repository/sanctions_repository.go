@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"transaction-service/internal/domain/model"
+
+	"github.com/google/uuid"
+)
+
+// SanctionsRepository keeps the sanctions cases and the whitelist of false
+// positives.
+type SanctionsRepository interface {
+	// CreateCase stores a new case.
+	CreateCase(ctx context.Context, c model.SanctionsCase) error
+
+	// FetchCase returns a case by its ID, or model.ErrCaseNotFound.
+	FetchCase(ctx context.Context, id uuid.UUID) (model.SanctionsCase, error)
+
+	// ResolveCase closes an open case with the given status and note. It fails
+	// with model.ErrCaseNotFound, or model.ErrCaseResolved when the case is no
+	// longer open.
+	ResolveCase(ctx context.Context, id uuid.UUID, status model.CaseStatus, note string, resolvedAt time.Time) error
+
+	// FindCases retrieves up to filter.Limit cases matching the filter, newest first.
+	FindCases(ctx context.Context, filter model.CaseFilter) ([]model.SanctionsCase, error)
+
+	// AddToWhitelist stores whitelist entries. Pairs of wallet and entry already
+	// whitelisted are kept as they are.
+	AddToWhitelist(ctx context.Context, entries ...model.WhitelistEntry) error
+
+	// RemoveFromWhitelist deletes the whitelist entry of a wallet, or fails with
+	// model.ErrNotWhitelisted.
+	RemoveFromWhitelist(ctx context.Context, walletID, entry string) error
+
+	// Whitelist returns the whitelist entries of a wallet, or of every wallet when
+	// walletID is empty, oldest first.
+	Whitelist(ctx context.Context, walletID string) ([]model.WhitelistEntry, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"transaction-service/internal/domain/model"
+
+	"github.com/google/uuid"
+)
+
+// StatusRepository keeps the history of wallet status changes.
+type StatusRepository interface {
+	// SaveChange records a status change.
+	SaveChange(ctx context.Context, change model.StatusChange) error
+
+	// FetchChanges returns the status changes of a wallet, oldest first.
+	FetchChanges(ctx context.Context, walletID uuid.UUID) ([]model.StatusChange, error)
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"transaction-service/internal/domain/model"
+)
+
+// TransactionArchive holds the transactions of past months, moved out of the
+// transaction log by the retention policy.
+type TransactionArchive interface {
+	// Create starts the archive of the month beginning at month. Once committed it
+	// replaces an earlier archive of the same month.
+	Create(month time.Time) (ArchiveWriter, error)
+
+	// Months returns the first day of every archived month, oldest first.
+	Months(ctx context.Context) ([]time.Time, error)
+
+	// FindTransactions retrieves up to filter.Limit archived transactions matching
+	// the filter, newest first.
+	FindTransactions(ctx context.Context, filter model.HistoryFilter) ([]model.Transaction, error)
+}
+
+// ArchiveWriter writes the archive of one month, in log order.
+type ArchiveWriter interface {
+	// Write appends a transaction to the archive.
+	Write(transaction model.Transaction) error
+
+	// Commit makes the archive readable.
+	Commit() error
+
+	// Abort discards the archive. It does nothing after Commit.
+	Abort() error
+}
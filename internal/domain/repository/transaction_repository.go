@@ -4,14 +4,39 @@ package repository
 import (
 	"context"
 	"github.com/google/uuid"
+	"time"
 	"transaction-service/internal/domain/model"
 )
 
 // TransactionRepository defines methods for managing transactions in the database.
 type TransactionRepository interface {
-	// Create adds a new transaction to the database.
+	// Create adds a new transaction to the database, linking it into the hash chain.
+	// It sets the Seq, CreatedAt and chain hashes of the transaction as stored.
 	Create(ctx context.Context, transaction *model.Transaction) (uuid.UUID, error)
 
-	// GetTransactions retrieves a list of transactions from the database.
-	GetTransactions(ctx context.Context) ([]model.Transaction, error)
+	// GetTransactions retrieves the 100 most recent transactions, newest first. When
+	// walletIDs is not nil, only transactions sent from or to one of them count.
+	GetTransactions(ctx context.Context, walletIDs []string) ([]model.Transaction, error)
+
+	// GetTransactionsAfter retrieves up to limit transactions with a sequence number
+	// greater than seq, oldest first.
+	GetTransactionsAfter(ctx context.Context, seq int64, filter model.TransactionFilter, limit int) ([]model.Transaction, error)
+
+	// FindTransactions retrieves up to filter.Limit transactions matching the
+	// filter, newest first.
+	FindTransactions(ctx context.Context, filter model.HistoryFilter) ([]model.Transaction, error)
+
+	// Totals sums the transfers from and to a wallet, archived ones included.
+	Totals(ctx context.Context, walletID string) (model.TransferTotals, error)
+
+	// Velocity counts and sums the transfers sent from a wallet at or after since.
+	Velocity(ctx context.Context, walletID string, since time.Time) (model.TransferVelocity, error)
+
+	// HasTransferred reports whether the log or an archived month holds a transfer
+	// from one wallet to the other.
+	HasTransferred(ctx context.Context, fromID, toID string) (bool, error)
+
+	// ArchivedTotals sums, by wallet ID, the transfers moved out of the log into
+	// the archive by the retention policy.
+	ArchivedTotals(ctx context.Context) (map[string]model.TransferTotals, error)
 }
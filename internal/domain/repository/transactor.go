@@ -0,0 +1,28 @@
+package repository
+
+import "context"
+
+// Transactor groups repository calls into one storage transaction.
+type Transactor interface {
+	// WithinTransaction runs fn in a transaction carried by the context passed to
+	// fn; repository calls made with that context take part in it. The transaction
+	// commits when fn returns nil and rolls back otherwise. Calls nested in an open
+	// transaction join it.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type snapshotKey struct{}
+
+// WithSnapshot returns a copy of ctx whose transactions read one snapshot of the
+// database throughout, rather than each statement seeing whatever committed before
+// it started. It applies to transactions begun with ctx, not to ones they join.
+// Stores that run one transaction at a time give every transaction a snapshot.
+func WithSnapshot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, snapshotKey{}, true)
+}
+
+// SnapshotFromContext reports whether ctx asks for snapshot transactions.
+func SnapshotFromContext(ctx context.Context) bool {
+	snapshot, _ := ctx.Value(snapshotKey{}).(bool)
+	return snapshot
+}
@@ -4,7 +4,6 @@ package repository
 import (
 	"context"
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"transaction-service/internal/domain/model"
 )
 
@@ -13,18 +12,22 @@ type WalletRepository interface {
 	// FetchByID retrieves a wallet by its unique ID.
 	FetchByID(ctx context.Context, id uuid.UUID) (*model.Wallet, error)
 
-	// Create adds a new wallet to the database and returns its ID.
+	// Create adds a new active wallet with a zero balance to the database and
+	// returns its ID.
 	Create(ctx context.Context) (uuid.UUID, error)
 
-	// Update modifies the details of an existing wallet.
+	// CreateSystem adds a system wallet with the given ID and a zero balance, unless
+	// it exists. System wallets are left out of FetchAll, FetchByOwner and Summarize.
+	CreateSystem(ctx context.Context, id uuid.UUID) error
+
+	// Update stores the balance and status of a wallet if its version is unchanged
+	// since it was fetched, and increments the version. A changed version is
+	// model.ErrConflict.
 	Update(ctx context.Context, wallet *model.Wallet) (*model.Wallet, error)
 
 	// Delete removes a wallet from the database by its ID.
 	Delete(ctx context.Context, id uuid.UUID) error
 
-	// BeginTransaction starts a new database transaction.
-	BeginTransaction() (*sqlx.Tx, error)
-
 	// IsServiceInitialized shows if there are 10 records in the database.
 	IsServiceInitialized(ctx context.Context) (bool, error)
 
@@ -33,4 +36,14 @@ type WalletRepository interface {
 
 	// FetchAll returns all records from the database
 	FetchAll(ctx context.Context) ([]*model.Wallet, error)
+
+	// FetchByOwner returns the wallets owned by the given subject.
+	FetchByOwner(ctx context.Context, ownerID string) ([]*model.Wallet, error)
+
+	// SetOwner assigns a wallet to an owner and records the owner's legal name;
+	// empty values clear them.
+	SetOwner(ctx context.Context, id uuid.UUID, ownerID, ownerName string) error
+
+	// Summarize returns the number of wallets and the sum of their balances.
+	Summarize(ctx context.Context) (model.WalletSummary, error)
 }
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyPrefix marks strings issued by this service as API keys.
+const apiKeyPrefix = "tsk"
+
+// APIKeyService defines methods for issuing API keys and authenticating with them.
+type APIKeyService interface {
+	// Issue creates a key with the given scopes, optionally bound to wallets, and
+	// returns it together with the plaintext key. The plaintext is never stored.
+	Issue(ctx context.Context, name string, scopes []model.Scope, walletIDs []uuid.UUID) (*model.APIKey, string, error)
+
+	// Rotate replaces the secret of an active key and returns the new plaintext key.
+	Rotate(ctx context.Context, id uuid.UUID) (string, error)
+
+	// Revoke disables a key permanently.
+	Revoke(ctx context.Context, id uuid.UUID) error
+
+	// FetchAll returns every key, including revoked ones.
+	FetchAll(ctx context.Context) ([]*model.APIKey, error)
+
+	// Authenticate resolves a plaintext key to the principal it acts as.
+	Authenticate(ctx context.Context, key string) (*model.Principal, error)
+}
+
+type apiKeyService struct {
+	repo repository.APIKeyRepository
+}
+
+// NewAPIKeyService creates a new instance of APIKeyService.
+func NewAPIKeyService(repo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{repo: repo}
+}
+
+func (s *apiKeyService) Issue(ctx context.Context, name string, scopes []model.Scope, walletIDs []uuid.UUID) (*model.APIKey, string, error) {
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: an api key needs a name", model.ErrNameRequired)
+	}
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", model.ErrInvalidScope)
+	}
+	for _, scope := range scopes {
+		if _, ok := model.ParseScope(string(scope)); !ok {
+			return nil, "", fmt.Errorf("%w: %q", model.ErrInvalidScope, scope)
+		}
+	}
+
+	id := uuid.New()
+	plaintext, hash, err := newSecret(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &model.APIKey{
+		ID:         id,
+		Name:       name,
+		SecretHash: hash,
+		Scopes:     scopes,
+		WalletIDs:  walletIDs,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, "", fmt.Errorf("failed to store api key: %w", err)
+	}
+	slog.InfoContext(ctx, "api key issued", "api_key_id", id, "name", name, "scopes", scopes)
+	return key, plaintext, nil
+}
+
+func (s *apiKeyService) Rotate(ctx context.Context, id uuid.UUID) (string, error) {
+	plaintext, hash, err := newSecret(id)
+	if err != nil {
+		return "", err
+	}
+	if err := s.repo.UpdateSecret(ctx, id, hash); err != nil {
+		return "", fmt.Errorf("failed to rotate api key: %w", err)
+	}
+	slog.InfoContext(ctx, "api key rotated", "api_key_id", id)
+	return plaintext, nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.Revoke(ctx, id); err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	slog.InfoContext(ctx, "api key revoked", "api_key_id", id)
+	return nil
+}
+
+func (s *apiKeyService) FetchAll(ctx context.Context) ([]*model.APIKey, error) {
+	keys, err := s.repo.FetchAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch api keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, plaintext string) (_ *model.Principal, err error) {
+	ctx, span := tracer.Start(ctx, "APIKeyService.Authenticate")
+	defer func() { endSpan(span, err) }()
+
+	id, secret, ok := parseKey(plaintext)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed api key", model.ErrUnauthenticated)
+	}
+
+	key, err := s.repo.FetchByID(ctx, id)
+	if errors.Is(err, model.ErrAPIKeyNotFound) {
+		return nil, fmt.Errorf("%w: unknown api key", model.ErrUnauthenticated)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch api key: %w", err)
+	}
+
+	hash := sha256.Sum256(secret)
+	if subtle.ConstantTimeCompare(hash[:], key.SecretHash) != 1 {
+		return nil, fmt.Errorf("%w: unknown api key", model.ErrUnauthenticated)
+	}
+	if key.RevokedAt != nil {
+		return nil, fmt.Errorf("%w: api key revoked", model.ErrUnauthenticated)
+	}
+
+	return key.Principal(), nil
+}
+
+// newSecret generates a random secret for the key and returns the plaintext key
+// handed to the client together with the hash that is stored.
+func newSecret(id uuid.UUID) (string, []byte, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("failed to generate api key secret: %w", err)
+	}
+	hash := sha256.Sum256(secret)
+
+	plaintext := strings.Join([]string{
+		apiKeyPrefix,
+		hex.EncodeToString(id[:]),
+		base64.RawURLEncoding.EncodeToString(secret),
+	}, "_")
+	return plaintext, hash[:], nil
+}
+
+// parseKey splits a plaintext key of the form tsk_<id>_<secret>.
+func parseKey(plaintext string) (uuid.UUID, []byte, bool) {
+	prefix, rest, ok := strings.Cut(plaintext, "_")
+	if !ok || prefix != apiKeyPrefix {
+		return uuid.Nil, nil, false
+	}
+	rawID, rawSecret, ok := strings.Cut(rest, "_")
+	if !ok {
+		return uuid.Nil, nil, false
+	}
+
+	idBytes, err := hex.DecodeString(rawID)
+	if err != nil {
+		return uuid.Nil, nil, false
+	}
+	id, err := uuid.FromBytes(idBytes)
+	if err != nil {
+		return uuid.Nil, nil, false
+	}
+
+	secret, err := base64.RawURLEncoding.DecodeString(rawSecret)
+	if err != nil {
+		return uuid.Nil, nil, false
+	}
+	return id, secret, true
+}
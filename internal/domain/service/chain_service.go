@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+)
+
+const (
+	// chainBatchSize limits how many transactions are read per query while verifying.
+	chainBatchSize = 500
+
+	// chainCheckpointsVerified is how many of the newest checkpoints a verification
+	// compares the chain with.
+	chainCheckpointsVerified = 10000
+)
+
+// ChainService verifies the hash chain of the transaction log and signs
+// checkpoints of it.
+type ChainService interface {
+	// Verify walks the chain from its first link to the head and reports the first
+	// link that is broken: a transaction edited, removed or inserted after the fact.
+	Verify(ctx context.Context) (model.ChainVerification, error)
+
+	// Checkpoint signs and stores the current head of the chain. When the head has
+	// not moved since the last checkpoint, that checkpoint is returned instead.
+	Checkpoint(ctx context.Context) (model.Checkpoint, error)
+
+	// Checkpoints returns up to limit checkpoints, newest first.
+	Checkpoints(ctx context.Context, limit int) ([]model.Checkpoint, error)
+}
+
+// Signer signs checkpoints and receipts.
+type Signer interface {
+	// KeyID identifies the key signatures are made with, so they can be verified.
+	KeyID() string
+
+	// Sign returns the Ed25519 signature of message.
+	Sign(message []byte) ([]byte, error)
+}
+
+// Keyring holds the signing keys of the service. The current key may be rotated
+// while the service runs; retired keys are still published, so the signatures
+// they made can be verified.
+type Keyring interface {
+	// Current returns the key new signatures are made with.
+	Current() Signer
+
+	// PublicKeys returns every key signatures are verified with, current first.
+	PublicKeys() []model.PublicKey
+}
+
+type chainService struct {
+	chain        repository.ChainRepository
+	transactions repository.TransactionRepository
+	keys         Keyring // nil when no signing key is configured
+}
+
+// NewChainService creates a ChainService. Without keys, checkpoints cannot be made
+// but the chain can still be verified.
+func NewChainService(chain repository.ChainRepository, transactions repository.TransactionRepository, keys Keyring) ChainService {
+	return &chainService{chain: chain, transactions: transactions, keys: keys}
+}
+
+// Verify reads the head before the log, so transactions committed during the walk
+// are past the head and left out. Transactions created before the chain began are
+// counted but not verified. Once months are archived, the log must link to the
+// last hash archived, and the links walked and archived must add up to the count
+// of the head, so removing transactions from either end is noticed.
+func (c *chainService) Verify(ctx context.Context) (_ model.ChainVerification, err error) {
+	ctx, span := tracer.Start(ctx, "ChainService.Verify")
+	defer func() { endSpan(span, err) }()
+
+	// A replica may lag behind the head read from the primary.
+	ctx = model.WithConsistentRead(ctx)
+
+	head, err := c.chain.Head(ctx)
+	if err != nil {
+		return model.ChainVerification{}, err
+	}
+	archived, err := c.chain.ArchivedHead(ctx)
+	if err != nil {
+		return model.ChainVerification{}, err
+	}
+	checkpoints, err := c.chain.Checkpoints(ctx, chainCheckpointsVerified)
+	if err != nil {
+		return model.ChainVerification{}, err
+	}
+	checkpointHashes := make(map[int64]string, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		checkpointHashes[checkpoint.Seq] = checkpoint.Hash
+	}
+
+	report := model.ChainVerification{Head: head}
+	var (
+		seq     int64
+		prev    = archived.Hash
+		started bool
+	)
+walk:
+	for seq < head.Seq {
+		batch, err := c.transactions.GetTransactionsAfter(ctx, seq, model.TransactionFilter{}, chainBatchSize)
+		if err != nil {
+			return model.ChainVerification{}, fmt.Errorf("failed to read transaction log: %w", err)
+		}
+		if len(batch) == 0 {
+			break walk
+		}
+		for _, t := range batch {
+			if t.Seq > head.Seq {
+				break walk
+			}
+			seq = t.Seq
+			if !started {
+				if t.Hash == "" {
+					report.Unchained++
+					continue
+				}
+				started = true
+			}
+
+			if t.PrevHash != prev {
+				report.Broken = brokenLink(t, "links to a different previous transaction", prev, t.PrevHash)
+				return report, nil
+			}
+			if want := model.ChainHash(prev, t); t.Hash != want {
+				report.Broken = brokenLink(t, "content does not match its hash", want, t.Hash)
+				return report, nil
+			}
+			if hash, ok := checkpointHashes[t.Seq]; ok {
+				report.Checkpoints++
+				if t.Hash != hash {
+					report.Broken = brokenLink(t, "hash does not match the signed checkpoint", hash, t.Hash)
+					return report, nil
+				}
+			}
+			prev = t.Hash
+			report.Checked++
+		}
+	}
+
+	if prev != head.Hash {
+		report.Broken = &model.BrokenLink{
+			Seq: head.Seq, Problem: "log ends before the chain head", Expected: head.Hash, Actual: prev,
+		}
+	} else if count := archived.Count + int64(report.Checked); count != head.Count {
+		report.Broken = &model.BrokenLink{
+			Seq: head.Seq, Problem: "chain head counts a different number of transactions",
+			Expected: strconv.FormatInt(head.Count, 10), Actual: strconv.FormatInt(count, 10),
+		}
+	}
+	return report, nil
+}
+
+func brokenLink(t model.Transaction, problem, expected, actual string) *model.BrokenLink {
+	return &model.BrokenLink{Seq: t.Seq, TransactionID: t.ID, Problem: problem, Expected: expected, Actual: actual}
+}
+
+func (c *chainService) Checkpoint(ctx context.Context) (_ model.Checkpoint, err error) {
+	ctx, span := tracer.Start(ctx, "ChainService.Checkpoint")
+	defer func() { endSpan(span, err) }()
+
+	if c.keys == nil {
+		return model.Checkpoint{}, model.ErrSigningDisabled
+	}
+	ctx = model.WithConsistentRead(ctx)
+	head, err := c.chain.Head(ctx)
+	if err != nil {
+		return model.Checkpoint{}, err
+	}
+	latest, err := c.chain.Checkpoints(ctx, 1)
+	if err != nil {
+		return model.Checkpoint{}, err
+	}
+	if len(latest) > 0 && latest[0].Seq == head.Seq {
+		return latest[0], nil
+	}
+
+	signer := c.keys.Current()
+	// Postgres keeps microseconds, so the time signed is the time stored.
+	checkpoint := model.Checkpoint{
+		ChainHead: head,
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+		KeyID:     signer.KeyID(),
+	}
+	if checkpoint.Signature, err = signer.Sign(checkpoint.SignedPayload()); err != nil {
+		return model.Checkpoint{}, fmt.Errorf("failed to sign checkpoint: %w", err)
+	}
+	if err := c.chain.SaveCheckpoint(ctx, checkpoint); err != nil {
+		return model.Checkpoint{}, err
+	}
+	slog.InfoContext(ctx, "chain checkpoint signed", "seq", checkpoint.Seq, "hash", checkpoint.Hash, "key_id", checkpoint.KeyID)
+	return checkpoint, nil
+}
+
+func (c *chainService) Checkpoints(ctx context.Context, limit int) (_ []model.Checkpoint, err error) {
+	ctx, span := tracer.Start(ctx, "ChainService.Checkpoints")
+	defer func() { endSpan(span, err) }()
+
+	return c.chain.Checkpoints(ctx, limit)
+}
+
+// ChainCheckpointer signs a checkpoint of the chain periodically.
+type ChainCheckpointer struct {
+	chain    ChainService
+	interval time.Duration
+}
+
+func NewChainCheckpointer(chain ChainService, interval time.Duration) *ChainCheckpointer {
+	return &ChainCheckpointer{chain: chain, interval: interval}
+}
+
+// Run signs a checkpoint every interval until ctx is done. Failures are logged and
+// retried on the next tick.
+func (c *ChainCheckpointer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if _, err := c.chain.Checkpoint(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to sign chain checkpoint", "error", err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+)
+
+// HealthService decides whether the service can take traffic.
+type HealthService interface {
+	// Ready runs every readiness check. The service is ready when all of them are healthy.
+	Ready(ctx context.Context) (bool, []model.HealthCheck)
+
+	// MarkInitialized records that startup initialization has finished.
+	MarkInitialized()
+
+	// MarkShuttingDown makes the service report not ready, so load balancers stop
+	// routing to it while in-flight requests drain.
+	MarkShuttingDown()
+}
+
+type healthService struct {
+	repo          repository.HealthRepository
+	schemaVersion int64
+
+	initialized  atomic.Bool
+	shuttingDown atomic.Bool
+}
+
+// NewHealthService creates a HealthService expecting the database schema to be at
+// least at schemaVersion.
+func NewHealthService(repo repository.HealthRepository, schemaVersion int64) HealthService {
+	return &healthService{repo: repo, schemaVersion: schemaVersion}
+}
+
+func (h *healthService) Ready(ctx context.Context) (bool, []model.HealthCheck) {
+	checks := []model.HealthCheck{
+		h.checkDatabase(ctx),
+		h.checkMigrations(ctx),
+		check(model.CheckInitialization, h.initialized.Load(), "wallets not initialized yet"),
+		check(model.CheckShutdown, !h.shuttingDown.Load(), "shutting down"),
+	}
+
+	ready := true
+	for _, c := range checks {
+		ready = ready && c.Healthy
+	}
+	return ready, checks
+}
+
+func (h *healthService) MarkInitialized() {
+	h.initialized.Store(true)
+}
+
+func (h *healthService) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+func (h *healthService) checkDatabase(ctx context.Context) model.HealthCheck {
+	if err := h.repo.Ping(ctx); err != nil {
+		return model.HealthCheck{Name: model.CheckDatabase, Detail: err.Error()}
+	}
+	return model.HealthCheck{Name: model.CheckDatabase, Healthy: true}
+}
+
+func (h *healthService) checkMigrations(ctx context.Context) model.HealthCheck {
+	version, err := h.repo.SchemaVersion(ctx)
+	if err != nil {
+		return model.HealthCheck{Name: model.CheckMigrations, Detail: err.Error()}
+	}
+	// A newer schema is fine: it belongs to a newer replica rolling out alongside this one.
+	if version >= h.schemaVersion {
+		return model.HealthCheck{Name: model.CheckMigrations, Healthy: true}
+	}
+	return model.HealthCheck{
+		Name:   model.CheckMigrations,
+		Detail: fmt.Sprintf("schema version %d, expected %d: run migrate up or enable auto_migrate", version, h.schemaVersion),
+	}
+}
+
+func check(name string, healthy bool, detail string) model.HealthCheck {
+	if healthy {
+		detail = ""
+	}
+	return model.HealthCheck{Name: name, Healthy: healthy, Detail: detail}
+}
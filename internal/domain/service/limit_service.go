@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LimitService manages the spending limits of tiers and wallets and enforces them
+// on transfers.
+type LimitService interface {
+	// Enforce fails with model.ErrLimitExceeded when sending amount from the wallet
+	// would go over one of its limits. It must run in the transaction of the
+	// transfer, after the sender was read for its version checked update. Every
+	// transfer from the wallet committed before that read is then counted, even at
+	// READ COMMITTED where each statement takes its own snapshot, and one committed
+	// after it makes the update conflict and the transfer be checked again.
+	Enforce(ctx context.Context, walletID uuid.UUID, amount int) error
+
+	// Allowance returns the limits of a wallet and what it has sent against them.
+	Allowance(ctx context.Context, walletID uuid.UUID) (model.Allowance, error)
+
+	// Tiers returns every tier, by name.
+	Tiers(ctx context.Context) ([]model.TierLimits, error)
+
+	// SetTier creates a tier or replaces its limits.
+	SetTier(ctx context.Context, name string, limits model.Limits) (model.TierLimits, error)
+
+	// WalletLimits returns the tier and overrides of a wallet.
+	WalletLimits(ctx context.Context, walletID uuid.UUID) (model.WalletLimits, error)
+
+	// SetWalletLimits assigns a wallet to a tier, DefaultTier when empty, and
+	// replaces its overrides. The tier must exist unless it is the default one.
+	SetWalletLimits(ctx context.Context, walletID uuid.UUID, tier string, limits model.Limits) (model.WalletLimits, error)
+}
+
+type limitService struct {
+	walletRepo      repository.WalletRepository
+	transactionRepo repository.TransactionRepository
+	limitRepo       repository.LimitRepository
+}
+
+// NewLimitService creates a LimitService.
+func NewLimitService(
+	walletRepo repository.WalletRepository,
+	transactionRepo repository.TransactionRepository,
+	limitRepo repository.LimitRepository,
+) LimitService {
+	return &limitService{walletRepo: walletRepo, transactionRepo: transactionRepo, limitRepo: limitRepo}
+}
+
+func (s *limitService) Enforce(ctx context.Context, walletID uuid.UUID, amount int) (err error) {
+	ctx, span := tracer.Start(ctx, "LimitService.Enforce", trace.WithAttributes(attribute.String("wallet.id", walletID.String())))
+	defer func() { endSpan(span, err) }()
+
+	allowance, err := s.allowance(ctx, walletID, time.Now(), false)
+	if err != nil {
+		return err
+	}
+	return allowance.Check(amount)
+}
+
+func (s *limitService) Allowance(ctx context.Context, walletID uuid.UUID) (_ model.Allowance, err error) {
+	ctx, span := tracer.Start(ctx, "LimitService.Allowance", trace.WithAttributes(attribute.String("wallet.id", walletID.String())))
+	defer func() { endSpan(span, err) }()
+
+	if _, err := s.walletRepo.FetchByID(ctx, walletID); err != nil {
+		return model.Allowance{}, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	return s.allowance(ctx, walletID, time.Now(), true)
+}
+
+// allowance combines the limits of a wallet with what it sent by now. Unless all
+// is set, only the windows the wallet has a limit for are summed.
+func (s *limitService) allowance(ctx context.Context, walletID uuid.UUID, now time.Time, all bool) (model.Allowance, error) {
+	walletLimits, err := s.limitRepo.FetchWalletLimits(ctx, walletID)
+	if err != nil {
+		return model.Allowance{}, fmt.Errorf("failed to fetch wallet limits: %w", err)
+	}
+	allowance := model.Allowance{WalletID: walletID, Tier: walletLimits.TierName(), Limits: walletLimits.Limits}
+	tier, err := s.limitRepo.FetchTier(ctx, allowance.Tier)
+	switch {
+	case err == nil:
+		allowance.Limits = walletLimits.Limits.Over(tier.Limits)
+	case !errors.Is(err, model.ErrTierNotFound) || allowance.Tier != model.DefaultTier:
+		return model.Allowance{}, fmt.Errorf("failed to fetch limit tier: %w", err)
+	}
+
+	now = now.UTC()
+	windows := []struct {
+		limit *int
+		since time.Time
+		used  func(model.TransferVelocity)
+	}{
+		{allowance.Limits.DailyTotal, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+			func(v model.TransferVelocity) { allowance.Used.DailyTotal = int(v.Amount) }},
+		{allowance.Limits.MonthlyTotal, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+			func(v model.TransferVelocity) { allowance.Used.MonthlyTotal = int(v.Amount) }},
+		{allowance.Limits.HourlyCount, now.Add(-time.Hour),
+			func(v model.TransferVelocity) { allowance.Used.HourlyCount = v.Count }},
+	}
+	for _, window := range windows {
+		if window.limit == nil && !all {
+			continue
+		}
+		velocity, err := s.transactionRepo.Velocity(ctx, walletID.String(), window.since)
+		if err != nil {
+			return model.Allowance{}, fmt.Errorf("failed to sum sent transfers: %w", err)
+		}
+		window.used(velocity)
+	}
+	return allowance, nil
+}
+
+func (s *limitService) Tiers(ctx context.Context) (_ []model.TierLimits, err error) {
+	ctx, span := tracer.Start(ctx, "LimitService.Tiers")
+	defer func() { endSpan(span, err) }()
+
+	tiers, err := s.limitRepo.Tiers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch limit tiers: %w", err)
+	}
+	return tiers, nil
+}
+
+func (s *limitService) SetTier(ctx context.Context, name string, limits model.Limits) (_ model.TierLimits, err error) {
+	ctx, span := tracer.Start(ctx, "LimitService.SetTier", trace.WithAttributes(attribute.String("tier", name)))
+	defer func() { endSpan(span, err) }()
+
+	if name == "" {
+		return model.TierLimits{}, fmt.Errorf("%w: tier needs a name", model.ErrInvalidLimits)
+	}
+	if err := limits.Validate(); err != nil {
+		return model.TierLimits{}, err
+	}
+	tier := model.TierLimits{Name: name, Limits: limits, UpdatedAt: time.Now()}
+	if err := s.limitRepo.SaveTier(ctx, tier); err != nil {
+		return model.TierLimits{}, fmt.Errorf("failed to save limit tier: %w", err)
+	}
+	slog.InfoContext(ctx, "limit tier saved", "tier", name)
+	return tier, nil
+}
+
+func (s *limitService) WalletLimits(ctx context.Context, walletID uuid.UUID) (_ model.WalletLimits, err error) {
+	ctx, span := tracer.Start(ctx, "LimitService.WalletLimits", trace.WithAttributes(attribute.String("wallet.id", walletID.String())))
+	defer func() { endSpan(span, err) }()
+
+	if _, err := s.walletRepo.FetchByID(ctx, walletID); err != nil {
+		return model.WalletLimits{}, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	limits, err := s.limitRepo.FetchWalletLimits(ctx, walletID)
+	if err != nil {
+		return model.WalletLimits{}, fmt.Errorf("failed to fetch wallet limits: %w", err)
+	}
+	return limits, nil
+}
+
+func (s *limitService) SetWalletLimits(ctx context.Context, walletID uuid.UUID, tier string, limits model.Limits) (_ model.WalletLimits, err error) {
+	ctx, span := tracer.Start(ctx, "LimitService.SetWalletLimits", trace.WithAttributes(
+		attribute.String("wallet.id", walletID.String()),
+		attribute.String("tier", tier),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if walletID == model.MintWalletID {
+		return model.WalletLimits{}, fmt.Errorf("%w: it has no limits", model.ErrSystemWallet)
+	}
+	if err := limits.Validate(); err != nil {
+		return model.WalletLimits{}, err
+	}
+	if tier == model.DefaultTier {
+		tier = ""
+	}
+	if tier != "" {
+		if _, err := s.limitRepo.FetchTier(ctx, tier); err != nil {
+			return model.WalletLimits{}, fmt.Errorf("failed to fetch limit tier %q: %w", tier, err)
+		}
+	}
+	if _, err := s.walletRepo.FetchByID(ctx, walletID); err != nil {
+		return model.WalletLimits{}, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+
+	walletLimits := model.WalletLimits{WalletID: walletID, Tier: tier, Limits: limits, UpdatedAt: time.Now()}
+	if err := s.limitRepo.SaveWalletLimits(ctx, walletLimits); err != nil {
+		return model.WalletLimits{}, fmt.Errorf("failed to save wallet limits: %w", err)
+	}
+	slog.InfoContext(ctx, "wallet limits saved", "wallet_id", walletID, "tier", walletLimits.TierName())
+	return walletLimits, nil
+}
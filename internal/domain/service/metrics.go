@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+	"time"
+	"transaction-service/internal/domain/model"
+)
+
+// Transfer outcomes reported to Metrics.
+const (
+	OutcomeSuccess           = "success"
+	OutcomeInsufficientFunds = "insufficient_funds"
+	OutcomeNotFound          = "not_found"
+	OutcomeInvalid           = "invalid"
+	OutcomeConflict          = "conflict"
+	OutcomeDenied            = "denied"
+	OutcomeError             = "error"
+)
+
+// Metrics receives measurements from the domain services. Implementations must be
+// safe for concurrent use.
+type Metrics interface {
+	// TransferCompleted counts a SendMoney call by its outcome.
+	TransferCompleted(outcome string)
+
+	// LockWaited records how long a transfer waited for its wallet locks.
+	LockWaited(d time.Duration)
+
+	// TransferRetried counts a transfer attempt repeated after a conflict.
+	TransferRetried()
+}
+
+// NopMetrics returns a Metrics implementation that discards every measurement.
+func NopMetrics() Metrics {
+	return nopMetrics{}
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) TransferCompleted(string) {}
+func (nopMetrics) LockWaited(time.Duration) {}
+func (nopMetrics) TransferRetried()         {}
+
+// TransferOutcome classifies the error returned by SendMoney.
+func TransferOutcome(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, model.ErrInsufficientFunds):
+		return OutcomeInsufficientFunds
+	case errors.Is(err, model.ErrWalletNotFound):
+		return OutcomeNotFound
+	case errors.Is(err, model.ErrInvalidAmount),
+		errors.Is(err, model.ErrInvalidWalletID),
+		errors.Is(err, model.ErrSameWallet):
+		return OutcomeInvalid
+	case errors.Is(err, model.ErrConflict):
+		return OutcomeConflict
+	case errors.Is(err, model.ErrTransferDenied),
+		errors.Is(err, model.ErrSanctionsHit),
+		errors.Is(err, model.ErrLimitExceeded),
+		errors.Is(err, model.ErrWalletStatus):
+		return OutcomeDenied
+	default:
+		return OutcomeError
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ReceiptService signs receipts of transfers and publishes the keys they are
+// verified with.
+type ReceiptService interface {
+	// Issue signs a receipt of a committed transaction.
+	Issue(ctx context.Context, t model.Transaction) (model.Receipt, error)
+
+	// PublicKeys returns the keys receipts and checkpoints are verified with,
+	// current first. It is empty when no signing key is configured.
+	PublicKeys(ctx context.Context) []model.PublicKey
+}
+
+type receiptService struct {
+	keys Keyring // nil when no signing key is configured
+}
+
+// NewReceiptService creates a ReceiptService. Without keys, Issue fails with
+// model.ErrSigningDisabled.
+func NewReceiptService(keys Keyring) ReceiptService {
+	return &receiptService{keys: keys}
+}
+
+func (r *receiptService) Issue(ctx context.Context, t model.Transaction) (_ model.Receipt, err error) {
+	_, span := tracer.Start(ctx, "ReceiptService.Issue", trace.WithAttributes(attribute.String("transaction.id", t.ID.String())))
+	defer func() { endSpan(span, err) }()
+
+	if r.keys == nil {
+		return model.Receipt{}, model.ErrSigningDisabled
+	}
+	signer := r.keys.Current()
+	receipt := model.NewReceipt(t)
+	receipt.IssuedAt = time.Now().UTC()
+	receipt.KeyID = signer.KeyID()
+	if receipt.Signature, err = signer.Sign(receipt.SignedPayload()); err != nil {
+		return model.Receipt{}, fmt.Errorf("failed to sign receipt: %w", err)
+	}
+	return receipt, nil
+}
+
+func (r *receiptService) PublicKeys(context.Context) []model.PublicKey {
+	if r.keys == nil {
+		return nil
+	}
+	return r.keys.PublicKeys()
+}
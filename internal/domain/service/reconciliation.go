@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"transaction-service/internal/domain/model"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// reconcileBatchSize limits how many transactions are read per query.
+	reconcileBatchSize = 500
+
+	// reconcileRechecks is how many times a mismatched wallet is read again before
+	// it is reported.
+	reconcileRechecks = 3
+)
+
+// Reconcile replays the transaction log and compares the balance it adds up to
+// with the stored balance of every wallet, the mint wallet included. Wallets start
+// empty and are funded by the mint, so the log alone accounts for every balance;
+// the replay starts from the totals of the months archived out of the log.
+// The log is read before the balances, so
+// a transfer committed in between is in a balance but not yet in the replay; such
+// wallets are checked again after replaying the rest of the log.
+func (w *walletService) Reconcile(ctx context.Context) (_ model.Reconciliation, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.Reconcile")
+	defer func() { endSpan(span, err) }()
+
+	// Replicas lag by different amounts, so the log and the balances must come from
+	// the primary to be compared.
+	ctx = model.WithConsistentRead(ctx)
+
+	var (
+		report model.Reconciliation
+		seq    int64
+		flows  = make(map[string]int)
+	)
+	archived, err := w.transactionRepo.ArchivedTotals(ctx)
+	if err != nil {
+		return model.Reconciliation{}, fmt.Errorf("failed to read archived totals: %w", err)
+	}
+	for id, totals := range archived {
+		flows[id] = int(totals.Received - totals.Sent)
+	}
+	replay := func() error {
+		for {
+			batch, err := w.transactionRepo.GetTransactionsAfter(ctx, seq, model.TransactionFilter{}, reconcileBatchSize)
+			if err != nil {
+				return fmt.Errorf("failed to read transaction log: %w", err)
+			}
+			for _, t := range batch {
+				flows[t.From] -= t.Amount
+				flows[t.To] += t.Amount
+				seq = t.Seq
+			}
+			report.Transactions += len(batch)
+			if len(batch) < reconcileBatchSize {
+				return nil
+			}
+		}
+	}
+	expected := func(id uuid.UUID) int {
+		return flows[id.String()]
+	}
+
+	if err := replay(); err != nil {
+		return model.Reconciliation{}, err
+	}
+	wallets, err := w.walletRepo.FetchAll(ctx)
+	if err != nil {
+		return model.Reconciliation{}, fmt.Errorf("failed to fetch wallets: %w", err)
+	}
+	mint, err := w.walletRepo.FetchByID(ctx, model.MintWalletID)
+	switch {
+	case err == nil:
+		wallets = append(wallets, mint)
+	case !errors.Is(err, model.ErrWalletNotFound):
+		return model.Reconciliation{}, fmt.Errorf("failed to fetch mint wallet: %w", err)
+	}
+
+	balances := make(map[uuid.UUID]int, len(wallets))
+	var mismatched []uuid.UUID
+	for _, wallet := range wallets {
+		balances[wallet.ID] = wallet.Amount
+		if wallet.Amount != expected(wallet.ID) {
+			mismatched = append(mismatched, wallet.ID)
+		}
+	}
+
+	for recheck := 0; recheck < reconcileRechecks && len(mismatched) > 0; recheck++ {
+		if err := replay(); err != nil {
+			return model.Reconciliation{}, err
+		}
+		still := mismatched[:0]
+		for _, id := range mismatched {
+			wallet, err := w.walletRepo.FetchByID(ctx, id)
+			if errors.Is(err, model.ErrWalletNotFound) {
+				delete(balances, id) // Closed meanwhile
+				continue
+			}
+			if err != nil {
+				return model.Reconciliation{}, fmt.Errorf("failed to fetch wallet: %w", err)
+			}
+			balances[id] = wallet.Amount
+			if wallet.Amount != expected(id) {
+				still = append(still, id)
+			}
+		}
+		mismatched = still
+	}
+
+	report.Wallets = len(balances)
+	for _, balance := range balances {
+		report.TotalAmount += int64(balance)
+	}
+	for _, id := range mismatched {
+		mismatch := model.BalanceMismatch{WalletID: id, Balance: balances[id], Expected: expected(id)}
+		report.Mismatches = append(report.Mismatches, mismatch)
+		slog.WarnContext(ctx, "wallet balance does not match transaction log",
+			"wallet_id", id, "amount_cents", mismatch.Balance, "expected_cents", mismatch.Expected)
+	}
+	return report, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TransferCheck vets a transfer before the wallet service executes it. A check
+// rejects the transfer by returning an error.
+type TransferCheck interface {
+	CheckTransfer(ctx context.Context, fromID, toID uuid.UUID, amount int) error
+}
+
+// RiskRules provides the rules transfers are evaluated against. The rules may
+// change between calls.
+type RiskRules interface {
+	Rules() []model.RiskRule
+}
+
+// RiskService evaluates transfers against the risk rules and keeps the log of its
+// decisions.
+type RiskService interface {
+	// CheckTransfer evaluates a transfer and records the decision. It fails with
+	// model.ErrTransferDenied when a deny rule fires; a transfer flagged for
+	// review goes ahead.
+	TransferCheck
+
+	// Decisions returns the recorded decisions matching the filter, newest first.
+	Decisions(ctx context.Context, filter model.RiskDecisionFilter) ([]model.RiskDecision, error)
+}
+
+type riskService struct {
+	rules           RiskRules // nil when no rules are configured
+	transactionRepo repository.TransactionRepository
+	riskRepo        repository.RiskRepository
+}
+
+// NewRiskService creates a RiskService. Without rules every transfer is allowed.
+func NewRiskService(rules RiskRules, transactionRepo repository.TransactionRepository, riskRepo repository.RiskRepository) RiskService {
+	return &riskService{rules: rules, transactionRepo: transactionRepo, riskRepo: riskRepo}
+}
+
+func (r *riskService) CheckTransfer(ctx context.Context, fromID, toID uuid.UUID, amount int) (err error) {
+	ctx, span := tracer.Start(ctx, "RiskService.CheckTransfer", trace.WithAttributes(
+		attribute.String("wallet.from", fromID.String()),
+		attribute.String("wallet.to", toID.String()),
+	))
+	defer func() { endSpan(span, err) }()
+
+	decision := model.RiskDecision{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		From:      fromID.String(),
+		To:        toID.String(),
+		Amount:    amount,
+		Action:    model.RiskAllow,
+		Fired:     []model.FiredRule{},
+		RequestID: model.RequestIDFromContext(ctx),
+	}
+	if r.rules != nil {
+		// A replica may not have the latest transfers of the wallet yet.
+		evalCtx := model.WithConsistentRead(ctx)
+		for _, rule := range r.rules.Rules() {
+			reason, fired, err := r.evaluate(evalCtx, rule, fromID, toID, amount)
+			if err != nil {
+				return fmt.Errorf("failed to evaluate risk rule %s: %w", rule.Name, err)
+			}
+			if !fired {
+				continue
+			}
+			decision.Fired = append(decision.Fired, model.FiredRule{Rule: rule.Name, Action: rule.Action, Reason: reason})
+			if rule.Action.Severity() > decision.Action.Severity() {
+				decision.Action = rule.Action
+			}
+		}
+	}
+	span.SetAttributes(attribute.String("risk.action", string(decision.Action)))
+
+	// Without its decision on record a rejection could not be explained, so the
+	// transfer does not go ahead either.
+	if err := r.riskRepo.SaveDecision(ctx, decision); err != nil {
+		return fmt.Errorf("failed to record risk decision: %w", err)
+	}
+
+	names := make([]string, len(decision.Fired))
+	for n, fired := range decision.Fired {
+		names[n] = fired.Rule
+	}
+	switch decision.Action {
+	case model.RiskDeny:
+		return fmt.Errorf("%w: decision %s, rules %s", model.ErrTransferDenied, decision.ID, strings.Join(names, ", "))
+	case model.RiskReview:
+		slog.WarnContext(ctx, "transfer flagged for review",
+			"decision_id", decision.ID, "from_wallet", fromID, "to_wallet", toID, "amount_cents", amount, "rules", names)
+	}
+	return nil
+}
+
+// evaluate reports whether rule fires for the transfer, and why.
+func (r *riskService) evaluate(ctx context.Context, rule model.RiskRule, fromID, toID uuid.UUID, amount int) (string, bool, error) {
+	switch rule.Type {
+	case model.RuleAmount:
+		return fmt.Sprintf("amount of %d cents is at least %d", amount, rule.MinAmount), amount >= rule.MinAmount, nil
+	case model.RuleVelocity:
+		velocity, err := r.transactionRepo.Velocity(ctx, fromID.String(), time.Now().Add(-rule.Window))
+		if err != nil {
+			return "", false, err
+		}
+		if rule.MaxCount > 0 && velocity.Count+1 > rule.MaxCount {
+			return fmt.Sprintf("%d transfers within %s exceed %d", velocity.Count+1, rule.Window, rule.MaxCount), true, nil
+		}
+		if total := velocity.Amount + int64(amount); rule.MaxAmount > 0 && total > int64(rule.MaxAmount) {
+			return fmt.Sprintf("%d cents sent within %s exceed %d", total, rule.Window, rule.MaxAmount), true, nil
+		}
+		return "", false, nil
+	case model.RuleNewCounterparty:
+		if amount < rule.MinAmount {
+			return "", false, nil
+		}
+		transferred, err := r.transactionRepo.HasTransferred(ctx, fromID.String(), toID.String())
+		if err != nil {
+			return "", false, err
+		}
+		return "first transfer to the receiver", !transferred, nil
+	case model.RuleBlocklist:
+		switch {
+		case rule.Blocks(fromID):
+			return "sender is blocklisted", true, nil
+		case rule.Blocks(toID):
+			return "receiver is blocklisted", true, nil
+		}
+		return "", false, nil
+	default:
+		return "", false, fmt.Errorf("unknown rule type %q", rule.Type)
+	}
+}
+
+func (r *riskService) Decisions(ctx context.Context, filter model.RiskDecisionFilter) (_ []model.RiskDecision, err error) {
+	ctx, span := tracer.Start(ctx, "RiskService.Decisions")
+	defer func() { endSpan(span, err) }()
+
+	decisions, err := r.riskRepo.FindDecisions(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch risk decisions: %w", err)
+	}
+	return decisions, nil
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Watchlists screens wallets against sanctions lists and blocklists. The lists
+// may change between calls.
+type Watchlists interface {
+	// Screen returns the entries matching the wallet, or its owner when it has
+	// one. The party of the hits is left for the caller to set.
+	Screen(walletID uuid.UUID, owner string) []model.ScreeningHit
+}
+
+// SanctionsService screens the parties of transfers against the watchlists and
+// keeps the cases opened for the transfers it blocks.
+type SanctionsService interface {
+	// CheckTransfer screens both wallets of a transfer and their owners. On a hit
+	// that is not whitelisted it opens a case and fails with model.ErrSanctionsHit.
+	TransferCheck
+
+	// Cases returns the cases matching the filter, newest first.
+	Cases(ctx context.Context, filter model.CaseFilter) ([]model.SanctionsCase, error)
+
+	// Case returns a case by its ID.
+	Case(ctx context.Context, id uuid.UUID) (model.SanctionsCase, error)
+
+	// ResolveCase closes an open case as confirmed or as a false positive, with a
+	// note saying why. A false positive whitelists every wallet of the case for the
+	// entry it matched, so it no longer blocks their transfers.
+	ResolveCase(ctx context.Context, id uuid.UUID, status model.CaseStatus, note string) (model.SanctionsCase, error)
+
+	// AddToWhitelist clears a wallet of a watchlist entry by hand, with a reason
+	// saying why. An entry already whitelisted is kept as it is.
+	AddToWhitelist(ctx context.Context, walletID uuid.UUID, entry, reason string) (model.WhitelistEntry, error)
+
+	// RemoveFromWhitelist screens a wallet for a whitelisted entry again. The
+	// reason is required and logged.
+	RemoveFromWhitelist(ctx context.Context, walletID uuid.UUID, entry, reason string) error
+
+	// Whitelist returns the whitelist entries of a wallet, or of every wallet when
+	// walletID is empty.
+	Whitelist(ctx context.Context, walletID string) ([]model.WhitelistEntry, error)
+}
+
+type sanctionsService struct {
+	lists         Watchlists // nil when no watchlists are configured
+	walletRepo    repository.WalletRepository
+	sanctionsRepo repository.SanctionsRepository
+	transactor    repository.Transactor
+}
+
+// NewSanctionsService creates a SanctionsService. Without lists no transfer is
+// blocked.
+func NewSanctionsService(
+	lists Watchlists,
+	walletRepo repository.WalletRepository,
+	sanctionsRepo repository.SanctionsRepository,
+	transactor repository.Transactor,
+) SanctionsService {
+	return &sanctionsService{lists: lists, walletRepo: walletRepo, sanctionsRepo: sanctionsRepo, transactor: transactor}
+}
+
+func (s *sanctionsService) CheckTransfer(ctx context.Context, fromID, toID uuid.UUID, amount int) (err error) {
+	ctx, span := tracer.Start(ctx, "SanctionsService.CheckTransfer", trace.WithAttributes(
+		attribute.String("wallet.from", fromID.String()),
+		attribute.String("wallet.to", toID.String()),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if s.lists == nil {
+		return nil
+	}
+
+	// A replica may not have the latest owner or whitelist yet.
+	readCtx := model.WithConsistentRead(ctx)
+	var hits []model.ScreeningHit
+	for _, party := range []struct {
+		name string
+		id   uuid.UUID
+	}{{model.PartySender, fromID}, {model.PartyReceiver, toID}} {
+		partyHits, err := s.screen(readCtx, party.id)
+		if err != nil {
+			return err
+		}
+		for _, hit := range partyHits {
+			hit.Party = party.name
+			hits = append(hits, hit)
+		}
+	}
+	if len(hits) == 0 {
+		return nil
+	}
+
+	c := model.SanctionsCase{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		From:      fromID.String(),
+		To:        toID.String(),
+		Amount:    amount,
+		Hits:      hits,
+		Status:    model.CaseOpen,
+		RequestID: model.RequestIDFromContext(ctx),
+	}
+	if err := s.sanctionsRepo.CreateCase(ctx, c); err != nil {
+		return fmt.Errorf("failed to open sanctions case: %w", err)
+	}
+	slog.WarnContext(ctx, "transfer blocked by sanctions screening",
+		"case_id", c.ID, "from_wallet", fromID, "to_wallet", toID, "amount_cents", amount, "hits", len(hits))
+	return fmt.Errorf("%w: case %s", model.ErrSanctionsHit, c.ID)
+}
+
+// screen returns the hits of a wallet and its owner's name that are not
+// whitelisted. An unknown wallet is screened by its ID alone; the transfer fails
+// on it later.
+func (s *sanctionsService) screen(ctx context.Context, id uuid.UUID) ([]model.ScreeningHit, error) {
+	var owner string
+	wallet, err := s.walletRepo.FetchByID(ctx, id)
+	switch {
+	case err == nil:
+		owner = wallet.OwnerName
+	case !errors.Is(err, model.ErrWalletNotFound):
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+
+	hits := s.lists.Screen(id, owner)
+	if len(hits) == 0 {
+		return nil, nil
+	}
+	whitelist, err := s.sanctionsRepo.Whitelist(ctx, id.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch whitelist: %w", err)
+	}
+	cleared := make(map[string]bool, len(whitelist))
+	for _, entry := range whitelist {
+		cleared[entry.Entry] = true
+	}
+
+	var result []model.ScreeningHit
+	for _, hit := range hits {
+		if !cleared[hit.Entry] {
+			result = append(result, hit)
+		}
+	}
+	return result, nil
+}
+
+func (s *sanctionsService) Cases(ctx context.Context, filter model.CaseFilter) (_ []model.SanctionsCase, err error) {
+	ctx, span := tracer.Start(ctx, "SanctionsService.Cases")
+	defer func() { endSpan(span, err) }()
+
+	cases, err := s.sanctionsRepo.FindCases(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sanctions cases: %w", err)
+	}
+	return cases, nil
+}
+
+func (s *sanctionsService) Case(ctx context.Context, id uuid.UUID) (_ model.SanctionsCase, err error) {
+	ctx, span := tracer.Start(ctx, "SanctionsService.Case", trace.WithAttributes(attribute.String("case.id", id.String())))
+	defer func() { endSpan(span, err) }()
+
+	c, err := s.sanctionsRepo.FetchCase(ctx, id)
+	if err != nil {
+		return model.SanctionsCase{}, fmt.Errorf("failed to fetch sanctions case: %w", err)
+	}
+	return c, nil
+}
+
+func (s *sanctionsService) ResolveCase(ctx context.Context, id uuid.UUID, status model.CaseStatus, note string) (_ model.SanctionsCase, err error) {
+	ctx, span := tracer.Start(ctx, "SanctionsService.ResolveCase", trace.WithAttributes(
+		attribute.String("case.id", id.String()),
+		attribute.String("case.status", string(status)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if status != model.CaseConfirmed && status != model.CaseFalsePositive {
+		return model.SanctionsCase{}, fmt.Errorf("%w: %q, want %s or %s",
+			model.ErrInvalidResolution, status, model.CaseConfirmed, model.CaseFalsePositive)
+	}
+	if note == "" {
+		return model.SanctionsCase{}, model.ErrReasonRequired
+	}
+
+	now := time.Now()
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.sanctionsRepo.ResolveCase(ctx, id, status, note, now); err != nil {
+			return fmt.Errorf("failed to resolve sanctions case: %w", err)
+		}
+		if status != model.CaseFalsePositive {
+			return nil
+		}
+		c, err := s.sanctionsRepo.FetchCase(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to fetch sanctions case: %w", err)
+		}
+		entries := make([]model.WhitelistEntry, len(c.Hits))
+		for n, hit := range c.Hits {
+			entries[n] = model.WhitelistEntry{WalletID: hit.WalletID, Entry: hit.Entry, CaseID: id, CreatedAt: now}
+		}
+		if err := s.sanctionsRepo.AddToWhitelist(ctx, entries...); err != nil {
+			return fmt.Errorf("failed to whitelist false positives: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.SanctionsCase{}, err
+	}
+	slog.InfoContext(ctx, "sanctions case resolved", "case_id", id, "status", status)
+
+	c, err := s.sanctionsRepo.FetchCase(model.WithConsistentRead(ctx), id)
+	if err != nil {
+		return model.SanctionsCase{}, fmt.Errorf("failed to fetch sanctions case: %w", err)
+	}
+	return c, nil
+}
+
+func (s *sanctionsService) AddToWhitelist(ctx context.Context, walletID uuid.UUID, entry, reason string) (_ model.WhitelistEntry, err error) {
+	ctx, span := tracer.Start(ctx, "SanctionsService.AddToWhitelist", trace.WithAttributes(attribute.String("wallet.id", walletID.String())))
+	defer func() { endSpan(span, err) }()
+
+	if entry == "" {
+		return model.WhitelistEntry{}, model.ErrEntryRequired
+	}
+	if reason == "" {
+		return model.WhitelistEntry{}, model.ErrReasonRequired
+	}
+
+	added := model.WhitelistEntry{WalletID: walletID.String(), Entry: entry, Reason: reason, CreatedAt: time.Now()}
+	if p, ok := model.PrincipalFromContext(ctx); ok {
+		added.Actor = p.Subject
+	}
+	if err := s.sanctionsRepo.AddToWhitelist(ctx, added); err != nil {
+		return model.WhitelistEntry{}, fmt.Errorf("failed to whitelist: %w", err)
+	}
+	slog.InfoContext(ctx, "sanctions whitelist entry added",
+		"wallet_id", walletID, "entry", entry, "reason", reason, "actor", added.Actor)
+
+	// The entry may have been whitelisted before, by a case or by hand.
+	entries, err := s.sanctionsRepo.Whitelist(model.WithConsistentRead(ctx), added.WalletID)
+	if err != nil {
+		return model.WhitelistEntry{}, fmt.Errorf("failed to fetch whitelist: %w", err)
+	}
+	for _, e := range entries {
+		if e.Entry == entry {
+			return e, nil
+		}
+	}
+	return added, nil
+}
+
+func (s *sanctionsService) RemoveFromWhitelist(ctx context.Context, walletID uuid.UUID, entry, reason string) (err error) {
+	ctx, span := tracer.Start(ctx, "SanctionsService.RemoveFromWhitelist", trace.WithAttributes(attribute.String("wallet.id", walletID.String())))
+	defer func() { endSpan(span, err) }()
+
+	if entry == "" {
+		return model.ErrEntryRequired
+	}
+	if reason == "" {
+		return model.ErrReasonRequired
+	}
+
+	if err := s.sanctionsRepo.RemoveFromWhitelist(ctx, walletID.String(), entry); err != nil {
+		return fmt.Errorf("failed to remove from whitelist: %w", err)
+	}
+	var actor string
+	if p, ok := model.PrincipalFromContext(ctx); ok {
+		actor = p.Subject
+	}
+	slog.InfoContext(ctx, "sanctions whitelist entry removed",
+		"wallet_id", walletID, "entry", entry, "reason", reason, "actor", actor)
+	return nil
+}
+
+func (s *sanctionsService) Whitelist(ctx context.Context, walletID string) (_ []model.WhitelistEntry, err error) {
+	ctx, span := tracer.Start(ctx, "SanctionsService.Whitelist")
+	defer func() { endSpan(span, err) }()
+
+	entries, err := s.sanctionsRepo.Whitelist(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch whitelist: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StatusService changes the status of wallets and keeps the history of changes.
+type StatusService interface {
+	// SetStatus changes the status of a wallet, recording who did it and why. Closed
+	// is final, and only an empty wallet may be closed.
+	SetStatus(ctx context.Context, walletID uuid.UUID, status model.WalletStatus, reason string) (model.StatusChange, error)
+
+	// History returns the status changes of a wallet, oldest first.
+	History(ctx context.Context, walletID uuid.UUID) ([]model.StatusChange, error)
+}
+
+type statusService struct {
+	walletRepo repository.WalletRepository
+	statusRepo repository.StatusRepository
+	transactor repository.Transactor
+}
+
+// NewStatusService creates a StatusService.
+func NewStatusService(
+	walletRepo repository.WalletRepository,
+	statusRepo repository.StatusRepository,
+	transactor repository.Transactor,
+) StatusService {
+	return &statusService{walletRepo: walletRepo, statusRepo: statusRepo, transactor: transactor}
+}
+
+func (s *statusService) SetStatus(ctx context.Context, walletID uuid.UUID, status model.WalletStatus, reason string) (_ model.StatusChange, err error) {
+	ctx, span := tracer.Start(ctx, "StatusService.SetStatus", trace.WithAttributes(
+		attribute.String("wallet.id", walletID.String()),
+		attribute.String("wallet.status", string(status)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if walletID == model.MintWalletID {
+		return model.StatusChange{}, fmt.Errorf("%w: its status cannot change", model.ErrSystemWallet)
+	}
+	if reason == "" {
+		return model.StatusChange{}, model.ErrReasonRequired
+	}
+
+	change := model.StatusChange{
+		ID:        uuid.New(),
+		WalletID:  walletID,
+		To:        status,
+		Reason:    reason,
+		RequestID: model.RequestIDFromContext(ctx),
+		CreatedAt: time.Now(),
+	}
+	if p, ok := model.PrincipalFromContext(ctx); ok {
+		change.Actor = p.Subject
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		wallet, err := s.walletRepo.FetchByID(ctx, walletID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch wallet: %w", err)
+		}
+		if !wallet.Status.CanBecome(status) {
+			return fmt.Errorf("%w: wallet %s is %s", model.ErrStatusTransition, walletID, wallet.Status)
+		}
+		if status == model.WalletClosed && wallet.Amount != 0 {
+			return fmt.Errorf("%w: %d cents left", model.ErrWalletNotEmpty, wallet.Amount)
+		}
+
+		// The version checked update makes a transfer from or to the wallet meanwhile
+		// fail one of the two transactions, so none lands under the old status.
+		change.From = wallet.Status
+		wallet.Status = status
+		if _, err := s.walletRepo.Update(ctx, wallet); err != nil {
+			return fmt.Errorf("failed to update wallet: %w", err)
+		}
+		if err := s.statusRepo.SaveChange(ctx, change); err != nil {
+			return fmt.Errorf("failed to record status change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.StatusChange{}, err
+	}
+	slog.InfoContext(ctx, "wallet status changed",
+		"wallet_id", walletID, "from", change.From, "to", change.To, "reason", reason, "actor", change.Actor)
+	return change, nil
+}
+
+func (s *statusService) History(ctx context.Context, walletID uuid.UUID) (_ []model.StatusChange, err error) {
+	ctx, span := tracer.Start(ctx, "StatusService.History", trace.WithAttributes(attribute.String("wallet.id", walletID.String())))
+	defer func() { endSpan(span, err) }()
+
+	if _, err := s.walletRepo.FetchByID(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	changes, err := s.statusRepo.FetchChanges(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch status changes: %w", err)
+	}
+	return changes, nil
+}
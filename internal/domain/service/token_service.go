@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// endUserScopes are granted to tokens that do not list scopes of their own.
+var endUserScopes = []model.Scope{model.ScopeWalletsRead, model.ScopeTransfersWrite}
+
+// TokenVerifier validates bearer tokens and extracts their claims.
+type TokenVerifier interface {
+	// Verify checks the token signature, issuer and lifetime and returns its claims.
+	Verify(token string) (*model.TokenClaims, error)
+}
+
+// TokenService defines methods for authenticating end users with bearer tokens.
+type TokenService interface {
+	// Authenticate resolves a bearer token to a principal limited to the wallets
+	// owned by the token subject, unless the token carries the admin claim.
+	Authenticate(ctx context.Context, token string) (*model.Principal, error)
+}
+
+type tokenService struct {
+	verifier   TokenVerifier
+	walletRepo repository.WalletRepository
+}
+
+// NewTokenService creates a new instance of TokenService.
+func NewTokenService(verifier TokenVerifier, walletRepo repository.WalletRepository) TokenService {
+	return &tokenService{verifier: verifier, walletRepo: walletRepo}
+}
+
+func (s *tokenService) Authenticate(ctx context.Context, token string) (_ *model.Principal, err error) {
+	ctx, span := tracer.Start(ctx, "TokenService.Authenticate")
+	defer func() { endSpan(span, err) }()
+
+	claims, err := s.verifier.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := claims.Scopes
+	if len(scopes) == 0 {
+		scopes = endUserScopes
+	}
+	// Admin access is only granted through the admin claim, never through a scope
+	// listed in the token, so end-user tokens cannot escalate themselves.
+	scopes = slices.DeleteFunc(slices.Clone(scopes), func(s model.Scope) bool { return s == model.ScopeAdmin })
+	if claims.Admin {
+		scopes = append(scopes, model.ScopeAdmin)
+	}
+
+	principal := &model.Principal{Subject: claims.Subject, Scopes: scopes, AllWallets: claims.Admin}
+	if !claims.Admin {
+		wallets, err := s.walletRepo.FetchByOwner(ctx, claims.Subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch owned wallets: %w", err)
+		}
+		principal.WalletIDs = lo.Map(wallets, func(w *model.Wallet, _ int) uuid.UUID { return w.ID })
+	}
+	return principal, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"transaction-service/internal/domain/model"
+)
+
+// subscriberBufferSize is how many transactions may queue up for a subscriber
+// before it is considered too slow and dropped.
+const subscriberBufferSize = 256
+
+// TransactionBroker fans committed transactions out to live subscribers.
+type TransactionBroker interface {
+	// Subscribe registers a subscriber for transactions matching the filter.
+	// The returned channel is closed when the subscriber falls too far behind
+	// or when the returned cancel function is called. head is the highest seq
+	// published before the subscription; only transactions committed out of seq
+	// order are delivered at or below it.
+	Subscribe(filter model.TransactionFilter) (ch <-chan model.Transaction, head int64, cancel func())
+
+	// Publish delivers a committed transaction to every matching subscriber.
+	Publish(transaction model.Transaction)
+
+	// Close disconnects every subscriber, ending open streams on shutdown.
+	// Later subscribers get an already closed channel.
+	Close()
+}
+
+type subscriber struct {
+	filter model.TransactionFilter
+	ch     chan model.Transaction
+}
+
+type transactionBroker struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+	closed      bool
+	head        atomic.Int64 // Highest seq published
+}
+
+// NewTransactionBroker creates a new in-process TransactionBroker.
+func NewTransactionBroker() TransactionBroker {
+	return &transactionBroker{subscribers: make(map[*subscriber]struct{})}
+}
+
+func (b *transactionBroker) Subscribe(filter model.TransactionFilter) (<-chan model.Transaction, int64, func()) {
+	sub := &subscriber{filter: filter, ch: make(chan model.Transaction, subscriberBufferSize)}
+
+	// Publish holds the read lock, so head covers exactly the transactions
+	// published before the subscriber was added.
+	b.mu.Lock()
+	if b.closed {
+		close(sub.ch)
+	} else {
+		b.subscribers[sub] = struct{}{}
+	}
+	head := b.head.Load()
+	b.mu.Unlock()
+
+	return sub.ch, head, func() { b.remove(sub) }
+}
+
+func (b *transactionBroker) Publish(transaction model.Transaction) {
+	b.mu.RLock()
+	for head := b.head.Load(); transaction.Seq > head; head = b.head.Load() {
+		if b.head.CompareAndSwap(head, transaction.Seq) {
+			break
+		}
+	}
+	var slow []*subscriber
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(transaction) {
+			continue
+		}
+		select {
+		case sub.ch <- transaction:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	// Slow subscribers are disconnected rather than blocking everyone else;
+	// clients resume from their last seen sequence number.
+	for _, sub := range slow {
+		b.remove(sub)
+	}
+}
+
+func (b *transactionBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.ch)
+	}
+}
+
+func (b *transactionBroker) remove(sub *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.ch)
+	}
+}
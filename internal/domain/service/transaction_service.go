@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"sync"
 	"transaction-service/internal/domain/model"
 	"transaction-service/internal/domain/repository"
@@ -9,17 +11,36 @@ import (
 
 // TransactionService defines methods for managing transaction operations.
 type TransactionService interface {
-	// GetNTransactions retrieves the last N transactions.
-	GetNTransactions(ctx context.Context, n int) ([]model.Transaction, error)
+	// GetNTransactions retrieves the last N transactions. When walletIDs is not nil,
+	// only transactions sent from or to one of them count.
+	GetNTransactions(ctx context.Context, n int, walletIDs []string) ([]model.Transaction, error)
+
+	// WatchTransactions streams committed transactions matching the filter.
+	// When cursor is set, transactions after that sequence number are replayed
+	// page by page before live ones. The channel is closed when ctx is done, a
+	// replay page fails to load or the watcher falls too far behind.
+	WatchTransactions(ctx context.Context, filter model.TransactionFilter, cursor *int64) (<-chan model.Transaction, error)
+
+	// History returns up to filter.Limit transactions matching the filter, newest
+	// first. With filter.IncludeArchived, archived months follow the log.
+	History(ctx context.Context, filter model.HistoryFilter) ([]model.Transaction, error)
 }
 
 type transactionService struct {
 	repository     repository.TransactionRepository
+	archive        repository.TransactionArchive
+	broker         TransactionBroker
 	workerPoolSize int
 }
 
-func (t *transactionService) GetNTransactions(ctx context.Context, n int) ([]model.Transaction, error) {
-	transactions, err := t.repository.GetTransactions(ctx)
+// replayBatchSize limits how many transactions are read per query while replaying.
+const replayBatchSize = 500
+
+func (t *transactionService) GetNTransactions(ctx context.Context, n int, walletIDs []string) (_ []model.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.GetNTransactions")
+	defer func() { endSpan(span, err) }()
+
+	transactions, err := t.repository.GetTransactions(ctx, walletIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +59,10 @@ func (t *transactionService) GetNTransactions(ctx context.Context, n int) ([]mod
 			for j := start; j < len(transactions); j += workerCount {
 				select {
 				case <-ctx.Done():
-					errCh <- ctx.Err()
+					select {
+					case errCh <- ctx.Err():
+					default: // Another worker reported it already.
+					}
 					return
 				default:
 					resultsMu.Lock()
@@ -54,22 +78,136 @@ func (t *transactionService) GetNTransactions(ctx context.Context, n int) ([]mod
 		}(i)
 	}
 
+	// Wait for the workers before reading their results.
+	wg.Wait()
+	close(resultCh)
+	close(errCh)
+
+	if err, ok := <-errCh; ok {
+		return nil, err
+	}
+
+	return results[:min(len(results), n)], nil
+}
+
+func (t *transactionService) WatchTransactions(ctx context.Context, filter model.TransactionFilter, cursor *int64) (<-chan model.Transaction, error) {
+	// Subscribe before replaying so nothing committed in between is lost;
+	// duplicates are dropped by sequence number below.
+	live, head, cancel := t.broker.Subscribe(filter)
+
+	// The first page is read before returning so a failing replay is reported to
+	// the caller rather than ending the stream.
+	var page []model.Transaction
+	if cursor != nil {
+		var err error
+		if page, err = t.replayPage(ctx, *cursor, filter); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	out := make(chan model.Transaction)
 	go func() {
-		wg.Wait()
-		close(resultCh)
-		close(errCh)
+		defer close(out)
+		defer cancel()
+
+		send := func(transaction model.Transaction) bool {
+			select {
+			case out <- transaction:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// Only replayed seqs above head are remembered: the rest were published before
+		// the subscription, short of one committed out of seq order and published
+		// late, which is then sent twice. Each is forgotten once delivered live.
+		// Live transactions are dropped only when already replayed. One at or below
+		// the cursor committed after a higher seq, so the client has not seen it.
+		replayed := make(map[int64]struct{})
+		for len(page) > 0 {
+			for _, transaction := range page {
+				if transaction.Seq > head {
+					replayed[transaction.Seq] = struct{}{}
+				}
+				if !send(transaction) {
+					return
+				}
+			}
+			if len(page) < replayBatchSize {
+				break
+			}
+			var err error
+			if page, err = t.replayPage(ctx, page[len(page)-1].Seq, filter); err != nil {
+				if ctx.Err() == nil {
+					slog.ErrorContext(ctx, "watch replay failed", "error", err)
+				}
+				return
+			}
+		}
+		for {
+			select {
+			case transaction, ok := <-live:
+				if !ok {
+					return
+				}
+				if _, ok := replayed[transaction.Seq]; ok {
+					delete(replayed, transaction.Seq)
+					continue
+				}
+				if !send(transaction) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
 	}()
 
-	if len(errCh) > 0 {
-		return nil, <-errCh
+	return out, nil
+}
+
+// replayPage reads the next page of the replay after seq.
+func (t *transactionService) replayPage(ctx context.Context, seq int64, filter model.TransactionFilter) ([]model.Transaction, error) {
+	// The broker is fed from the primary, so replaying from a lagging replica
+	// could skip transactions published before the subscription.
+	page, err := t.repository.GetTransactionsAfter(model.WithConsistentRead(ctx), seq, filter, replayBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay transactions: %w", err)
 	}
+	return page, nil
+}
 
-	return results[:min(len(results), n)], nil
+func (t *transactionService) History(ctx context.Context, filter model.HistoryFilter) (_ []model.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.History")
+	defer func() { endSpan(span, err) }()
+
+	transactions, err := t.repository.FindTransactions(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transactions: %w", err)
+	}
+	if !filter.IncludeArchived || len(transactions) >= filter.Limit {
+		return transactions, nil
+	}
+
+	// Archived months precede the log, so the archive continues where it ran out.
+	rest := filter
+	rest.Limit -= len(transactions)
+	if len(transactions) > 0 {
+		rest.BeforeSeq = transactions[len(transactions)-1].Seq
+	}
+	archived, err := t.archive.FindTransactions(ctx, rest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the archive: %w", err)
+	}
+	return append(transactions, archived...), nil
 }
 
-// NewTransactionService creates a new instance of TransactionService.
-func NewTransactionService(repository repository.TransactionRepository) TransactionService {
-	return &transactionService{repository: repository, workerPoolSize: 5}
+// NewTransactionService creates a new instance of TransactionService. archive is
+// searched by History for months moved out of the log.
+func NewTransactionService(repository repository.TransactionRepository, archive repository.TransactionArchive, broker TransactionBroker) TransactionService {
+	return &transactionService{repository: repository, archive: archive, broker: broker, workerPoolSize: 5}
 }
 
 func min(a, b int) int {
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+)
+
+// transactionLog serves GetTransactionsAfter from a slice; the other methods are
+// not called. beforeRead runs before every page is read.
+type transactionLog struct {
+	repository.TransactionRepository
+	transactions []model.Transaction
+	beforeRead   func()
+	reads        int
+}
+
+func (l *transactionLog) GetTransactionsAfter(_ context.Context, seq int64, _ model.TransactionFilter, limit int) ([]model.Transaction, error) {
+	l.reads++
+	if l.beforeRead != nil {
+		l.beforeRead()
+	}
+	var page []model.Transaction
+	for _, transaction := range l.transactions {
+		if transaction.Seq > seq && len(page) < limit {
+			page = append(page, transaction)
+		}
+	}
+	return page, nil
+}
+
+func TestWatchTransactionsReplay(t *testing.T) {
+	const logged = 2*replayBatchSize + 10
+	log := &transactionLog{}
+	for seq := int64(1); seq <= logged; seq++ {
+		log.transactions = append(log.transactions, model.Transaction{Seq: seq})
+	}
+
+	broker := NewTransactionBroker()
+	// Published before the subscription, so the replay alone delivers them.
+	for seq := int64(1); seq <= logged-5; seq++ {
+		broker.Publish(model.Transaction{Seq: seq})
+	}
+	// Published after the subscription and replayed too, except the last one.
+	log.beforeRead = func() {
+		if log.reads == 1 {
+			for seq := int64(logged - 4); seq <= logged+1; seq++ {
+				broker.Publish(model.Transaction{Seq: seq})
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cursor := int64(0)
+	watch, err := NewTransactionService(log, nil, broker).WatchTransactions(ctx, model.TransactionFilter{}, &cursor)
+	if err != nil {
+		t.Fatalf("WatchTransactions: %v", err)
+	}
+
+	for want := int64(1); want <= logged+1; want++ {
+		select {
+		case transaction := <-watch:
+			if transaction.Seq != want {
+				t.Fatalf("got seq %d, want %d", transaction.Seq, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for seq %d", want)
+		}
+	}
+	if log.reads != 3 {
+		t.Errorf("replay read %d pages, want 3", log.reads)
+	}
+
+	broker.Publish(model.Transaction{Seq: logged + 2})
+	select {
+	case transaction := <-watch:
+		if transaction.Seq != logged+2 {
+			t.Errorf("got seq %d after the replay, want %d", transaction.Seq, logged+2)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a live transaction")
+	}
+}
@@ -1,9 +1,15 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"math/rand/v2"
 	"sync"
 	"time"
 	"transaction-service/internal/domain/model"
@@ -12,8 +18,9 @@ import (
 
 // WalletService defines methods for wallet-related operations.
 type WalletService interface {
-	// SendMoney transfers funds between two wallets.
-	SendMoney(ctx context.Context, fromID, toID uuid.UUID, amount int) error
+	// SendMoney transfers funds between two wallets and returns the transaction
+	// recording it.
+	SendMoney(ctx context.Context, fromID, toID uuid.UUID, amount int) (model.Transaction, error)
 
 	// GetBalance retrieves the balance of a wallet by its ID.
 	GetBalance(ctx context.Context, id uuid.UUID) (amount int, err error)
@@ -21,17 +28,71 @@ type WalletService interface {
 	// InitializeWallets create 10 wallets for first launch
 	InitializeWallets(ctx context.Context) error
 	FetchAll(ctx context.Context) ([]*model.Wallet, error)
+
+	// SetOwner assigns a wallet to an end user with the given legal name; an
+	// empty ownerID unassigns it.
+	SetOwner(ctx context.Context, id uuid.UUID, ownerID, ownerName string) error
+
+	// CreateWallet opens a wallet with a zero balance.
+	CreateWallet(ctx context.Context) (*model.Wallet, error)
+
+	// FetchByID returns a wallet by its ID.
+	FetchByID(ctx context.Context, id uuid.UUID) (*model.Wallet, error)
+
+	// Reconcile checks every wallet balance against the transaction log.
+	Reconcile(ctx context.Context) (model.Reconciliation, error)
+
+	// Mint issues new money into a wallet, transferring it from the mint wallet.
+	Mint(ctx context.Context, toID uuid.UUID, amount int, reason string) error
+
+	// Burn destroys money held by a wallet, transferring it to the mint wallet.
+	Burn(ctx context.Context, fromID uuid.UUID, amount int, reason string) error
+
+	// Supply reports the money issued and burned and the total held by user wallets.
+	Supply(ctx context.Context) (model.Supply, error)
+}
+
+// initialBalance is minted into each wallet InitializeWallets creates, in cents.
+const initialBalance = 100
+
+// maxAmount bounds any single transfer, in cents, whatever the spending limits.
+const maxAmount = 10000000
+
+// Locking strategies selectable with TransferConfig.Locking.
+const (
+	// LockingPessimistic serializes transfers of a wallet on in-process locks.
+	// Version checks still catch transfers made by other replicas.
+	LockingPessimistic = "pessimistic"
+
+	// LockingOptimistic runs transfers concurrently and retries the ones that
+	// lose a version check.
+	LockingOptimistic = "optimistic"
+)
+
+// TransferConfig controls how concurrent transfers of the same wallet are handled.
+type TransferConfig struct {
+	Locking      string        // LockingPessimistic or LockingOptimistic
+	MaxAttempts  int           // Attempts per transfer, including the first
+	RetryBackoff time.Duration // Base delay before a retry, doubled per attempt with full jitter
 }
 
 type walletService struct {
 	walletRepo      repository.WalletRepository
 	transactionRepo repository.TransactionRepository
+	transactor      repository.Transactor
+	metrics         Metrics
+	config          TransferConfig
+	limits          LimitService    // Enforced in the transaction of each SendMoney, if set
+	checks          []TransferCheck // Run before each SendMoney, in order
 
 	lockMap sync.Map
 }
 
 // FetchAll returns all records from the database
-func (w *walletService) FetchAll(ctx context.Context) ([]*model.Wallet, error) {
+func (w *walletService) FetchAll(ctx context.Context) (_ []*model.Wallet, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.FetchAll")
+	defer func() { endSpan(span, err) }()
+
 	wallets, err := w.walletRepo.FetchAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch wallets: %w", err)
@@ -39,15 +100,84 @@ func (w *walletService) FetchAll(ctx context.Context) ([]*model.Wallet, error) {
 	return wallets, nil
 }
 
-// NewWalletService creates a new instance of WalletService.
-func NewWalletService(walletRepo repository.WalletRepository, transactionRepo repository.TransactionRepository) WalletService {
+func (w *walletService) SetOwner(ctx context.Context, id uuid.UUID, ownerID, ownerName string) (err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.SetOwner", trace.WithAttributes(attribute.String("wallet.id", id.String())))
+	defer func() { endSpan(span, err) }()
+
+	if id == model.MintWalletID {
+		return fmt.Errorf("%w: it cannot be owned", model.ErrSystemWallet)
+	}
+	if err := w.walletRepo.SetOwner(ctx, id, ownerID, ownerName); err != nil {
+		return fmt.Errorf("failed to set wallet owner: %w", err)
+	}
+	return nil
+}
+
+func (w *walletService) CreateWallet(ctx context.Context) (_ *model.Wallet, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.CreateWallet")
+	defer func() { endSpan(span, err) }()
+
+	id, err := w.walletRepo.Create(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wallet: %w", err)
+	}
+	slog.InfoContext(ctx, "wallet created", "wallet_id", id)
+
+	// A replica may not have the new wallet yet.
+	wallet, err := w.walletRepo.FetchByID(model.WithConsistentRead(ctx), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	return wallet, nil
+}
+
+func (w *walletService) FetchByID(ctx context.Context, id uuid.UUID) (_ *model.Wallet, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.FetchByID", trace.WithAttributes(attribute.String("wallet.id", id.String())))
+	defer func() { endSpan(span, err) }()
+
+	wallet, err := w.walletRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	return wallet, nil
+}
+
+// NewWalletService creates a new instance of WalletService. Transfers made with
+// SendMoney must keep within the spending limits, pass every check and be allowed
+// by the status of both wallets; mints and burns only stop at closed wallets.
+func NewWalletService(
+	walletRepo repository.WalletRepository,
+	transactionRepo repository.TransactionRepository,
+	transactor repository.Transactor,
+	metrics Metrics,
+	config TransferConfig,
+	limits LimitService,
+	checks ...TransferCheck,
+) (WalletService, error) {
+	switch config.Locking {
+	case LockingPessimistic, LockingOptimistic:
+	default:
+		return nil, fmt.Errorf("unknown locking strategy %q", config.Locking)
+	}
+	config.MaxAttempts = max(config.MaxAttempts, 1)
+
 	return &walletService{
 		walletRepo:      walletRepo,
 		transactionRepo: transactionRepo,
-	}
+		transactor:      transactor,
+		metrics:         metrics,
+		config:          config,
+		limits:          limits,
+		checks:          checks,
+	}, nil
 }
 
 func (w *walletService) InitializeWallets(ctx context.Context) error {
+	// Migrations create the mint wallet; the in-memory store starts without one.
+	if err := w.walletRepo.CreateSystem(ctx, model.MintWalletID); err != nil {
+		return fmt.Errorf("failed to create mint wallet: %w", err)
+	}
+
 	initialized, err := w.walletRepo.IsServiceInitialized(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to check initialization state: %w", err)
@@ -57,73 +187,199 @@ func (w *walletService) InitializeWallets(ctx context.Context) error {
 		return nil // Кошельки уже были созданы
 	}
 
-	tx, err := w.walletRepo.BeginTransaction()
+	err = w.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i := 0; i < 10; i++ {
+			id, err := w.walletRepo.Create(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to create wallet #%d: %w", i+1, err)
+			}
+			if _, err := w.transfer(ctx, model.MintWalletID, id, initialBalance, "initial balance", false); err != nil {
+				return fmt.Errorf("failed to fund wallet #%d: %w", i+1, err)
+			}
+			slog.InfoContext(ctx, "wallet created", "wallet_id", id)
+		}
+
+		if err := w.walletRepo.SetServiceInitialized(ctx); err != nil {
+			return fmt.Errorf("failed to set service initialized: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return fmt.Errorf("failed to initialize wallets: %w", err)
 	}
-	defer tx.Rollback()
+	return nil
+}
 
-	for i := 0; i < 10; i++ {
-		if _, err := w.walletRepo.Create(ctx); err != nil {
-			return fmt.Errorf("failed to create wallet #%d: %w", i+1, err)
-		}
+func (w *walletService) SendMoney(ctx context.Context, fromID, toID uuid.UUID, amount int) (model.Transaction, error) {
+	if fromID == model.MintWalletID || toID == model.MintWalletID {
+		return model.Transaction{}, fmt.Errorf("%w: use mint and burn to move money from or to it", model.ErrSystemWallet)
 	}
+	return w.send(ctx, fromID, toID, amount, "", true)
+}
 
-	if err := w.walletRepo.SetServiceInitialized(ctx); err != nil {
-		return fmt.Errorf("failed to set service initialized: %w", err)
+func (w *walletService) Mint(ctx context.Context, toID uuid.UUID, amount int, reason string) error {
+	if reason == "" {
+		return model.ErrReasonRequired
+	}
+	if toID == model.MintWalletID {
+		return fmt.Errorf("%w: cannot mint into the mint wallet", model.ErrSystemWallet)
 	}
+	_, err := w.send(ctx, model.MintWalletID, toID, amount, reason, false)
+	return err
+}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit initialization: %w", err)
+func (w *walletService) Burn(ctx context.Context, fromID uuid.UUID, amount int, reason string) error {
+	if reason == "" {
+		return model.ErrReasonRequired
+	}
+	if fromID == model.MintWalletID {
+		return fmt.Errorf("%w: cannot burn from the mint wallet", model.ErrSystemWallet)
 	}
+	_, err := w.send(ctx, fromID, model.MintWalletID, amount, reason, false)
+	return err
+}
 
-	return nil
+func (w *walletService) Supply(ctx context.Context) (_ model.Supply, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.Supply")
+	defer func() { endSpan(span, err) }()
+
+	// Both reads share a snapshot transaction, so a transfer committing between
+	// them is seen by both or neither.
+	var supply model.Supply
+	err = w.transactor.WithinTransaction(repository.WithSnapshot(ctx), func(ctx context.Context) error {
+		totals, err := w.transactionRepo.Totals(ctx, model.MintWalletID.String())
+		if err != nil {
+			return fmt.Errorf("failed to sum mint transfers: %w", err)
+		}
+		summary, err := w.walletRepo.Summarize(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to summarize wallets: %w", err)
+		}
+		supply = model.Supply{Issued: totals.Sent, Burned: totals.Received, WalletTotal: summary.TotalAmount}
+		return nil
+	})
+	if err != nil {
+		return model.Supply{}, err
+	}
+	return supply, nil
 }
 
-func (w *walletService) SendMoney(ctx context.Context, fromID, toID uuid.UUID, amount int) error {
+// send moves amount between two wallets, retrying conflicts, with reason recorded
+// on the transaction it returns. A transfer by a wallet holder must pass the checks
+// first, and the spending limits of the sender are enforced in the transaction of
+// each attempt.
+func (w *walletService) send(ctx context.Context, fromID, toID uuid.UUID, amount int, reason string, holder bool) (_ model.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.SendMoney", trace.WithAttributes(
+		attribute.String("wallet.from", fromID.String()),
+		attribute.String("wallet.to", toID.String()),
+		attribute.Int("transfer.amount_cents", amount),
+		attribute.String("transfer.reason", reason),
+	))
+	defer func() {
+		outcome := TransferOutcome(err)
+		w.metrics.TransferCompleted(outcome)
+		endSpan(span, err)
+
+		attrs := []any{"from_wallet", fromID, "to_wallet", toID, "amount_cents", amount, "outcome", outcome}
+		if reason != "" {
+			attrs = append(attrs, "reason", reason)
+		}
+		switch outcome {
+		case OutcomeSuccess:
+			slog.InfoContext(ctx, "transfer committed", attrs...)
+		case OutcomeError:
+			slog.ErrorContext(ctx, "transfer failed", append(attrs, "error", err)...)
+		default:
+			slog.InfoContext(ctx, "transfer rejected", append(attrs, "error", err)...)
+		}
+	}()
+
 	if fromID == toID {
-		return fmt.Errorf("cannot send money to the same wallet")
+		return model.Transaction{}, model.ErrSameWallet
 	}
-	if amount <= 0 || amount > 10000000 {
-		return fmt.Errorf("amount must be between 0 and 10,000")
+	if amount <= 0 || amount > maxAmount {
+		return model.Transaction{}, fmt.Errorf("%w: amount must be between 0.01 and %d", model.ErrInvalidAmount, maxAmount/100)
+	}
+	if holder {
+		for _, check := range w.checks {
+			if err := check.CheckTransfer(ctx, fromID, toID, amount); err != nil {
+				return model.Transaction{}, err
+			}
+		}
 	}
 
-	fromLock := w.getLock(fromID)
-	toLock := w.getLock(toID)
-
-	fromLock.Lock()
-	defer fromLock.Unlock()
+	if w.config.Locking == LockingPessimistic {
+		unlock := w.lockWallets(ctx, fromID, toID)
+		defer unlock()
+	}
 
-	toLock.Lock()
-	defer toLock.Unlock()
+	for attempt := 1; ; attempt++ {
+		var transaction model.Transaction
+		err = w.transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+			transaction, err = w.transfer(ctx, fromID, toID, amount, reason, holder)
+			return err
+		})
+		if err == nil {
+			return transaction, nil
+		}
+		if !errors.Is(err, model.ErrConflict) {
+			return model.Transaction{}, err
+		}
+		if attempt == w.config.MaxAttempts {
+			return model.Transaction{}, fmt.Errorf("transfer gave up after %d attempts: %w", attempt, err)
+		}
 
-	tx, err := w.walletRepo.BeginTransaction()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		w.metrics.TransferRetried()
+		slog.DebugContext(ctx, "transfer conflicted, retrying",
+			"from_wallet", fromID, "to_wallet", toID, "attempt", attempt, "error", err)
+		if err := sleep(ctx, w.retryDelay(attempt)); err != nil {
+			return model.Transaction{}, err
+		}
 	}
-	defer tx.Rollback()
+}
 
+// transfer moves amount between the wallets in the transaction carried by ctx. Both
+// updates are version checked, so a concurrent transfer or status change makes it
+// fail with model.ErrConflict instead of losing an update. The mint wallet may go
+// negative. Closed wallets never take part; for a wallet holder, the sender must be
+// able to send, stay within its spending limits, and the receiver must be able to
+// receive. It returns the transaction recording the transfer.
+func (w *walletService) transfer(ctx context.Context, fromID, toID uuid.UUID, amount int, reason string, holder bool) (model.Transaction, error) {
 	senderWallet, err := w.walletRepo.FetchByID(ctx, fromID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch sender wallet: %w", err)
+		return model.Transaction{}, fmt.Errorf("failed to fetch sender wallet: %w", err)
 	}
-	if senderWallet.Amount < amount {
-		return fmt.Errorf("insufficient funds")
+	if senderWallet.Status == model.WalletClosed || holder && !senderWallet.Status.CanSend() {
+		return model.Transaction{}, fmt.Errorf("%w: sender %s is %s", model.ErrWalletStatus, fromID, senderWallet.Status)
+	}
+	if senderWallet.Amount < amount && !senderWallet.System {
+		return model.Transaction{}, model.ErrInsufficientFunds
+	}
+	// The limits are checked after the sender is read: a transfer committed after
+	// that read fails the version checked update below, and one committed before it
+	// is in the velocity the check reads.
+	if holder && w.limits != nil {
+		if err := w.limits.Enforce(ctx, fromID, amount); err != nil {
+			return model.Transaction{}, err
+		}
 	}
 
 	receiverWallet, err := w.walletRepo.FetchByID(ctx, toID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch receiver wallet: %w", err)
+		return model.Transaction{}, fmt.Errorf("failed to fetch receiver wallet: %w", err)
+	}
+	if receiverWallet.Status == model.WalletClosed || holder && !receiverWallet.Status.CanReceive() {
+		return model.Transaction{}, fmt.Errorf("%w: receiver %s is %s", model.ErrWalletStatus, toID, receiverWallet.Status)
 	}
 
 	senderWallet.Amount -= amount
 	receiverWallet.Amount += amount
 
 	if _, err := w.walletRepo.Update(ctx, senderWallet); err != nil {
-		return fmt.Errorf("failed to update sender wallet: %w", err)
+		return model.Transaction{}, fmt.Errorf("failed to update sender wallet: %w", err)
 	}
 	if _, err := w.walletRepo.Update(ctx, receiverWallet); err != nil {
-		return fmt.Errorf("failed to update receiver wallet: %w", err)
+		return model.Transaction{}, fmt.Errorf("failed to update receiver wallet: %w", err)
 	}
 
 	transaction := &model.Transaction{
@@ -132,19 +388,74 @@ func (w *walletService) SendMoney(ctx context.Context, fromID, toID uuid.UUID, a
 		To:        toID.String(),
 		Amount:    amount,
 		CreatedAt: time.Now(),
+		RequestID: model.RequestIDFromContext(ctx),
+		Reason:    reason,
 	}
 	if _, err := w.transactionRepo.Create(ctx, transaction); err != nil {
-		return fmt.Errorf("failed to create transaction: %w", err)
+		return model.Transaction{}, fmt.Errorf("failed to create transaction: %w", err)
 	}
+	return *transaction, nil
+}
+
+// lockWallets takes the in-process locks of both wallets and returns a function
+// releasing them. Locks are always taken in the same order, so opposite transfers
+// between two wallets cannot deadlock.
+func (w *walletService) lockWallets(ctx context.Context, fromID, toID uuid.UUID) func() {
+	_, span := tracer.Start(ctx, "WalletService.acquireLocks")
+	defer span.End()
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	first, second := fromID, toID
+	if bytes.Compare(first[:], second[:]) > 0 {
+		first, second = second, first
 	}
+	firstLock, secondLock := w.getLock(first), w.getLock(second)
 
-	return nil
+	start := time.Now()
+	firstLock.Lock()
+	secondLock.Lock()
+	wait := time.Since(start)
+
+	w.metrics.LockWaited(wait)
+	slog.DebugContext(ctx, "wallet locks acquired", "from_wallet", fromID, "to_wallet", toID, "wait", wait)
+
+	return func() {
+		secondLock.Unlock()
+		firstLock.Unlock()
+	}
+}
+
+// maxRetryDelay caps the backoff between transfer attempts.
+const maxRetryDelay = time.Second
+
+// retryDelay returns a random delay of up to RetryBackoff doubled per failed attempt,
+// so conflicting transfers do not retry in lockstep.
+func (w *walletService) retryDelay(attempt int) time.Duration {
+	ceiling := w.config.RetryBackoff << (attempt - 1)
+	if ceiling > maxRetryDelay || ceiling < 0 {
+		ceiling = maxRetryDelay
+	}
+	if ceiling == 0 {
+		return 0
+	}
+	return rand.N(ceiling)
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (w *walletService) GetBalance(ctx context.Context, id uuid.UUID) (amount int, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.GetBalance", trace.WithAttributes(attribute.String("wallet.id", id.String())))
+	defer func() { endSpan(span, err) }()
+
 	wallet, err := w.walletRepo.FetchByID(ctx, id)
 	if err != nil {
 		return 0, fmt.Errorf("failed to fetch wallet: %w", err)
@@ -0,0 +1,274 @@
+// Package archive stores the transactions of past months as files in a directory,
+// one gzip-compressed NDJSON file per month in log order:
+//
+//	transactions-2025-01.ndjson.gz
+//	transactions-2025-01.ndjson.gz.sha256
+//
+// The .sha256 file holds the SHA-256 checksum of the compressed file in the format
+// of sha256sum, so archives can be checked with sha256sum -c. Reads verify it too.
+// The directory may be shared by every replica of the service.
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	filePrefix     = "transactions-"
+	fileExt        = ".ndjson.gz"
+	checksumExt    = ".sha256"
+	monthLayout    = "2006-01"
+	maxRecordBytes = 1 << 20
+)
+
+// Archive is a directory of monthly transaction archives.
+type Archive struct {
+	dir string
+}
+
+// New returns the archive in dir. The directory is created on the first write.
+func New(dir string) *Archive {
+	return &Archive{dir: dir}
+}
+
+// record is the archived form of a transaction, one JSON object per line.
+type record struct {
+	ID        uuid.UUID `json:"id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Amount    int       `json:"amount"` // In cents
+	CreatedAt time.Time `json:"created_at"`
+	Seq       int64     `json:"seq"`
+	RequestID string    `json:"request_id,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	PrevHash  string    `json:"prev_hash,omitempty"`
+	Hash      string    `json:"hash,omitempty"`
+}
+
+// path returns the archive file of the month.
+func (a *Archive) path(month time.Time) string {
+	return filepath.Join(a.dir, filePrefix+month.Format(monthLayout)+fileExt)
+}
+
+func (a *Archive) Create(month time.Time) (repository.ArchiveWriter, error) {
+	if err := os.MkdirAll(a.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	path := a.path(month)
+	file, err := os.CreateTemp(a.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive file: %w", err)
+	}
+	w := &writer{path: path, file: file, hash: sha256.New()}
+	w.buf = bufio.NewWriter(io.MultiWriter(file, w.hash))
+	w.gzip = gzip.NewWriter(w.buf)
+	w.enc = json.NewEncoder(w.gzip)
+	return w, nil
+}
+
+type writer struct {
+	path string
+	file *os.File
+	hash hash.Hash
+	buf  *bufio.Writer
+	gzip *gzip.Writer
+	enc  *json.Encoder
+	done bool
+}
+
+func (w *writer) Write(t model.Transaction) error {
+	err := w.enc.Encode(record{
+		ID: t.ID, From: t.From, To: t.To, Amount: t.Amount, CreatedAt: t.CreatedAt.UTC(),
+		Seq: t.Seq, RequestID: t.RequestID, Reason: t.Reason, PrevHash: t.PrevHash, Hash: t.Hash,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// Commit flushes the archive to disk and moves it and its checksum into place.
+// The archive is renamed last, so a listed month always has a checksum.
+func (w *writer) Commit() error {
+	if err := w.gzip.Close(); err != nil {
+		return fmt.Errorf("failed to compress archive: %w", err)
+	}
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync archive: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+
+	checksum := fmt.Sprintf("%s  %s\n", hex.EncodeToString(w.hash.Sum(nil)), filepath.Base(w.path))
+	if err := writeFile(w.path+checksumExt, []byte(checksum)); err != nil {
+		os.Remove(w.file.Name())
+		return fmt.Errorf("failed to write archive checksum: %w", err)
+	}
+	if err := os.Rename(w.file.Name(), w.path); err != nil {
+		os.Remove(w.file.Name())
+		return fmt.Errorf("failed to move archive into place: %w", err)
+	}
+	w.done = true
+	return nil
+}
+
+func (w *writer) Abort() error {
+	if w.done {
+		return nil
+	}
+	w.done = true
+	w.file.Close()
+	return os.Remove(w.file.Name())
+}
+
+// writeFile replaces the file at path with data in one rename.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (a *Archive) Months(context.Context) ([]time.Time, error) {
+	entries, err := os.ReadDir(a.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archive: %w", err)
+	}
+
+	var months []time.Time
+	for _, entry := range entries {
+		name, ok := strings.CutPrefix(entry.Name(), filePrefix)
+		if !ok {
+			continue
+		}
+		name, ok = strings.CutSuffix(name, fileExt)
+		if !ok {
+			continue
+		}
+		month, err := time.Parse(monthLayout, name)
+		if err != nil {
+			continue
+		}
+		months = append(months, month)
+	}
+	slices.SortFunc(months, time.Time.Compare)
+	return months, nil
+}
+
+// FindTransactions reads the archived months overlapping the filter, newest first,
+// until filter.Limit transactions match.
+func (a *Archive) FindTransactions(ctx context.Context, filter model.HistoryFilter) ([]model.Transaction, error) {
+	months, err := a.Months(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.Transaction
+	for _, month := range slices.Backward(months) {
+		if len(result) >= filter.Limit {
+			break
+		}
+		if !filter.Until.IsZero() && !month.Before(filter.Until) ||
+			!filter.Since.IsZero() && !month.AddDate(0, 1, 0).After(filter.Since) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		matched, err := a.read(month, filter)
+		if err != nil {
+			return nil, err
+		}
+		// Keep the newest of the month, which is archived oldest first.
+		matched = matched[max(0, len(matched)-(filter.Limit-len(result))):]
+		slices.Reverse(matched)
+		result = append(result, matched...)
+	}
+	return result, nil
+}
+
+// read returns the transactions of the month matching the filter, oldest first. The
+// whole file is read, so its checksum is verified before anything is returned.
+func (a *Archive) read(month time.Time, filter model.HistoryFilter) ([]model.Transaction, error) {
+	path := a.path(month)
+	want, err := readChecksum(path + checksumExt)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	zr, err := gzip.NewReader(io.TeeReader(bufio.NewReader(file), hash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", filepath.Base(path), err)
+	}
+
+	var matched []model.Transaction
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(nil, maxRecordBytes)
+	for scanner.Scan() {
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("failed to decode archive %s: %w", filepath.Base(path), err)
+		}
+		t := model.Transaction(r)
+		if filter.Matches(t) {
+			matched = append(matched, t)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", filepath.Base(path), err)
+	}
+	// Read past the end of the gzip stream, so the whole file is hashed.
+	if _, err := io.Copy(io.Discard, zr); err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", filepath.Base(path), err)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+		return nil, fmt.Errorf("archive %s does not match its checksum", filepath.Base(path))
+	}
+	return matched, nil
+}
+
+// readChecksum returns the hex checksum from a sha256sum file.
+func readChecksum(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read archive checksum: %w", err)
+	}
+	checksum, _, _ := strings.Cut(string(data), " ")
+	if len(checksum) != sha256.Size*2 {
+		return "", fmt.Errorf("archive checksum %s is malformed", filepath.Base(path))
+	}
+	return checksum, nil
+}
@@ -0,0 +1,137 @@
+package archive_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/infrastructure/archive"
+
+	"github.com/google/uuid"
+)
+
+// write archives the transactions of the month, one per day from its first.
+func write(t *testing.T, a *archive.Archive, month time.Time, seqs ...int64) {
+	t.Helper()
+	w, err := a.Create(month)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for n, seq := range seqs {
+		err := w.Write(model.Transaction{
+			ID: uuid.New(), From: "a", To: "b", Amount: int(seq) * 100,
+			CreatedAt: month.AddDate(0, 0, n), Seq: seq, Reason: "test",
+		})
+		if err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+}
+
+func seqs(transactions []model.Transaction) []int64 {
+	result := make([]int64, len(transactions))
+	for n, transaction := range transactions {
+		result[n] = transaction.Seq
+	}
+	return result
+}
+
+func TestFindTransactions(t *testing.T) {
+	ctx := context.Background()
+	a := archive.New(filepath.Join(t.TempDir(), "archive"))
+	jan := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+
+	if months, err := a.Months(ctx); err != nil || len(months) != 0 {
+		t.Fatalf("Months of a missing directory = %v, %v; want none", months, err)
+	}
+	write(t, a, feb, 4, 5, 6)
+	write(t, a, jan, 1, 2, 3)
+
+	months, err := a.Months(ctx)
+	if err != nil {
+		t.Fatalf("Months: %v", err)
+	}
+	if !slices.Equal(months, []time.Time{jan, feb}) {
+		t.Errorf("Months = %v, want January and February", months)
+	}
+
+	tests := []struct {
+		name   string
+		filter model.HistoryFilter
+		want   []int64
+	}{
+		{"across months", model.HistoryFilter{Limit: 4}, []int64{6, 5, 4, 3}},
+		{"page", model.HistoryFilter{BeforeSeq: 3, Limit: 10}, []int64{2, 1}},
+		{"until", model.HistoryFilter{Until: feb, Limit: 10}, []int64{3, 2, 1}},
+		{"since", model.HistoryFilter{Since: feb.AddDate(0, 0, 1), Limit: 10}, []int64{6, 5}},
+		{"amount", model.HistoryFilter{MinAmount: 200, MaxAmount: 400, Limit: 10}, []int64{4, 3, 2}},
+		{"wallet", model.HistoryFilter{WalletID: "c", Limit: 10}, []int64{}},
+	}
+	for _, tt := range tests {
+		found, err := a.FindTransactions(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("FindTransactions(%s): %v", tt.name, err)
+		}
+		if got := seqs(found); !slices.Equal(got, tt.want) {
+			t.Errorf("FindTransactions(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	found, _ := a.FindTransactions(ctx, model.HistoryFilter{Limit: 1})
+	if len(found) != 1 || found[0].Reason != "test" || !found[0].CreatedAt.Equal(feb.AddDate(0, 0, 2)) {
+		t.Errorf("archived transaction = %+v, want every field kept", found)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	a := archive.New(dir)
+	month := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	write(t, a, month, 1)
+
+	path := filepath.Join(dir, "transactions-2025-03.ndjson.gz")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read archive: %v", err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("failed to corrupt archive: %v", err)
+	}
+	if _, err := a.FindTransactions(ctx, model.HistoryFilter{Limit: 10}); err == nil {
+		t.Error("FindTransactions read a corrupted archive")
+	}
+
+	// Rewriting the month replaces the archive and its checksum.
+	write(t, a, month, 1, 2)
+	found, err := a.FindTransactions(ctx, model.HistoryFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("FindTransactions: %v", err)
+	}
+	if got := seqs(found); !slices.Equal(got, []int64{2, 1}) {
+		t.Errorf("FindTransactions = %v, want [2 1]", got)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	dir := t.TempDir()
+	a := archive.New(dir)
+	w, err := a.Create(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := w.Abort(); err != nil {
+		t.Fatalf("Abort: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("aborted archive left %d files behind", len(entries))
+	}
+}
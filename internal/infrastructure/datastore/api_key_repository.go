@@ -0,0 +1,154 @@
+package datastore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/samber/lo"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+)
+
+type apiKeyRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) repository.APIKeyRepository {
+	return &apiKeyRepositoryImpl{db: db}
+}
+
+func (r *apiKeyRepositoryImpl) Create(ctx context.Context, key *model.APIKey) (err error) {
+	ctx, span := startSpan(ctx, "api_keys.create")
+	defer func() { endSpan(span, err) }()
+
+	if key == nil {
+		return fmt.Errorf("api key cannot be nil")
+	}
+
+	row := toDBAPIKey(key)
+	_, err = conn(ctx, r.db).NamedExecContext(ctx, `
+        INSERT INTO api_keys (id, name, secret_hash, scopes, wallet_ids, created_at)
+        VALUES (:id, :name, :secret_hash, :scopes, :wallet_ids, :created_at)
+    `, row)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+func (r *apiKeyRepositoryImpl) FetchByID(ctx context.Context, id uuid.UUID) (_ *model.APIKey, err error) {
+	ctx, span := startSpan(ctx, "api_keys.fetch_by_id")
+	defer func() { endSpan(span, err) }()
+
+	var row dbAPIKey
+	err = conn(ctx, r.db).GetContext(ctx, &row, `
+        SELECT id, name, secret_hash, scopes, wallet_ids, created_at, rotated_at, revoked_at
+        FROM api_keys WHERE id = $1
+    `, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch api key: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *apiKeyRepositoryImpl) FetchAll(ctx context.Context) (_ []*model.APIKey, err error) {
+	ctx, span := startSpan(ctx, "api_keys.fetch_all")
+	defer func() { endSpan(span, err) }()
+
+	var rows []dbAPIKey
+	err = conn(ctx, r.db).SelectContext(ctx, &rows, `
+        SELECT id, name, secret_hash, scopes, wallet_ids, created_at, rotated_at, revoked_at
+        FROM api_keys ORDER BY created_at DESC
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch api keys: %w", err)
+	}
+	return lo.Map(rows, func(row dbAPIKey, _ int) *model.APIKey {
+		return row.toModel()
+	}), nil
+}
+
+func (r *apiKeyRepositoryImpl) UpdateSecret(ctx context.Context, id uuid.UUID, secretHash []byte) (err error) {
+	ctx, span := startSpan(ctx, "api_keys.update_secret")
+	defer func() { endSpan(span, err) }()
+
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE api_keys SET secret_hash = $2, rotated_at = NOW() WHERE id = $1 AND revoked_at IS NULL`,
+		id, secretHash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to rotate api key: %w", err)
+	}
+	return requireAffected(res, model.ErrAPIKeyNotFound)
+}
+
+func (r *apiKeyRepositoryImpl) Revoke(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "api_keys.revoke")
+	defer func() { endSpan(span, err) }()
+
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return requireAffected(res, model.ErrAPIKeyNotFound)
+}
+
+// requireAffected returns notFound when an UPDATE or DELETE matched no rows.
+func requireAffected(res sql.Result, notFound error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
+
+type dbAPIKey struct {
+	ID         uuid.UUID      `db:"id"`
+	Name       string         `db:"name"`
+	SecretHash []byte         `db:"secret_hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	WalletIDs  pq.StringArray `db:"wallet_ids"`
+	CreatedAt  time.Time      `db:"created_at"`
+	RotatedAt  *time.Time     `db:"rotated_at"`
+	RevokedAt  *time.Time     `db:"revoked_at"`
+}
+
+func toDBAPIKey(key *model.APIKey) dbAPIKey {
+	return dbAPIKey{
+		ID:         key.ID,
+		Name:       key.Name,
+		SecretHash: key.SecretHash,
+		Scopes:     lo.Map(key.Scopes, func(s model.Scope, _ int) string { return string(s) }),
+		WalletIDs:  lo.Map(key.WalletIDs, func(id uuid.UUID, _ int) string { return id.String() }),
+		CreatedAt:  key.CreatedAt,
+		RotatedAt:  key.RotatedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+func (row dbAPIKey) toModel() *model.APIKey {
+	return &model.APIKey{
+		ID:         row.ID,
+		Name:       row.Name,
+		SecretHash: row.SecretHash,
+		Scopes:     lo.Map(row.Scopes, func(s string, _ int) model.Scope { return model.Scope(s) }),
+		WalletIDs:  lo.Map(row.WalletIDs, func(id string, _ int) uuid.UUID { return uuid.MustParse(id) }),
+		CreatedAt:  row.CreatedAt,
+		RotatedAt:  row.RotatedAt,
+		RevokedAt:  row.RevokedAt,
+	}
+}
@@ -0,0 +1,110 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+)
+
+type chainRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewChainRepository returns the chain repository. Its reads go to the primary:
+// the chain is verified against the head, which a lagging replica would not match.
+func NewChainRepository(db *sqlx.DB) repository.ChainRepository {
+	return &chainRepositoryImpl{db: db}
+}
+
+func (r *chainRepositoryImpl) Head(ctx context.Context) (_ model.ChainHead, err error) {
+	ctx, span := startSpan(ctx, "transaction_chain_head.get")
+	defer func() { endSpan(span, err) }()
+
+	var head dbChainHead
+	if err := conn(ctx, r.db).GetContext(ctx, &head, `SELECT seq, hash, count FROM transaction_chain_head WHERE id = 1`); err != nil {
+		return model.ChainHead{}, fmt.Errorf("failed to fetch chain head: %w", err)
+	}
+	return model.ChainHead(head), nil
+}
+
+func (r *chainRepositoryImpl) ArchivedHead(ctx context.Context) (_ model.ChainHead, err error) {
+	ctx, span := startSpan(ctx, "archived_chain.get")
+	defer func() { endSpan(span, err) }()
+
+	var head dbChainHead
+	err = conn(ctx, r.db).GetContext(ctx, &head, `
+        SELECT COALESCE((SELECT seq FROM archived_chain ORDER BY seq DESC LIMIT 1), 0) AS seq,
+               COALESCE((SELECT hash FROM archived_chain ORDER BY seq DESC LIMIT 1), '') AS hash,
+               COALESCE((SELECT SUM(count) FROM archived_chain), 0) AS count
+    `)
+	if err != nil {
+		return model.ChainHead{}, fmt.Errorf("failed to fetch archived chain head: %w", err)
+	}
+	return model.ChainHead(head), nil
+}
+
+func (r *chainRepositoryImpl) SaveCheckpoint(ctx context.Context, checkpoint model.Checkpoint) (err error) {
+	ctx, span := startSpan(ctx, "chain_checkpoints.save")
+	defer func() { endSpan(span, err) }()
+
+	_, err = conn(ctx, r.db).NamedExecContext(ctx, `
+        INSERT INTO chain_checkpoints (seq, count, hash, created_at, key_id, signature)
+        VALUES (:seq, :count, :hash, :created_at, :key_id, :signature)
+        ON CONFLICT (seq) DO NOTHING
+    `, toDBCheckpoint(checkpoint))
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (r *chainRepositoryImpl) Checkpoints(ctx context.Context, limit int) (_ []model.Checkpoint, err error) {
+	ctx, span := startSpan(ctx, "chain_checkpoints.list")
+	defer func() { endSpan(span, err) }()
+
+	var rows []dbCheckpoint
+	err = conn(ctx, r.db).SelectContext(ctx, &rows, `
+        SELECT seq, count, hash, created_at, key_id, signature FROM chain_checkpoints
+        ORDER BY seq DESC LIMIT $1
+    `, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch checkpoints: %w", err)
+	}
+	return lo.Map(rows, func(row dbCheckpoint, _ int) model.Checkpoint {
+		return row.toModel()
+	}), nil
+}
+
+type dbChainHead struct {
+	Seq   int64  `db:"seq"`
+	Hash  string `db:"hash"`
+	Count int64  `db:"count"`
+}
+
+type dbCheckpoint struct {
+	Seq       int64     `db:"seq"`
+	Count     int64     `db:"count"`
+	Hash      string    `db:"hash"`
+	CreatedAt time.Time `db:"created_at"`
+	KeyID     string    `db:"key_id"`
+	Signature []byte    `db:"signature"`
+}
+
+func toDBCheckpoint(c model.Checkpoint) dbCheckpoint {
+	return dbCheckpoint{
+		Seq: c.Seq, Count: c.Count, Hash: c.Hash, CreatedAt: c.CreatedAt.UTC(),
+		KeyID: c.KeyID, Signature: c.Signature,
+	}
+}
+
+func (c dbCheckpoint) toModel() model.Checkpoint {
+	return model.Checkpoint{
+		ChainHead: model.ChainHead{Seq: c.Seq, Hash: c.Hash, Count: c.Count},
+		CreatedAt: c.CreatedAt.UTC(), KeyID: c.KeyID, Signature: c.Signature,
+	}
+}
@@ -0,0 +1,44 @@
+package datastore_test
+
+import (
+	"os"
+	"testing"
+	"transaction-service/internal/domain/repository/repositorytest"
+	"transaction-service/internal/infrastructure/datastore"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TestConformance runs against the migrated Postgres database at TEST_DATABASE_DSN,
+// which it empties before every test.
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Backend {
+		if _, err := db.Exec(`TRUNCATE wallets, transactions, archived_transfer_totals, archived_transfer_pairs, archived_chain, chain_checkpoints, api_keys, risk_decisions, sanctions_whitelist, sanctions_cases, wallet_status_changes, wallet_limits, limit_tiers, service_state RESTART IDENTITY`); err != nil {
+			t.Fatalf("failed to empty database: %v", err)
+		}
+		if _, err := db.Exec(`UPDATE transaction_chain_head SET seq = 0, hash = '', count = 0`); err != nil {
+			t.Fatalf("failed to reset chain head: %v", err)
+		}
+		return repositorytest.Backend{
+			Wallets:      datastore.NewWalletRepositoryImpl(db, nil),
+			Transactions: datastore.NewTransactionRepository(db, nil),
+			APIKeys:      datastore.NewAPIKeyRepository(db),
+			Chain:        datastore.NewChainRepository(db),
+			Risk:         datastore.NewRiskRepository(db),
+			Sanctions:    datastore.NewSanctionsRepository(db),
+			Limits:       datastore.NewLimitRepository(db),
+			Statuses:     datastore.NewStatusRepository(db),
+			Transactor:   datastore.NewTransactor(db),
+		}
+	})
+}
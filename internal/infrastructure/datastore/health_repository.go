@@ -0,0 +1,44 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type healthRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewHealthRepository(db *sqlx.DB) repository.HealthRepository {
+	return &healthRepositoryImpl{db: db}
+}
+
+func (h *healthRepositoryImpl) Ping(ctx context.Context) error {
+	if err := h.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
+
+// schemaVersionQuery reads the goose version table. A version counts as applied
+// unless a later row rolled it back.
+const schemaVersionQuery = `
+    SELECT COALESCE(MAX(v.version_id), 0) FROM goose_db_version v
+    WHERE v.is_applied AND NOT EXISTS (
+        SELECT 1 FROM goose_db_version d
+        WHERE d.version_id = v.version_id AND d.id > v.id AND NOT d.is_applied
+    )
+`
+
+func (h *healthRepositoryImpl) SchemaVersion(ctx context.Context) (version int64, err error) {
+	ctx, span := startSpan(ctx, "goose_db_version.schema_version")
+	defer func() { endSpan(span, err) }()
+
+	if err = h.db.GetContext(ctx, &version, schemaVersionQuery); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
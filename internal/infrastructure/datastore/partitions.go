@@ -0,0 +1,278 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// partitionLockID is the advisory lock held while maintaining partitions, so only
+// one replica does it at a time.
+const partitionLockID = 0x74786e5f70617274
+
+// defaultPartition catches transactions of months without a partition of their own.
+const defaultPartition = "transactions_default"
+
+// PartitionPolicy configures Partitions.
+type PartitionPolicy struct {
+	Ahead     int           // Months partitioned beyond the current one
+	Retention int           // Months kept in the log before archiving, 0 keeps every month
+	Interval  time.Duration // Time between maintenance runs
+}
+
+// Partitions maintains the monthly partitions of the transactions table. Partitions
+// are created ahead of time; once a month is older than the retention period its
+// transactions are written to the archive and its partition is detached. Detached
+// partitions are left in the database, to be dropped once the archive is backed up.
+type Partitions struct {
+	db      *sqlx.DB
+	archive repository.TransactionArchive
+	policy  PartitionPolicy
+}
+
+func NewPartitions(db *sqlx.DB, archive repository.TransactionArchive, policy PartitionPolicy) *Partitions {
+	return &Partitions{db: db, archive: archive, policy: policy}
+}
+
+// Run maintains the partitions every policy.Interval until ctx is done. Failures
+// are logged and retried on the next run.
+func (p *Partitions) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Maintain(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to maintain transaction partitions", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Maintain creates the partitions of the month of now and the policy.Ahead months
+// after it, then archives the months older than the retention period. It does
+// nothing while another replica maintains the partitions.
+func (p *Partitions) Maintain(ctx context.Context, now time.Time) (err error) {
+	ctx, span := startSpan(ctx, "transactions.maintain_partitions")
+	defer func() { endSpan(span, err) }()
+
+	lockConn, err := p.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer lockConn.Close()
+
+	var locked bool
+	if err := lockConn.GetContext(ctx, &locked, `SELECT pg_try_advisory_lock($1)`, partitionLockID); err != nil {
+		return fmt.Errorf("failed to lock partitions: %w", err)
+	}
+	if !locked {
+		return nil
+	}
+	defer lockConn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, partitionLockID)
+
+	current := monthOf(now)
+	for n := range p.policy.Ahead + 1 {
+		if err := p.create(ctx, current.AddDate(0, n, 0)); err != nil {
+			return err
+		}
+	}
+	if p.policy.Retention <= 0 {
+		return nil
+	}
+
+	months, err := p.attached(ctx)
+	if err != nil {
+		return err
+	}
+	cutoff := current.AddDate(0, -p.policy.Retention, 0)
+	for _, month := range months {
+		if !month.Before(cutoff) {
+			break
+		}
+		if err := p.archiveMonth(ctx, month); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// create adds the partition of the month unless it exists. Transactions of the
+// month already caught by the default partition are moved into it.
+func (p *Partitions) create(ctx context.Context, month time.Time) error {
+	name := partitionName(month)
+	var exists bool
+	if err := p.db.GetContext(ctx, &exists, `SELECT to_regclass($1) IS NOT NULL`, name); err != nil {
+		return fmt.Errorf("failed to look up partition %s: %w", name, err)
+	}
+	if exists {
+		return nil
+	}
+
+	from, to := month, month.AddDate(0, 1, 0)
+	create := fmt.Sprintf(`CREATE TABLE %s PARTITION OF transactions FOR VALUES FROM ('%s') TO ('%s')`,
+		name, from.Format(time.DateOnly), to.Format(time.DateOnly))
+
+	err := NewTransactor(p.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := conn(ctx, p.db)
+		inRange := ` WHERE created_at >= $1 AND created_at < $2`
+		var stranded bool
+		err := tx.GetContext(ctx, &stranded, `SELECT EXISTS (SELECT 1 FROM `+defaultPartition+inRange+`)`, from, to)
+		if err != nil {
+			return err
+		}
+		if !stranded {
+			_, err := tx.ExecContext(ctx, create)
+			return err
+		}
+
+		// A partition cannot be added while the default one holds rows in its range.
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE transactions DETACH PARTITION `+defaultPartition); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, create); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO `+name+` SELECT * FROM `+defaultPartition+inRange, from, to); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+defaultPartition+inRange, from, to); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `ALTER TABLE transactions ATTACH PARTITION `+defaultPartition+` DEFAULT`)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create partition %s: %w", name, err)
+	}
+	slog.InfoContext(ctx, "transaction partition created", "partition", name)
+	return nil
+}
+
+// attached returns the first day of every month with an attached partition, oldest first.
+func (p *Partitions) attached(ctx context.Context) ([]time.Time, error) {
+	var names []string
+	err := p.db.SelectContext(ctx, &names, `
+        SELECT c.relname FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid
+        WHERE i.inhparent = 'transactions'::regclass
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	var months []time.Time
+	for _, name := range names {
+		var year, month int
+		if _, err := fmt.Sscanf(name, "transactions_y%04dm%02d", &year, &month); err != nil {
+			continue // The default partition
+		}
+		months = append(months, time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC))
+	}
+	slices.SortFunc(months, time.Time.Compare)
+	return months, nil
+}
+
+// archiveMonth writes the transactions of the month to the archive, then detaches
+// its partition. The transfer totals of the month are kept in the same transaction
+// as the detach, so Totals counts every transfer exactly once.
+func (p *Partitions) archiveMonth(ctx context.Context, month time.Time) (err error) {
+	name := partitionName(month)
+	ctx, span := startSpan(ctx, "transactions.archive_partition")
+	defer func() { endSpan(span, err) }()
+
+	w, err := p.archive.Create(month)
+	if err != nil {
+		return err
+	}
+	defer w.Abort()
+
+	rows, err := p.db.QueryxContext(ctx, `SELECT `+transactionColumns+` FROM `+name+` ORDER BY seq`)
+	if err != nil {
+		return fmt.Errorf("failed to read partition %s: %w", name, err)
+	}
+	defer rows.Close()
+	count := 0
+	for rows.Next() {
+		var transaction dbTransaction
+		if err := rows.StructScan(&transaction); err != nil {
+			return fmt.Errorf("failed to read partition %s: %w", name, err)
+		}
+		if err := w.Write(model.Transaction(transaction)); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read partition %s: %w", name, err)
+	}
+	if err := w.Commit(); err != nil {
+		return err
+	}
+
+	err = NewTransactor(p.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := conn(ctx, p.db)
+		_, err := tx.ExecContext(ctx, `
+            INSERT INTO archived_transfer_totals (wallet_id, sent, received)
+            SELECT wallet_id, SUM(sent), SUM(received) FROM (
+                SELECT "from" AS wallet_id, amount AS sent, 0 AS received FROM `+name+`
+                UNION ALL
+                SELECT "to", 0, amount FROM `+name+`
+            ) AS flows
+            GROUP BY wallet_id
+            ON CONFLICT (wallet_id) DO UPDATE SET
+                sent = archived_transfer_totals.sent + EXCLUDED.sent,
+                received = archived_transfer_totals.received + EXCLUDED.received
+        `)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO archived_transfer_pairs (from_id, to_id)
+            SELECT DISTINCT "from", "to" FROM `+name+`
+            ON CONFLICT DO NOTHING
+        `)
+		if err != nil {
+			return err
+		}
+		// The chain of the remaining log links to the last hash archived.
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO archived_chain (month, seq, hash, count)
+            SELECT $1,
+                   COALESCE((SELECT seq FROM `+name+` WHERE hash IS NOT NULL ORDER BY seq DESC LIMIT 1), 0),
+                   COALESCE((SELECT hash FROM `+name+` WHERE hash IS NOT NULL ORDER BY seq DESC LIMIT 1), ''),
+                   (SELECT COUNT(*) FROM `+name+` WHERE hash IS NOT NULL)
+        `, month)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `ALTER TABLE transactions DETACH PARTITION `+name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detach partition %s: %w", name, err)
+	}
+	slog.InfoContext(ctx, "transaction partition archived and detached",
+		"partition", name, "transactions", count, "month", month.Format("2006-01"))
+	return nil
+}
+
+// monthOf returns the first day of the month of t, in UTC like created_at.
+func monthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// partitionName returns the name of the partition of the month, as created by the
+// migration partitioning the table.
+func partitionName(month time.Time) string {
+	return fmt.Sprintf("transactions_y%04dm%02d", month.Year(), int(month.Month()))
+}
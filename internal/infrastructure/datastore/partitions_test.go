@@ -0,0 +1,77 @@
+package datastore_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/infrastructure/archive"
+	"transaction-service/internal/infrastructure/datastore"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TestPartitionRetention runs against the migrated Postgres database at
+// TEST_DATABASE_DSN. It works in January 2020, long before any partition the
+// migration creates.
+func TestPartitionRetention(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	ctx := context.Background()
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec(`DROP TABLE IF EXISTS transactions_y2020m01, transactions_y2020m03`)
+		db.Close()
+	})
+	if _, err := db.Exec(`TRUNCATE transactions, archived_transfer_totals, archived_transfer_pairs, archived_chain RESTART IDENTITY`); err != nil {
+		t.Fatalf("failed to empty database: %v", err)
+	}
+
+	// Without a partition of its own the transfer lands in the default partition,
+	// and is moved once the partition is created.
+	_, err = db.Exec(`INSERT INTO transactions (id, "from", "to", amount, created_at, prev_hash, hash)
+        VALUES (gen_random_uuid(), 'alice', 'bob', 250, '2020-01-15', '', 'h1')`)
+	if err != nil {
+		t.Fatalf("failed to insert transaction: %v", err)
+	}
+	dir := t.TempDir()
+	partitions := datastore.NewPartitions(db, archive.New(dir), datastore.PartitionPolicy{Retention: 1})
+	if err := partitions.Maintain(ctx, time.Date(2020, time.January, 20, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Maintain(January): %v", err)
+	}
+	var count int
+	if err := db.Get(&count, `SELECT COUNT(*) FROM transactions_y2020m01`); err != nil || count != 1 {
+		t.Fatalf("January partition holds %d transactions, %v; want 1", count, err)
+	}
+
+	// In March, January is past the retention period of one month.
+	if err := partitions.Maintain(ctx, time.Date(2020, time.March, 5, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Maintain(March): %v", err)
+	}
+	if err := db.Get(&count, `SELECT COUNT(*) FROM transactions`); err != nil || count != 0 {
+		t.Errorf("transactions holds %d rows after archiving, %v; want 0", count, err)
+	}
+
+	transactions := datastore.NewTransactionRepository(db, nil)
+	totals, err := transactions.Totals(ctx, "alice")
+	if err != nil || totals != (model.TransferTotals{Sent: 250}) {
+		t.Errorf("Totals(alice) = %+v, %v; want the archived transfer", totals, err)
+	}
+	if found, err := transactions.HasTransferred(ctx, "alice", "bob"); err != nil || !found {
+		t.Errorf("HasTransferred(alice, bob) = %v, %v; want the archived transfer found", found, err)
+	}
+	head, err := datastore.NewChainRepository(db).ArchivedHead(ctx)
+	if err != nil || head != (model.ChainHead{Seq: 1, Hash: "h1", Count: 1}) {
+		t.Errorf("ArchivedHead = %+v, %v; want the January transfer", head, err)
+	}
+	archived, err := archive.New(dir).FindTransactions(ctx, model.HistoryFilter{Limit: 10})
+	if err != nil || len(archived) != 1 || archived[0].Amount != 250 {
+		t.Errorf("archive = %+v, %v; want the January transfer", archived, err)
+	}
+}
@@ -0,0 +1,168 @@
+package datastore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+	"transaction-service/internal/domain/model"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Replicas routes reads to read-only replicas of the primary database, round robin.
+// A replica is used only after passing a health check; one failing a check or a
+// query is taken out of rotation until a later check succeeds. With no replica
+// healthy, reads go to the primary.
+type Replicas struct {
+	replicas []*replica
+	next     atomic.Uint64
+	interval time.Duration
+}
+
+type replica struct {
+	name    string // Logged instead of the DSN, which may hold a password
+	db      *sqlx.DB
+	healthy atomic.Bool
+}
+
+// NewReplicas returns replicas checked every interval once Run is called. Until
+// then every read goes to the primary.
+func NewReplicas(interval time.Duration, dbs ...*sqlx.DB) *Replicas {
+	r := &Replicas{interval: interval}
+	for n, db := range dbs {
+		r.replicas = append(r.replicas, &replica{name: fmt.Sprintf("replica-%d", n+1), db: db})
+	}
+	return r
+}
+
+// OpenReplicas connects to the replicas at dsns. Connections are opened lazily, so
+// a replica that is down does not prevent startup.
+func OpenReplicas(dsns []string, interval time.Duration) (*Replicas, error) {
+	dbs := make([]*sqlx.DB, 0, len(dsns))
+	for n, dsn := range dsns {
+		db, err := sqlx.Open("postgres", dsn)
+		if err != nil {
+			for _, db := range dbs {
+				db.Close()
+			}
+			return nil, fmt.Errorf("failed to open database replica #%d: %w", n+1, err)
+		}
+		dbs = append(dbs, db)
+	}
+	return NewReplicas(interval, dbs...), nil
+}
+
+// Run checks the health of every replica until ctx is done.
+func (r *Replicas) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.check(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Healthy returns the number of replicas in rotation.
+func (r *Replicas) Healthy() int {
+	healthy := 0
+	for _, rep := range r.replicas {
+		if rep.healthy.Load() {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// Close closes the connections to every replica.
+func (r *Replicas) Close() error {
+	var errs []error
+	for _, rep := range r.replicas {
+		errs = append(errs, rep.db.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Replicas) check(ctx context.Context) {
+	for _, rep := range r.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, r.interval)
+		err := rep.db.PingContext(pingCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			r.markDown(ctx, rep, err)
+			continue
+		}
+		if !rep.healthy.Swap(true) {
+			slog.InfoContext(ctx, "database replica up", "replica", rep.name)
+		}
+	}
+}
+
+func (r *Replicas) markDown(ctx context.Context, rep *replica, err error) {
+	if rep.healthy.Swap(false) {
+		slog.WarnContext(ctx, "database replica down, reading from the primary", "replica", rep.name, "error", err)
+	}
+}
+
+// pick returns the next healthy replica, or nil when there is none.
+func (r *Replicas) pick() *replica {
+	if r == nil || len(r.replicas) == 0 {
+		return nil
+	}
+	start := r.next.Add(1)
+	for n := range uint64(len(r.replicas)) {
+		rep := r.replicas[(start+n)%uint64(len(r.replicas))]
+		if rep.healthy.Load() {
+			return rep
+		}
+	}
+	return nil
+}
+
+// read runs fn on the transaction carried by ctx or, outside of one, on a healthy
+// replica unless ctx asks for a consistent read. A replica failing with anything
+// but an error reported by the server is taken out of rotation and fn runs again
+// on the primary.
+func read(ctx context.Context, db *sqlx.DB, replicas *Replicas, fn func(q querier) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok || model.ConsistentReadFromContext(ctx) {
+		return fn(conn(ctx, db))
+	}
+	rep := replicas.pick()
+	if rep == nil {
+		return fn(db)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("db.replica", rep.name))
+	err := fn(rep.db)
+	if err == nil || !isConnectionError(ctx, err) {
+		return err
+	}
+	replicas.markDown(ctx, rep, err)
+	span.SetAttributes(attribute.String("db.replica", "primary"))
+	return fn(db)
+}
+
+// isConnectionError reports whether err means the database could not be reached,
+// rather than that it rejected or found nothing for the query.
+func isConnectionError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+	var pqErr *pq.Error
+	return !errors.As(err, &pqErr)
+}
@@ -0,0 +1,102 @@
+package datastore_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/infrastructure/datastore"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+// TestReplicaRouting stands SQLite databases in for the primary and a replica, with
+// a different number of wallets in each, to tell which one a read went to.
+func TestReplicaRouting(t *testing.T) {
+	ctx := context.Background()
+	primary, replica := openWallets(t, "primary", 1), openWallets(t, "replica", 2)
+
+	replicas := datastore.NewReplicas(10*time.Millisecond, replica)
+	wallets := datastore.NewWalletRepositoryImpl(primary, replicas)
+	transactor := datastore.NewTransactor(primary)
+	count := func(ctx context.Context) int {
+		t.Helper()
+		all, err := wallets.FetchAll(ctx)
+		if err != nil {
+			t.Fatalf("FetchAll: %v", err)
+		}
+		return len(all)
+	}
+
+	if n := count(ctx); n != 1 {
+		t.Errorf("read before the first health check went to the replica")
+	}
+
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	go replicas.Run(runCtx)
+	waitFor(t, func() bool { return replicas.Healthy() == 1 })
+
+	if n := count(ctx); n != 2 {
+		t.Errorf("read with a healthy replica went to the primary")
+	}
+	if n := count(model.WithConsistentRead(ctx)); n != 1 {
+		t.Errorf("consistent read went to the replica")
+	}
+	err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if n := count(ctx); n != 1 {
+			t.Errorf("read in a transaction went to the replica")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithinTransaction: %v", err)
+	}
+
+	// A replica failing a query is taken out of rotation at once, without waiting
+	// for the next health check.
+	replica.Close()
+	if n := count(ctx); n != 1 {
+		t.Errorf("read after the replica failed = %d wallets, want the primary's 1", n)
+	}
+	if healthy := replicas.Healthy(); healthy != 0 {
+		t.Errorf("%d replicas healthy after the replica failed, want 0", healthy)
+	}
+}
+
+// openWallets opens a database with a wallets table holding n wallets.
+func openWallets(t *testing.T, name string, n int) *sqlx.DB {
+	t.Helper()
+	db, err := sqlx.Open("sqlite", filepath.Join(t.TempDir(), name+".db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE wallets (
+        id TEXT PRIMARY KEY, amount INTEGER NOT NULL, owner_id TEXT, owner_name TEXT, status TEXT NOT NULL DEFAULT 'active',
+        version INTEGER NOT NULL DEFAULT 0, system INTEGER NOT NULL DEFAULT 0)`)
+	if err != nil {
+		t.Fatalf("failed to create wallets: %v", err)
+	}
+	for range n {
+		if _, err := db.Exec(`INSERT INTO wallets (id, amount) VALUES ($1, 0)`, uuid.NewString()); err != nil {
+			t.Fatalf("failed to insert wallet: %v", err)
+		}
+	}
+	return db
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 5s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
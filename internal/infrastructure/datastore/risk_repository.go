@@ -0,0 +1,103 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type riskRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewRiskRepository returns the risk decision repository. Its reads go to the
+// primary: decisions are looked up right after the transfer they were made on.
+func NewRiskRepository(db *sqlx.DB) repository.RiskRepository {
+	return &riskRepositoryImpl{db: db}
+}
+
+func (r *riskRepositoryImpl) SaveDecision(ctx context.Context, decision model.RiskDecision) (err error) {
+	ctx, span := startSpan(ctx, "risk_decisions.save")
+	defer func() { endSpan(span, err) }()
+
+	row, err := toDBRiskDecision(decision)
+	if err != nil {
+		return err
+	}
+	_, err = conn(ctx, r.db).NamedExecContext(ctx, `
+        INSERT INTO risk_decisions (id, created_at, "from", "to", amount, action, fired, request_id)
+        VALUES (:id, :created_at, :from, :to, :amount, :action, :fired, NULLIF(:request_id, ''))
+    `, row)
+	if err != nil {
+		return fmt.Errorf("failed to save risk decision: %w", err)
+	}
+	return nil
+}
+
+func (r *riskRepositoryImpl) FindDecisions(ctx context.Context, filter model.RiskDecisionFilter) (_ []model.RiskDecision, err error) {
+	ctx, span := startSpan(ctx, "risk_decisions.find")
+	defer func() { endSpan(span, err) }()
+
+	var rows []dbRiskDecision
+	err = conn(ctx, r.db).SelectContext(ctx, &rows, `
+        SELECT id, created_at, "from", "to", amount, action, fired, COALESCE(request_id, '') AS request_id
+        FROM risk_decisions
+        WHERE ($1 = '' OR "from" = $1 OR "to" = $1)
+          AND ($2 = '' OR action = $2)
+          AND ($3::timestamp IS NULL OR created_at >= $3)
+          AND ($4::timestamp IS NULL OR created_at < $4)
+        ORDER BY created_at DESC
+        LIMIT $5
+    `, filter.WalletID, filter.Action, nullTime(filter.Since), nullTime(filter.Until), filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch risk decisions: %w", err)
+	}
+
+	decisions := make([]model.RiskDecision, len(rows))
+	for n, row := range rows {
+		if decisions[n], err = row.toModel(); err != nil {
+			return nil, err
+		}
+	}
+	return decisions, nil
+}
+
+// dbRiskDecision holds the fired rules as a JSON array.
+type dbRiskDecision struct {
+	ID        uuid.UUID `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	From      string    `db:"from"`
+	To        string    `db:"to"`
+	Amount    int       `db:"amount"`
+	Action    string    `db:"action"`
+	Fired     string    `db:"fired"`
+	RequestID string    `db:"request_id"`
+}
+
+func toDBRiskDecision(d model.RiskDecision) (dbRiskDecision, error) {
+	fired, err := json.Marshal(d.Fired)
+	if err != nil {
+		return dbRiskDecision{}, fmt.Errorf("failed to encode fired rules: %w", err)
+	}
+	return dbRiskDecision{
+		ID: d.ID, CreatedAt: d.CreatedAt.UTC(), From: d.From, To: d.To, Amount: d.Amount,
+		Action: string(d.Action), Fired: string(fired), RequestID: d.RequestID,
+	}, nil
+}
+
+func (row dbRiskDecision) toModel() (model.RiskDecision, error) {
+	var fired []model.FiredRule
+	if err := json.Unmarshal([]byte(row.Fired), &fired); err != nil {
+		return model.RiskDecision{}, fmt.Errorf("failed to decode fired rules of risk decision %s: %w", row.ID, err)
+	}
+	return model.RiskDecision{
+		ID: row.ID, CreatedAt: row.CreatedAt.UTC(), From: row.From, To: row.To, Amount: row.Amount,
+		Action: model.RiskAction(row.Action), Fired: fired, RequestID: row.RequestID,
+	}, nil
+}
@@ -0,0 +1,220 @@
+package datastore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type sanctionsRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewSanctionsRepository returns the repository of sanctions cases. Its reads go
+// to the primary: a case is reviewed right after the transfer it blocked.
+func NewSanctionsRepository(db *sqlx.DB) repository.SanctionsRepository {
+	return &sanctionsRepositoryImpl{db: db}
+}
+
+func (r *sanctionsRepositoryImpl) CreateCase(ctx context.Context, c model.SanctionsCase) (err error) {
+	ctx, span := startSpan(ctx, "sanctions_cases.create")
+	defer func() { endSpan(span, err) }()
+
+	row, err := toDBSanctionsCase(c)
+	if err != nil {
+		return err
+	}
+	_, err = conn(ctx, r.db).NamedExecContext(ctx, `
+        INSERT INTO sanctions_cases (id, created_at, "from", "to", amount, hits, status, note, resolved_at, request_id)
+        VALUES (:id, :created_at, :from, :to, :amount, :hits, :status, :note, :resolved_at, NULLIF(:request_id, ''))
+    `, row)
+	if err != nil {
+		return fmt.Errorf("failed to create sanctions case: %w", err)
+	}
+	return nil
+}
+
+func (r *sanctionsRepositoryImpl) FetchCase(ctx context.Context, id uuid.UUID) (_ model.SanctionsCase, err error) {
+	ctx, span := startSpan(ctx, "sanctions_cases.fetch")
+	defer func() { endSpan(span, err) }()
+
+	var row dbSanctionsCase
+	err = conn(ctx, r.db).GetContext(ctx, &row, `SELECT `+sanctionsCaseColumns+` FROM sanctions_cases WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.SanctionsCase{}, model.ErrCaseNotFound
+	}
+	if err != nil {
+		return model.SanctionsCase{}, fmt.Errorf("failed to fetch sanctions case: %w", err)
+	}
+	return row.toModel()
+}
+
+func (r *sanctionsRepositoryImpl) ResolveCase(ctx context.Context, id uuid.UUID, status model.CaseStatus, note string, resolvedAt time.Time) (err error) {
+	ctx, span := startSpan(ctx, "sanctions_cases.resolve")
+	defer func() { endSpan(span, err) }()
+
+	q := conn(ctx, r.db)
+	res, err := q.ExecContext(ctx,
+		`UPDATE sanctions_cases SET status = $1, note = $2, resolved_at = $3 WHERE id = $4 AND status = $5`,
+		status, note, resolvedAt.UTC(), id, model.CaseOpen,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to resolve sanctions case: %w", err)
+	}
+	if requireAffected(res, model.ErrCaseResolved) == nil {
+		return nil
+	}
+	var current string
+	err = q.GetContext(ctx, &current, `SELECT status FROM sanctions_cases WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ErrCaseNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch sanctions case: %w", err)
+	}
+	return fmt.Errorf("%w: it is %s", model.ErrCaseResolved, current)
+}
+
+func (r *sanctionsRepositoryImpl) FindCases(ctx context.Context, filter model.CaseFilter) (_ []model.SanctionsCase, err error) {
+	ctx, span := startSpan(ctx, "sanctions_cases.find")
+	defer func() { endSpan(span, err) }()
+
+	var rows []dbSanctionsCase
+	err = conn(ctx, r.db).SelectContext(ctx, &rows, `
+        SELECT `+sanctionsCaseColumns+`
+        FROM sanctions_cases
+        WHERE ($1 = '' OR "from" = $1 OR "to" = $1)
+          AND ($2 = '' OR status = $2)
+        ORDER BY created_at DESC
+        LIMIT $3
+    `, filter.WalletID, filter.Status, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sanctions cases: %w", err)
+	}
+
+	cases := make([]model.SanctionsCase, len(rows))
+	for n, row := range rows {
+		if cases[n], err = row.toModel(); err != nil {
+			return nil, err
+		}
+	}
+	return cases, nil
+}
+
+func (r *sanctionsRepositoryImpl) AddToWhitelist(ctx context.Context, entries ...model.WhitelistEntry) (err error) {
+	ctx, span := startSpan(ctx, "sanctions_whitelist.add")
+	defer func() { endSpan(span, err) }()
+
+	for _, entry := range entries {
+		_, err := conn(ctx, r.db).ExecContext(ctx, `
+            INSERT INTO sanctions_whitelist (wallet_id, entry, case_id, reason, actor, created_at)
+            VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+            ON CONFLICT (wallet_id, entry) DO NOTHING
+        `, entry.WalletID, entry.Entry, nullUUID(entry.CaseID), entry.Reason, entry.Actor, entry.CreatedAt.UTC())
+		if err != nil {
+			return fmt.Errorf("failed to whitelist: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *sanctionsRepositoryImpl) Whitelist(ctx context.Context, walletID string) (_ []model.WhitelistEntry, err error) {
+	ctx, span := startSpan(ctx, "sanctions_whitelist.find")
+	defer func() { endSpan(span, err) }()
+
+	var rows []struct {
+		WalletID  string        `db:"wallet_id"`
+		Entry     string        `db:"entry"`
+		CaseID    uuid.NullUUID `db:"case_id"`
+		Reason    string        `db:"reason"`
+		Actor     string        `db:"actor"`
+		CreatedAt time.Time     `db:"created_at"`
+	}
+	err = conn(ctx, r.db).SelectContext(ctx, &rows, `
+        SELECT wallet_id, entry, case_id, reason, COALESCE(actor, '') AS actor, created_at
+        FROM sanctions_whitelist
+        WHERE $1 = '' OR wallet_id = $1
+        ORDER BY created_at, wallet_id, entry
+    `, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch whitelist: %w", err)
+	}
+
+	entries := make([]model.WhitelistEntry, len(rows))
+	for n, row := range rows {
+		entries[n] = model.WhitelistEntry{
+			WalletID: row.WalletID, Entry: row.Entry, CaseID: row.CaseID.UUID,
+			Reason: row.Reason, Actor: row.Actor, CreatedAt: row.CreatedAt.UTC(),
+		}
+	}
+	return entries, nil
+}
+
+func (r *sanctionsRepositoryImpl) RemoveFromWhitelist(ctx context.Context, walletID, entry string) (err error) {
+	ctx, span := startSpan(ctx, "sanctions_whitelist.remove")
+	defer func() { endSpan(span, err) }()
+
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM sanctions_whitelist WHERE wallet_id = $1 AND entry = $2`,
+		walletID, entry,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove from whitelist: %w", err)
+	}
+	return requireAffected(res, model.ErrNotWhitelisted)
+}
+
+// nullUUID passes uuid.Nil as NULL, for the whitelist entries added by hand.
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+const sanctionsCaseColumns = `id, created_at, "from", "to", amount, hits, status, note, resolved_at, COALESCE(request_id, '') AS request_id`
+
+// dbSanctionsCase holds the hits as a JSON array.
+type dbSanctionsCase struct {
+	ID         uuid.UUID  `db:"id"`
+	CreatedAt  time.Time  `db:"created_at"`
+	From       string     `db:"from"`
+	To         string     `db:"to"`
+	Amount     int        `db:"amount"`
+	Hits       string     `db:"hits"`
+	Status     string     `db:"status"`
+	Note       string     `db:"note"`
+	ResolvedAt *time.Time `db:"resolved_at"`
+	RequestID  string     `db:"request_id"`
+}
+
+func toDBSanctionsCase(c model.SanctionsCase) (dbSanctionsCase, error) {
+	hits, err := json.Marshal(c.Hits)
+	if err != nil {
+		return dbSanctionsCase{}, fmt.Errorf("failed to encode screening hits: %w", err)
+	}
+	return dbSanctionsCase{
+		ID: c.ID, CreatedAt: c.CreatedAt.UTC(), From: c.From, To: c.To, Amount: c.Amount, Hits: string(hits),
+		Status: string(c.Status), Note: c.Note, ResolvedAt: nullTime(c.ResolvedAt), RequestID: c.RequestID,
+	}, nil
+}
+
+func (row dbSanctionsCase) toModel() (model.SanctionsCase, error) {
+	var hits []model.ScreeningHit
+	if err := json.Unmarshal([]byte(row.Hits), &hits); err != nil {
+		return model.SanctionsCase{}, fmt.Errorf("failed to decode screening hits of sanctions case %s: %w", row.ID, err)
+	}
+	c := model.SanctionsCase{
+		ID: row.ID, CreatedAt: row.CreatedAt.UTC(), From: row.From, To: row.To, Amount: row.Amount, Hits: hits,
+		Status: model.CaseStatus(row.Status), Note: row.Note, RequestID: row.RequestID,
+	}
+	if row.ResolvedAt != nil {
+		c.ResolvedAt = row.ResolvedAt.UTC()
+	}
+	return c, nil
+}
@@ -0,0 +1,30 @@
+package datastore
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("transaction-service/internal/infrastructure/datastore")
+
+// startSpan starts a client span for a database statement. The statement name,
+// e.g. "wallets.fetch_by_id", is both the span name and db.operation.name.
+func startSpan(ctx context.Context, statement string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, statement,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(statement)),
+	)
+}
+
+// endSpan marks the span as failed when err is set and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
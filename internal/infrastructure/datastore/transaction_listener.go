@@ -0,0 +1,154 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+	"transaction-service/internal/domain/service"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/samber/lo"
+)
+
+// transactionsChannel is the Postgres NOTIFY channel the transactions table trigger writes to.
+const transactionsChannel = "transactions_created"
+
+const (
+	// gapTimeout is how long a skipped seq is looked for. The sequence hands out
+	// seq before the transaction commits, so a transaction may become visible after
+	// one with a higher seq; seqs of rolled back transactions never do.
+	gapTimeout = time.Minute
+
+	// maxGaps bounds the skipped seqs looked for at once, the lowest are given up first.
+	maxGaps = 10000
+)
+
+// TransactionListener relays transactions committed by any replica to the local broker.
+// It listens on Postgres NOTIFY and reads new rows by sequence number, so missed or
+// coalesced notifications are caught up on the next one. Seqs skipped over are
+// looked for again until gapTimeout, so transactions committed out of seq order
+// are published late rather than lost.
+type TransactionListener struct {
+	db     *sqlx.DB
+	dsn    string
+	repo   repository.TransactionRepository
+	broker service.TransactionBroker
+
+	last int64               // Highest seq published
+	gaps map[int64]time.Time // Skipped seqs below last, by when they were skipped
+}
+
+func NewTransactionListener(db *sqlx.DB, dsn string, broker service.TransactionBroker) *TransactionListener {
+	return &TransactionListener{
+		db:     db,
+		dsn:    dsn,
+		repo:   NewTransactionRepository(db, nil), // Replicas may lag the notifications
+		broker: broker,
+		gaps:   make(map[int64]time.Time),
+	}
+}
+
+// Run listens for new transactions until ctx is done.
+func (l *TransactionListener) Run(ctx context.Context) error {
+	if err := l.db.GetContext(ctx, &l.last, `SELECT COALESCE(MAX(seq), 0) FROM transactions`); err != nil {
+		return fmt.Errorf("failed to read transaction log head: %w", err)
+	}
+
+	listener := pq.NewListener(l.dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Warn("transaction listener connection event", "event", event, "error", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(transactionsChannel); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", transactionsChannel, err)
+	}
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-listener.Notify:
+			// A nil notification means the connection was re-established; catch up either way.
+		case <-ticker.C:
+			if err := listener.Ping(); err != nil {
+				slog.Warn("transaction listener ping failed", "error", err)
+			}
+		}
+
+		if err := l.catchUp(ctx, time.Now()); err != nil {
+			slog.Error("transaction listener catch-up failed", "last_seq", l.last, "error", err)
+		}
+	}
+}
+
+func (l *TransactionListener) catchUp(ctx context.Context, now time.Time) error {
+	if err := l.fillGaps(ctx, now); err != nil {
+		return err
+	}
+	for {
+		batch, err := l.repo.GetTransactionsAfter(ctx, l.last, model.TransactionFilter{}, 500)
+		if err != nil {
+			return err
+		}
+		for _, transaction := range batch {
+			for seq := max(l.last+1, transaction.Seq-maxGaps); seq < transaction.Seq; seq++ {
+				l.gaps[seq] = now
+			}
+			l.broker.Publish(transaction)
+			l.last = transaction.Seq
+		}
+		l.trimGaps()
+		if len(batch) < 500 {
+			return nil
+		}
+	}
+}
+
+// fillGaps publishes the transactions committed since under skipped seqs and gives
+// up on seqs skipped for longer than gapTimeout.
+func (l *TransactionListener) fillGaps(ctx context.Context, now time.Time) error {
+	var seqs []int64
+	for seq, skipped := range l.gaps {
+		if now.Sub(skipped) > gapTimeout {
+			delete(l.gaps, seq)
+		} else {
+			seqs = append(seqs, seq)
+		}
+	}
+	if len(seqs) == 0 {
+		return nil
+	}
+
+	var transactions []dbTransaction
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE seq = ANY($1) ORDER BY seq ASC`
+	if err := l.db.SelectContext(ctx, &transactions, query, pq.Array(seqs)); err != nil {
+		return fmt.Errorf("failed to fetch skipped transactions: %w", err)
+	}
+	for _, transaction := range transactions {
+		delete(l.gaps, transaction.Seq)
+		l.broker.Publish(model.Transaction(transaction))
+	}
+	return nil
+}
+
+// trimGaps gives up on the lowest skipped seqs beyond maxGaps.
+func (l *TransactionListener) trimGaps() {
+	if len(l.gaps) <= maxGaps {
+		return
+	}
+	seqs := lo.Keys(l.gaps)
+	slices.Sort(seqs)
+	for _, seq := range seqs[:len(seqs)-maxGaps] {
+		delete(l.gaps, seq)
+	}
+}
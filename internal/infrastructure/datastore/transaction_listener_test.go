@@ -0,0 +1,78 @@
+package datastore_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/service"
+	"transaction-service/internal/infrastructure/datastore"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TestTransactionListenerLateCommit runs against the migrated Postgres database at
+// TEST_DATABASE_DSN. A transaction committed after one with a higher seq must
+// still reach the broker.
+func TestTransactionListenerLateCommit(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`TRUNCATE transactions RESTART IDENTITY`); err != nil {
+		t.Fatalf("failed to empty database: %v", err)
+	}
+
+	broker := service.NewTransactionBroker()
+	live, _, unsubscribe := broker.Subscribe(model.TransactionFilter{})
+	defer unsubscribe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := datastore.NewTransactionListener(db, dsn, broker).Run(ctx); err != nil {
+			t.Errorf("Run: %v", err)
+		}
+	}()
+	t.Cleanup(func() { cancel(); <-done })
+	time.Sleep(100 * time.Millisecond) // Let the listener read the log head and listen
+
+	insert := `INSERT INTO transactions (id, "from", "to", amount, created_at)
+        VALUES (gen_random_uuid(), 'alice', 'bob', $1, now())`
+	late, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("failed to begin: %v", err)
+	}
+	if _, err := late.Exec(insert, 100); err != nil {
+		t.Fatalf("failed to insert the late transaction: %v", err)
+	}
+	if _, err := db.Exec(insert, 200); err != nil {
+		t.Fatalf("failed to insert the early transaction: %v", err)
+	}
+	if got := receive(t, live); got.Amount != 200 || got.Seq != 2 {
+		t.Fatalf("first published = %+v; want amount 200 at seq 2", got)
+	}
+	if err := late.Commit(); err != nil {
+		t.Fatalf("failed to commit the late transaction: %v", err)
+	}
+	if got := receive(t, live); got.Amount != 100 || got.Seq != 1 {
+		t.Fatalf("second published = %+v; want amount 100 at seq 1", got)
+	}
+}
+
+func receive(t *testing.T, live <-chan model.Transaction) model.Transaction {
+	t.Helper()
+	select {
+	case transaction := <-live:
+		return transaction
+	case <-time.After(5 * time.Second):
+		t.Fatal("no transaction published")
+		return model.Transaction{}
+	}
+}
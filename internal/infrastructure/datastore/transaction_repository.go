@@ -5,78 +5,150 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/samber/lo"
+	"log/slog"
 	"time"
 	"transaction-service/internal/domain/model"
 	"transaction-service/internal/domain/repository"
 )
 
+// transactionColumns selects a dbTransaction.
+const transactionColumns = `id, "from", "to", amount, created_at, seq,
+    COALESCE(request_id, '') AS request_id, COALESCE(reason, '') AS reason,
+    COALESCE(prev_hash, '') AS prev_hash, COALESCE(hash, '') AS hash`
+
 type transactionRepositoryImpl struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	replicas *Replicas // Serves reads outside of transactions, nil for none
 }
 
-func NewTransactionRepository(db *sqlx.DB) repository.TransactionRepository {
-	return &transactionRepositoryImpl{db: db}
+func NewTransactionRepository(db *sqlx.DB, replicas *Replicas) repository.TransactionRepository {
+	return &transactionRepositoryImpl{db: db, replicas: replicas}
 }
 
-func (tr *transactionRepositoryImpl) BeginTransaction() (*sqlx.Tx, error) {
-	tx, err := tr.db.Beginx()
-	if err != nil {
-		return nil, err
-	}
-	return tx, nil
-}
+func (tr *transactionRepositoryImpl) Create(ctx context.Context, transaction *model.Transaction) (_ uuid.UUID, err error) {
+	ctx, span := startSpan(ctx, "transactions.create")
+	defer func() { endSpan(span, err) }()
 
-func (tr *transactionRepositoryImpl) Create(ctx context.Context, transaction *model.Transaction) (uuid.UUID, error) {
 	if transaction == nil {
 		return uuid.Nil, fmt.Errorf("transaction cannot be nil")
 	}
 
-	tx, err := tr.BeginTransaction()
+	// The chain head is locked until the transaction commits, so transactions are
+	// chained one at a time in the order of their seq.
+	err = NewTransactor(tr.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		q := conn(ctx, tr.db)
+		var head string
+		if err := q.GetContext(ctx, &head, `SELECT hash FROM transaction_chain_head WHERE id = 1 FOR UPDATE`); err != nil {
+			return fmt.Errorf("failed to lock chain head: %w", err)
+		}
+		if err := q.GetContext(ctx, &transaction.Seq, `SELECT nextval('transactions_seq_seq')`); err != nil {
+			return fmt.Errorf("failed to assign seq: %w", err)
+		}
+		// Postgres keeps microseconds, so the time hashed is the time stored.
+		transaction.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+		transaction.Link(head)
+
+		_, err := q.ExecContext(ctx, `
+            INSERT INTO transactions (id, "from", "to", amount, created_at, seq, request_id, reason, prev_hash, hash)
+            VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9, $10)
+        `,
+			transaction.ID, transaction.From, transaction.To, transaction.Amount, transaction.CreatedAt,
+			transaction.Seq, transaction.RequestID, transaction.Reason, transaction.PrevHash, transaction.Hash,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute query: %w", err)
+		}
+		_, err = q.ExecContext(ctx, `
+            UPDATE transaction_chain_head SET seq = $1, hash = $2, count = count + 1 WHERE id = 1
+        `, transaction.Seq, transaction.Hash)
+		if err != nil {
+			return fmt.Errorf("failed to advance chain head: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return uuid.Nil, err
 	}
-	defer tx.Rollback()
+	id := transaction.ID
+	slog.DebugContext(ctx, "transaction stored",
+		"transaction_id", id, "from_wallet", transaction.From, "to_wallet", transaction.To, "amount_cents", transaction.Amount)
+
+	return id, nil
+}
 
+func (tr *transactionRepositoryImpl) GetTransactions(ctx context.Context, walletIDs []string) (_ []model.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions.get_transactions")
+	defer func() { endSpan(span, err) }()
+
+	var transactions []dbTransaction
 	query := `
-        INSERT INTO transactions (id, "from", "to", amount, created_at) 
-        VALUES (:id, :from, :to, :amount, NOW()) 
-        RETURNING id
+        SELECT ` + transactionColumns + ` FROM transactions
+        WHERE ($1::text[] IS NULL OR "from" = ANY($1) OR "to" = ANY($1))
+        ORDER BY created_at DESC
+        LIMIT 100
     `
-	stmt, err := tx.PrepareNamed(query)
-	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to prepare query: %w", err)
-	}
-	defer stmt.Close()
-
-	var id uuid.UUID
-	err = stmt.GetContext(ctx, &id, map[string]interface{}{
-		"id":     transaction.ID,
-		"from":   transaction.From,
-		"to":     transaction.To,
-		"amount": transaction.Amount,
+	err = read(ctx, tr.db, tr.replicas, func(q querier) error {
+		return q.SelectContext(ctx, &transactions, query, pq.Array(walletIDs))
 	})
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to execute query: %w", err)
-	}
-
-	if err := tx.Commit(); err != nil {
-		return uuid.Nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
 	}
 
-	return id, nil
+	return lo.Map(transactions, func(transaction dbTransaction, _ int) model.Transaction {
+		return model.Transaction(transaction)
+	}), nil
 }
 
-func (tr *transactionRepositoryImpl) GetTransactions(ctx context.Context) ([]model.Transaction, error) {
-	conn, err := tr.db.Connx(ctx)
+func (tr *transactionRepositoryImpl) GetTransactionsAfter(ctx context.Context, seq int64, filter model.TransactionFilter, limit int) (_ []model.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions.get_transactions_after")
+	defer func() { endSpan(span, err) }()
+
+	var transactions []dbTransaction
+	query := `
+        SELECT ` + transactionColumns + ` FROM transactions
+        WHERE seq > $1 AND ($2 = '' OR "from" = $2 OR "to" = $2)
+        ORDER BY seq ASC
+        LIMIT $3
+    `
+	err = read(ctx, tr.db, tr.replicas, func(q querier) error {
+		return q.SelectContext(ctx, &transactions, query, seq, filter.WalletID, limit)
+	})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
 	}
-	defer conn.Close()
+
+	return lo.Map(transactions, func(transaction dbTransaction, _ int) model.Transaction {
+		return model.Transaction(transaction)
+	}), nil
+}
+
+func (tr *transactionRepositoryImpl) FindTransactions(ctx context.Context, filter model.HistoryFilter) (_ []model.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions.find_transactions")
+	defer func() { endSpan(span, err) }()
 
 	var transactions []dbTransaction
-	query := `SELECT id, "from", "to", amount, created_at FROM transactions ORDER BY created_at DESC LIMIT 100`
-	if err := conn.SelectContext(ctx, &transactions, query); err != nil {
+	query := `
+        SELECT ` + transactionColumns + ` FROM transactions
+        WHERE ($1 = '' OR "from" = $1 OR "to" = $1)
+          AND ($2::timestamp IS NULL OR created_at >= $2)
+          AND ($3::timestamp IS NULL OR created_at < $3)
+          AND ($4 = 0 OR amount >= $4)
+          AND ($5 = 0 OR amount <= $5)
+          AND ($6 = 0 OR seq < $6)
+          AND ($8::text[] IS NULL OR "from" = ANY($8) OR "to" = ANY($8))
+        ORDER BY seq DESC
+        LIMIT $7
+    `
+	err = read(ctx, tr.db, tr.replicas, func(q querier) error {
+		return q.SelectContext(ctx, &transactions, query,
+			filter.WalletID, nullTime(filter.Since), nullTime(filter.Until),
+			filter.MinAmount, filter.MaxAmount, filter.BeforeSeq, filter.Limit,
+			pq.Array(filter.WalletIDs),
+		)
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
 	}
 
@@ -85,10 +157,105 @@ func (tr *transactionRepositoryImpl) GetTransactions(ctx context.Context) ([]mod
 	}), nil
 }
 
+func (tr *transactionRepositoryImpl) Totals(ctx context.Context, walletID string) (_ model.TransferTotals, err error) {
+	ctx, span := startSpan(ctx, "transactions.totals")
+	defer func() { endSpan(span, err) }()
+
+	var totals struct {
+		Sent     int64 `db:"sent"`
+		Received int64 `db:"received"`
+	}
+	query := `
+        SELECT COALESCE(SUM(amount) FILTER (WHERE "from" = $1), 0)
+                   + COALESCE((SELECT sent FROM archived_transfer_totals WHERE wallet_id = $1), 0) AS sent,
+               COALESCE(SUM(amount) FILTER (WHERE "to" = $1), 0)
+                   + COALESCE((SELECT received FROM archived_transfer_totals WHERE wallet_id = $1), 0) AS received
+        FROM transactions WHERE "from" = $1 OR "to" = $1
+    `
+	err = read(ctx, tr.db, tr.replicas, func(q querier) error {
+		return q.GetContext(ctx, &totals, query, walletID)
+	})
+	if err != nil {
+		return model.TransferTotals{}, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+	return model.TransferTotals{Sent: totals.Sent, Received: totals.Received}, nil
+}
+
+func (tr *transactionRepositoryImpl) Velocity(ctx context.Context, walletID string, since time.Time) (_ model.TransferVelocity, err error) {
+	ctx, span := startSpan(ctx, "transactions.velocity")
+	defer func() { endSpan(span, err) }()
+
+	var velocity struct {
+		Count  int   `db:"count"`
+		Amount int64 `db:"amount"`
+	}
+	query := `SELECT COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount FROM transactions WHERE "from" = $1 AND created_at >= $2`
+	err = read(ctx, tr.db, tr.replicas, func(q querier) error {
+		return q.GetContext(ctx, &velocity, query, walletID, since.UTC())
+	})
+	if err != nil {
+		return model.TransferVelocity{}, fmt.Errorf("failed to count transactions: %w", err)
+	}
+	return model.TransferVelocity(velocity), nil
+}
+
+func (tr *transactionRepositoryImpl) HasTransferred(ctx context.Context, fromID, toID string) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "transactions.has_transferred")
+	defer func() { endSpan(span, err) }()
+
+	var found bool
+	query := `SELECT EXISTS (SELECT 1 FROM transactions WHERE "from" = $1 AND "to" = $2)
+        OR EXISTS (SELECT 1 FROM archived_transfer_pairs WHERE from_id = $1 AND to_id = $2)`
+	err = read(ctx, tr.db, tr.replicas, func(q querier) error {
+		return q.GetContext(ctx, &found, query, fromID, toID)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to look up transactions: %w", err)
+	}
+	return found, nil
+}
+
+func (tr *transactionRepositoryImpl) ArchivedTotals(ctx context.Context) (_ map[string]model.TransferTotals, err error) {
+	ctx, span := startSpan(ctx, "transactions.archived_totals")
+	defer func() { endSpan(span, err) }()
+
+	var rows []struct {
+		WalletID string `db:"wallet_id"`
+		Sent     int64  `db:"sent"`
+		Received int64  `db:"received"`
+	}
+	err = read(ctx, tr.db, tr.replicas, func(q querier) error {
+		return q.SelectContext(ctx, &rows, `SELECT wallet_id, sent, received FROM archived_transfer_totals`)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch archived totals: %w", err)
+	}
+
+	totals := make(map[string]model.TransferTotals, len(rows))
+	for _, row := range rows {
+		totals[row.WalletID] = model.TransferTotals{Sent: row.Sent, Received: row.Received}
+	}
+	return totals, nil
+}
+
+// nullTime passes a zero time as NULL and any other in UTC, the zone created_at is stored in.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
 type dbTransaction struct {
 	ID        uuid.UUID `db:"id"`
 	From      string    `db:"from"`
 	To        string    `db:"to"`
 	Amount    int       `db:"amount"`
 	CreatedAt time.Time `db:"created_at"`
+	Seq       int64     `db:"seq"`
+	RequestID string    `db:"request_id"`
+	Reason    string    `db:"reason"`
+	PrevHash  string    `db:"prev_hash"`
+	Hash      string    `db:"hash"`
 }
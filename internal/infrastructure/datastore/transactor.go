@@ -0,0 +1,87 @@
+package datastore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Postgres error codes that mean the transaction lost a race and may be retried.
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+type txKey struct{}
+
+// querier is the part of *sqlx.DB and *sqlx.Tx the repositories use.
+type querier interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error)
+}
+
+// conn returns the transaction carried by ctx, or db outside of one.
+func conn(ctx context.Context, db *sqlx.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+type transactorImpl struct {
+	db *sqlx.DB
+}
+
+func NewTransactor(db *sqlx.DB) repository.Transactor {
+	return &transactorImpl{db: db}
+}
+
+func (t *transactorImpl) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	ctx, span := startSpan(ctx, "transaction")
+	defer func() { endSpan(span, err) }()
+
+	// READ COMMITTED, the default, gives each statement a snapshot of its own.
+	var opts *sql.TxOptions
+	if repository.SnapshotFromContext(ctx) {
+		opts = &sql.TxOptions{Isolation: sql.LevelRepeatableRead}
+	}
+	tx, err := t.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return translateError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return translateError(fmt.Errorf("failed to commit transaction: %w", err))
+	}
+	return nil
+}
+
+// translateError marks serialization failures and deadlocks as conflicts, which
+// callers may retry.
+func translateError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+			return fmt.Errorf("%w: %w", model.ErrConflict, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package datastore_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"transaction-service/internal/domain/repository"
+	"transaction-service/internal/infrastructure/datastore"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TestSnapshotTransaction runs against the migrated Postgres database at
+// TEST_DATABASE_DSN. A wallet committed between two reads of a snapshot
+// transaction is seen by neither, while a plain transaction sees it in the second.
+func TestSnapshotTransaction(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	wallets, transactor := datastore.NewWalletRepositoryImpl(db, nil), datastore.NewTransactor(db)
+	for _, tc := range []struct {
+		name     string
+		ctx      context.Context
+		wantSeen bool
+	}{
+		{"read committed", context.Background(), true},
+		{"snapshot", repository.WithSnapshot(context.Background()), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var before, after int
+			err := transactor.WithinTransaction(tc.ctx, func(ctx context.Context) error {
+				summary, err := wallets.Summarize(ctx)
+				if err != nil {
+					return err
+				}
+				before = summary.Count
+				if _, err := wallets.Create(context.Background()); err != nil {
+					return err
+				}
+				summary, err = wallets.Summarize(ctx)
+				after = summary.Count
+				return err
+			})
+			if err != nil {
+				t.Fatalf("WithinTransaction: %v", err)
+			}
+			if seen := after == before+1; seen != tc.wantSeen {
+				t.Errorf("wallets before and after a concurrent create = %d, %d; want it seen: %v", before, after, tc.wantSeen)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// walletsChannel is the Postgres NOTIFY channel replicas report written wallets on.
+const walletsChannel = "wallets_written"
+
+// walletsPerNotification keeps a payload of IDs under the 8000 byte NOTIFY limit.
+const walletsPerNotification = 200
+
+// WalletNotifier tells the wallet caches of the other replicas which wallets this
+// one wrote, over Postgres NOTIFY. Notifications carry the replica they came from,
+// so a replica skips its own.
+type WalletNotifier struct {
+	db     *sqlx.DB
+	dsn    string
+	origin string
+}
+
+func NewWalletNotifier(db *sqlx.DB, dsn string) *WalletNotifier {
+	return &WalletNotifier{db: db, dsn: dsn, origin: uuid.NewString()}
+}
+
+// Publish notifies the other replicas that the wallets were written. A failure is
+// only logged: their caches expire the wallets after the TTL.
+func (n *WalletNotifier) Publish(ids ...uuid.UUID) {
+	for start := 0; start < len(ids); start += walletsPerNotification {
+		payload := formatWalletNotification(n.origin, ids[start:min(start+walletsPerNotification, len(ids))])
+		if _, err := n.db.Exec(`SELECT pg_notify($1, $2)`, walletsChannel, payload); err != nil {
+			slog.Error("failed to notify replicas of written wallets", "error", err)
+		}
+	}
+}
+
+// Listener returns the worker passing the wallets other replicas wrote to invalidate.
+func (n *WalletNotifier) Listener(invalidate func(ids ...uuid.UUID)) *WalletListener {
+	return &WalletListener{notifier: n, invalidate: invalidate}
+}
+
+// WalletListener receives the wallets written by other replicas.
+type WalletListener struct {
+	notifier   *WalletNotifier
+	invalidate func(ids ...uuid.UUID)
+}
+
+// Run listens for written wallets until ctx is done. Notifications sent while the
+// connection is down are lost; the cache TTL bounds how long that leaves a wallet stale.
+func (l *WalletListener) Run(ctx context.Context) error {
+	listener := pq.NewListener(l.notifier.dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Warn("wallet listener connection event", "event", event, "error", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(walletsChannel); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", walletsChannel, err)
+	}
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			if notification == nil {
+				continue // Reconnected
+			}
+			origin, ids, err := parseWalletNotification(notification.Extra)
+			if err != nil {
+				slog.Warn("ignoring malformed wallet notification", "payload", notification.Extra, "error", err)
+				continue
+			}
+			if origin != l.notifier.origin {
+				l.invalidate(ids...)
+			}
+		case <-ticker.C:
+			if err := listener.Ping(); err != nil {
+				slog.Warn("wallet listener ping failed", "error", err)
+			}
+		}
+	}
+}
+
+// formatWalletNotification encodes the replica and the wallets it wrote as
+// "origin:id,id,...".
+func formatWalletNotification(origin string, ids []uuid.UUID) string {
+	var b strings.Builder
+	b.WriteString(origin)
+	b.WriteByte(':')
+	for i, id := range ids {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(id.String())
+	}
+	return b.String()
+}
+
+func parseWalletNotification(payload string) (origin string, ids []uuid.UUID, err error) {
+	origin, list, ok := strings.Cut(payload, ":")
+	if !ok {
+		return "", nil, fmt.Errorf("missing origin")
+	}
+	for _, s := range strings.Split(list, ",") {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return "", nil, err
+		}
+		ids = append(ids, id)
+	}
+	return origin, ids, nil
+}
@@ -0,0 +1,67 @@
+package datastore_test
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+	"time"
+	"transaction-service/internal/infrastructure/datastore"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// TestWalletNotifier relays written wallets between two replicas through the
+// Postgres database at TEST_DATABASE_DSN.
+func TestWalletNotifier(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	writer, reader := datastore.NewWalletNotifier(db, dsn), datastore.NewWalletNotifier(db, dsn)
+	written := make(chan []uuid.UUID, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	for _, n := range []*datastore.WalletNotifier{writer, reader} {
+		listener := n.Listener(func(ids ...uuid.UUID) { written <- ids })
+		go func() {
+			defer func() { done <- struct{}{} }()
+			if err := listener.Run(ctx); err != nil {
+				t.Errorf("Run: %v", err)
+			}
+		}()
+	}
+	t.Cleanup(func() { cancel(); <-done; <-done })
+	time.Sleep(100 * time.Millisecond) // Let the listeners connect
+
+	ids := make([]uuid.UUID, 250) // More than one notification holds
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	writer.Publish(ids...)
+
+	var got []uuid.UUID
+	for len(got) < len(ids) {
+		select {
+		case batch := <-written:
+			got = append(got, batch...)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d of %d written wallets", len(got), len(ids))
+		}
+	}
+	if !slices.Equal(got, ids) {
+		t.Errorf("received wallets differ from the written ones")
+	}
+	select {
+	case batch := <-written:
+		t.Errorf("received %d more wallets, want the writer to skip its own", len(batch))
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -2,156 +2,232 @@ package datastore
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"log/slog"
 	"transaction-service/internal/domain/model"
 	"transaction-service/internal/domain/repository"
 )
 
 type walletRepositoryImpl struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	replicas *Replicas // Serves reads outside of transactions, nil for none
 }
 
-func NewWalletRepositoryImpl(db *sqlx.DB) repository.WalletRepository {
-	return &walletRepositoryImpl{db: db}
+func NewWalletRepositoryImpl(db *sqlx.DB, replicas *Replicas) repository.WalletRepository {
+	return &walletRepositoryImpl{db: db, replicas: replicas}
 }
 
-func (w *walletRepositoryImpl) BeginTransaction() (*sqlx.Tx, error) {
-	tx, err := w.db.Beginx()
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	return tx, nil
-}
+func (w *walletRepositoryImpl) IsServiceInitialized(ctx context.Context) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "service_state.is_initialized")
+	defer func() { endSpan(span, err) }()
 
-func (w *walletRepositoryImpl) IsServiceInitialized(ctx context.Context) (bool, error) {
 	var count int
-	err := w.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM service_state WHERE key = 'initialized'`).Scan(&count)
+	err = conn(ctx, w.db).QueryRowxContext(ctx, `SELECT COUNT(*) FROM service_state WHERE key = 'initialized'`).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check service state: %w", err)
 	}
 	return count > 0, nil
 }
 
-func (w *walletRepositoryImpl) SetServiceInitialized(ctx context.Context) error {
-	_, err := w.db.ExecContext(ctx, `INSERT INTO service_state (key, value) VALUES ('initialized', 'true')`)
+func (w *walletRepositoryImpl) SetServiceInitialized(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "service_state.set_initialized")
+	defer func() { endSpan(span, err) }()
+
+	_, err = conn(ctx, w.db).ExecContext(ctx, `INSERT INTO service_state (key, value) VALUES ('initialized', 'true')`)
 	if err != nil {
 		return fmt.Errorf("failed to set service initialized: %w", err)
 	}
 	return nil
 }
 
-func (w *walletRepositoryImpl) Create(ctx context.Context) (uuid.UUID, error) {
-	tx, err := w.BeginTransaction()
-	if err != nil {
-		return uuid.Nil, err
-	}
-	defer tx.Rollback()
+func (w *walletRepositoryImpl) Create(ctx context.Context) (_ uuid.UUID, err error) {
+	ctx, span := startSpan(ctx, "wallets.create")
+	defer func() { endSpan(span, err) }()
 
 	var id uuid.UUID
-	err = tx.QueryRowxContext(
+	err = conn(ctx, w.db).QueryRowxContext(
 		ctx,
-		"INSERT INTO wallets (id, amount) VALUES ($1, $2) RETURNING id",
+		"INSERT INTO wallets (id, amount) VALUES ($1, 0) RETURNING id",
 		uuid.New(),
-		100,
 	).Scan(&id)
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, fmt.Errorf("failed to create wallet: %w", err)
 	}
+	return id, nil
+}
 
-	if err := tx.Commit(); err != nil {
-		return uuid.Nil, err
-	}
+func (w *walletRepositoryImpl) CreateSystem(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "wallets.create_system")
+	defer func() { endSpan(span, err) }()
 
-	return id, nil
+	_, err = conn(ctx, w.db).ExecContext(ctx,
+		`INSERT INTO wallets (id, amount, system) VALUES ($1, 0, true) ON CONFLICT (id) DO NOTHING`, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create system wallet: %w", err)
+	}
+	return nil
 }
 
-func (w *walletRepositoryImpl) FetchByID(ctx context.Context, id uuid.UUID) (*model.Wallet, error) {
+func (w *walletRepositoryImpl) FetchByID(ctx context.Context, id uuid.UUID) (_ *model.Wallet, err error) {
+	ctx, span := startSpan(ctx, "wallets.fetch_by_id")
+	defer func() { endSpan(span, err) }()
+
 	if id == uuid.Nil {
-		return nil, fmt.Errorf("invalid wallet ID")
+		return nil, model.ErrInvalidWalletID
 	}
 
 	var wallet dbWallet
-	query := `SELECT id, amount FROM wallets WHERE id = :id`
-	stmt, err := w.db.PrepareNamedContext(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	query := `SELECT id, amount, owner_id, owner_name, status, version, system FROM wallets WHERE id = :id`
+	err = read(ctx, w.db, w.replicas, func(q querier) error {
+		stmt, err := q.PrepareNamedContext(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to prepare query: %w", err)
+		}
+		defer stmt.Close()
+		return stmt.GetContext(ctx, &wallet, map[string]interface{}{"id": id})
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrWalletNotFound
 	}
-	defer stmt.Close()
-
-	err = stmt.GetContext(ctx, &wallet, map[string]interface{}{"id": id})
 	if err != nil {
-		return nil, fmt.Errorf("wallet not found: %w", err)
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
 	}
 
-	return &model.Wallet{ID: wallet.ID, Amount: wallet.Amount}, nil
+	return wallet.toModel(), nil
 }
 
-func (w *walletRepositoryImpl) Update(ctx context.Context, wallet *model.Wallet) (*model.Wallet, error) {
+func (w *walletRepositoryImpl) Update(ctx context.Context, wallet *model.Wallet) (_ *model.Wallet, err error) {
+	ctx, span := startSpan(ctx, "wallets.update")
+	defer func() { endSpan(span, err) }()
+
 	if wallet == nil || wallet.ID == uuid.Nil {
 		return nil, fmt.Errorf("invalid wallet data")
 	}
 
-	tx, err := w.BeginTransaction()
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback()
-
-	_, err = tx.NamedExecContext(ctx,
-		`UPDATE wallets SET amount = :amount WHERE id = :id`,
+	// Compare-and-swap on the version read by FetchByID: no rows means another
+	// transfer updated the wallet in between.
+	res, err := conn(ctx, w.db).NamedExecContext(ctx,
+		`UPDATE wallets SET amount = :amount, status = :status, version = version + 1 WHERE id = :id AND version = :version`,
 		map[string]interface{}{
-			"amount": wallet.Amount,
-			"id":     wallet.ID,
+			"amount":  wallet.Amount,
+			"status":  string(wallet.Status),
+			"id":      wallet.ID,
+			"version": wallet.Version,
 		},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update wallet: %w", err)
 	}
-
-	if err := tx.Commit(); err != nil {
-		return nil, err
+	if err := requireAffected(res, model.ErrConflict); err != nil {
+		return nil, fmt.Errorf("failed to update wallet %s at version %d: %w", wallet.ID, wallet.Version, err)
 	}
+	wallet.Version++
+	slog.DebugContext(ctx, "wallet balance updated", "wallet_id", wallet.ID, "amount_cents", wallet.Amount, "version", wallet.Version)
 
 	return wallet, nil
 }
 
-func (w *walletRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	tx, err := w.BeginTransaction()
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
+func (w *walletRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "wallets.delete")
+	defer func() { endSpan(span, err) }()
 
-	_, err = tx.ExecContext(ctx, `DELETE FROM wallets WHERE id = $1`, id)
+	_, err = conn(ctx, w.db).ExecContext(ctx, `DELETE FROM wallets WHERE id = $1`, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete wallet: %w", err)
 	}
+	return nil
+}
 
-	if err := tx.Commit(); err != nil {
-		return err
+func (w *walletRepositoryImpl) FetchAll(ctx context.Context) (_ []*model.Wallet, err error) {
+	ctx, span := startSpan(ctx, "wallets.fetch_all")
+	defer func() { endSpan(span, err) }()
+
+	var wallets []dbWallet
+	query := `SELECT id, amount, owner_id, owner_name, status, version, system FROM wallets WHERE NOT system`
+	err = read(ctx, w.db, w.replicas, func(q querier) error {
+		return q.SelectContext(ctx, &wallets, query)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallets: %w", err)
 	}
 
-	return nil
+	var result []*model.Wallet
+	for _, wallet := range wallets {
+		result = append(result, wallet.toModel())
+	}
+	return result, nil
 }
 
-func (w *walletRepositoryImpl) FetchAll(ctx context.Context) ([]*model.Wallet, error) {
+func (w *walletRepositoryImpl) FetchByOwner(ctx context.Context, ownerID string) (_ []*model.Wallet, err error) {
+	ctx, span := startSpan(ctx, "wallets.fetch_by_owner")
+	defer func() { endSpan(span, err) }()
+
 	var wallets []dbWallet
-	query := `SELECT id, amount FROM wallets`
-	err := w.db.SelectContext(ctx, &wallets, query)
+	query := `SELECT id, amount, owner_id, owner_name, status, version, system FROM wallets WHERE owner_id = $1 AND NOT system`
+	err = read(ctx, w.db, w.replicas, func(q querier) error {
+		return q.SelectContext(ctx, &wallets, query, ownerID)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch wallets: %w", err)
 	}
 
-	var result []*model.Wallet
+	result := make([]*model.Wallet, 0, len(wallets))
 	for _, wallet := range wallets {
-		result = append(result, &model.Wallet{ID: wallet.ID, Amount: wallet.Amount})
+		result = append(result, wallet.toModel())
 	}
 	return result, nil
 }
 
+func (w *walletRepositoryImpl) SetOwner(ctx context.Context, id uuid.UUID, ownerID, ownerName string) (err error) {
+	ctx, span := startSpan(ctx, "wallets.set_owner")
+	defer func() { endSpan(span, err) }()
+
+	res, err := conn(ctx, w.db).ExecContext(ctx,
+		`UPDATE wallets SET owner_id = NULLIF($2, ''), owner_name = NULLIF($3, '') WHERE id = $1`,
+		id, ownerID, ownerName,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set wallet owner: %w", err)
+	}
+	return requireAffected(res, model.ErrWalletNotFound)
+}
+
+func (w *walletRepositoryImpl) Summarize(ctx context.Context) (_ model.WalletSummary, err error) {
+	ctx, span := startSpan(ctx, "wallets.summarize")
+	defer func() { endSpan(span, err) }()
+
+	var summary struct {
+		Count       int   `db:"count"`
+		TotalAmount int64 `db:"total_amount"`
+	}
+	query := `SELECT COUNT(*) AS count, COALESCE(SUM(amount), 0) AS total_amount FROM wallets WHERE NOT system`
+	err = read(ctx, w.db, w.replicas, func(q querier) error {
+		return q.GetContext(ctx, &summary, query)
+	})
+	if err != nil {
+		return model.WalletSummary{}, fmt.Errorf("failed to summarize wallets: %w", err)
+	}
+	return model.WalletSummary{Count: summary.Count, TotalAmount: summary.TotalAmount}, nil
+}
+
 type dbWallet struct {
-	ID     uuid.UUID `db:"id"`
-	Amount int       `db:"amount"`
+	ID        uuid.UUID      `db:"id"`
+	Amount    int            `db:"amount"`
+	OwnerID   sql.NullString `db:"owner_id"`
+	OwnerName sql.NullString `db:"owner_name"`
+	Status    string         `db:"status"`
+	Version   int64          `db:"version"`
+	System    bool           `db:"system"`
+}
+
+func (w dbWallet) toModel() *model.Wallet {
+	return &model.Wallet{
+		ID: w.ID, Amount: w.Amount, OwnerID: w.OwnerID.String, OwnerName: w.OwnerName.String,
+		Status: model.WalletStatus(w.Status), Version: w.Version, System: w.System,
+	}
 }
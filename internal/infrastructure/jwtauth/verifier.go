@@ -0,0 +1,70 @@
+package jwtauth
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/service"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// signingMethods are the algorithms accepted in tokens; "none" and HMAC never are.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+type verifier struct {
+	keys     *KeySet
+	issuers  []string
+	audience string
+	parser   *jwt.Parser
+}
+
+// NewVerifier creates a TokenVerifier accepting tokens signed by a key from keys and
+// issued by one of issuers. When audience is set, tokens must be issued for it.
+func NewVerifier(keys *KeySet, issuers []string, audience string) service.TokenVerifier {
+	return &verifier{
+		keys:     keys,
+		issuers:  issuers,
+		audience: audience,
+		parser:   jwt.NewParser(jwt.WithValidMethods(signingMethods)),
+	}
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	Scope string `json:"scope"`
+	Admin bool   `json:"admin"`
+}
+
+func (v *verifier) Verify(token string) (*model.TokenClaims, error) {
+	var c claims
+	_, err := v.parser.ParseWithClaims(token, &c, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrUnauthenticated, err)
+	}
+
+	if !slices.Contains(v.issuers, c.Issuer) {
+		return nil, fmt.Errorf("%w: untrusted issuer %q", model.ErrUnauthenticated, c.Issuer)
+	}
+	if v.audience != "" && !c.VerifyAudience(v.audience, true) {
+		return nil, fmt.Errorf("%w: token not issued for this audience", model.ErrUnauthenticated)
+	}
+	if c.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: token has no expiry", model.ErrUnauthenticated)
+	}
+	if c.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", model.ErrUnauthenticated)
+	}
+
+	result := &model.TokenClaims{Subject: c.Subject, Admin: c.Admin}
+	for _, s := range strings.Fields(c.Scope) {
+		if scope, ok := model.ParseScope(s); ok {
+			result.Scopes = append(result.Scopes, scope)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+// Package logging configures structured slog logging. Records logged with a context
+// carry the request ID and trace ID found in it, so callers only add their own fields.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"transaction-service/internal/domain/model"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Formats selectable with New.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Field names shared by every log record.
+const (
+	FieldRequestID = "request_id"
+	FieldTraceID   = "trace_id"
+)
+
+// New creates a logger writing records of at least the given level (debug, info,
+// warn or error) in the given format (json or text) to w.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	return slog.New(contextHandler{handler}), nil
+}
+
+// contextHandler adds the request and trace IDs of the record's context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := model.RequestIDFromContext(ctx); id != "" {
+		r.AddAttrs(slog.String(FieldRequestID, id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		r.AddAttrs(slog.String(FieldTraceID, sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
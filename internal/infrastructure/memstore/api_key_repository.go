@@ -0,0 +1,105 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type apiKeyRepositoryImpl struct {
+	store *Store
+}
+
+func NewAPIKeyRepository(store *Store) repository.APIKeyRepository {
+	return &apiKeyRepositoryImpl{store: store}
+}
+
+func (r *apiKeyRepositoryImpl) Create(ctx context.Context, key *model.APIKey) error {
+	if key == nil {
+		return fmt.Errorf("api key cannot be nil")
+	}
+
+	err := r.store.write(ctx, func(t *tx) error {
+		if _, ok := t.apiKey(key.ID); ok {
+			return fmt.Errorf("api key %s already exists", key.ID)
+		}
+		t.apiKeys[key.ID] = cloneAPIKey(*key)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+func (r *apiKeyRepositoryImpl) FetchByID(ctx context.Context, id uuid.UUID) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.store.read(ctx, func(t *tx) error {
+		var ok bool
+		if key, ok = t.apiKey(id); !ok {
+			return model.ErrAPIKeyNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	key = cloneAPIKey(key)
+	return &key, nil
+}
+
+func (r *apiKeyRepositoryImpl) FetchAll(ctx context.Context) (result []*model.APIKey, err error) {
+	err = r.store.read(ctx, func(t *tx) error {
+		keys := t.allAPIKeys()
+		sort.SliceStable(keys, func(i, j int) bool {
+			return keys[i].CreatedAt.After(keys[j].CreatedAt)
+		})
+		for _, key := range keys {
+			key = cloneAPIKey(key)
+			result = append(result, &key)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (r *apiKeyRepositoryImpl) UpdateSecret(ctx context.Context, id uuid.UUID, secretHash []byte) error {
+	return r.updateActive(ctx, id, func(key *model.APIKey, now time.Time) {
+		key.SecretHash = slices.Clone(secretHash)
+		key.RotatedAt = &now
+	})
+}
+
+func (r *apiKeyRepositoryImpl) Revoke(ctx context.Context, id uuid.UUID) error {
+	return r.updateActive(ctx, id, func(key *model.APIKey, now time.Time) {
+		key.RevokedAt = &now
+	})
+}
+
+// updateActive applies fn to a key that has not been revoked.
+func (r *apiKeyRepositoryImpl) updateActive(ctx context.Context, id uuid.UUID, fn func(key *model.APIKey, now time.Time)) error {
+	return r.store.write(ctx, func(t *tx) error {
+		key, ok := t.apiKey(id)
+		if !ok || key.RevokedAt != nil {
+			return model.ErrAPIKeyNotFound
+		}
+		key = cloneAPIKey(key)
+		fn(&key, time.Now().UTC())
+		t.apiKeys[id] = key
+		return nil
+	})
+}
+
+// cloneAPIKey copies the slices of key, so callers cannot modify stored keys.
+func cloneAPIKey(key model.APIKey) model.APIKey {
+	key.SecretHash = slices.Clone(key.SecretHash)
+	key.Scopes = slices.Clone(key.Scopes)
+	key.WalletIDs = slices.Clone(key.WalletIDs)
+	return key
+}
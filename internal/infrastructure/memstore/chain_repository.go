@@ -0,0 +1,58 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+)
+
+type chainRepositoryImpl struct {
+	store *Store
+}
+
+func NewChainRepository(store *Store) repository.ChainRepository {
+	return &chainRepositoryImpl{store: store}
+}
+
+func (r *chainRepositoryImpl) Head(ctx context.Context) (head model.ChainHead, err error) {
+	err = r.store.read(ctx, func(t *tx) error {
+		head = t.chainHead()
+		return nil
+	})
+	return head, err
+}
+
+// ArchivedHead returns the zero head: the memory store is never archived.
+func (r *chainRepositoryImpl) ArchivedHead(context.Context) (model.ChainHead, error) {
+	return model.ChainHead{}, nil
+}
+
+func (r *chainRepositoryImpl) SaveCheckpoint(ctx context.Context, checkpoint model.Checkpoint) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		if _, ok := t.checkpoint(checkpoint.Seq); ok {
+			return nil
+		}
+		checkpoint.Signature = slices.Clone(checkpoint.Signature)
+		t.checkpoints[checkpoint.Seq] = checkpoint
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (r *chainRepositoryImpl) Checkpoints(ctx context.Context, limit int) (result []model.Checkpoint, err error) {
+	err = r.store.read(ctx, func(t *tx) error {
+		all := t.allCheckpoints()
+		for i := len(all) - 1; i >= 0 && len(result) < limit; i-- {
+			checkpoint := all[i]
+			checkpoint.Signature = slices.Clone(checkpoint.Signature)
+			result = append(result, checkpoint)
+		}
+		return nil
+	})
+	return result, err
+}
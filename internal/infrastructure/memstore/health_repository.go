@@ -0,0 +1,24 @@
+package memstore
+
+import (
+	"context"
+	"transaction-service/internal/domain/repository"
+)
+
+type healthRepositoryImpl struct {
+	schemaVersion int64
+}
+
+// NewHealthRepository returns a repository that is always reachable. The store has
+// no migrations, so it reports schemaVersion, the version of the running build.
+func NewHealthRepository(schemaVersion int64) repository.HealthRepository {
+	return &healthRepositoryImpl{schemaVersion: schemaVersion}
+}
+
+func (h *healthRepositoryImpl) Ping(context.Context) error {
+	return nil
+}
+
+func (h *healthRepositoryImpl) SchemaVersion(context.Context) (int64, error) {
+	return h.schemaVersion, nil
+}
@@ -0,0 +1,68 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type limitRepositoryImpl struct {
+	store *Store
+}
+
+func NewLimitRepository(store *Store) repository.LimitRepository {
+	return &limitRepositoryImpl{store: store}
+}
+
+func (r *limitRepositoryImpl) SaveTier(ctx context.Context, tier model.TierLimits) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		t.tiers[tier.Name] = tier
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save limit tier: %w", err)
+	}
+	return nil
+}
+
+func (r *limitRepositoryImpl) FetchTier(ctx context.Context, name string) (result model.TierLimits, err error) {
+	err = r.store.read(ctx, func(t *tx) error {
+		tier, ok := t.tier(name)
+		if !ok {
+			return model.ErrTierNotFound
+		}
+		result = tier
+		return nil
+	})
+	return result, err
+}
+
+func (r *limitRepositoryImpl) Tiers(ctx context.Context) (result []model.TierLimits, err error) {
+	err = r.store.read(ctx, func(t *tx) error {
+		result = t.allTiers()
+		return nil
+	})
+	return result, err
+}
+
+func (r *limitRepositoryImpl) SaveWalletLimits(ctx context.Context, limits model.WalletLimits) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		t.walletLimits[limits.WalletID] = limits
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save wallet limits: %w", err)
+	}
+	return nil
+}
+
+func (r *limitRepositoryImpl) FetchWalletLimits(ctx context.Context, walletID uuid.UUID) (result model.WalletLimits, err error) {
+	err = r.store.read(ctx, func(t *tx) error {
+		result = t.limitsOf(walletID)
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,24 @@
+package memstore_test
+
+import (
+	"testing"
+	"transaction-service/internal/domain/repository/repositorytest"
+	"transaction-service/internal/infrastructure/memstore"
+)
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Backend {
+		store := memstore.New()
+		return repositorytest.Backend{
+			Wallets:      memstore.NewWalletRepository(store),
+			Transactions: memstore.NewTransactionRepository(store),
+			APIKeys:      memstore.NewAPIKeyRepository(store),
+			Chain:        memstore.NewChainRepository(store),
+			Risk:         memstore.NewRiskRepository(store),
+			Sanctions:    memstore.NewSanctionsRepository(store),
+			Limits:       memstore.NewLimitRepository(store),
+			Statuses:     memstore.NewStatusRepository(store),
+			Transactor:   memstore.NewTransactor(store),
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+)
+
+type riskRepositoryImpl struct {
+	store *Store
+}
+
+func NewRiskRepository(store *Store) repository.RiskRepository {
+	return &riskRepositoryImpl{store: store}
+}
+
+func (r *riskRepositoryImpl) SaveDecision(ctx context.Context, decision model.RiskDecision) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		decision.Fired = slices.Clone(decision.Fired)
+		t.decisions = append(t.decisions, decision)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save risk decision: %w", err)
+	}
+	return nil
+}
+
+func (r *riskRepositoryImpl) FindDecisions(ctx context.Context, filter model.RiskDecisionFilter) (result []model.RiskDecision, err error) {
+	err = r.store.read(ctx, func(t *tx) error {
+		for _, decision := range slices.Backward(t.allDecisions()) {
+			if len(result) >= filter.Limit {
+				break
+			}
+			if filter.Matches(decision) {
+				decision.Fired = slices.Clone(decision.Fired)
+				result = append(result, decision)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,115 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type sanctionsRepositoryImpl struct {
+	store *Store
+}
+
+func NewSanctionsRepository(store *Store) repository.SanctionsRepository {
+	return &sanctionsRepositoryImpl{store: store}
+}
+
+func (r *sanctionsRepositoryImpl) CreateCase(ctx context.Context, c model.SanctionsCase) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		c.Hits = slices.Clone(c.Hits)
+		t.cases[c.ID] = c
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create sanctions case: %w", err)
+	}
+	return nil
+}
+
+func (r *sanctionsRepositoryImpl) FetchCase(ctx context.Context, id uuid.UUID) (result model.SanctionsCase, err error) {
+	err = r.store.read(ctx, func(t *tx) error {
+		c, ok := t.sanctionsCase(id)
+		if !ok {
+			return model.ErrCaseNotFound
+		}
+		c.Hits = slices.Clone(c.Hits)
+		result = c
+		return nil
+	})
+	return result, err
+}
+
+func (r *sanctionsRepositoryImpl) ResolveCase(ctx context.Context, id uuid.UUID, status model.CaseStatus, note string, resolvedAt time.Time) error {
+	return r.store.write(ctx, func(t *tx) error {
+		c, ok := t.sanctionsCase(id)
+		if !ok {
+			return model.ErrCaseNotFound
+		}
+		if c.Status != model.CaseOpen {
+			return fmt.Errorf("%w: it is %s", model.ErrCaseResolved, c.Status)
+		}
+		c.Status, c.Note, c.ResolvedAt = status, note, resolvedAt
+		t.cases[id] = c
+		return nil
+	})
+}
+
+func (r *sanctionsRepositoryImpl) FindCases(ctx context.Context, filter model.CaseFilter) (result []model.SanctionsCase, err error) {
+	err = r.store.read(ctx, func(t *tx) error {
+		for _, c := range slices.Backward(t.allCases()) {
+			if len(result) >= filter.Limit {
+				break
+			}
+			if filter.Matches(c) {
+				c.Hits = slices.Clone(c.Hits)
+				result = append(result, c)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (r *sanctionsRepositoryImpl) AddToWhitelist(ctx context.Context, entries ...model.WhitelistEntry) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		for _, entry := range entries {
+			key := whitelistKey{walletID: entry.WalletID, entry: entry.Entry}
+			if !t.whitelisted(key) {
+				t.whitelist[key] = &entry
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to whitelist: %w", err)
+	}
+	return nil
+}
+
+func (r *sanctionsRepositoryImpl) RemoveFromWhitelist(ctx context.Context, walletID, entry string) error {
+	return r.store.write(ctx, func(t *tx) error {
+		key := whitelistKey{walletID: walletID, entry: entry}
+		if !t.whitelisted(key) {
+			return model.ErrNotWhitelisted
+		}
+		t.whitelist[key] = nil
+		return nil
+	})
+}
+
+func (r *sanctionsRepositoryImpl) Whitelist(ctx context.Context, walletID string) (result []model.WhitelistEntry, err error) {
+	err = r.store.read(ctx, func(t *tx) error {
+		for _, entry := range t.allWhitelist() {
+			if walletID == "" || entry.WalletID == walletID {
+				result = append(result, entry)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
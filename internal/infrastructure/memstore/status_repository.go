@@ -0,0 +1,42 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type statusRepositoryImpl struct {
+	store *Store
+}
+
+func NewStatusRepository(store *Store) repository.StatusRepository {
+	return &statusRepositoryImpl{store: store}
+}
+
+func (r *statusRepositoryImpl) SaveChange(ctx context.Context, change model.StatusChange) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		t.statuses = append(t.statuses, change)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save status change: %w", err)
+	}
+	return nil
+}
+
+func (r *statusRepositoryImpl) FetchChanges(ctx context.Context, walletID uuid.UUID) ([]model.StatusChange, error) {
+	result := make([]model.StatusChange, 0)
+	err := r.store.read(ctx, func(t *tx) error {
+		for _, change := range t.allStatusChanges() {
+			if change.WalletID == walletID {
+				result = append(result, change)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
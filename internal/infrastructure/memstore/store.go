@@ -0,0 +1,457 @@
+// Package memstore implements the repository interfaces in process memory, for
+// local development and tests that should not need a database.
+//
+// Transactions are serialized: one runs at a time and sees a snapshot of the
+// committed state plus its own writes, which become visible to other callers only
+// when it commits. Writes made outside a transaction commit immediately.
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// Store holds the committed state shared by the memstore repositories.
+type Store struct {
+	// writer admits one transaction at a time. It is a channel rather than a mutex so
+	// waiting for it honours context cancellation.
+	writer chan struct{}
+
+	mu          sync.RWMutex
+	initialized bool
+	wallets     map[uuid.UUID]model.Wallet
+	apiKeys     map[uuid.UUID]model.APIKey
+	// transactions is in commit order, so the slice index is Seq-1.
+	transactions []model.Transaction
+	// committed is closed and replaced whenever transactions are committed.
+	committed    chan struct{}
+	checkpoints  map[int64]model.Checkpoint // By Seq
+	decisions    []model.RiskDecision       // In the order they were saved
+	cases        map[uuid.UUID]model.SanctionsCase
+	whitelist    map[whitelistKey]model.WhitelistEntry
+	tiers        map[string]model.TierLimits
+	walletLimits map[uuid.UUID]model.WalletLimits
+	statuses     []model.StatusChange // In the order they were saved
+}
+
+// whitelistKey identifies a whitelisted pair of wallet and watchlist entry.
+type whitelistKey struct {
+	walletID string
+	entry    string
+}
+
+// New returns an empty store.
+func New() *Store {
+	return &Store{
+		writer:       make(chan struct{}, 1),
+		wallets:      make(map[uuid.UUID]model.Wallet),
+		apiKeys:      make(map[uuid.UUID]model.APIKey),
+		committed:    make(chan struct{}),
+		checkpoints:  make(map[int64]model.Checkpoint),
+		cases:        make(map[uuid.UUID]model.SanctionsCase),
+		whitelist:    make(map[whitelistKey]model.WhitelistEntry),
+		tiers:        make(map[string]model.TierLimits),
+		walletLimits: make(map[uuid.UUID]model.WalletLimits),
+	}
+}
+
+type txKey struct{}
+
+// tx holds the writes of an open transaction until it commits. Rolling back
+// simply drops it.
+type tx struct {
+	store        *Store
+	initialized  bool
+	wallets      map[uuid.UUID]*model.Wallet // nil marks a deleted wallet
+	apiKeys      map[uuid.UUID]model.APIKey
+	transactions []model.Transaction
+	checkpoints  map[int64]model.Checkpoint
+	decisions    []model.RiskDecision
+	cases        map[uuid.UUID]model.SanctionsCase
+	whitelist    map[whitelistKey]*model.WhitelistEntry // nil marks a removed entry
+	tiers        map[string]model.TierLimits
+	walletLimits map[uuid.UUID]model.WalletLimits
+	statuses     []model.StatusChange
+}
+
+func (s *Store) begin() *tx {
+	return &tx{
+		store:        s,
+		wallets:      make(map[uuid.UUID]*model.Wallet),
+		apiKeys:      make(map[uuid.UUID]model.APIKey),
+		checkpoints:  make(map[int64]model.Checkpoint),
+		cases:        make(map[uuid.UUID]model.SanctionsCase),
+		whitelist:    make(map[whitelistKey]*model.WhitelistEntry),
+		tiers:        make(map[string]model.TierLimits),
+		walletLimits: make(map[uuid.UUID]model.WalletLimits),
+	}
+}
+
+func (s *Store) commit(t *tx) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.initialized = s.initialized || t.initialized
+	for id, wallet := range t.wallets {
+		if wallet == nil {
+			delete(s.wallets, id)
+			continue
+		}
+		s.wallets[id] = *wallet
+	}
+	for id, key := range t.apiKeys {
+		s.apiKeys[id] = key
+	}
+	if len(t.transactions) > 0 {
+		s.transactions = append(s.transactions, t.transactions...)
+		close(s.committed)
+		s.committed = make(chan struct{})
+	}
+	for seq, checkpoint := range t.checkpoints {
+		s.checkpoints[seq] = checkpoint
+	}
+	s.decisions = append(s.decisions, t.decisions...)
+	for id, c := range t.cases {
+		s.cases[id] = c
+	}
+	for key, entry := range t.whitelist {
+		if entry == nil {
+			delete(s.whitelist, key)
+			continue
+		}
+		s.whitelist[key] = *entry
+	}
+	for name, tier := range t.tiers {
+		s.tiers[name] = tier
+	}
+	for id, limits := range t.walletLimits {
+		s.walletLimits[id] = limits
+	}
+	s.statuses = append(s.statuses, t.statuses...)
+}
+
+// read runs fn against the transaction carried by ctx or, outside of one, against
+// the committed state.
+func (s *Store) read(ctx context.Context, fn func(t *tx) error) error {
+	if t, ok := ctx.Value(txKey{}).(*tx); ok && t.store == s {
+		return fn(t)
+	}
+	return fn(s.begin())
+}
+
+// write runs fn in the transaction carried by ctx or, outside of one, in a
+// transaction of its own.
+func (s *Store) write(ctx context.Context, fn func(t *tx) error) error {
+	return s.WithinTransaction(ctx, func(ctx context.Context) error {
+		return fn(ctx.Value(txKey{}).(*tx))
+	})
+}
+
+// Changed returns a channel closed the next time transactions are committed.
+func (s *Store) Changed() <-chan struct{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.committed
+}
+
+// NewTransactor returns the store itself, which runs the transactions of its repositories.
+func NewTransactor(store *Store) repository.Transactor {
+	return store
+}
+
+// WithinTransaction implements repository.Transactor.
+func (s *Store) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if t, ok := ctx.Value(txKey{}).(*tx); ok && t.store == s {
+		return fn(ctx)
+	}
+
+	select {
+	case s.writer <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to begin transaction: %w", ctx.Err())
+	}
+	defer func() { <-s.writer }()
+
+	t := s.begin()
+	if err := fn(context.WithValue(ctx, txKey{}, t)); err != nil {
+		return err
+	}
+	s.commit(t)
+	return nil
+}
+
+func (t *tx) isInitialized() bool {
+	if t.initialized {
+		return true
+	}
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	return t.store.initialized
+}
+
+func (t *tx) wallet(id uuid.UUID) (model.Wallet, bool) {
+	if wallet, ok := t.wallets[id]; ok {
+		if wallet == nil {
+			return model.Wallet{}, false
+		}
+		return *wallet, true
+	}
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	wallet, ok := t.store.wallets[id]
+	return wallet, ok
+}
+
+func (t *tx) putWallet(wallet model.Wallet) {
+	t.wallets[wallet.ID] = &wallet
+}
+
+// allWallets returns every visible wallet, ordered by ID.
+func (t *tx) allWallets() []model.Wallet {
+	t.store.mu.RLock()
+	wallets := make([]model.Wallet, 0, len(t.store.wallets)+len(t.wallets))
+	for id, wallet := range t.store.wallets {
+		if _, ok := t.wallets[id]; !ok {
+			wallets = append(wallets, wallet)
+		}
+	}
+	t.store.mu.RUnlock()
+
+	for _, wallet := range t.wallets {
+		if wallet != nil {
+			wallets = append(wallets, *wallet)
+		}
+	}
+	sort.Slice(wallets, func(i, j int) bool {
+		return wallets[i].ID.String() < wallets[j].ID.String()
+	})
+	return wallets
+}
+
+func (t *tx) apiKey(id uuid.UUID) (model.APIKey, bool) {
+	if key, ok := t.apiKeys[id]; ok {
+		return key, true
+	}
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	key, ok := t.store.apiKeys[id]
+	return key, ok
+}
+
+func (t *tx) allAPIKeys() []model.APIKey {
+	t.store.mu.RLock()
+	keys := make([]model.APIKey, 0, len(t.store.apiKeys)+len(t.apiKeys))
+	for id, key := range t.store.apiKeys {
+		if _, ok := t.apiKeys[id]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	t.store.mu.RUnlock()
+
+	for _, key := range t.apiKeys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// allTransactions returns the committed transactions followed by the ones
+// created in t, in Seq order. The result must not be modified.
+func (t *tx) allTransactions() []model.Transaction {
+	t.store.mu.RLock()
+	committed := t.store.transactions[:len(t.store.transactions):len(t.store.transactions)]
+	t.store.mu.RUnlock()
+
+	if len(t.transactions) == 0 {
+		return committed
+	}
+	return append(committed, t.transactions...)
+}
+
+func (t *tx) addTransaction(transaction model.Transaction) model.Transaction {
+	t.store.mu.RLock()
+	committed := len(t.store.transactions)
+	t.store.mu.RUnlock()
+
+	transaction.Seq = int64(committed + len(t.transactions) + 1)
+	transaction.CreatedAt = time.Now().UTC()
+	transaction.Link(t.chainHead().Hash)
+	t.transactions = append(t.transactions, transaction)
+	return transaction
+}
+
+// chainHead returns the last link of the hash chain, which every transaction of
+// the store is part of.
+func (t *tx) chainHead() model.ChainHead {
+	all := t.allTransactions()
+	if len(all) == 0 {
+		return model.ChainHead{}
+	}
+	last := all[len(all)-1]
+	return model.ChainHead{Seq: last.Seq, Hash: last.Hash, Count: int64(len(all))}
+}
+
+func (t *tx) checkpoint(seq int64) (model.Checkpoint, bool) {
+	if checkpoint, ok := t.checkpoints[seq]; ok {
+		return checkpoint, true
+	}
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	checkpoint, ok := t.store.checkpoints[seq]
+	return checkpoint, ok
+}
+
+// allCheckpoints returns every visible checkpoint, ordered by Seq.
+func (t *tx) allCheckpoints() []model.Checkpoint {
+	t.store.mu.RLock()
+	checkpoints := make([]model.Checkpoint, 0, len(t.store.checkpoints)+len(t.checkpoints))
+	for seq, checkpoint := range t.store.checkpoints {
+		if _, ok := t.checkpoints[seq]; !ok {
+			checkpoints = append(checkpoints, checkpoint)
+		}
+	}
+	t.store.mu.RUnlock()
+
+	for _, checkpoint := range t.checkpoints {
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Seq < checkpoints[j].Seq
+	})
+	return checkpoints
+}
+
+// allDecisions returns every visible risk decision in the order they were saved.
+func (t *tx) allDecisions() []model.RiskDecision {
+	t.store.mu.RLock()
+	committed := t.store.decisions[:len(t.store.decisions):len(t.store.decisions)]
+	t.store.mu.RUnlock()
+
+	if len(t.decisions) == 0 {
+		return committed
+	}
+	return append(committed, t.decisions...)
+}
+
+// allStatusChanges returns every visible status change in the order they were saved.
+func (t *tx) allStatusChanges() []model.StatusChange {
+	t.store.mu.RLock()
+	committed := t.store.statuses[:len(t.store.statuses):len(t.store.statuses)]
+	t.store.mu.RUnlock()
+
+	if len(t.statuses) == 0 {
+		return committed
+	}
+	return append(committed, t.statuses...)
+}
+
+func (t *tx) sanctionsCase(id uuid.UUID) (model.SanctionsCase, bool) {
+	if c, ok := t.cases[id]; ok {
+		return c, true
+	}
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	c, ok := t.store.cases[id]
+	return c, ok
+}
+
+// allCases returns every visible sanctions case, oldest first.
+func (t *tx) allCases() []model.SanctionsCase {
+	t.store.mu.RLock()
+	cases := make([]model.SanctionsCase, 0, len(t.store.cases)+len(t.cases))
+	for id, c := range t.store.cases {
+		if _, ok := t.cases[id]; !ok {
+			cases = append(cases, c)
+		}
+	}
+	t.store.mu.RUnlock()
+
+	for _, c := range t.cases {
+		cases = append(cases, c)
+	}
+	sort.Slice(cases, func(i, j int) bool {
+		return cases[i].CreatedAt.Before(cases[j].CreatedAt)
+	})
+	return cases
+}
+
+func (t *tx) whitelisted(key whitelistKey) bool {
+	if entry, ok := t.whitelist[key]; ok {
+		return entry != nil
+	}
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	_, ok := t.store.whitelist[key]
+	return ok
+}
+
+// allWhitelist returns every visible whitelist entry, oldest first.
+func (t *tx) allWhitelist() []model.WhitelistEntry {
+	t.store.mu.RLock()
+	entries := make([]model.WhitelistEntry, 0, len(t.store.whitelist)+len(t.whitelist))
+	for key, entry := range t.store.whitelist {
+		if _, ok := t.whitelist[key]; !ok {
+			entries = append(entries, entry)
+		}
+	}
+	t.store.mu.RUnlock()
+
+	for _, entry := range t.whitelist {
+		if entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	return entries
+}
+
+func (t *tx) tier(name string) (model.TierLimits, bool) {
+	if tier, ok := t.tiers[name]; ok {
+		return tier, true
+	}
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	tier, ok := t.store.tiers[name]
+	return tier, ok
+}
+
+// allTiers returns every visible tier, by name.
+func (t *tx) allTiers() []model.TierLimits {
+	t.store.mu.RLock()
+	tiers := make([]model.TierLimits, 0, len(t.store.tiers)+len(t.tiers))
+	for name, tier := range t.store.tiers {
+		if _, ok := t.tiers[name]; !ok {
+			tiers = append(tiers, tier)
+		}
+	}
+	t.store.mu.RUnlock()
+
+	for _, tier := range t.tiers {
+		tiers = append(tiers, tier)
+	}
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Name < tiers[j].Name
+	})
+	return tiers
+}
+
+// limitsOf returns the limits saved for a wallet, or none.
+func (t *tx) limitsOf(walletID uuid.UUID) model.WalletLimits {
+	if limits, ok := t.walletLimits[walletID]; ok {
+		return limits
+	}
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	if limits, ok := t.store.walletLimits[walletID]; ok {
+		return limits
+	}
+	return model.WalletLimits{WalletID: walletID}
+}
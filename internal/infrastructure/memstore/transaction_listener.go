@@ -0,0 +1,63 @@
+package memstore
+
+import (
+	"context"
+	"log/slog"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+	"transaction-service/internal/domain/service"
+)
+
+// TransactionListener relays transactions committed to the store to the broker,
+// like its datastore counterpart does for Postgres notifications.
+type TransactionListener struct {
+	store  *Store
+	repo   repository.TransactionRepository
+	broker service.TransactionBroker
+}
+
+func NewTransactionListener(store *Store, broker service.TransactionBroker) *TransactionListener {
+	return &TransactionListener{
+		store:  store,
+		repo:   NewTransactionRepository(store),
+		broker: broker,
+	}
+}
+
+// Run publishes new transactions until ctx is done.
+func (l *TransactionListener) Run(ctx context.Context) error {
+	changed := l.store.Changed()
+	l.store.mu.RLock()
+	last := int64(len(l.store.transactions))
+	l.store.mu.RUnlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+
+		changed = l.store.Changed()
+		var err error
+		if last, err = l.catchUp(ctx, last); err != nil {
+			slog.ErrorContext(ctx, "transaction listener catch-up failed", "last_seq", last, "error", err)
+		}
+	}
+}
+
+func (l *TransactionListener) catchUp(ctx context.Context, last int64) (int64, error) {
+	for {
+		batch, err := l.repo.GetTransactionsAfter(ctx, last, model.TransactionFilter{}, 500)
+		if err != nil {
+			return last, err
+		}
+		for _, transaction := range batch {
+			l.broker.Publish(transaction)
+			last = transaction.Seq
+		}
+		if len(batch) < 500 {
+			return last, nil
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// recentLimit matches the number of transactions the datastore returns from GetTransactions.
+const recentLimit = 100
+
+type transactionRepositoryImpl struct {
+	store *Store
+}
+
+func NewTransactionRepository(store *Store) repository.TransactionRepository {
+	return &transactionRepositoryImpl{store: store}
+}
+
+func (tr *transactionRepositoryImpl) Create(ctx context.Context, transaction *model.Transaction) (uuid.UUID, error) {
+	if transaction == nil {
+		return uuid.Nil, fmt.Errorf("transaction cannot be nil")
+	}
+
+	err := tr.store.write(ctx, func(t *tx) error {
+		for _, existing := range t.allTransactions() {
+			if existing.ID == transaction.ID {
+				return fmt.Errorf("transaction %s already exists", transaction.ID)
+			}
+		}
+		*transaction = t.addTransaction(*transaction)
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return transaction.ID, nil
+}
+
+func (tr *transactionRepositoryImpl) GetTransactions(ctx context.Context, walletIDs []string) (result []model.Transaction, err error) {
+	filter := model.HistoryFilter{WalletIDs: walletIDs}
+	err = tr.store.read(ctx, func(t *tx) error {
+		all := t.allTransactions()
+		for i := len(all) - 1; i >= 0 && len(result) < recentLimit; i-- {
+			if filter.Matches(all[i]) {
+				result = append(result, all[i])
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (tr *transactionRepositoryImpl) GetTransactionsAfter(ctx context.Context, seq int64, filter model.TransactionFilter, limit int) (result []model.Transaction, err error) {
+	err = tr.store.read(ctx, func(t *tx) error {
+		all := t.allTransactions()
+		// Seq starts at 1 and has no gaps, so the first candidate is at index seq.
+		for _, transaction := range all[min(max(seq, 0), int64(len(all))):] {
+			if len(result) == limit {
+				break
+			}
+			if filter.Matches(transaction) {
+				result = append(result, transaction)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (tr *transactionRepositoryImpl) FindTransactions(ctx context.Context, filter model.HistoryFilter) (result []model.Transaction, err error) {
+	err = tr.store.read(ctx, func(t *tx) error {
+		all := t.allTransactions()
+		for i := len(all) - 1; i >= 0 && len(result) < filter.Limit; i-- {
+			if filter.Matches(all[i]) {
+				result = append(result, all[i])
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (tr *transactionRepositoryImpl) Totals(ctx context.Context, walletID string) (totals model.TransferTotals, err error) {
+	err = tr.store.read(ctx, func(t *tx) error {
+		for _, transaction := range t.allTransactions() {
+			if transaction.From == walletID {
+				totals.Sent += int64(transaction.Amount)
+			}
+			if transaction.To == walletID {
+				totals.Received += int64(transaction.Amount)
+			}
+		}
+		return nil
+	})
+	return totals, err
+}
+
+func (tr *transactionRepositoryImpl) Velocity(ctx context.Context, walletID string, since time.Time) (velocity model.TransferVelocity, err error) {
+	err = tr.store.read(ctx, func(t *tx) error {
+		for _, transaction := range t.allTransactions() {
+			if transaction.From == walletID && !transaction.CreatedAt.Before(since) {
+				velocity.Count++
+				velocity.Amount += int64(transaction.Amount)
+			}
+		}
+		return nil
+	})
+	return velocity, err
+}
+
+func (tr *transactionRepositoryImpl) HasTransferred(ctx context.Context, fromID, toID string) (found bool, err error) {
+	err = tr.store.read(ctx, func(t *tx) error {
+		found = slices.ContainsFunc(t.allTransactions(), func(transaction model.Transaction) bool {
+			return transaction.From == fromID && transaction.To == toID
+		})
+		return nil
+	})
+	return found, err
+}
+
+// ArchivedTotals returns no totals, as the memory store never archives transactions.
+func (tr *transactionRepositoryImpl) ArchivedTotals(context.Context) (map[string]model.TransferTotals, error) {
+	return map[string]model.TransferTotals{}, nil
+}
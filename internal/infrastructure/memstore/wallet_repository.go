@@ -0,0 +1,156 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type walletRepositoryImpl struct {
+	store *Store
+}
+
+func NewWalletRepository(store *Store) repository.WalletRepository {
+	return &walletRepositoryImpl{store: store}
+}
+
+func (w *walletRepositoryImpl) IsServiceInitialized(ctx context.Context) (initialized bool, err error) {
+	err = w.store.read(ctx, func(t *tx) error {
+		initialized = t.isInitialized()
+		return nil
+	})
+	return initialized, err
+}
+
+func (w *walletRepositoryImpl) SetServiceInitialized(ctx context.Context) error {
+	return w.store.write(ctx, func(t *tx) error {
+		t.initialized = true
+		return nil
+	})
+}
+
+func (w *walletRepositoryImpl) Create(ctx context.Context) (uuid.UUID, error) {
+	id := uuid.New()
+	err := w.store.write(ctx, func(t *tx) error {
+		t.putWallet(model.Wallet{ID: id, Status: model.WalletActive})
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create wallet: %w", err)
+	}
+	return id, nil
+}
+
+func (w *walletRepositoryImpl) CreateSystem(ctx context.Context, id uuid.UUID) error {
+	return w.store.write(ctx, func(t *tx) error {
+		if _, ok := t.wallet(id); !ok {
+			t.putWallet(model.Wallet{ID: id, Status: model.WalletActive, System: true})
+		}
+		return nil
+	})
+}
+
+func (w *walletRepositoryImpl) FetchByID(ctx context.Context, id uuid.UUID) (*model.Wallet, error) {
+	if id == uuid.Nil {
+		return nil, model.ErrInvalidWalletID
+	}
+
+	var wallet model.Wallet
+	err := w.store.read(ctx, func(t *tx) error {
+		var ok bool
+		if wallet, ok = t.wallet(id); !ok {
+			return model.ErrWalletNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (w *walletRepositoryImpl) Update(ctx context.Context, wallet *model.Wallet) (*model.Wallet, error) {
+	if wallet == nil || wallet.ID == uuid.Nil {
+		return nil, fmt.Errorf("invalid wallet data")
+	}
+
+	err := w.store.write(ctx, func(t *tx) error {
+		stored, ok := t.wallet(wallet.ID)
+		if !ok || stored.Version != wallet.Version {
+			return model.ErrConflict
+		}
+		stored.Amount = wallet.Amount
+		stored.Status = wallet.Status
+		stored.Version++
+		t.putWallet(stored)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update wallet %s at version %d: %w", wallet.ID, wallet.Version, err)
+	}
+	wallet.Version++
+
+	return wallet, nil
+}
+
+func (w *walletRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return w.store.write(ctx, func(t *tx) error {
+		t.wallets[id] = nil
+		return nil
+	})
+}
+
+func (w *walletRepositoryImpl) FetchAll(ctx context.Context) (result []*model.Wallet, err error) {
+	err = w.store.read(ctx, func(t *tx) error {
+		for _, wallet := range t.allWallets() {
+			if !wallet.System {
+				result = append(result, &wallet)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (w *walletRepositoryImpl) FetchByOwner(ctx context.Context, ownerID string) ([]*model.Wallet, error) {
+	result := make([]*model.Wallet, 0)
+	err := w.store.read(ctx, func(t *tx) error {
+		for _, wallet := range t.allWallets() {
+			if wallet.OwnerID == ownerID && !wallet.System {
+				result = append(result, &wallet)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (w *walletRepositoryImpl) SetOwner(ctx context.Context, id uuid.UUID, ownerID, ownerName string) error {
+	return w.store.write(ctx, func(t *tx) error {
+		wallet, ok := t.wallet(id)
+		if !ok {
+			return model.ErrWalletNotFound
+		}
+		wallet.OwnerID = ownerID
+		wallet.OwnerName = ownerName
+		t.putWallet(wallet)
+		return nil
+	})
+}
+
+func (w *walletRepositoryImpl) Summarize(ctx context.Context) (summary model.WalletSummary, err error) {
+	err = w.store.read(ctx, func(t *tx) error {
+		for _, wallet := range t.allWallets() {
+			if wallet.System {
+				continue
+			}
+			summary.Count++
+			summary.TotalAmount += int64(wallet.Amount)
+		}
+		return nil
+	})
+	return summary, err
+}
@@ -0,0 +1,208 @@
+// Package metrics exposes service metrics in the Prometheus format.
+//
+// Metric names and labels are part of the service's public contract and are kept
+// stable; dashboards and alerts depend on them:
+//
+//	transaction_service_http_requests_total{method,route,status}            counter
+//	transaction_service_http_request_duration_seconds{method,route,status}  histogram
+//	transaction_service_transfers_total{outcome}                            counter
+//	transaction_service_transfer_retries_total                              counter
+//	transaction_service_wallet_lock_wait_seconds                            histogram
+//	transaction_service_balance_cache_requests_total{result}                counter
+//	transaction_service_balance_cache_invalidations_total                   counter
+//	transaction_service_wallets                                             gauge
+//	transaction_service_total_supply_cents                                  gauge
+//	transaction_service_db_*                                                 sqlx pool stats
+//
+// route is the matched route pattern (e.g. /api/wallet/:address/balance), never the
+// raw path, so label cardinality stays bounded. outcome is one of success,
+// insufficient_funds, not_found, invalid, conflict and error. result is hit or miss.
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+	"transaction-service/internal/domain/repository"
+	"transaction-service/internal/domain/service"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes every metric name.
+const Namespace = "transaction_service"
+
+// summaryTimeout bounds the wallet summary query run on every scrape.
+const summaryTimeout = 5 * time.Second
+
+// Prometheus implements service.Metrics and records HTTP requests on its own registry.
+type Prometheus struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	transfers    *prometheus.CounterVec
+	retries      prometheus.Counter
+	lockWait     prometheus.Histogram
+	cacheLookups *prometheus.CounterVec
+	cacheDrops   prometheus.Counter
+}
+
+// NewPrometheus registers the service metrics. Business gauges are computed from
+// wallets on scrape; pool stats are read from db, which is nil without a database.
+func NewPrometheus(db *sqlx.DB, wallets repository.WalletRepository) *Prometheus {
+	labels := []string{"method", "route", "status"}
+	m := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route pattern and status code.",
+		}, labels),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		transfers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "transfers_total",
+			Help:      "Transfers by outcome: success, insufficient_funds, not_found, invalid, conflict or error.",
+		}, []string{"outcome"}),
+		retries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "transfer_retries_total",
+			Help:      "Transfer attempts repeated after a version conflict, serialization failure or deadlock.",
+		}),
+		lockWait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "wallet_lock_wait_seconds",
+			Help:      "Time transfers spent waiting for wallet locks.",
+			Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1, 5},
+		}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "balance_cache_requests_total",
+			Help:      "Wallet reads by ID outside of transactions, by result: hit or miss.",
+		}, []string{"result"}),
+		cacheDrops: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "balance_cache_invalidations_total",
+			Help:      "Wallets dropped from the balance cache after a write or a relayed transfer.",
+		}),
+	}
+	m.cacheLookups.WithLabelValues("hit")
+	m.cacheLookups.WithLabelValues("miss")
+
+	// Pre-create every outcome so rates are defined before the first failure.
+	for _, outcome := range []string{
+		service.OutcomeSuccess,
+		service.OutcomeInsufficientFunds,
+		service.OutcomeNotFound,
+		service.OutcomeInvalid,
+		service.OutcomeConflict,
+		service.OutcomeDenied,
+		service.OutcomeError,
+	} {
+		m.transfers.WithLabelValues(outcome)
+	}
+
+	m.registry.MustRegister(
+		m.httpRequests,
+		m.httpDuration,
+		m.transfers,
+		m.retries,
+		m.lockWait,
+		m.cacheLookups,
+		m.cacheDrops,
+		newWalletCollector(wallets),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	if db != nil {
+		m.registry.MustRegister(collectors.NewDBStatsCollector(db.DB, Namespace))
+	}
+	return m
+}
+
+// Handler serves the registered metrics for scraping.
+func (m *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Prometheus) TransferCompleted(outcome string) {
+	m.transfers.WithLabelValues(outcome).Inc()
+}
+
+func (m *Prometheus) TransferRetried() {
+	m.retries.Inc()
+}
+
+func (m *Prometheus) LockWaited(d time.Duration) {
+	m.lockWait.Observe(d.Seconds())
+}
+
+func (m *Prometheus) BalanceCacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(result).Inc()
+}
+
+func (m *Prometheus) BalanceCacheInvalidated(n int) {
+	m.cacheDrops.Add(float64(n))
+}
+
+// ObserveRequest records a served HTTP request.
+func (m *Prometheus) ObserveRequest(method, route string, status int, d time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(d.Seconds())
+}
+
+// walletCollector reports wallet count and total supply, read from the database on scrape.
+type walletCollector struct {
+	wallets repository.WalletRepository
+	count   *prometheus.Desc
+	supply  *prometheus.Desc
+}
+
+func newWalletCollector(wallets repository.WalletRepository) *walletCollector {
+	return &walletCollector{
+		wallets: wallets,
+		count: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "", "wallets"),
+			"Number of user wallets.", nil, nil,
+		),
+		supply: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "", "total_supply_cents"),
+			"Sum of user wallet balances, in cents. The mint wallet is excluded.", nil, nil,
+		),
+	}
+}
+
+func (c *walletCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.count
+	ch <- c.supply
+}
+
+func (c *walletCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), summaryTimeout)
+	defer cancel()
+
+	summary, err := c.wallets.Summarize(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to collect wallet metrics", "error", err)
+		ch <- prometheus.NewInvalidMetric(c.count, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.count, prometheus.GaugeValue, float64(summary.Count))
+	ch <- prometheus.MustNewConstMetric(c.supply, prometheus.GaugeValue, float64(summary.TotalAmount))
+}
@@ -0,0 +1,114 @@
+// Package migrator applies the SQL migrations embedded in the binary with goose.
+// Versions are recorded in the goose_db_version table, so databases migrated with
+// the goose CLI carry on where it stopped.
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"time"
+	"transaction-service/migrations"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// Migrator migrates one database.
+type Migrator struct {
+	provider *goose.Provider
+}
+
+// Status describes one migration.
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time // Zero unless applied
+}
+
+// NewPostgres returns a migrator for a Postgres database. Migrations run under a
+// session advisory lock, so replicas starting together apply them once.
+func NewPostgres(db *sql.DB) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration lock: %w", err)
+	}
+	return newMigrator(goose.DialectPostgres, db, migrations.FS, goose.WithSessionLocker(locker))
+}
+
+// NewSQLite returns a migrator for a SQLite database. SQLite serializes writers on
+// its own, so no lock is taken.
+func NewSQLite(db *sql.DB) (*Migrator, error) {
+	return newMigrator(goose.DialectSQLite3, db, migrations.SQLite())
+}
+
+func newMigrator(dialect goose.Dialect, db *sql.DB, fsys fs.FS, opts ...goose.ProviderOption) (*Migrator, error) {
+	provider, err := goose.NewProvider(dialect, db, fsys, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	return &Migrator{provider: provider}, nil
+}
+
+// Up applies every pending migration and returns the versions applied.
+func (m *Migrator) Up(ctx context.Context) ([]int64, error) {
+	results, err := m.provider.Up(ctx)
+	applied := make([]int64, 0, len(results))
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		applied = append(applied, result.Source.Version)
+		slog.InfoContext(ctx, "migration applied",
+			"version", result.Source.Version, "name", result.Source.Path, "duration", result.Duration)
+	}
+	if err != nil {
+		return applied, fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// Down rolls back the newest applied migration and returns its version.
+func (m *Migrator) Down(ctx context.Context) (int64, error) {
+	result, err := m.provider.Down(ctx)
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		return 0, fmt.Errorf("no migration to roll back")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to roll back migration: %w", err)
+	}
+	slog.InfoContext(ctx, "migration rolled back",
+		"version", result.Source.Version, "name", result.Source.Path, "duration", result.Duration)
+	return result.Source.Version, nil
+}
+
+// Status reports every migration, oldest first.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration status: %w", err)
+	}
+	result := make([]Status, 0, len(statuses))
+	for _, status := range statuses {
+		result = append(result, Status{
+			Version:   status.Source.Version,
+			Name:      status.Source.Path,
+			Applied:   status.State == goose.StateApplied,
+			AppliedAt: status.AppliedAt,
+		})
+	}
+	return result, nil
+}
+
+// Version returns the version of the newest applied migration, 0 for an empty database.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	version, err := m.provider.GetDBVersion(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
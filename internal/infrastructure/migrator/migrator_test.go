@@ -0,0 +1,71 @@
+package migrator_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"transaction-service/internal/infrastructure/migrator"
+	"transaction-service/migrations"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestUpDown(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	m, err := migrator.NewSQLite(db)
+	if err != nil {
+		t.Fatalf("NewSQLite: %v", err)
+	}
+	versions, err := migrations.Versions(migrations.SQLite())
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := versions[len(versions)-1]
+
+	if version, err := m.Version(ctx); err != nil || version != 0 {
+		t.Fatalf("Version of an empty database = %d, %v; want 0", version, err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(applied) != len(versions) {
+		t.Errorf("Up applied %v, want %v", applied, versions)
+	}
+	if applied, err := m.Up(ctx); err != nil || len(applied) != 0 {
+		t.Errorf("second Up = %v, %v; want nothing applied", applied, err)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.AppliedAt.IsZero() {
+			t.Errorf("status %+v, want applied", status)
+		}
+	}
+
+	rolledBack, err := m.Down(ctx)
+	if err != nil || rolledBack != latest {
+		t.Fatalf("Down = %d, %v; want %d", rolledBack, err, latest)
+	}
+	if version, err := m.Version(ctx); err != nil || version != versions[len(versions)-2] {
+		t.Errorf("Version after Down = %d, %v; want %d", version, err, versions[len(versions)-2])
+	}
+	statuses, err = m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if last := statuses[len(statuses)-1]; last.Version != latest || last.Applied {
+		t.Errorf("last status %+v, want %d pending", last, latest)
+	}
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBucketRefill(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		name    string
+		tokens  float64
+		elapsed time.Duration
+		limit   Limit
+		want    float64
+	}{
+		{"no time passed", 2, 0, Limit{Rate: 1, Burst: 5}, 2},
+		{"partial refill", 0, 1500 * time.Millisecond, Limit{Rate: 2, Burst: 5}, 3},
+		{"capped at burst", 4, time.Minute, Limit{Rate: 1, Burst: 5}, 5},
+		{"slow rate", 0, 2 * time.Second, Limit{Rate: 0.25, Burst: 1}, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &bucket{tokens: tt.tokens, updated: start, limit: tt.limit}
+			b.refill(start.Add(tt.elapsed))
+			if b.tokens != tt.want {
+				t.Errorf("tokens = %v, want %v", b.tokens, tt.want)
+			}
+			if !b.updated.Equal(start.Add(tt.elapsed)) {
+				t.Errorf("updated = %v, want %v", b.updated, start.Add(tt.elapsed))
+			}
+		})
+	}
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	store := &memoryStore{buckets: make(map[string]*bucket), now: func() time.Time { return now }}
+	limit := Limit{Rate: 1, Burst: 2}
+
+	tests := []struct {
+		name      string
+		advance   time.Duration
+		key       string
+		allowed   bool
+		remaining float64
+	}{
+		{"first request gets the burst", 0, "a", true, 1},
+		{"second request empties it", 0, "a", true, 0},
+		{"third request is refused", 0, "a", false, 0},
+		{"other keys have their own bucket", 0, "b", true, 1},
+		{"half a second refills half a token", 500 * time.Millisecond, "a", false, 0.5},
+		{"a full token lets one through", 500 * time.Millisecond, "a", true, 0},
+		{"idle buckets refill to the burst", time.Hour, "a", true, 1},
+	}
+	for _, tt := range tests {
+		now = now.Add(tt.advance)
+		res, err := store.Take(context.Background(), tt.key, limit)
+		if err != nil {
+			t.Fatalf("%s: Take: %v", tt.name, err)
+		}
+		if res.Allowed != tt.allowed || res.Remaining != tt.remaining || res.Limit != limit.Burst || res.Rate != limit.Rate {
+			t.Errorf("%s: Take = %+v, want allowed %v with %v remaining", tt.name, res, tt.allowed, tt.remaining)
+		}
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	store := &memoryStore{buckets: make(map[string]*bucket), now: func() time.Time { return now }}
+	limit := Limit{Rate: 1, Burst: 2}
+
+	for _, key := range []string{"idle", "busy"} {
+		if _, err := store.Take(context.Background(), key, limit); err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+	}
+	now = now.Add(sweepInterval)
+	if _, err := store.Take(context.Background(), "busy", limit); err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+	// The sweep runs before the take, so busy was full and dropped, then taken anew.
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("full idle bucket was not swept")
+	}
+	if b, ok := store.buckets["busy"]; !ok || b.tokens != 1 {
+		t.Errorf("busy bucket = %+v, want one token left", b)
+	}
+}
+
+func TestResultDurations(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      Result
+		retry, full time.Duration
+	}{
+		{"tokens left", Result{Limit: 10, Remaining: 4, Rate: 2}, 0, 3 * time.Second},
+		{"empty", Result{Limit: 10, Remaining: 0, Rate: 2}, time.Second, 5 * time.Second},
+		{"rounded up", Result{Limit: 3, Remaining: 0.2, Rate: 0.5}, 2 * time.Second, 6 * time.Second},
+		{"full", Result{Limit: 3, Remaining: 3, Rate: 1}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.RetryAfter(); got != tt.retry {
+				t.Errorf("RetryAfter = %v, want %v", got, tt.retry)
+			}
+			if got := tt.result.ResetAfter(); got != tt.full {
+				t.Errorf("ResetAfter = %v, want %v", got, tt.full)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresStore struct {
+	db          *sqlx.DB
+	lastCleanup atomic.Int64
+	cleanups    sync.WaitGroup
+}
+
+// NewPostgresStore creates a Store that keeps buckets in the rate_limit_buckets
+// table, so limits are shared by every replica. Buckets idle for an hour are
+// deleted; limits refilling slower than that should use a larger burst instead.
+func NewPostgresStore(db *sqlx.DB) Store {
+	return &postgresStore{db: db}
+}
+
+// takeQuery refills and takes from a bucket in a single statement. Every SET
+// expression sees the row as it was before the update, so tokens and allowed are
+// derived from the same refilled amount:
+//
+//	LEAST(burst, tokens + seconds since update * rate)
+const takeQuery = `
+    INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+    VALUES ($1, $2::FLOAT8 - 1, TRUE, NOW())
+    ON CONFLICT (key) DO UPDATE SET
+        tokens = LEAST($2::FLOAT8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at) * $3::FLOAT8)
+            - (LEAST($2::FLOAT8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at) * $3::FLOAT8) >= 1)::INT,
+        allowed = LEAST($2::FLOAT8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at) * $3::FLOAT8) >= 1,
+        updated_at = NOW()
+    RETURNING tokens, allowed
+`
+
+func (s *postgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	var row struct {
+		Tokens  float64 `db:"tokens"`
+		Allowed bool    `db:"allowed"`
+	}
+	if err := s.db.GetContext(ctx, &row, takeQuery, key, float64(limit.Burst), limit.Rate); err != nil {
+		return Result{}, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	s.cleanup()
+	return Result{Allowed: row.Allowed, Limit: limit.Burst, Remaining: row.Tokens, Rate: limit.Rate}, nil
+}
+
+// cleanup occasionally deletes buckets idle for long enough to have refilled completely.
+func (s *postgresStore) cleanup() {
+	now := time.Now()
+	last := s.lastCleanup.Load()
+	if now.Sub(time.Unix(0, last)) < sweepInterval || !s.lastCleanup.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
+	s.cleanups.Add(1)
+	go func() {
+		defer s.cleanups.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE updated_at < NOW() - INTERVAL '1 hour'`); err != nil {
+			slog.Error("rate limit cleanup failed", "error", err)
+		}
+	}()
+}
+
+func (s *postgresStore) Close() error {
+	s.cleanups.Wait()
+	return nil
+}
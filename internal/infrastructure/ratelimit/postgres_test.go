@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresStoreTake runs against the migrated Postgres database at
+// TEST_DATABASE_DSN.
+func TestPostgresStoreTake(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store := NewPostgresStore(db)
+	t.Cleanup(func() { store.Close() })
+
+	ctx := context.Background()
+	key, other := "test:"+uuid.NewString(), "test:"+uuid.NewString()
+	t.Cleanup(func() { db.Exec(`DELETE FROM rate_limit_buckets WHERE key IN ($1, $2)`, key, other) })
+	// Slow enough that the refill between the statements stays below a token.
+	limit := Limit{Rate: 0.001, Burst: 2}
+
+	for n, want := range []bool{true, true, false, false} {
+		res, err := store.Take(ctx, key, limit)
+		if err != nil {
+			t.Fatalf("Take %d: %v", n+1, err)
+		}
+		if res.Allowed != want || res.Limit != 2 || res.Remaining < 0 || res.Remaining >= 1 && !want {
+			t.Errorf("Take %d = %+v, want allowed %v", n+1, res, want)
+		}
+	}
+	if res, err := store.Take(ctx, other, limit); err != nil || !res.Allowed || res.Remaining != 1 {
+		t.Errorf("Take(other key) = %+v, %v, want a fresh bucket", res, err)
+	}
+
+	// A refill of the elapsed time at the full rate lets the next request through.
+	if _, err := db.Exec(`UPDATE rate_limit_buckets SET updated_at = updated_at - INTERVAL '1000 seconds' WHERE key = $1`, key); err != nil {
+		t.Fatalf("failed to age bucket: %v", err)
+	}
+	if res, err := store.Take(ctx, key, limit); err != nil || !res.Allowed {
+		t.Errorf("Take after refill = %+v, %v, want allowed", res, err)
+	}
+}
@@ -0,0 +1,48 @@
+// Package ratelimit implements token bucket rate limiting with pluggable bucket storage.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit describes a token bucket: Burst requests at once, refilled at Rate requests per second.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed   bool    // Whether the request may proceed
+	Limit     int     // Bucket capacity
+	Remaining float64 // Tokens left after this request
+	Rate      float64 // Refill rate in tokens per second
+}
+
+// ResetAfter is how long until the bucket is full again.
+func (r Result) ResetAfter() time.Duration {
+	return secondsToDuration((float64(r.Limit) - r.Remaining) / r.Rate)
+}
+
+// RetryAfter is how long until the next request would be allowed.
+func (r Result) RetryAfter() time.Duration {
+	if r.Remaining >= 1 {
+		return 0
+	}
+	return secondsToDuration((1 - r.Remaining) / r.Rate)
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s)) * time.Second
+}
+
+// Store keeps token buckets.
+type Store interface {
+	// Take removes one token from the bucket identified by key, if there is one.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+
+	// Close waits for background maintenance to finish.
+	Close() error
+}
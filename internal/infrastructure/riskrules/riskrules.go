@@ -0,0 +1,173 @@
+// Package riskrules reads the risk rules transfers are evaluated against from an
+// HCL file that may be edited while the service runs:
+//
+//	rule "large-transfer" {
+//	  type       = "amount"
+//	  action     = "review"
+//	  min_amount = 5000
+//	}
+//
+//	rule "burst" {
+//	  type      = "velocity"
+//	  action    = "deny"
+//	  window    = "1m"
+//	  max_count = 10
+//	}
+//
+// Amounts are in whole currency units, as in the API.
+package riskrules
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"sync"
+	"time"
+	"transaction-service/internal/domain/model"
+
+	"github.com/hashicorp/hcl"
+)
+
+// File holds the rules of a rules file. A rules file that fails to parse or
+// validate is not applied, and the rules read before stay in use.
+type File struct {
+	path     string
+	interval time.Duration
+
+	mu    sync.RWMutex
+	rules []model.RiskRule
+	stat  fileStat // Of the file the rules were read from
+	read  bool
+}
+
+// fileStat tells whether a file was replaced since it was read.
+type fileStat struct {
+	modTime time.Time
+	size    int64
+}
+
+// Load reads the rules file at path. Run rereads it every interval once it
+// changes.
+func Load(path string, interval time.Duration) (*File, error) {
+	f := &File{path: path, interval: interval}
+	if _, err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File) Rules() []model.RiskRule {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.rules
+}
+
+// Reload rereads the rules file if it changed since it was last read, and reports
+// whether it did.
+func (f *File) Reload() (bool, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read risk rules: %w", err)
+	}
+	stat := fileStat{modTime: info.ModTime(), size: info.Size()}
+	f.mu.RLock()
+	unchanged := f.read && stat == f.stat
+	f.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read risk rules: %w", err)
+	}
+	rules, err := parse(data)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse risk rules %s: %w", f.path, err)
+	}
+
+	f.mu.Lock()
+	reloaded := f.read
+	f.rules, f.stat, f.read = rules, stat, true
+	f.mu.Unlock()
+
+	if reloaded {
+		slog.Info("risk rules reloaded", "path", f.path, "rules", len(rules))
+	}
+	return true, nil
+}
+
+// Run reloads the rules file every interval until ctx is done. Failures are
+// logged and the rules in use are kept.
+func (f *File) Run(ctx context.Context) error {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if _, err := f.Reload(); err != nil {
+			slog.ErrorContext(ctx, "failed to reload risk rules", "error", err)
+		}
+	}
+}
+
+// file is the layout of a rules file.
+type file struct {
+	Rules []rule `hcl:"rule"`
+}
+
+type rule struct {
+	Name      string   `hcl:",key"`
+	Type      string   `hcl:"type"`
+	Action    string   `hcl:"action"`
+	MinAmount float64  `hcl:"min_amount"`
+	Window    string   `hcl:"window"`
+	MaxCount  int      `hcl:"max_count"`
+	MaxAmount float64  `hcl:"max_amount"`
+	Wallets   []string `hcl:"wallets"`
+}
+
+func parse(data []byte) ([]model.RiskRule, error) {
+	var parsed file
+	if err := hcl.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+
+	rules := make([]model.RiskRule, 0, len(parsed.Rules))
+	names := make(map[string]bool, len(parsed.Rules))
+	for _, r := range parsed.Rules {
+		if names[r.Name] {
+			return nil, fmt.Errorf("rule %s is defined twice", r.Name)
+		}
+		names[r.Name] = true
+
+		var window time.Duration
+		if r.Window != "" {
+			var err error
+			if window, err = time.ParseDuration(r.Window); err != nil {
+				return nil, fmt.Errorf("rule %s: invalid window: %w", r.Name, err)
+			}
+		}
+		rule := model.RiskRule{
+			Name:      r.Name,
+			Type:      r.Type,
+			Action:    model.RiskAction(r.Action),
+			MinAmount: int(math.Round(r.MinAmount * 100)),
+			Window:    window,
+			MaxCount:  r.MaxCount,
+			MaxAmount: int(math.Round(r.MaxAmount * 100)),
+			Wallets:   r.Wallets,
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,51 @@
+package riskrules
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"transaction-service/internal/domain/model"
+)
+
+func TestParse(t *testing.T) {
+	rules, err := parse([]byte(`
+rule "large" {
+  type       = "amount"
+  action     = "review"
+  min_amount = 5000.5
+}
+
+rule "burst" {
+  type       = "velocity"
+  action     = "deny"
+  window     = "10m"
+  max_count  = 5
+  max_amount = 100
+}
+`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := []model.RiskRule{
+		{Name: "large", Type: model.RuleAmount, Action: model.RiskReview, MinAmount: 500050},
+		{Name: "burst", Type: model.RuleVelocity, Action: model.RiskDeny, Window: 10 * time.Minute, MaxCount: 5, MaxAmount: 10000},
+	}
+	if fmt.Sprint(rules) != fmt.Sprint(want) {
+		t.Errorf("parse = %+v, want %+v", rules, want)
+	}
+
+	for _, tt := range []struct {
+		name, rules, want string
+	}{
+		{"duplicate", `rule "a" { type = "new_counterparty" action = "review" } rule "a" { type = "new_counterparty" action = "deny" }`, "defined twice"},
+		{"unknown type", `rule "a" { type = "country" action = "deny" }`, "unknown type"},
+		{"unknown action", `rule "a" { type = "new_counterparty" action = "block" }`, "unknown action"},
+		{"bad window", `rule "a" { type = "velocity" action = "deny" window = "soon" max_count = 1 }`, "invalid window"},
+		{"bad wallet", `rule "a" { type = "blocklist" action = "deny" wallets = ["nobody"] }`, "invalid wallet ID"},
+	} {
+		if _, err := parse([]byte(tt.rules)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parse(%s) error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
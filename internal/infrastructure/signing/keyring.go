@@ -0,0 +1,171 @@
+package signing
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/service"
+)
+
+// Keyring holds the keys of a key file that may be replaced while the service
+// runs. The first private key in the file signs. Every other key in it, a private
+// key or a PKIX public key, is retired: it no longer signs but is still published,
+// so the signatures it made can be verified. To rotate, put the new key first and
+// keep the old one after it:
+//
+//	openssl genpkey -algorithm ed25519 -out new.pem
+//	cat new.pem signing.pem > signing.pem.new && mv signing.pem.new signing.pem
+type Keyring struct {
+	path     string
+	interval time.Duration
+
+	mu      sync.RWMutex
+	current *Key
+	retired []ed25519.PublicKey
+	stat    fileStat // Of the file the keys were read from
+}
+
+// fileStat tells whether a file was replaced since it was read.
+type fileStat struct {
+	modTime time.Time
+	size    int64
+}
+
+// LoadKeyring reads the key file at path. Run rereads it every interval once
+// it changes.
+func LoadKeyring(path string, interval time.Duration) (*Keyring, error) {
+	k := &Keyring{path: path, interval: interval}
+	if _, err := k.Reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (k *Keyring) Current() service.Signer {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.current
+}
+
+func (k *Keyring) PublicKeys() []model.PublicKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keys := []model.PublicKey{{ID: k.current.KeyID(), Key: k.current.PublicKey(), Current: true}}
+	for _, public := range k.retired {
+		keys = append(keys, model.PublicKey{ID: KeyID(public), Key: public})
+	}
+	return keys
+}
+
+// Reload rereads the key file if it changed since it was last read, and reports
+// whether it did. The keys are kept when the file cannot be read.
+func (k *Keyring) Reload() (bool, error) {
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	stat := fileStat{modTime: info.ModTime(), size: info.Size()}
+	k.mu.RLock()
+	unchanged := k.current != nil && stat == k.stat
+	k.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(k.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	current, retired, err := parseKeys(k.path, data)
+	if err != nil {
+		return false, err
+	}
+
+	k.mu.Lock()
+	previous := k.current
+	k.current, k.retired, k.stat = current, retired, stat
+	k.mu.Unlock()
+
+	if previous != nil && previous.KeyID() != current.KeyID() {
+		slog.Info("signing key rotated", "key_id", current.KeyID(), "previous_key_id", previous.KeyID())
+		kept := slices.ContainsFunc(retired, func(public ed25519.PublicKey) bool {
+			return public.Equal(previous.PublicKey())
+		})
+		if !kept {
+			slog.Warn("previous signing key dropped from the key file; its signatures can no longer be verified",
+				"key_id", previous.KeyID())
+		}
+	}
+	return true, nil
+}
+
+// Run reloads the key file every interval until ctx is done. Failures are logged
+// and the keys in use are kept.
+func (k *Keyring) Run(ctx context.Context) error {
+	ticker := time.NewTicker(k.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if _, err := k.Reload(); err != nil {
+			slog.ErrorContext(ctx, "failed to reload signing keys", "error", err)
+		}
+	}
+}
+
+// parseKeys returns the signing key of a key file and its retired public keys.
+func parseKeys(path string, data []byte) (*Key, []ed25519.PublicKey, error) {
+	var (
+		current *Key
+		retired []ed25519.PublicKey
+	)
+	for n := 1; ; n++ {
+		var block *pem.Block
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+
+		var public ed25519.PublicKey
+		switch block.Type {
+		case "PRIVATE KEY":
+			private, err := parsePrivateKey(block.Bytes)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to parse key #%d of %s: %w", n, path, err)
+			}
+			if current == nil {
+				current = &Key{id: KeyID(private.Public().(ed25519.PublicKey)), private: private}
+				continue
+			}
+			public = private.Public().(ed25519.PublicKey)
+		case "PUBLIC KEY":
+			parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to parse key #%d of %s: %w", n, path, err)
+			}
+			var ok bool
+			if public, ok = parsed.(ed25519.PublicKey); !ok {
+				return nil, nil, fmt.Errorf("key #%d of %s is not an Ed25519 key", n, path)
+			}
+		default:
+			return nil, nil, fmt.Errorf("key #%d of %s is a PEM %q, not a key", n, path, block.Type)
+		}
+		retired = append(retired, public)
+	}
+	if current == nil {
+		return nil, nil, fmt.Errorf("signing key %s holds no PEM private key", path)
+	}
+	return current, retired, nil
+}
@@ -0,0 +1,74 @@
+// Package signing signs with an Ed25519 private key kept in a PEM file, as
+// written by:
+//
+//	openssl genpkey -algorithm ed25519 -out signing.pem
+//
+// A Keyring reloads the file, so the key can be rotated without a restart.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Key is an Ed25519 signing key.
+type Key struct {
+	id      string
+	private ed25519.PrivateKey
+}
+
+// LoadKey reads a PKCS #8 Ed25519 private key from the PEM file at path.
+func LoadKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("signing key %s is not a PEM private key", path)
+	}
+	private, err := parsePrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key %s: %w", path, err)
+	}
+	return &Key{id: KeyID(private.Public().(ed25519.PublicKey)), private: private}, nil
+}
+
+// parsePrivateKey parses a PKCS #8 Ed25519 private key.
+func parsePrivateKey(der []byte) (ed25519.PrivateKey, error) {
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an Ed25519 key")
+	}
+	return private, nil
+}
+
+// KeyID returns the ID of a public key: the hex of the first 8 bytes of its
+// SHA-256 hash.
+func KeyID(public ed25519.PublicKey) string {
+	sum := sha256.Sum256(public)
+	return hex.EncodeToString(sum[:8])
+}
+
+func (k *Key) KeyID() string {
+	return k.id
+}
+
+// PublicKey returns the key signatures are verified with.
+func (k *Key) PublicKey() ed25519.PublicKey {
+	return k.private.Public().(ed25519.PublicKey)
+}
+
+func (k *Key) Sign(message []byte) ([]byte, error) {
+	return ed25519.Sign(k.private, message), nil
+}
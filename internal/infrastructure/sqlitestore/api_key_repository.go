@@ -0,0 +1,181 @@
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type apiKeyRepositoryImpl struct {
+	db *DB
+}
+
+func NewAPIKeyRepository(db *DB) repository.APIKeyRepository {
+	return &apiKeyRepositoryImpl{db: db}
+}
+
+func (r *apiKeyRepositoryImpl) Create(ctx context.Context, key *model.APIKey) (err error) {
+	ctx, span := startSpan(ctx, "api_keys.create")
+	defer func() { endSpan(span, err) }()
+
+	if key == nil {
+		return fmt.Errorf("api key cannot be nil")
+	}
+
+	row := toDBAPIKey(key)
+	err = write(ctx, r.db, func(q querier) error {
+		_, err := q.NamedExecContext(ctx, `
+            INSERT INTO api_keys (id, name, secret_hash, scopes, wallet_ids, created_at)
+            VALUES (:id, :name, :secret_hash, :scopes, :wallet_ids, :created_at)
+        `, row)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+func (r *apiKeyRepositoryImpl) FetchByID(ctx context.Context, id uuid.UUID) (_ *model.APIKey, err error) {
+	ctx, span := startSpan(ctx, "api_keys.fetch_by_id")
+	defer func() { endSpan(span, err) }()
+
+	var row dbAPIKey
+	err = conn(ctx, r.db).GetContext(ctx, &row, `
+        SELECT id, name, secret_hash, scopes, wallet_ids, created_at, rotated_at, revoked_at
+        FROM api_keys WHERE id = ?
+    `, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch api key: %w", err)
+	}
+	return row.toModel()
+}
+
+func (r *apiKeyRepositoryImpl) FetchAll(ctx context.Context) (_ []*model.APIKey, err error) {
+	ctx, span := startSpan(ctx, "api_keys.fetch_all")
+	defer func() { endSpan(span, err) }()
+
+	var rows []dbAPIKey
+	err = conn(ctx, r.db).SelectContext(ctx, &rows, `
+        SELECT id, name, secret_hash, scopes, wallet_ids, created_at, rotated_at, revoked_at
+        FROM api_keys ORDER BY created_at DESC
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch api keys: %w", err)
+	}
+
+	keys := make([]*model.APIKey, 0, len(rows))
+	for _, row := range rows {
+		key, err := row.toModel()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepositoryImpl) UpdateSecret(ctx context.Context, id uuid.UUID, secretHash []byte) (err error) {
+	ctx, span := startSpan(ctx, "api_keys.update_secret")
+	defer func() { endSpan(span, err) }()
+
+	return write(ctx, r.db, func(q querier) error {
+		res, err := q.ExecContext(ctx,
+			`UPDATE api_keys SET secret_hash = ?, rotated_at = ? WHERE id = ? AND revoked_at IS NULL`,
+			secretHash, time.Now().UTC(), id,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to rotate api key: %w", err)
+		}
+		return requireAffected(res, model.ErrAPIKeyNotFound)
+	})
+}
+
+func (r *apiKeyRepositoryImpl) Revoke(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "api_keys.revoke")
+	defer func() { endSpan(span, err) }()
+
+	return write(ctx, r.db, func(q querier) error {
+		res, err := q.ExecContext(ctx,
+			`UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`,
+			time.Now().UTC(), id,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to revoke api key: %w", err)
+		}
+		return requireAffected(res, model.ErrAPIKeyNotFound)
+	})
+}
+
+// dbAPIKey stores scopes and wallet IDs as JSON arrays, SQLite having no array type.
+type dbAPIKey struct {
+	ID         uuid.UUID  `db:"id"`
+	Name       string     `db:"name"`
+	SecretHash []byte     `db:"secret_hash"`
+	Scopes     string     `db:"scopes"`
+	WalletIDs  string     `db:"wallet_ids"`
+	CreatedAt  time.Time  `db:"created_at"`
+	RotatedAt  *time.Time `db:"rotated_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+}
+
+func toDBAPIKey(key *model.APIKey) dbAPIKey {
+	return dbAPIKey{
+		ID:         key.ID,
+		Name:       key.Name,
+		SecretHash: key.SecretHash,
+		Scopes:     mustJSON(lo.Map(key.Scopes, func(s model.Scope, _ int) string { return string(s) })),
+		WalletIDs:  mustJSON(lo.Map(key.WalletIDs, func(id uuid.UUID, _ int) string { return id.String() })),
+		CreatedAt:  key.CreatedAt.UTC(),
+		RotatedAt:  key.RotatedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+func (row dbAPIKey) toModel() (*model.APIKey, error) {
+	var scopes, walletIDs []string
+	if err := json.Unmarshal([]byte(row.Scopes), &scopes); err != nil {
+		return nil, fmt.Errorf("failed to decode scopes of api key %s: %w", row.ID, err)
+	}
+	if err := json.Unmarshal([]byte(row.WalletIDs), &walletIDs); err != nil {
+		return nil, fmt.Errorf("failed to decode wallets of api key %s: %w", row.ID, err)
+	}
+	// Unlike Postgres, SQLite stores the IDs as text, so they may not parse.
+	ids := make([]uuid.UUID, len(walletIDs))
+	for i, id := range walletIDs {
+		var err error
+		if ids[i], err = uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("failed to decode wallets of api key %s: %w", row.ID, err)
+		}
+	}
+	return &model.APIKey{
+		ID:         row.ID,
+		Name:       row.Name,
+		SecretHash: row.SecretHash,
+		Scopes:     lo.Map(scopes, func(s string, _ int) model.Scope { return model.Scope(s) }),
+		WalletIDs:  ids,
+		CreatedAt:  row.CreatedAt,
+		RotatedAt:  row.RotatedAt,
+		RevokedAt:  row.RevokedAt,
+	}, nil
+}
+
+// mustJSON encodes a string slice, which cannot fail.
+func mustJSON(values []string) string {
+	data, err := json.Marshal(values)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
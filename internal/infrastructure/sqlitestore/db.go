@@ -0,0 +1,180 @@
+// Package sqlitestore implements the repository interfaces on SQLite, for
+// deployments that cannot run Postgres. It uses a pure-Go driver, so the service
+// still builds without cgo.
+//
+// SQLite allows a single writer. Transactions begin IMMEDIATE, taking the write
+// lock up front instead of failing when a read lock cannot be upgraded, and writes
+// from this process queue up in Go rather than spinning on SQLITE_BUSY. Other
+// processes sharing the file wait for the busy timeout; if it expires, the error
+// is reported as model.ErrConflict so transfers retry it.
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// defaultParams are added to the DSN unless it sets them: wait up to 5s for locks
+// held by other processes, let readers run alongside the writer, enforce foreign
+// keys, begin transactions IMMEDIATE and store times in a sortable format.
+var defaultParams = []struct{ name, value string }{
+	{"busy_timeout", "_pragma=busy_timeout(5000)"},
+	{"journal_mode", "_pragma=journal_mode(WAL)"},
+	{"foreign_keys", "_pragma=foreign_keys(1)"},
+	{"_txlock", "_txlock=immediate"},
+	{"_time_format", "_time_format=sqlite"},
+}
+
+// DB is a SQLite database opened by Open.
+type DB struct {
+	*sqlx.DB
+
+	// writer admits one write transaction of this process at a time.
+	writer chan struct{}
+
+	mu sync.Mutex
+	// committed is closed and replaced whenever this process commits a write.
+	committed chan struct{}
+}
+
+// Open opens the SQLite database at dsn, a file name or file: URI.
+func Open(dsn string) (*DB, error) {
+	for _, param := range defaultParams {
+		if strings.Contains(dsn, param.name) {
+			continue
+		}
+		if strings.Contains(dsn, "?") {
+			dsn += "&" + param.value
+		} else {
+			dsn += "?" + param.value
+		}
+	}
+
+	conn, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// sqlx does not know the driver name, but binds parameters the same way as sqlite3.
+	db := sqlx.NewDb(conn, "sqlite3")
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	return &DB{
+		DB:        db,
+		writer:    make(chan struct{}, 1),
+		committed: make(chan struct{}),
+	}, nil
+}
+
+// Changed returns a channel closed the next time this process commits a write.
+func (db *DB) Changed() <-chan struct{} {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.committed
+}
+
+func (db *DB) notifyCommitted() {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	close(db.committed)
+	db.committed = make(chan struct{})
+}
+
+type txKey struct{}
+
+// querier is the part of *sqlx.DB and *sqlx.Tx the repositories use.
+type querier interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+}
+
+// conn returns the transaction carried by ctx, or db outside of one.
+func conn(ctx context.Context, db *DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db.DB
+}
+
+// write runs fn in the transaction carried by ctx or, outside of one, in a
+// transaction of its own, so single writes also queue for the write lock.
+func write(ctx context.Context, db *DB, fn func(q querier) error) error {
+	return db.WithinTransaction(ctx, func(ctx context.Context) error {
+		return fn(conn(ctx, db))
+	})
+}
+
+// NewTransactor returns the database itself, which runs the transactions of its repositories.
+func NewTransactor(db *DB) repository.Transactor {
+	return db
+}
+
+// WithinTransaction implements repository.Transactor.
+func (db *DB) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	ctx, span := startSpan(ctx, "transaction")
+	defer func() { endSpan(span, err) }()
+
+	select {
+	case db.writer <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to begin transaction: %w", ctx.Err())
+	}
+	defer func() { <-db.writer }()
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return translateError(fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return translateError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return translateError(fmt.Errorf("failed to commit transaction: %w", err))
+	}
+	db.notifyCommitted()
+	return nil
+}
+
+// translateError marks lock timeouts as conflicts, which callers may retry.
+func translateError(err error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() & 0xff {
+		case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+			return fmt.Errorf("%w: %w", model.ErrConflict, err)
+		}
+	}
+	return err
+}
+
+// requireAffected returns notFound when an UPDATE or DELETE matched no rows.
+func requireAffected(res sql.Result, notFound error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
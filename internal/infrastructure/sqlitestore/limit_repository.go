@@ -0,0 +1,153 @@
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type limitRepositoryImpl struct {
+	db *DB
+}
+
+func NewLimitRepository(db *DB) repository.LimitRepository {
+	return &limitRepositoryImpl{db: db}
+}
+
+func (r *limitRepositoryImpl) SaveTier(ctx context.Context, tier model.TierLimits) (err error) {
+	ctx, span := startSpan(ctx, "limit_tiers.save")
+	defer func() { endSpan(span, err) }()
+
+	err = write(ctx, r.db, func(q querier) error {
+		_, err := q.NamedExecContext(ctx, `
+            INSERT INTO limit_tiers (name, max_transfer, daily_total, monthly_total, hourly_count, updated_at)
+            VALUES (:name, :max_transfer, :daily_total, :monthly_total, :hourly_count, :updated_at)
+            ON CONFLICT (name) DO UPDATE SET
+                max_transfer = excluded.max_transfer,
+                daily_total = excluded.daily_total,
+                monthly_total = excluded.monthly_total,
+                hourly_count = excluded.hourly_count,
+                updated_at = excluded.updated_at
+        `, dbTier{Name: tier.Name, dbLimits: toDBLimits(tier.Limits), UpdatedAt: tier.UpdatedAt.UTC()})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save limit tier: %w", err)
+	}
+	return nil
+}
+
+func (r *limitRepositoryImpl) FetchTier(ctx context.Context, name string) (_ model.TierLimits, err error) {
+	ctx, span := startSpan(ctx, "limit_tiers.fetch")
+	defer func() { endSpan(span, err) }()
+
+	var row dbTier
+	err = conn(ctx, r.db).GetContext(ctx, &row, `SELECT `+tierColumns+` FROM limit_tiers WHERE name = ?`, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.TierLimits{}, model.ErrTierNotFound
+	}
+	if err != nil {
+		return model.TierLimits{}, fmt.Errorf("failed to fetch limit tier: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *limitRepositoryImpl) Tiers(ctx context.Context) (_ []model.TierLimits, err error) {
+	ctx, span := startSpan(ctx, "limit_tiers.list")
+	defer func() { endSpan(span, err) }()
+
+	var rows []dbTier
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, `SELECT `+tierColumns+` FROM limit_tiers ORDER BY name`); err != nil {
+		return nil, fmt.Errorf("failed to fetch limit tiers: %w", err)
+	}
+	tiers := make([]model.TierLimits, len(rows))
+	for n, row := range rows {
+		tiers[n] = row.toModel()
+	}
+	return tiers, nil
+}
+
+func (r *limitRepositoryImpl) SaveWalletLimits(ctx context.Context, limits model.WalletLimits) (err error) {
+	ctx, span := startSpan(ctx, "wallet_limits.save")
+	defer func() { endSpan(span, err) }()
+
+	row := dbWalletLimits{WalletID: limits.WalletID, Tier: limits.Tier, dbLimits: toDBLimits(limits.Limits), UpdatedAt: limits.UpdatedAt.UTC()}
+	err = write(ctx, r.db, func(q querier) error {
+		_, err := q.NamedExecContext(ctx, `
+            INSERT INTO wallet_limits (wallet_id, tier, max_transfer, daily_total, monthly_total, hourly_count, updated_at)
+            VALUES (:wallet_id, NULLIF(:tier, ''), :max_transfer, :daily_total, :monthly_total, :hourly_count, :updated_at)
+            ON CONFLICT (wallet_id) DO UPDATE SET
+                tier = excluded.tier,
+                max_transfer = excluded.max_transfer,
+                daily_total = excluded.daily_total,
+                monthly_total = excluded.monthly_total,
+                hourly_count = excluded.hourly_count,
+                updated_at = excluded.updated_at
+        `, row)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save wallet limits: %w", err)
+	}
+	return nil
+}
+
+func (r *limitRepositoryImpl) FetchWalletLimits(ctx context.Context, walletID uuid.UUID) (_ model.WalletLimits, err error) {
+	ctx, span := startSpan(ctx, "wallet_limits.fetch")
+	defer func() { endSpan(span, err) }()
+
+	var row dbWalletLimits
+	err = conn(ctx, r.db).GetContext(ctx, &row, `
+        SELECT wallet_id, COALESCE(tier, '') AS tier, max_transfer, daily_total, monthly_total, hourly_count, updated_at
+        FROM wallet_limits
+        WHERE wallet_id = ?
+    `, walletID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.WalletLimits{WalletID: walletID}, nil
+	}
+	if err != nil {
+		return model.WalletLimits{}, fmt.Errorf("failed to fetch wallet limits: %w", err)
+	}
+	return model.WalletLimits{WalletID: row.WalletID, Tier: row.Tier, Limits: row.toModel(), UpdatedAt: row.UpdatedAt.UTC()}, nil
+}
+
+const tierColumns = `name, max_transfer, daily_total, monthly_total, hourly_count, updated_at`
+
+// dbLimits stores a limit that caps nothing as NULL.
+type dbLimits struct {
+	MaxTransfer  *int `db:"max_transfer"`
+	DailyTotal   *int `db:"daily_total"`
+	MonthlyTotal *int `db:"monthly_total"`
+	HourlyCount  *int `db:"hourly_count"`
+}
+
+func toDBLimits(l model.Limits) dbLimits {
+	return dbLimits{MaxTransfer: l.MaxTransfer, DailyTotal: l.DailyTotal, MonthlyTotal: l.MonthlyTotal, HourlyCount: l.HourlyCount}
+}
+
+func (row dbLimits) toModel() model.Limits {
+	return model.Limits{MaxTransfer: row.MaxTransfer, DailyTotal: row.DailyTotal, MonthlyTotal: row.MonthlyTotal, HourlyCount: row.HourlyCount}
+}
+
+type dbTier struct {
+	Name string `db:"name"`
+	dbLimits
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (row dbTier) toModel() model.TierLimits {
+	return model.TierLimits{Name: row.Name, Limits: row.dbLimits.toModel(), UpdatedAt: row.UpdatedAt.UTC()}
+}
+
+type dbWalletLimits struct {
+	WalletID uuid.UUID `db:"wallet_id"`
+	Tier     string    `db:"tier"`
+	dbLimits
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -0,0 +1,103 @@
+package sqlitestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type riskRepositoryImpl struct {
+	db *DB
+}
+
+func NewRiskRepository(db *DB) repository.RiskRepository {
+	return &riskRepositoryImpl{db: db}
+}
+
+func (r *riskRepositoryImpl) SaveDecision(ctx context.Context, decision model.RiskDecision) (err error) {
+	ctx, span := startSpan(ctx, "risk_decisions.save")
+	defer func() { endSpan(span, err) }()
+
+	row, err := toDBRiskDecision(decision)
+	if err != nil {
+		return err
+	}
+	err = write(ctx, r.db, func(q querier) error {
+		_, err := q.NamedExecContext(ctx, `
+            INSERT INTO risk_decisions (id, created_at, "from", "to", amount, action, fired, request_id)
+            VALUES (:id, :created_at, :from, :to, :amount, :action, :fired, NULLIF(:request_id, ''))
+        `, row)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save risk decision: %w", err)
+	}
+	return nil
+}
+
+func (r *riskRepositoryImpl) FindDecisions(ctx context.Context, filter model.RiskDecisionFilter) (_ []model.RiskDecision, err error) {
+	ctx, span := startSpan(ctx, "risk_decisions.find")
+	defer func() { endSpan(span, err) }()
+
+	var rows []dbRiskDecision
+	err = conn(ctx, r.db).SelectContext(ctx, &rows, `
+        SELECT id, created_at, "from", "to", amount, action, fired, COALESCE(request_id, '') AS request_id
+        FROM risk_decisions
+        WHERE (?1 = '' OR "from" = ?1 OR "to" = ?1)
+          AND (?2 = '' OR action = ?2)
+          AND (?3 IS NULL OR created_at >= ?3)
+          AND (?4 IS NULL OR created_at < ?4)
+        ORDER BY created_at DESC
+        LIMIT ?5
+    `, filter.WalletID, filter.Action, nullTime(filter.Since), nullTime(filter.Until), filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch risk decisions: %w", err)
+	}
+
+	decisions := make([]model.RiskDecision, len(rows))
+	for n, row := range rows {
+		if decisions[n], err = row.toModel(); err != nil {
+			return nil, err
+		}
+	}
+	return decisions, nil
+}
+
+// dbRiskDecision stores the fired rules as a JSON array.
+type dbRiskDecision struct {
+	ID        uuid.UUID `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	From      string    `db:"from"`
+	To        string    `db:"to"`
+	Amount    int       `db:"amount"`
+	Action    string    `db:"action"`
+	Fired     string    `db:"fired"`
+	RequestID string    `db:"request_id"`
+}
+
+func toDBRiskDecision(d model.RiskDecision) (dbRiskDecision, error) {
+	fired, err := json.Marshal(d.Fired)
+	if err != nil {
+		return dbRiskDecision{}, fmt.Errorf("failed to encode fired rules: %w", err)
+	}
+	return dbRiskDecision{
+		ID: d.ID, CreatedAt: d.CreatedAt.UTC(), From: d.From, To: d.To, Amount: d.Amount,
+		Action: string(d.Action), Fired: string(fired), RequestID: d.RequestID,
+	}, nil
+}
+
+func (row dbRiskDecision) toModel() (model.RiskDecision, error) {
+	var fired []model.FiredRule
+	if err := json.Unmarshal([]byte(row.Fired), &fired); err != nil {
+		return model.RiskDecision{}, fmt.Errorf("failed to decode fired rules of risk decision %s: %w", row.ID, err)
+	}
+	return model.RiskDecision{
+		ID: row.ID, CreatedAt: row.CreatedAt.UTC(), From: row.From, To: row.To, Amount: row.Amount,
+		Action: model.RiskAction(row.Action), Fired: fired, RequestID: row.RequestID,
+	}, nil
+}
@@ -0,0 +1,122 @@
+package sqlitestore_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository/repositorytest"
+	"transaction-service/internal/infrastructure/migrator"
+	"transaction-service/internal/infrastructure/sqlitestore"
+
+	"github.com/google/uuid"
+)
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Backend {
+		db := openTestDB(t)
+		return repositorytest.Backend{
+			Wallets:      sqlitestore.NewWalletRepository(db),
+			Transactions: sqlitestore.NewTransactionRepository(db),
+			APIKeys:      sqlitestore.NewAPIKeyRepository(db),
+			Chain:        sqlitestore.NewChainRepository(db),
+			Risk:         sqlitestore.NewRiskRepository(db),
+			Sanctions:    sqlitestore.NewSanctionsRepository(db),
+			Limits:       sqlitestore.NewLimitRepository(db),
+			Statuses:     sqlitestore.NewStatusRepository(db),
+			Transactor:   sqlitestore.NewTransactor(db),
+		}
+	})
+}
+
+// TestConcurrentWriters checks that writers queue for SQLite's single write lock
+// instead of failing with SQLITE_BUSY.
+func TestConcurrentWriters(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	wallets := sqlitestore.NewWalletRepository(db)
+	transactor := sqlitestore.NewTransactor(db)
+
+	id, err := wallets.Create(ctx)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				wallet, err := wallets.FetchByID(ctx, id)
+				if err != nil {
+					return err
+				}
+				wallet.Amount++
+				_, err = wallets.Update(ctx, wallet)
+				return err
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("WithinTransaction: %v", err)
+		}
+	}
+
+	wallet, err := wallets.FetchByID(ctx, id)
+	if err != nil {
+		t.Fatalf("FetchByID: %v", err)
+	}
+	if wallet.Amount != writers || wallet.Version != writers {
+		t.Errorf("wallet = %+v, want amount %d at version %d", wallet, writers, writers)
+	}
+}
+
+// openTestDB opens a new database file with every migration applied.
+// TestMalformedAPIKeyWallets checks that a wallet ID stored as text that does not
+// parse fails the read instead of panicking.
+func TestMalformedAPIKeyWallets(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	keys := sqlitestore.NewAPIKeyRepository(db)
+
+	key := &model.APIKey{ID: uuid.New(), Name: "ci", SecretHash: []byte("hash"), Scopes: []model.Scope{model.ScopeWalletsRead},
+		WalletIDs: []uuid.UUID{uuid.New()}, CreatedAt: time.Now()}
+	if err := keys.Create(ctx, key); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE api_keys SET wallet_ids = '["not-a-uuid"]'`); err != nil {
+		t.Fatalf("failed to corrupt the key: %v", err)
+	}
+	if _, err := keys.FetchByID(ctx, key.ID); err == nil {
+		t.Error("FetchByID of a key with a malformed wallet ID succeeded")
+	}
+	if _, err := keys.FetchAll(ctx); err == nil {
+		t.Error("FetchAll with a malformed wallet ID succeeded")
+	}
+}
+
+func openTestDB(t *testing.T) *sqlitestore.DB {
+	t.Helper()
+	db, err := sqlitestore.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := migrator.NewSQLite(db.DB.DB)
+	if err != nil {
+		t.Fatalf("NewSQLite: %v", err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db
+}
@@ -0,0 +1,84 @@
+package sqlitestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type statusRepositoryImpl struct {
+	db *DB
+}
+
+func NewStatusRepository(db *DB) repository.StatusRepository {
+	return &statusRepositoryImpl{db: db}
+}
+
+func (r *statusRepositoryImpl) SaveChange(ctx context.Context, change model.StatusChange) (err error) {
+	ctx, span := startSpan(ctx, "wallet_status_changes.save")
+	defer func() { endSpan(span, err) }()
+
+	err = write(ctx, r.db, func(q querier) error {
+		_, err := q.NamedExecContext(ctx, `
+            INSERT INTO wallet_status_changes (id, wallet_id, from_status, to_status, reason, actor, request_id, created_at)
+            VALUES (:id, :wallet_id, :from_status, :to_status, :reason, NULLIF(:actor, ''), NULLIF(:request_id, ''), :created_at)
+        `, toDBStatusChange(change))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save status change: %w", err)
+	}
+	return nil
+}
+
+func (r *statusRepositoryImpl) FetchChanges(ctx context.Context, walletID uuid.UUID) (_ []model.StatusChange, err error) {
+	ctx, span := startSpan(ctx, "wallet_status_changes.fetch")
+	defer func() { endSpan(span, err) }()
+
+	var rows []dbStatusChange
+	err = conn(ctx, r.db).SelectContext(ctx, &rows, `
+        SELECT id, wallet_id, from_status, to_status, reason,
+               COALESCE(actor, '') AS actor, COALESCE(request_id, '') AS request_id, created_at
+        FROM wallet_status_changes
+        WHERE wallet_id = ?
+        ORDER BY created_at
+    `, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch status changes: %w", err)
+	}
+
+	changes := make([]model.StatusChange, len(rows))
+	for n, row := range rows {
+		changes[n] = row.toModel()
+	}
+	return changes, nil
+}
+
+type dbStatusChange struct {
+	ID        uuid.UUID `db:"id"`
+	WalletID  uuid.UUID `db:"wallet_id"`
+	From      string    `db:"from_status"`
+	To        string    `db:"to_status"`
+	Reason    string    `db:"reason"`
+	Actor     string    `db:"actor"`
+	RequestID string    `db:"request_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toDBStatusChange(c model.StatusChange) dbStatusChange {
+	return dbStatusChange{
+		ID: c.ID, WalletID: c.WalletID, From: string(c.From), To: string(c.To), Reason: c.Reason,
+		Actor: c.Actor, RequestID: c.RequestID, CreatedAt: c.CreatedAt.UTC(),
+	}
+}
+
+func (row dbStatusChange) toModel() model.StatusChange {
+	return model.StatusChange{
+		ID: row.ID, WalletID: row.WalletID, From: model.WalletStatus(row.From), To: model.WalletStatus(row.To),
+		Reason: row.Reason, Actor: row.Actor, RequestID: row.RequestID, CreatedAt: row.CreatedAt.UTC(),
+	}
+}
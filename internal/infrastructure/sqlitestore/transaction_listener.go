@@ -0,0 +1,74 @@
+package sqlitestore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+	"transaction-service/internal/domain/service"
+)
+
+// pollInterval bounds the delay before transactions committed by other processes
+// sharing the database file reach the broker. SQLite has no LISTEN/NOTIFY.
+const pollInterval = time.Second
+
+// TransactionListener relays committed transactions to the local broker. Commits
+// of this process wake it immediately; others are picked up by polling.
+type TransactionListener struct {
+	db     *DB
+	repo   repository.TransactionRepository
+	broker service.TransactionBroker
+}
+
+func NewTransactionListener(db *DB, broker service.TransactionBroker) *TransactionListener {
+	return &TransactionListener{
+		db:     db,
+		repo:   NewTransactionRepository(db),
+		broker: broker,
+	}
+}
+
+// Run publishes new transactions until ctx is done.
+func (l *TransactionListener) Run(ctx context.Context) error {
+	changed := l.db.Changed()
+	var last int64
+	if err := l.db.GetContext(ctx, &last, `SELECT COALESCE(MAX(seq), 0) FROM transactions`); err != nil {
+		return fmt.Errorf("failed to read transaction log head: %w", err)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+			changed = l.db.Changed()
+		case <-ticker.C:
+		}
+
+		var err error
+		if last, err = l.catchUp(ctx, last); err != nil {
+			slog.ErrorContext(ctx, "transaction listener catch-up failed", "last_seq", last, "error", err)
+		}
+	}
+}
+
+func (l *TransactionListener) catchUp(ctx context.Context, last int64) (int64, error) {
+	for {
+		batch, err := l.repo.GetTransactionsAfter(ctx, last, model.TransactionFilter{}, 500)
+		if err != nil {
+			return last, err
+		}
+		for _, transaction := range batch {
+			l.broker.Publish(transaction)
+			last = transaction.Seq
+		}
+		if len(batch) < 500 {
+			return last, nil
+		}
+	}
+}
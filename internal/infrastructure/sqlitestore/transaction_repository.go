@@ -0,0 +1,228 @@
+package sqlitestore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// transactionColumns selects a dbTransaction.
+const transactionColumns = `id, "from", "to", amount, created_at, seq,
+    COALESCE(request_id, '') AS request_id, COALESCE(reason, '') AS reason,
+    COALESCE(prev_hash, '') AS prev_hash, COALESCE(hash, '') AS hash`
+
+type transactionRepositoryImpl struct {
+	db *DB
+}
+
+func NewTransactionRepository(db *DB) repository.TransactionRepository {
+	return &transactionRepositoryImpl{db: db}
+}
+
+func (tr *transactionRepositoryImpl) Create(ctx context.Context, transaction *model.Transaction) (_ uuid.UUID, err error) {
+	ctx, span := startSpan(ctx, "transactions.create")
+	defer func() { endSpan(span, err) }()
+
+	if transaction == nil {
+		return uuid.Nil, fmt.Errorf("transaction cannot be nil")
+	}
+
+	// seq is assigned here rather than by a sequence; holding the write lock keeps
+	// it unique and gapless, and chains transactions in its order.
+	err = write(ctx, tr.db, func(q querier) error {
+		var head string
+		if err := q.GetContext(ctx, &head, `SELECT hash FROM transaction_chain_head WHERE id = 1`); err != nil {
+			return fmt.Errorf("failed to read chain head: %w", err)
+		}
+		if err := q.GetContext(ctx, &transaction.Seq, `SELECT COALESCE(MAX(seq), 0) + 1 FROM transactions`); err != nil {
+			return fmt.Errorf("failed to assign seq: %w", err)
+		}
+		transaction.CreatedAt = time.Now().UTC()
+		transaction.Link(head)
+
+		_, err := q.ExecContext(ctx, `
+            INSERT INTO transactions (id, "from", "to", amount, created_at, seq, request_id, reason, prev_hash, hash)
+            VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)
+        `,
+			transaction.ID, transaction.From, transaction.To, transaction.Amount, transaction.CreatedAt,
+			transaction.Seq, transaction.RequestID, transaction.Reason, transaction.PrevHash, transaction.Hash,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute query: %w", err)
+		}
+		_, err = q.ExecContext(ctx, `
+            UPDATE transaction_chain_head SET seq = ?, hash = ?, count = count + 1 WHERE id = 1
+        `, transaction.Seq, transaction.Hash)
+		if err != nil {
+			return fmt.Errorf("failed to advance chain head: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	slog.DebugContext(ctx, "transaction stored",
+		"transaction_id", transaction.ID, "from_wallet", transaction.From, "to_wallet", transaction.To, "amount_cents", transaction.Amount)
+
+	return transaction.ID, nil
+}
+
+func (tr *transactionRepositoryImpl) GetTransactions(ctx context.Context, walletIDs []string) (_ []model.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions.get_transactions")
+	defer func() { endSpan(span, err) }()
+
+	var transactions []dbTransaction
+	query := `
+        SELECT ` + transactionColumns + ` FROM transactions
+        WHERE (?1 IS NULL OR "from" IN (SELECT value FROM json_each(?1)) OR "to" IN (SELECT value FROM json_each(?1)))
+        ORDER BY seq DESC
+        LIMIT 100
+    `
+	if err := conn(ctx, tr.db).SelectContext(ctx, &transactions, query, walletList(walletIDs)); err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	return lo.Map(transactions, func(transaction dbTransaction, _ int) model.Transaction {
+		return model.Transaction(transaction)
+	}), nil
+}
+
+func (tr *transactionRepositoryImpl) GetTransactionsAfter(ctx context.Context, seq int64, filter model.TransactionFilter, limit int) (_ []model.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions.get_transactions_after")
+	defer func() { endSpan(span, err) }()
+
+	var transactions []dbTransaction
+	query := `
+        SELECT ` + transactionColumns + ` FROM transactions
+        WHERE seq > ?1 AND (?2 = '' OR "from" = ?2 OR "to" = ?2)
+        ORDER BY seq ASC
+        LIMIT ?3
+    `
+	if err := conn(ctx, tr.db).SelectContext(ctx, &transactions, query, seq, filter.WalletID, limit); err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	return lo.Map(transactions, func(transaction dbTransaction, _ int) model.Transaction {
+		return model.Transaction(transaction)
+	}), nil
+}
+
+func (tr *transactionRepositoryImpl) FindTransactions(ctx context.Context, filter model.HistoryFilter) (_ []model.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions.find_transactions")
+	defer func() { endSpan(span, err) }()
+
+	var transactions []dbTransaction
+	query := `
+        SELECT ` + transactionColumns + ` FROM transactions
+        WHERE (?1 = '' OR "from" = ?1 OR "to" = ?1)
+          AND (?2 IS NULL OR created_at >= ?2)
+          AND (?3 IS NULL OR created_at < ?3)
+          AND (?4 = 0 OR amount >= ?4)
+          AND (?5 = 0 OR amount <= ?5)
+          AND (?6 = 0 OR seq < ?6)
+          AND (?8 IS NULL OR "from" IN (SELECT value FROM json_each(?8)) OR "to" IN (SELECT value FROM json_each(?8)))
+        ORDER BY seq DESC
+        LIMIT ?7
+    `
+	err = conn(ctx, tr.db).SelectContext(ctx, &transactions, query,
+		filter.WalletID, nullTime(filter.Since), nullTime(filter.Until),
+		filter.MinAmount, filter.MaxAmount, filter.BeforeSeq, filter.Limit,
+		walletList(filter.WalletIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	return lo.Map(transactions, func(transaction dbTransaction, _ int) model.Transaction {
+		return model.Transaction(transaction)
+	}), nil
+}
+
+func (tr *transactionRepositoryImpl) Totals(ctx context.Context, walletID string) (_ model.TransferTotals, err error) {
+	ctx, span := startSpan(ctx, "transactions.totals")
+	defer func() { endSpan(span, err) }()
+
+	var totals struct {
+		Sent     int64 `db:"sent"`
+		Received int64 `db:"received"`
+	}
+	query := `
+        SELECT COALESCE(SUM(CASE WHEN "from" = ?1 THEN amount END), 0) AS sent,
+               COALESCE(SUM(CASE WHEN "to" = ?1 THEN amount END), 0) AS received
+        FROM transactions WHERE "from" = ?1 OR "to" = ?1
+    `
+	if err := conn(ctx, tr.db).GetContext(ctx, &totals, query, walletID); err != nil {
+		return model.TransferTotals{}, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+	return model.TransferTotals{Sent: totals.Sent, Received: totals.Received}, nil
+}
+
+func (tr *transactionRepositoryImpl) Velocity(ctx context.Context, walletID string, since time.Time) (_ model.TransferVelocity, err error) {
+	ctx, span := startSpan(ctx, "transactions.velocity")
+	defer func() { endSpan(span, err) }()
+
+	var velocity struct {
+		Count  int   `db:"count"`
+		Amount int64 `db:"amount"`
+	}
+	query := `SELECT COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount FROM transactions WHERE "from" = ? AND created_at >= ?`
+	if err := conn(ctx, tr.db).GetContext(ctx, &velocity, query, walletID, since.UTC()); err != nil {
+		return model.TransferVelocity{}, fmt.Errorf("failed to count transactions: %w", err)
+	}
+	return model.TransferVelocity(velocity), nil
+}
+
+func (tr *transactionRepositoryImpl) HasTransferred(ctx context.Context, fromID, toID string) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "transactions.has_transferred")
+	defer func() { endSpan(span, err) }()
+
+	var found bool
+	query := `SELECT EXISTS (SELECT 1 FROM transactions WHERE "from" = ? AND "to" = ?)`
+	if err := conn(ctx, tr.db).GetContext(ctx, &found, query, fromID, toID); err != nil {
+		return false, fmt.Errorf("failed to look up transactions: %w", err)
+	}
+	return found, nil
+}
+
+// ArchivedTotals returns no totals: the retention policy archives Postgres
+// partitions only, so SQLite keeps every transaction in the log.
+func (tr *transactionRepositoryImpl) ArchivedTotals(context.Context) (map[string]model.TransferTotals, error) {
+	return map[string]model.TransferTotals{}, nil
+}
+
+// nullTime passes a zero time as NULL and any other in UTC, as created_at is stored.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+type dbTransaction struct {
+	ID        uuid.UUID `db:"id"`
+	From      string    `db:"from"`
+	To        string    `db:"to"`
+	Amount    int       `db:"amount"`
+	CreatedAt time.Time `db:"created_at"`
+	Seq       int64     `db:"seq"`
+	RequestID string    `db:"request_id"`
+	Reason    string    `db:"reason"`
+	PrevHash  string    `db:"prev_hash"`
+	Hash      string    `db:"hash"`
+}
+
+// walletList encodes a wallet filter as a JSON array for json_each, or NULL to
+// match every wallet.
+func walletList(walletIDs []string) any {
+	if walletIDs == nil {
+		return nil
+	}
+	return mustJSON(walletIDs)
+}
@@ -0,0 +1,216 @@
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type walletRepositoryImpl struct {
+	db *DB
+}
+
+func NewWalletRepository(db *DB) repository.WalletRepository {
+	return &walletRepositoryImpl{db: db}
+}
+
+func (w *walletRepositoryImpl) IsServiceInitialized(ctx context.Context) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "service_state.is_initialized")
+	defer func() { endSpan(span, err) }()
+
+	var count int
+	err = conn(ctx, w.db).QueryRowxContext(ctx, `SELECT COUNT(*) FROM service_state WHERE key = 'initialized'`).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check service state: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (w *walletRepositoryImpl) SetServiceInitialized(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "service_state.set_initialized")
+	defer func() { endSpan(span, err) }()
+
+	err = write(ctx, w.db, func(q querier) error {
+		_, err := q.ExecContext(ctx, `INSERT INTO service_state (key, value) VALUES ('initialized', 'true')`)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set service initialized: %w", err)
+	}
+	return nil
+}
+
+func (w *walletRepositoryImpl) Create(ctx context.Context) (_ uuid.UUID, err error) {
+	ctx, span := startSpan(ctx, "wallets.create")
+	defer func() { endSpan(span, err) }()
+
+	id := uuid.New()
+	err = write(ctx, w.db, func(q querier) error {
+		_, err := q.ExecContext(ctx, `INSERT INTO wallets (id, amount) VALUES (?, 0)`, id)
+		return err
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create wallet: %w", err)
+	}
+	return id, nil
+}
+
+func (w *walletRepositoryImpl) CreateSystem(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "wallets.create_system")
+	defer func() { endSpan(span, err) }()
+
+	err = write(ctx, w.db, func(q querier) error {
+		_, err := q.ExecContext(ctx, `INSERT OR IGNORE INTO wallets (id, amount, system) VALUES (?, 0, 1)`, id)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create system wallet: %w", err)
+	}
+	return nil
+}
+
+func (w *walletRepositoryImpl) FetchByID(ctx context.Context, id uuid.UUID) (_ *model.Wallet, err error) {
+	ctx, span := startSpan(ctx, "wallets.fetch_by_id")
+	defer func() { endSpan(span, err) }()
+
+	if id == uuid.Nil {
+		return nil, model.ErrInvalidWalletID
+	}
+
+	var wallet dbWallet
+	err = conn(ctx, w.db).GetContext(ctx, &wallet, `SELECT id, amount, owner_id, owner_name, status, version, system FROM wallets WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrWalletNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+
+	return wallet.toModel(), nil
+}
+
+func (w *walletRepositoryImpl) Update(ctx context.Context, wallet *model.Wallet) (_ *model.Wallet, err error) {
+	ctx, span := startSpan(ctx, "wallets.update")
+	defer func() { endSpan(span, err) }()
+
+	if wallet == nil || wallet.ID == uuid.Nil {
+		return nil, fmt.Errorf("invalid wallet data")
+	}
+
+	// Compare-and-swap on the version read by FetchByID, as in the datastore.
+	err = write(ctx, w.db, func(q querier) error {
+		res, err := q.ExecContext(ctx,
+			`UPDATE wallets SET amount = ?, status = ?, version = version + 1 WHERE id = ? AND version = ?`,
+			wallet.Amount, string(wallet.Status), wallet.ID, wallet.Version,
+		)
+		if err != nil {
+			return err
+		}
+		return requireAffected(res, model.ErrConflict)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update wallet %s at version %d: %w", wallet.ID, wallet.Version, err)
+	}
+	wallet.Version++
+	slog.DebugContext(ctx, "wallet balance updated", "wallet_id", wallet.ID, "amount_cents", wallet.Amount, "version", wallet.Version)
+
+	return wallet, nil
+}
+
+func (w *walletRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "wallets.delete")
+	defer func() { endSpan(span, err) }()
+
+	err = write(ctx, w.db, func(q querier) error {
+		_, err := q.ExecContext(ctx, `DELETE FROM wallets WHERE id = ?`, id)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete wallet: %w", err)
+	}
+	return nil
+}
+
+func (w *walletRepositoryImpl) FetchAll(ctx context.Context) (_ []*model.Wallet, err error) {
+	ctx, span := startSpan(ctx, "wallets.fetch_all")
+	defer func() { endSpan(span, err) }()
+
+	var wallets []dbWallet
+	if err := conn(ctx, w.db).SelectContext(ctx, &wallets, `SELECT id, amount, owner_id, owner_name, status, version, system FROM wallets WHERE NOT system`); err != nil {
+		return nil, fmt.Errorf("failed to fetch wallets: %w", err)
+	}
+
+	var result []*model.Wallet
+	for _, wallet := range wallets {
+		result = append(result, wallet.toModel())
+	}
+	return result, nil
+}
+
+func (w *walletRepositoryImpl) FetchByOwner(ctx context.Context, ownerID string) (_ []*model.Wallet, err error) {
+	ctx, span := startSpan(ctx, "wallets.fetch_by_owner")
+	defer func() { endSpan(span, err) }()
+
+	var wallets []dbWallet
+	query := `SELECT id, amount, owner_id, owner_name, status, version, system FROM wallets WHERE owner_id = ? AND NOT system`
+	if err := conn(ctx, w.db).SelectContext(ctx, &wallets, query, ownerID); err != nil {
+		return nil, fmt.Errorf("failed to fetch wallets: %w", err)
+	}
+
+	result := make([]*model.Wallet, 0, len(wallets))
+	for _, wallet := range wallets {
+		result = append(result, wallet.toModel())
+	}
+	return result, nil
+}
+
+func (w *walletRepositoryImpl) SetOwner(ctx context.Context, id uuid.UUID, ownerID, ownerName string) (err error) {
+	ctx, span := startSpan(ctx, "wallets.set_owner")
+	defer func() { endSpan(span, err) }()
+
+	return write(ctx, w.db, func(q querier) error {
+		res, err := q.ExecContext(ctx, `UPDATE wallets SET owner_id = NULLIF(?, ''), owner_name = NULLIF(?, '') WHERE id = ?`, ownerID, ownerName, id)
+		if err != nil {
+			return fmt.Errorf("failed to set wallet owner: %w", err)
+		}
+		return requireAffected(res, model.ErrWalletNotFound)
+	})
+}
+
+func (w *walletRepositoryImpl) Summarize(ctx context.Context) (_ model.WalletSummary, err error) {
+	ctx, span := startSpan(ctx, "wallets.summarize")
+	defer func() { endSpan(span, err) }()
+
+	var summary struct {
+		Count       int   `db:"count"`
+		TotalAmount int64 `db:"total_amount"`
+	}
+	query := `SELECT COUNT(*) AS count, COALESCE(SUM(amount), 0) AS total_amount FROM wallets WHERE NOT system`
+	if err := conn(ctx, w.db).GetContext(ctx, &summary, query); err != nil {
+		return model.WalletSummary{}, fmt.Errorf("failed to summarize wallets: %w", err)
+	}
+	return model.WalletSummary{Count: summary.Count, TotalAmount: summary.TotalAmount}, nil
+}
+
+type dbWallet struct {
+	ID        uuid.UUID      `db:"id"`
+	Amount    int            `db:"amount"`
+	OwnerID   sql.NullString `db:"owner_id"`
+	OwnerName sql.NullString `db:"owner_name"`
+	Status    string         `db:"status"`
+	Version   int64          `db:"version"`
+	System    bool           `db:"system"`
+}
+
+func (w dbWallet) toModel() *model.Wallet {
+	return &model.Wallet{
+		ID: w.ID, Amount: w.Amount, OwnerID: w.OwnerID.String, OwnerName: w.OwnerName.String,
+		Status: model.WalletStatus(w.Status), Version: w.Version, System: w.System,
+	}
+}
@@ -0,0 +1,179 @@
+package walletcache
+
+import (
+	"context"
+	"sync"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+	"transaction-service/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// Metrics records cache lookups and invalidations.
+type Metrics interface {
+	// BalanceCacheLookup records a FetchByID served from the cache or not.
+	BalanceCacheLookup(hit bool)
+
+	// BalanceCacheInvalidated records n wallets dropped from the cache.
+	BalanceCacheInvalidated(n int)
+}
+
+// Peers relays invalidations between the caches of replicas sharing a database.
+type Peers interface {
+	// Publish tells the other replicas the wallets were written. It is called once
+	// the writing transaction has ended.
+	Publish(ids ...uuid.UUID)
+}
+
+// Cache wraps the wallet repository, transactor and transaction broker of one
+// replica around a shared Store. All three must be wrapped: writes made in a
+// transaction of the wrapped transactor are invalidated once it ends, and
+// transfers relayed to the wrapped broker, including those committed by other
+// replicas, are invalidated before subscribers see them. Other writes, such as
+// status and owner changes, reach other replicas through Peers.
+type Cache struct {
+	store   Store
+	metrics Metrics
+	peers   Peers // nil when no other replica shares the database
+}
+
+// New returns a cache of wallets held in store. Writes are published to peers,
+// which may be nil for a single replica.
+func New(store Store, metrics Metrics, peers Peers) *Cache {
+	return &Cache{store: store, metrics: metrics, peers: peers}
+}
+
+// Invalidate drops wallets another replica wrote.
+func (c *Cache) Invalidate(ids ...uuid.UUID) {
+	c.invalidate(ids...)
+}
+
+// pendingKey carries the wallets written in the current transaction.
+type pendingKey struct{}
+
+type pending struct {
+	mu  sync.Mutex
+	ids []uuid.UUID
+}
+
+func (c *Cache) invalidate(ids ...uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+	c.store.Invalidate(ids...)
+	c.metrics.BalanceCacheInvalidated(len(ids))
+}
+
+// written invalidates id once the transaction carried by ctx ends, or at once
+// outside of one.
+func (c *Cache) written(ctx context.Context, id uuid.UUID) {
+	if p, ok := ctx.Value(pendingKey{}).(*pending); ok {
+		p.mu.Lock()
+		p.ids = append(p.ids, id)
+		p.mu.Unlock()
+		return
+	}
+	c.flush(id)
+}
+
+// flush invalidates written wallets here and on the other replicas.
+func (c *Cache) flush(ids ...uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+	c.invalidate(ids...)
+	if c.peers != nil {
+		c.peers.Publish(ids...)
+	}
+}
+
+// Wallets returns wallets with FetchByID served from the cache.
+func (c *Cache) Wallets(wallets repository.WalletRepository) repository.WalletRepository {
+	return &walletRepository{WalletRepository: wallets, cache: c}
+}
+
+type walletRepository struct {
+	repository.WalletRepository
+	cache *Cache
+}
+
+// FetchByID reads from the cache outside of transactions, unless ctx asks for a
+// consistent read. Misses are read from the primary, as a replica may lag behind
+// the transfers this replica has already seen.
+func (r *walletRepository) FetchByID(ctx context.Context, id uuid.UUID) (*model.Wallet, error) {
+	if _, ok := ctx.Value(pendingKey{}).(*pending); ok || model.ConsistentReadFromContext(ctx) {
+		return r.WalletRepository.FetchByID(ctx, id)
+	}
+	if wallet, ok := r.cache.store.Get(id); ok {
+		r.cache.metrics.BalanceCacheLookup(true)
+		return wallet, nil
+	}
+	r.cache.metrics.BalanceCacheLookup(false)
+
+	token := r.cache.store.Reserve()
+	wallet, err := r.WalletRepository.FetchByID(model.WithConsistentRead(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.store.Fill(wallet, token)
+	return wallet, nil
+}
+
+func (r *walletRepository) Update(ctx context.Context, wallet *model.Wallet) (*model.Wallet, error) {
+	defer r.cache.written(ctx, wallet.ID)
+	return r.WalletRepository.Update(ctx, wallet)
+}
+
+func (r *walletRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	defer r.cache.written(ctx, id)
+	return r.WalletRepository.Delete(ctx, id)
+}
+
+func (r *walletRepository) SetOwner(ctx context.Context, id uuid.UUID, ownerID, ownerName string) error {
+	defer r.cache.written(ctx, id)
+	return r.WalletRepository.SetOwner(ctx, id, ownerID, ownerName)
+}
+
+// Transactor returns transactor invalidating the wallets written in a transaction,
+// here and on the other replicas, as soon as it commits or rolls back.
+func (c *Cache) Transactor(transactor repository.Transactor) repository.Transactor {
+	return &cachingTransactor{Transactor: transactor, cache: c}
+}
+
+type cachingTransactor struct {
+	repository.Transactor
+	cache *Cache
+}
+
+func (t *cachingTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(pendingKey{}).(*pending); ok {
+		return t.Transactor.WithinTransaction(ctx, fn)
+	}
+	p := &pending{}
+	// Invalidated after a rollback too: a failed commit may have been applied.
+	defer func() { t.cache.flush(p.ids...) }()
+	return t.Transactor.WithinTransaction(context.WithValue(ctx, pendingKey{}, p), fn)
+}
+
+// Broker returns broker invalidating both wallets of every published transfer
+// before delivering it.
+func (c *Cache) Broker(broker service.TransactionBroker) service.TransactionBroker {
+	return &cachingBroker{TransactionBroker: broker, cache: c}
+}
+
+type cachingBroker struct {
+	service.TransactionBroker
+	cache *Cache
+}
+
+func (b *cachingBroker) Publish(transaction model.Transaction) {
+	var ids []uuid.UUID
+	for _, id := range []string{transaction.From, transaction.To} {
+		if id, err := uuid.Parse(id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	b.cache.invalidate(ids...)
+	b.TransactionBroker.Publish(transaction)
+}
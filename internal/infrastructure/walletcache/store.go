@@ -0,0 +1,156 @@
+// Package walletcache caches wallets read by ID in front of a WalletRepository,
+// mainly for balance lookups. Entries are invalidated when a transaction updating
+// the wallet commits, when the transaction listener relays a transfer committed by
+// another replica and when another replica reports a write through Peers, so a
+// replica never serves a balance older than the last transfer it has seen. A TTL
+// bounds staleness should a notification be lost.
+package walletcache
+
+import (
+	"sync"
+	"time"
+	"transaction-service/internal/domain/model"
+
+	"github.com/google/uuid"
+)
+
+// Store holds cached wallets. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns a copy of the cached wallet, if there is a fresh one.
+	Get(id uuid.UUID) (*model.Wallet, bool)
+
+	// Reserve returns the token to fill a wallet with once it is read from storage.
+	Reserve() Token
+
+	// Fill caches a wallet read after the token was reserved, unless the wallet was
+	// invalidated in between: the read may then predate the invalidating write.
+	Fill(wallet *model.Wallet, token Token)
+
+	// Invalidate drops the wallets and fails fills reserved before.
+	Invalidate(ids ...uuid.UUID)
+}
+
+// Token orders fills after invalidations.
+type Token struct {
+	clock    uint64
+	reserved time.Time
+}
+
+// sweepInterval limits how often a full MemoryStore scans for expired entries.
+const sweepInterval = time.Second
+
+// MemoryStore is a Store in process memory, holding up to a fixed number of wallets
+// and tombstones together.
+type MemoryStore struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu        sync.Mutex
+	clock     uint64 // Incremented by every invalidation
+	floor     uint64 // Fills reserved before this clock are rejected
+	entries   map[uuid.UUID]entry
+	lastSweep time.Time
+}
+
+// entry is a cached wallet, or a tombstone with a nil wallet recording when the
+// wallet was invalidated.
+type entry struct {
+	wallet      *model.Wallet
+	expires     time.Time
+	invalidated uint64
+}
+
+// NewMemoryStore returns a store keeping wallets for ttl and at most maxEntries
+// wallets and tombstones.
+func NewMemoryStore(ttl time.Duration, maxEntries int) *MemoryStore {
+	return &MemoryStore{ttl: ttl, maxEntries: maxEntries, entries: make(map[uuid.UUID]entry)}
+}
+
+func (s *MemoryStore) Get(id uuid.UUID) (*model.Wallet, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[id]
+	if !ok || e.wallet == nil {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(s.entries, id)
+		return nil, false
+	}
+	wallet := *e.wallet
+	return &wallet, true
+}
+
+func (s *MemoryStore) Reserve() Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Token{clock: s.clock, reserved: time.Now()}
+}
+
+func (s *MemoryStore) Fill(wallet *model.Wallet, token Token) {
+	now := time.Now()
+	// Tombstones live for a TTL, so an older reservation could miss its invalidation.
+	if now.Sub(token.reserved) >= s.ttl {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token.clock < s.floor {
+		return
+	}
+	if e, ok := s.entries[wallet.ID]; ok && e.invalidated > token.clock {
+		return
+	}
+	if _, ok := s.entries[wallet.ID]; !ok && len(s.entries) >= s.maxEntries {
+		s.sweep(now)
+		if len(s.entries) >= s.maxEntries {
+			return
+		}
+	}
+	cached := *wallet
+	s.entries[wallet.ID] = entry{wallet: &cached, expires: now.Add(s.ttl)}
+}
+
+func (s *MemoryStore) Invalidate(ids ...uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clock++
+	now := time.Now()
+	expires := now.Add(s.ttl)
+	for _, id := range ids {
+		if _, ok := s.entries[id]; !ok && len(s.entries) >= s.maxEntries {
+			s.sweep(now)
+		}
+		if _, ok := s.entries[id]; !ok && len(s.entries) >= s.maxEntries {
+			// No room for the tombstone, so every fill reserved before this
+			// invalidation is rejected instead.
+			s.floor = s.clock
+			continue
+		}
+		s.entries[id] = entry{expires: expires, invalidated: s.clock}
+	}
+}
+
+// Len returns the number of cached wallets and tombstones.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// sweep drops expired entries, at most once per sweepInterval.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for id, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, id)
+		}
+	}
+}
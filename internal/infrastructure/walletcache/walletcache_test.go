@@ -0,0 +1,298 @@
+package walletcache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/repository"
+	"transaction-service/internal/domain/service"
+	"transaction-service/internal/infrastructure/memstore"
+	"transaction-service/internal/infrastructure/walletcache"
+
+	"github.com/google/uuid"
+)
+
+type countingMetrics struct {
+	hits, misses, invalidated int
+}
+
+func (m *countingMetrics) BalanceCacheLookup(hit bool) {
+	if hit {
+		m.hits++
+	} else {
+		m.misses++
+	}
+}
+
+func (m *countingMetrics) BalanceCacheInvalidated(n int) {
+	m.invalidated += n
+}
+
+type fixture struct {
+	store      *walletcache.MemoryStore
+	metrics    *countingMetrics
+	inner      repository.WalletRepository
+	wallets    repository.WalletRepository
+	transactor repository.Transactor
+	broker     service.TransactionBroker
+}
+
+func newFixture(t *testing.T, ttl time.Duration) *fixture {
+	t.Helper()
+	mem := memstore.New()
+	f := &fixture{
+		store:   walletcache.NewMemoryStore(ttl, 100),
+		metrics: &countingMetrics{},
+		inner:   memstore.NewWalletRepository(mem),
+	}
+	cache := walletcache.New(f.store, f.metrics, nil)
+	f.wallets = cache.Wallets(f.inner)
+	f.transactor = cache.Transactor(memstore.NewTransactor(mem))
+	f.broker = cache.Broker(service.NewTransactionBroker())
+	return f
+}
+
+func (f *fixture) create(t *testing.T, amount int) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+	id, err := f.inner.Create(ctx)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	f.setAmount(t, f.inner, id, amount)
+	return id
+}
+
+func (f *fixture) setAmount(t *testing.T, wallets repository.WalletRepository, id uuid.UUID, amount int) {
+	t.Helper()
+	ctx := context.Background()
+	err := f.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		wallet, err := wallets.FetchByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		wallet.Amount = amount
+		_, err = wallets.Update(ctx, wallet)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("failed to set the balance: %v", err)
+	}
+}
+
+func (f *fixture) balance(t *testing.T, ctx context.Context, id uuid.UUID) int {
+	t.Helper()
+	wallet, err := f.wallets.FetchByID(ctx, id)
+	if err != nil {
+		t.Fatalf("FetchByID: %v", err)
+	}
+	return wallet.Amount
+}
+
+func TestHitAndMiss(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	id := f.create(t, 500)
+	ctx := context.Background()
+
+	for range 3 {
+		if got := f.balance(t, ctx, id); got != 500 {
+			t.Fatalf("balance = %d, want 500", got)
+		}
+	}
+	if f.metrics.hits != 2 || f.metrics.misses != 1 {
+		t.Errorf("hits, misses = %d, %d; want 2, 1", f.metrics.hits, f.metrics.misses)
+	}
+
+	// Callers may modify the wallet they get without affecting the cache.
+	wallet, _ := f.wallets.FetchByID(ctx, id)
+	wallet.Amount = 0
+	if got := f.balance(t, ctx, id); got != 500 {
+		t.Errorf("balance after modifying a returned wallet = %d, want 500", got)
+	}
+
+	if _, err := f.wallets.FetchByID(ctx, uuid.New()); !errors.Is(err, model.ErrWalletNotFound) {
+		t.Errorf("FetchByID of an unknown wallet = %v, want ErrWalletNotFound", err)
+	}
+}
+
+func TestInvalidatedOnCommit(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	id := f.create(t, 500)
+	ctx := context.Background()
+	f.balance(t, ctx, id)
+
+	f.setAmount(t, f.wallets, id, 300)
+	if got := f.balance(t, ctx, id); got != 300 {
+		t.Errorf("balance after a committed update = %d, want 300", got)
+	}
+
+	// Reads in a transaction bypass the cache, and a rolled back write drops the
+	// wallet all the same.
+	errRollback := errors.New("rollback")
+	err := f.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		wallet, err := f.wallets.FetchByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		wallet.Amount = 100
+		if _, err := f.wallets.Update(ctx, wallet); err != nil {
+			return err
+		}
+		if got := f.balance(t, ctx, id); got != 100 {
+			t.Errorf("balance in the transaction = %d, want 100", got)
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("WithinTransaction = %v, want the rollback error", err)
+	}
+	if got := f.balance(t, ctx, id); got != 300 {
+		t.Errorf("balance after a rollback = %d, want 300", got)
+	}
+}
+
+func TestInvalidatedOnPublish(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	from, to := f.create(t, 500), f.create(t, 0)
+	ctx := context.Background()
+	f.balance(t, ctx, from)
+	f.balance(t, ctx, to)
+
+	// Another replica commits a transfer; the cache holds on to the old balances
+	// until the listener relays it.
+	f.setAmount(t, f.inner, from, 400)
+	f.setAmount(t, f.inner, to, 100)
+	if got := f.balance(t, ctx, from); got != 500 {
+		t.Fatalf("balance before the transfer is relayed = %d, want the cached 500", got)
+	}
+	if got := f.balance(t, model.WithConsistentRead(ctx), from); got != 400 {
+		t.Errorf("consistent read = %d, want 400", got)
+	}
+
+	f.broker.Publish(model.Transaction{From: from.String(), To: to.String(), Amount: 100})
+	if got := f.balance(t, ctx, from); got != 400 {
+		t.Errorf("sender balance after the transfer is relayed = %d, want 400", got)
+	}
+	if got := f.balance(t, ctx, to); got != 100 {
+		t.Errorf("receiver balance after the transfer is relayed = %d, want 100", got)
+	}
+}
+
+func TestFillAfterInvalidationRejected(t *testing.T) {
+	store := walletcache.NewMemoryStore(time.Minute, 100)
+	wallet := &model.Wallet{ID: uuid.New(), Amount: 500}
+
+	// A read that started before a write committed may return the old balance.
+	token := store.Reserve()
+	store.Invalidate(wallet.ID)
+	store.Fill(wallet, token)
+	if _, ok := store.Get(wallet.ID); ok {
+		t.Error("fill reserved before an invalidation was cached")
+	}
+
+	store.Fill(wallet, store.Reserve())
+	if _, ok := store.Get(wallet.ID); !ok {
+		t.Error("fill reserved after the invalidation was not cached")
+	}
+}
+
+func TestTombstonesBounded(t *testing.T) {
+	store := walletcache.NewMemoryStore(time.Minute, 2)
+	wallet := &model.Wallet{ID: uuid.New(), Amount: 500}
+
+	token := store.Reserve()
+	for range 10 {
+		store.Invalidate(uuid.New())
+	}
+	if n := store.Len(); n > 2 {
+		t.Fatalf("store holds %d entries, want at most 2", n)
+	}
+
+	// The tombstone of the wallet found no room, so the older fill must still fail.
+	store.Invalidate(wallet.ID)
+	store.Fill(wallet, token)
+	if _, ok := store.Get(wallet.ID); ok {
+		t.Error("fill reserved before an untracked invalidation was cached")
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	store := walletcache.NewMemoryStore(20*time.Millisecond, 1)
+	first, second := &model.Wallet{ID: uuid.New()}, &model.Wallet{ID: uuid.New()}
+
+	store.Fill(first, store.Reserve())
+	store.Fill(second, store.Reserve())
+	if _, ok := store.Get(second.ID); ok {
+		t.Error("wallet cached beyond the maximum number of entries")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := store.Get(first.ID); ok {
+		t.Error("wallet served after its TTL")
+	}
+	store.Fill(second, store.Reserve())
+	if _, ok := store.Get(second.ID); !ok {
+		t.Error("wallet not cached once the expired entry was dropped")
+	}
+}
+
+// relay delivers the writes of one replica to the cache of another.
+type relay struct {
+	to *walletcache.Cache
+}
+
+func (r *relay) Publish(ids ...uuid.UUID) {
+	r.to.Invalidate(ids...)
+}
+
+func TestInvalidatedByPeers(t *testing.T) {
+	mem := memstore.New()
+	inner := memstore.NewWalletRepository(mem)
+	toB := &relay{}
+	a := walletcache.New(walletcache.NewMemoryStore(time.Minute, 100), &countingMetrics{}, toB)
+	b := walletcache.New(walletcache.NewMemoryStore(time.Minute, 100), &countingMetrics{}, nil)
+	toB.to = b
+	walletsA, transactorA := a.Wallets(inner), a.Transactor(memstore.NewTransactor(mem))
+	walletsB := b.Wallets(inner)
+
+	ctx := context.Background()
+	id, err := inner.Create(ctx)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	fetchB := func() *model.Wallet {
+		t.Helper()
+		wallet, err := walletsB.FetchByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FetchByID: %v", err)
+		}
+		return wallet
+	}
+	fetchB()
+
+	// Replica A freezes the wallet; no transfer is relayed, yet B drops its copy.
+	err = transactorA.WithinTransaction(ctx, func(ctx context.Context) error {
+		wallet, err := walletsA.FetchByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		wallet.Status = model.WalletFrozen
+		_, err = walletsA.Update(ctx, wallet)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("failed to freeze the wallet: %v", err)
+	}
+	if got := fetchB().Status; got != model.WalletFrozen {
+		t.Errorf("status on replica B = %s, want frozen", got)
+	}
+
+	if err := walletsA.SetOwner(ctx, id, "user:1", "Ivan Petrov"); err != nil {
+		t.Fatalf("SetOwner: %v", err)
+	}
+	if got := fetchB().OwnerID; got != "user:1" {
+		t.Errorf("owner on replica B = %q, want user:1", got)
+	}
+}
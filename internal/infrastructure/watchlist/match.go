@@ -0,0 +1,68 @@
+package watchlist
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// words splits a name into lower-case words of letters and digits, so case,
+// punctuation and separators such as the "|" of a token subject do not count.
+func words(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// similarity scores how alike a listed name and an owner are, from 0 to 1. Word
+// order is ignored. The owner may carry more words than the name, such as the
+// issuer prefix of a subject; the best run of as many words as the name has is
+// scored.
+func similarity(name, owner []string) float64 {
+	if len(name) == 0 || len(owner) == 0 {
+		return 0
+	}
+	listed := sortedJoin(name)
+	best := ratio(listed, sortedJoin(owner))
+	for start := 0; start+len(name) <= len(owner) && best < 1; start++ {
+		best = max(best, ratio(listed, sortedJoin(owner[start:start+len(name)])))
+	}
+	return best
+}
+
+func sortedJoin(words []string) string {
+	sorted := slices.Clone(words)
+	slices.Sort(sorted)
+	return strings.Join(sorted, " ")
+}
+
+// ratio is 1 minus the edit distance of a and b relative to the longer of them.
+func ratio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein counts the single-rune insertions, deletions and substitutions
+// turning a into b.
+func levenshtein(a, b []rune) int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
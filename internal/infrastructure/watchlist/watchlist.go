@@ -0,0 +1,234 @@
+// Package watchlist screens wallets against sanctions lists and blocklists kept in
+// local files, reread on a schedule. A list is a CSV file with a header naming its
+// columns:
+//
+//	kind,value,reference
+//	name,Ivan Petrov,SDN-1042
+//	wallet,5b0f4c9e-3d2a-4e71-8c6b-9a1d2e3f4a5b,
+//
+// or an XML file:
+//
+//	<watchlist>
+//	  <entry kind="name" reference="SDN-1042">Ivan Petrov</entry>
+//	  <entry kind="wallet">5b0f4c9e-3d2a-4e71-8c6b-9a1d2e3f4a5b</entry>
+//	</watchlist>
+//
+// The reference column is optional. A list is named after its file, without the
+// extension. Wallet entries match the wallet ID exactly; name entries match the
+// legal name of the wallet owner fuzzily.
+package watchlist
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"transaction-service/internal/domain/model"
+
+	"github.com/google/uuid"
+)
+
+// Lists holds the entries of the watchlist files.
+type Lists struct {
+	paths     []string
+	threshold float64
+	interval  time.Duration
+
+	mu      sync.RWMutex
+	wallets map[uuid.UUID][]entry
+	names   []entry
+}
+
+// entry is a watchlist entry with its name split into normalized words.
+type entry struct {
+	list      string
+	kind      string
+	value     string
+	reference string
+	words     []string // Of a name entry
+}
+
+// Load reads the watchlist files. A name matches an owner name when their similarity,
+// between 0 and 1, is at least threshold. Run rereads the files every interval.
+func Load(paths []string, threshold float64, interval time.Duration) (*Lists, error) {
+	if threshold <= 0 || threshold > 1 {
+		return nil, fmt.Errorf("watchlist name threshold must be above 0 and at most 1, got %v", threshold)
+	}
+	l := &Lists{paths: paths, threshold: threshold, interval: interval}
+	if err := l.Reload(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Lists) Screen(walletID uuid.UUID, ownerName string) []model.ScreeningHit {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var hits []model.ScreeningHit
+	for _, e := range l.wallets[walletID] {
+		hits = append(hits, e.hit(walletID, walletID.String(), 1))
+	}
+	if ownerName == "" {
+		return hits
+	}
+	ownerWords := words(ownerName)
+	for _, e := range l.names {
+		if score := similarity(e.words, ownerWords); score >= l.threshold {
+			hits = append(hits, e.hit(walletID, ownerName, score))
+		}
+	}
+	return hits
+}
+
+func (e entry) hit(walletID uuid.UUID, matched string, score float64) model.ScreeningHit {
+	return model.ScreeningHit{
+		List: e.list, Kind: e.kind, Entry: e.value, Reference: e.reference,
+		WalletID: walletID.String(), Matched: matched, Score: score,
+	}
+}
+
+// Reload rereads every watchlist file. When one cannot be read, the lists are
+// kept as they were.
+func (l *Lists) Reload() error {
+	wallets := make(map[uuid.UUID][]entry)
+	var names []entry
+	for _, path := range l.paths {
+		entries, err := readList(path)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if e.kind == model.WatchWallet {
+				id := uuid.MustParse(e.value)
+				wallets[id] = append(wallets[id], e)
+				continue
+			}
+			names = append(names, e)
+		}
+	}
+
+	l.mu.Lock()
+	l.wallets, l.names = wallets, names
+	l.mu.Unlock()
+	slog.Debug("watchlists loaded", "lists", len(l.paths), "wallets", len(wallets), "names", len(names))
+	return nil
+}
+
+// Run rereads the watchlist files every interval until ctx is done. Failures are
+// logged and the lists in use are kept.
+func (l *Lists) Run(ctx context.Context) error {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := l.Reload(); err != nil {
+			slog.ErrorContext(ctx, "failed to reload watchlists", "error", err)
+		}
+	}
+}
+
+// readList reads a CSV or XML watchlist file, by its extension.
+func readList(path string) ([]entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watchlist: %w", err)
+	}
+	defer f.Close()
+
+	ext := filepath.Ext(path)
+	list := strings.TrimSuffix(filepath.Base(path), ext)
+	var entries []entry
+	switch strings.ToLower(ext) {
+	case ".csv":
+		entries, err = parseCSV(f)
+	case ".xml":
+		entries, err = parseXML(f)
+	default:
+		return nil, fmt.Errorf("watchlist %s is neither .csv nor .xml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse watchlist %s: %w", path, err)
+	}
+
+	for n := range entries {
+		e := &entries[n]
+		e.list = list
+		e.value = strings.TrimSpace(e.value)
+		switch e.kind {
+		case model.WatchWallet:
+			if _, err := uuid.Parse(e.value); err != nil {
+				return nil, fmt.Errorf("entry %d of watchlist %s: %w %q", n+1, path, model.ErrInvalidWalletID, e.value)
+			}
+		case model.WatchName:
+			if e.words = words(e.value); len(e.words) == 0 {
+				return nil, fmt.Errorf("entry %d of watchlist %s has an empty name", n+1, path)
+			}
+		default:
+			return nil, fmt.Errorf("entry %d of watchlist %s has unknown kind %q", n+1, path, e.kind)
+		}
+	}
+	return entries, nil
+}
+
+func parseCSV(r io.Reader) ([]entry, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	kindCol, valueCol, referenceCol := slices.Index(header, "kind"), slices.Index(header, "value"), slices.Index(header, "reference")
+	if kindCol < 0 || valueCol < 0 {
+		return nil, errors.New("header must name the kind and value columns")
+	}
+
+	var entries []entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		e := entry{kind: record[kindCol], value: record[valueCol]}
+		if referenceCol >= 0 {
+			e.reference = record[referenceCol]
+		}
+		entries = append(entries, e)
+	}
+}
+
+func parseXML(r io.Reader) ([]entry, error) {
+	var doc struct {
+		Entries []struct {
+			Kind      string `xml:"kind,attr"`
+			Reference string `xml:"reference,attr"`
+			Value     string `xml:",chardata"`
+		} `xml:"entry"`
+	}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	entries := make([]entry, len(doc.Entries))
+	for n, e := range doc.Entries {
+		entries[n] = entry{kind: e.Kind, value: e.Value, reference: e.Reference}
+	}
+	return entries, nil
+}
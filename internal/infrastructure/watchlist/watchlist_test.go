@@ -0,0 +1,74 @@
+package watchlist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"transaction-service/internal/domain/model"
+
+	"github.com/google/uuid"
+)
+
+func TestSimilarity(t *testing.T) {
+	for _, tt := range []struct {
+		name, owner string
+		atLeast     float64
+		below       float64
+	}{
+		{"Ivan Petrov", "ivan.petrov", 1, 2},
+		{"Ivan Petrov", "Petrov, Ivan", 1, 2},
+		{"Ivan Petrov", "auth0|ivan-petrov", 1, 2},
+		{"Ivan Petrov", "Ivan Petrow", 0.9, 1},
+		{"Ivan Petrov", "Maria Sidorova", 0, 0.5},
+	} {
+		score := similarity(words(tt.name), words(tt.owner))
+		if score < tt.atLeast || score >= tt.below {
+			t.Errorf("similarity(%q, %q) = %.2f, want in [%v, %v)", tt.name, tt.owner, score, tt.atLeast, tt.below)
+		}
+	}
+}
+
+func TestScreen(t *testing.T) {
+	dir := t.TempDir()
+	listed := uuid.New()
+	csvList := filepath.Join(dir, "local.csv")
+	xmlList := filepath.Join(dir, "ofac.xml")
+	writeFile(t, csvList, "# blocked by support\nkind,value\nwallet,"+listed.String()+"\n")
+	writeFile(t, xmlList, `<watchlist><entry kind="name" reference="SDN-1">Ivan Petrov</entry></watchlist>`)
+
+	lists, err := Load([]string{csvList, xmlList}, 0.85, time.Hour)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	hits := lists.Screen(listed, "ivan.petrov")
+	if len(hits) != 2 {
+		t.Fatalf("Screen = %+v, want the wallet and the name", hits)
+	}
+	if hits[0].List != "local" || hits[0].Kind != model.WatchWallet || hits[0].Score != 1 {
+		t.Errorf("wallet hit = %+v", hits[0])
+	}
+	if hits[1].List != "ofac" || hits[1].Entry != "Ivan Petrov" || hits[1].Reference != "SDN-1" || hits[1].Matched != "ivan.petrov" {
+		t.Errorf("name hit = %+v", hits[1])
+	}
+	if hits := lists.Screen(uuid.New(), "Maria Sidorova"); len(hits) != 0 {
+		t.Errorf("Screen(unlisted) = %+v, want none", hits)
+	}
+
+	// A broken list keeps the lists in use.
+	writeFile(t, csvList, "kind,value\nwallet,nobody\n")
+	if err := lists.Reload(); err == nil {
+		t.Error("Reload of an invalid wallet ID succeeded")
+	}
+	if hits := lists.Screen(listed, ""); len(hits) != 1 {
+		t.Errorf("Screen after a failed reload = %+v, want the wallet still listed", hits)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
@@ -2,76 +2,506 @@ package interactor
 
 import (
 	"context"
-	"github.com/jmoiron/sqlx"
+	"fmt"
+	"sync"
+	"transaction-service/config"
 	"transaction-service/internal/domain/repository"
 	"transaction-service/internal/domain/service"
+	"transaction-service/internal/infrastructure/archive"
 	"transaction-service/internal/infrastructure/datastore"
+	"transaction-service/internal/infrastructure/metrics"
+	"transaction-service/internal/infrastructure/migrator"
+	"transaction-service/internal/infrastructure/ratelimit"
+	"transaction-service/internal/infrastructure/riskrules"
+	"transaction-service/internal/infrastructure/signing"
+	"transaction-service/internal/infrastructure/walletcache"
+	"transaction-service/internal/infrastructure/watchlist"
+	"transaction-service/internal/presenter/grpc/pb"
+	"transaction-service/internal/presenter/grpc/server"
 	"transaction-service/internal/presenter/http/handler"
 	"transaction-service/internal/usecase"
+	"transaction-service/migrations"
 )
 
 type Interactor interface {
 	NewWalletRepository() repository.WalletRepository
 	NewTransactionRepository() repository.TransactionRepository
+	NewAPIKeyRepository() repository.APIKeyRepository
+	NewHealthRepository() repository.HealthRepository
+	NewTransactor() repository.Transactor
 	NewWalletService() service.WalletService
 	NewTransactionService() service.TransactionService
+	NewAPIKeyService() service.APIKeyService
+	NewTokenService(verifier service.TokenVerifier) service.TokenService
+	NewHealthService() service.HealthService
+	NewTransactionBroker() service.TransactionBroker
 	NewWalletUsecase() usecase.WalletUsecase
 	NewTransactionUsecase() usecase.TransactionUsecase
+	NewAPIKeyUsecase() usecase.APIKeyUsecase
+	NewHealthUsecase() usecase.HealthUsecase
 	NewWalletHandler() handler.WalletHandler
 	NewTransactionHandler() handler.TransactionHandler
+	NewAPIKeyHandler() handler.APIKeyHandler
+	NewHealthHandler() handler.HealthHandler
+	NewChainRepository() repository.ChainRepository
+	NewChainService() service.ChainService
+	NewChainUsecase() usecase.ChainUsecase
+	NewChainHandler() handler.ChainHandler
+	NewReceiptService() service.ReceiptService
+	NewReceiptUsecase() usecase.ReceiptUsecase
+	NewReceiptHandler() handler.ReceiptHandler
+	NewRiskRepository() repository.RiskRepository
+	NewRiskService() service.RiskService
+	NewRiskUsecase() usecase.RiskUsecase
+	NewRiskHandler() handler.RiskHandler
+	NewSanctionsRepository() repository.SanctionsRepository
+	NewSanctionsService() service.SanctionsService
+	NewSanctionsUsecase() usecase.SanctionsUsecase
+	NewSanctionsHandler() handler.SanctionsHandler
+	NewLimitRepository() repository.LimitRepository
+	NewLimitService() service.LimitService
+	NewLimitUsecase() usecase.LimitUsecase
+	NewLimitHandler() handler.LimitHandler
+	NewStatusRepository() repository.StatusRepository
+	NewStatusService() service.StatusService
+	NewStatusUsecase() usecase.StatusUsecase
+	NewStatusHandler() handler.StatusHandler
 	NewAppHandler() handler.AppHandler
+	NewGRPCServer() pb.TransactionServiceServer
+	NewTransactionListener() Worker
+
+	// NewWalletCacheListener returns the worker invalidating the wallets other
+	// replicas wrote, or nil without a cache or other replicas.
+	NewWalletCacheListener() Worker
+
+	// NewReplicaHealthCheck returns the worker taking read replicas in and out of
+	// rotation, or nil when none are configured.
+	NewReplicaHealthCheck() Worker
+
+	// NewPartitionMaintenance returns the worker creating and archiving the monthly
+	// partitions of the transactions table, or nil unless the driver is postgres.
+	NewPartitionMaintenance() Worker
+
+	// NewChainCheckpointer returns the worker signing checkpoints of the transaction
+	// hash chain, or nil when no signing key is configured.
+	NewChainCheckpointer() Worker
+
+	// NewKeyReloader returns the worker rereading the signing key file, or nil when
+	// no signing key is configured.
+	NewKeyReloader() Worker
+
+	// NewRiskRulesReloader returns the worker rereading the risk rules file, or nil
+	// when no rules file is configured.
+	NewRiskRulesReloader() Worker
+
+	// NewWatchlistRefresher returns the worker rereading the sanctions lists, or nil
+	// when none are configured.
+	NewWatchlistRefresher() Worker
+	NewTransactionArchive() repository.TransactionArchive
+	NewRateLimitStore(kind string) (ratelimit.Store, error)
+	NewMetrics() *metrics.Prometheus
+	NewMigrator() (*migrator.Migrator, error)
 	InitializeService(ctx context.Context) error
+
+	// Close releases the storage connection.
+	Close() error
+}
+
+// Worker is a background task that runs until its context is cancelled.
+type Worker interface {
+	Run(ctx context.Context) error
 }
 
 type interactor struct {
-	DB *sqlx.DB
+	storage *storage
+	config  config.Config
+	broker  service.TransactionBroker
+	keys    *signing.Keyring   // nil when no signing key is configured
+	rules   *riskrules.File    // nil when no risk rules file is configured
+	lists   *watchlist.Lists   // nil when no sanctions lists are configured
+	cache   *walletcache.Cache // nil when the balance cache is disabled
+
+	schemaVersion int64
+
+	walletService service.WalletService
+
+	metricsOnce sync.Once
+	metrics     *metrics.Prometheus
+
+	healthServiceOnce sync.Once
+	healthService     service.HealthService
+}
+
+// NewInteractor opens the storage selected by cfg.DBDriver and wires the service
+// on top of it. Close releases the storage.
+func NewInteractor(cfg config.Config) (_ Interactor, err error) {
+	i := &interactor{config: cfg, broker: service.NewTransactionBroker()}
+
+	if i.schemaVersion, err = migrations.LatestVersion(); err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	if cfg.SigningKeyFile != "" {
+		if i.keys, err = signing.LoadKeyring(cfg.SigningKeyFile, cfg.SigningKeyReloadInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.RiskRulesFile != "" {
+		if i.rules, err = riskrules.Load(cfg.RiskRulesFile, cfg.RiskRulesReloadInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cfg.SanctionsListFiles) > 0 {
+		if i.lists, err = watchlist.Load(cfg.SanctionsListFiles, cfg.SanctionsNameThreshold, cfg.SanctionsRefreshInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	if i.storage, err = openStorage(cfg, i.schemaVersion); err != nil {
+		return nil, err
+	}
+
+	if cfg.BalanceCacheTTL > 0 {
+		var peers walletcache.Peers // A nil *datastore.WalletNotifier would make non-nil Peers
+		if i.storage.peers != nil {
+			peers = i.storage.peers
+		}
+		i.cache = walletcache.New(walletcache.NewMemoryStore(cfg.BalanceCacheTTL, cfg.BalanceCacheEntries), i.NewMetrics(), peers)
+		i.storage.wallets = i.cache.Wallets(i.storage.wallets)
+		i.storage.transactor = i.cache.Transactor(i.storage.transactor)
+		i.broker = i.cache.Broker(i.broker)
+	}
+
+	// Screening runs first, so a sanctioned party is blocked whatever the risk rules
+	// decide.
+	var checks []service.TransferCheck
+	if i.lists != nil {
+		checks = append(checks, i.NewSanctionsService())
+	}
+	if i.rules != nil {
+		checks = append(checks, i.NewRiskService())
+	}
+
+	// The wallet service owns the per-wallet transfer locks, so the REST and gRPC
+	// APIs must share one instance.
+	walletService, err := service.NewWalletService(
+		i.NewWalletRepository(),
+		i.NewTransactionRepository(),
+		i.NewTransactor(),
+		i.NewMetrics(),
+		service.TransferConfig{
+			Locking:      cfg.LockingStrategy,
+			MaxAttempts:  cfg.TransferMaxAttempts,
+			RetryBackoff: cfg.TransferRetryBackoff,
+		},
+		i.NewLimitService(),
+		checks...,
+	)
+	if err != nil {
+		i.Close()
+		return nil, fmt.Errorf("failed to configure transfers: %w", err)
+	}
+	i.walletService = walletService
+
+	return i, nil
 }
 
-func NewInteractor(db *sqlx.DB) Interactor {
-	return &interactor{DB: db}
+func (i *interactor) Close() error {
+	return i.storage.close()
 }
 
 type appHandler struct {
 	handler.WalletHandler
 	handler.TransactionHandler
+	handler.APIKeyHandler
+	handler.HealthHandler
+	handler.ChainHandler
+	handler.ReceiptHandler
+	handler.RiskHandler
+	handler.SanctionsHandler
+	handler.LimitHandler
+	handler.StatusHandler
+	handler.MetricsHandler
 }
 
 func (i *interactor) NewAppHandler() handler.AppHandler {
 	return &appHandler{
 		WalletHandler:      i.NewWalletHandler(),
 		TransactionHandler: i.NewTransactionHandler(),
+		APIKeyHandler:      i.NewAPIKeyHandler(),
+		HealthHandler:      i.NewHealthHandler(),
+		ChainHandler:       i.NewChainHandler(),
+		ReceiptHandler:     i.NewReceiptHandler(),
+		RiskHandler:        i.NewRiskHandler(),
+		SanctionsHandler:   i.NewSanctionsHandler(),
+		LimitHandler:       i.NewLimitHandler(),
+		StatusHandler:      i.NewStatusHandler(),
+		MetricsHandler:     handler.NewMetricsHandler(i.NewMetrics().Handler()),
+	}
+}
+
+// NewRateLimitStore returns the rate limit bucket store of the given kind: memory or postgres.
+func (i *interactor) NewRateLimitStore(kind string) (ratelimit.Store, error) {
+	switch kind {
+	case "memory":
+		return ratelimit.NewMemoryStore(), nil
+	case "postgres":
+		if i.config.DBDriver != DriverPostgres {
+			return nil, fmt.Errorf("rate limit store %q needs the %s database driver", kind, DriverPostgres)
+		}
+		return ratelimit.NewPostgresStore(i.storage.db), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", kind)
 	}
 }
 
+// NewMetrics returns the shared metrics registry fed by the services and HTTP middleware.
+func (i *interactor) NewMetrics() *metrics.Prometheus {
+	i.metricsOnce.Do(func() {
+		i.metrics = metrics.NewPrometheus(i.storage.db, i.NewWalletRepository())
+	})
+	return i.metrics
+}
+
+// NewMigrator returns the migrator of the configured database. The memory driver
+// has no schema and returns an error.
+func (i *interactor) NewMigrator() (*migrator.Migrator, error) {
+	if i.storage.migrator == nil {
+		return nil, fmt.Errorf("the %s database driver has no migrations", i.config.DBDriver)
+	}
+	return i.storage.migrator()
+}
+
+func (i *interactor) NewGRPCServer() pb.TransactionServiceServer {
+	return server.NewServer(i.NewWalletUsecase(), i.NewTransactionUsecase())
+}
+
 func (i *interactor) InitializeService(ctx context.Context) error {
 	walletService := i.NewWalletService()
-	return walletService.InitializeWallets(ctx)
+	if err := walletService.InitializeWallets(ctx); err != nil {
+		return err
+	}
+	i.NewHealthService().MarkInitialized()
+	return nil
 }
 
 func (i *interactor) NewWalletRepository() repository.WalletRepository {
-	return datastore.NewWalletRepositoryImpl(i.DB)
+	return i.storage.wallets
 }
 
 func (i *interactor) NewTransactionRepository() repository.TransactionRepository {
-	return datastore.NewTransactionRepository(i.DB)
+	return i.storage.transactions
 }
 
+func (i *interactor) NewAPIKeyRepository() repository.APIKeyRepository {
+	return i.storage.apiKeys
+}
+
+func (i *interactor) NewTransactor() repository.Transactor {
+	return i.storage.transactor
+}
+
+func (i *interactor) NewHealthRepository() repository.HealthRepository {
+	return i.storage.health
+}
+
+// NewWalletService returns the shared wallet service.
 func (i *interactor) NewWalletService() service.WalletService {
-	return service.NewWalletService(i.NewWalletRepository(), i.NewTransactionRepository())
+	return i.walletService
+}
+
+// NewHealthService returns the shared health service, which tracks the startup and
+// shutdown state reported by the readiness probe.
+func (i *interactor) NewHealthService() service.HealthService {
+	i.healthServiceOnce.Do(func() {
+		i.healthService = service.NewHealthService(i.NewHealthRepository(), i.schemaVersion)
+	})
+	return i.healthService
+}
+
+// NewTransactionBroker returns the broker shared by transaction streams and the listener.
+func (i *interactor) NewTransactionBroker() service.TransactionBroker {
+	return i.broker
 }
 
 func (i *interactor) NewTransactionService() service.TransactionService {
-	return service.NewTransactionService(i.NewTransactionRepository())
+	return service.NewTransactionService(i.NewTransactionRepository(), i.NewTransactionArchive(), i.broker)
+}
+
+// NewTransactionListener returns the worker that feeds committed transactions to the broker.
+func (i *interactor) NewTransactionListener() Worker {
+	return i.storage.listener(i.broker)
+}
+
+func (i *interactor) NewWalletCacheListener() Worker {
+	if i.cache == nil || i.storage.peers == nil {
+		return nil
+	}
+	return i.storage.peers.Listener(i.cache.Invalidate)
+}
+
+func (i *interactor) NewReplicaHealthCheck() Worker {
+	if i.storage.replicas == nil {
+		return nil
+	}
+	return i.storage.replicas
+}
+
+func (i *interactor) NewPartitionMaintenance() Worker {
+	if i.config.DBDriver != DriverPostgres {
+		return nil
+	}
+	return datastore.NewPartitions(i.storage.db, i.NewTransactionArchive(), datastore.PartitionPolicy{
+		Ahead:     i.config.TransactionPartitionsAhead,
+		Retention: i.config.TransactionRetentionMonths,
+		Interval:  i.config.TransactionMaintenanceInterval,
+	})
+}
+
+func (i *interactor) NewChainCheckpointer() Worker {
+	if i.keys == nil {
+		return nil
+	}
+	return service.NewChainCheckpointer(i.NewChainService(), i.config.ChainCheckpointInterval)
+}
+
+func (i *interactor) NewKeyReloader() Worker {
+	if i.keys == nil {
+		return nil
+	}
+	return i.keys
+}
+
+func (i *interactor) NewRiskRulesReloader() Worker {
+	if i.rules == nil {
+		return nil
+	}
+	return i.rules
+}
+
+func (i *interactor) NewWatchlistRefresher() Worker {
+	if i.lists == nil {
+		return nil
+	}
+	return i.lists
+}
+
+// NewTransactionArchive returns the archive of months moved out of the transaction log.
+func (i *interactor) NewTransactionArchive() repository.TransactionArchive {
+	return archive.New(i.config.TransactionArchiveDir)
+}
+
+func (i *interactor) NewChainRepository() repository.ChainRepository {
+	return i.storage.chain
+}
+
+func (i *interactor) NewChainService() service.ChainService {
+	return service.NewChainService(i.NewChainRepository(), i.NewTransactionRepository(), i.keyring())
+}
+
+func (i *interactor) NewReceiptService() service.ReceiptService {
+	return service.NewReceiptService(i.keyring())
+}
+
+// keyring returns the signing keys, or nil when none are configured. A nil
+// *signing.Keyring would make a non-nil service.Keyring.
+func (i *interactor) keyring() service.Keyring {
+	if i.keys == nil {
+		return nil
+	}
+	return i.keys
+}
+
+func (i *interactor) NewRiskRepository() repository.RiskRepository {
+	return i.storage.risk
+}
+
+func (i *interactor) NewRiskService() service.RiskService {
+	var rules service.RiskRules // A nil *riskrules.File would make a non-nil service.RiskRules
+	if i.rules != nil {
+		rules = i.rules
+	}
+	return service.NewRiskService(rules, i.NewTransactionRepository(), i.NewRiskRepository())
+}
+
+func (i *interactor) NewSanctionsRepository() repository.SanctionsRepository {
+	return i.storage.sanctions
+}
+
+func (i *interactor) NewSanctionsService() service.SanctionsService {
+	var lists service.Watchlists // A nil *watchlist.Lists would make a non-nil service.Watchlists
+	if i.lists != nil {
+		lists = i.lists
+	}
+	return service.NewSanctionsService(lists, i.NewWalletRepository(), i.NewSanctionsRepository(), i.NewTransactor())
+}
+
+func (i *interactor) NewLimitRepository() repository.LimitRepository {
+	return i.storage.limits
+}
+
+func (i *interactor) NewLimitService() service.LimitService {
+	return service.NewLimitService(i.NewWalletRepository(), i.NewTransactionRepository(), i.NewLimitRepository())
+}
+
+func (i *interactor) NewStatusRepository() repository.StatusRepository {
+	return i.storage.statuses
+}
+
+func (i *interactor) NewStatusService() service.StatusService {
+	return service.NewStatusService(i.NewWalletRepository(), i.NewStatusRepository(), i.NewTransactor())
+}
+
+func (i *interactor) NewAPIKeyService() service.APIKeyService {
+	return service.NewAPIKeyService(i.NewAPIKeyRepository())
+}
+
+func (i *interactor) NewTokenService(verifier service.TokenVerifier) service.TokenService {
+	return service.NewTokenService(verifier, i.NewWalletRepository())
 }
 
 func (i *interactor) NewWalletUsecase() usecase.WalletUsecase {
-	return usecase.NewWalletUsecase(i.NewWalletService())
+	return usecase.NewWalletUsecase(i.NewWalletService(), i.NewReceiptService())
 }
 
 func (i *interactor) NewTransactionUsecase() usecase.TransactionUsecase {
 	return usecase.NewTransactionUsecase(i.NewTransactionService())
 }
 
+func (i *interactor) NewAPIKeyUsecase() usecase.APIKeyUsecase {
+	return usecase.NewAPIKeyUsecase(i.NewAPIKeyService())
+}
+
+func (i *interactor) NewChainUsecase() usecase.ChainUsecase {
+	return usecase.NewChainUsecase(i.NewChainService())
+}
+
+func (i *interactor) NewReceiptUsecase() usecase.ReceiptUsecase {
+	return usecase.NewReceiptUsecase(i.NewReceiptService())
+}
+
+func (i *interactor) NewRiskUsecase() usecase.RiskUsecase {
+	return usecase.NewRiskUsecase(i.NewRiskService())
+}
+
+func (i *interactor) NewSanctionsUsecase() usecase.SanctionsUsecase {
+	return usecase.NewSanctionsUsecase(i.NewSanctionsService())
+}
+
+func (i *interactor) NewLimitUsecase() usecase.LimitUsecase {
+	return usecase.NewLimitUsecase(i.NewLimitService())
+}
+
+func (i *interactor) NewStatusUsecase() usecase.StatusUsecase {
+	return usecase.NewStatusUsecase(i.NewStatusService())
+}
+
+func (i *interactor) NewHealthUsecase() usecase.HealthUsecase {
+	return usecase.NewHealthUsecase(i.NewHealthService())
+}
+
 func (i *interactor) NewWalletHandler() handler.WalletHandler {
 	return handler.NewWalletHandler(i.NewWalletUsecase())
 }
@@ -79,3 +509,35 @@ func (i *interactor) NewWalletHandler() handler.WalletHandler {
 func (i *interactor) NewTransactionHandler() handler.TransactionHandler {
 	return handler.NewTransactionHandler(i.NewTransactionUsecase())
 }
+
+func (i *interactor) NewAPIKeyHandler() handler.APIKeyHandler {
+	return handler.NewAPIKeyHandler(i.NewAPIKeyUsecase())
+}
+
+func (i *interactor) NewHealthHandler() handler.HealthHandler {
+	return handler.NewHealthHandler(i.NewHealthUsecase())
+}
+
+func (i *interactor) NewChainHandler() handler.ChainHandler {
+	return handler.NewChainHandler(i.NewChainUsecase())
+}
+
+func (i *interactor) NewReceiptHandler() handler.ReceiptHandler {
+	return handler.NewReceiptHandler(i.NewReceiptUsecase())
+}
+
+func (i *interactor) NewRiskHandler() handler.RiskHandler {
+	return handler.NewRiskHandler(i.NewRiskUsecase())
+}
+
+func (i *interactor) NewSanctionsHandler() handler.SanctionsHandler {
+	return handler.NewSanctionsHandler(i.NewSanctionsUsecase())
+}
+
+func (i *interactor) NewLimitHandler() handler.LimitHandler {
+	return handler.NewLimitHandler(i.NewLimitUsecase())
+}
+
+func (i *interactor) NewStatusHandler() handler.StatusHandler {
+	return handler.NewStatusHandler(i.NewStatusUsecase())
+}
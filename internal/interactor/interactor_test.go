@@ -0,0 +1,684 @@
+package interactor_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"transaction-service/config"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/infrastructure/signing"
+	"transaction-service/internal/interactor"
+	"transaction-service/internal/presenter/http/middleware"
+	"transaction-service/internal/presenter/http/router"
+	"transaction-service/internal/usecase"
+	"transaction-service/pkg/receipt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo"
+)
+
+// TestEmbeddedDrivers serves the HTTP API from the storage drivers that need no
+// database server.
+func TestEmbeddedDrivers(t *testing.T) {
+	for _, driver := range []string{interactor.DriverMemory, interactor.DriverSQLite} {
+		for _, locking := range []string{"pessimistic", "optimistic"} {
+			t.Run(driver+"/"+locking, func(t *testing.T) {
+				cfg := config.Config{DBDriver: driver, LockingStrategy: locking, TransferMaxAttempts: 5,
+					BalanceCacheTTL: time.Minute, BalanceCacheEntries: 100, TransactionArchiveDir: t.TempDir(),
+					SigningKeyFile: writeSigningKey(t), SigningKeyReloadInterval: 10 * time.Millisecond,
+					RiskRulesFile: writeRiskRules(t, riskRules), RiskRulesReloadInterval: 10 * time.Millisecond,
+					SanctionsListFiles: []string{writeWatchlist(t)}, SanctionsRefreshInterval: time.Hour, SanctionsNameThreshold: 0.85}
+				if driver == interactor.DriverSQLite {
+					cfg.DBDSN = filepath.Join(t.TempDir(), "test.db")
+				}
+				testHTTPAPI(t, cfg)
+			})
+		}
+	}
+}
+
+func testHTTPAPI(t *testing.T, cfg config.Config) {
+	i, err := interactor.NewInteractor(cfg)
+	if err != nil {
+		t.Fatalf("NewInteractor: %v", err)
+	}
+	defer i.Close()
+
+	e := echo.New()
+	router.NewRouter(e, i.NewAppHandler(), router.Middleware{Auth: middleware.NewAnonymousAuth()})
+
+	if cfg.DBDriver != interactor.DriverMemory {
+		if rec := serve(e, http.MethodGet, "/readyz", ""); rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("GET /readyz before migrating = %d, want 503", rec.Code)
+		}
+		m, err := i.NewMigrator()
+		if err != nil {
+			t.Fatalf("NewMigrator: %v", err)
+		}
+		if _, err := m.Up(context.Background()); err != nil {
+			t.Fatalf("Up: %v", err)
+		}
+	}
+	if err := i.InitializeService(context.Background()); err != nil {
+		t.Fatalf("InitializeService: %v", err)
+	}
+
+	if rec := serve(e, http.MethodGet, "/readyz", ""); rec.Code != http.StatusOK {
+		t.Fatalf("GET /readyz = %d: %s", rec.Code, rec.Body)
+	}
+
+	var wallets []model.Wallet
+	rec := serve(e, http.MethodGet, "/api/wallets", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &wallets); err != nil || len(wallets) != 10 {
+		t.Fatalf("GET /api/wallets = %d %s, want 10 wallets", rec.Code, rec.Body)
+	}
+	from, to := wallets[0].ID.String(), wallets[1].ID.String()
+
+	body := `{"from":"` + from + `","to":"` + to + `","amount":0.4}`
+	rec = serve(e, http.MethodPost, "/api/send", body)
+	var sent struct {
+		Status      string                 `json:"status"`
+		Transaction usecase.TransactionDTO `json:"transaction"`
+		Receipt     json.RawMessage        `json:"receipt"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &sent); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/send = %d: %s", rec.Code, rec.Body)
+	}
+	if sent.Status != "success" || sent.Transaction.Seq != 11 || sent.Transaction.Hash == "" {
+		t.Errorf("POST /api/send = %s, want the transaction with seq 11", rec.Body)
+	}
+	body = `{"from":"` + from + `","to":"` + to + `","amount":1}`
+	if rec := serve(e, http.MethodPost, "/api/send", body); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("POST /api/send overdraft = %d, want %d: %s", rec.Code, http.StatusUnprocessableEntity, rec.Body)
+	}
+
+	for id, want := range map[string]string{from: `{"balance":0.6}`, to: `{"balance":1.4}`} {
+		rec := serve(e, http.MethodGet, "/api/wallet/"+id+"/balance", "")
+		if got := strings.TrimSpace(rec.Body.String()); got != want {
+			t.Errorf("balance of %s = %s, want %s", id, got, want)
+		}
+	}
+
+	var transactions []map[string]any
+	rec = serve(e, http.MethodGet, "/api/transactions?count=20", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &transactions); err != nil || len(transactions) != 11 {
+		t.Errorf("GET /api/transactions = %d %s, want 10 initial balances and one transfer", rec.Code, rec.Body)
+	}
+
+	// A month archived before the log began is searched once the log runs out.
+	archived, err := i.NewTransactionArchive().Create(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	err = archived.Write(model.Transaction{
+		ID: uuid.New(), From: to, To: from, Amount: 700, CreatedAt: time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil || archived.Commit() != nil {
+		t.Fatalf("failed to archive a transaction: %v", err)
+	}
+	history := func(query string) []map[string]any {
+		t.Helper()
+		var transactions []map[string]any
+		rec := serve(e, http.MethodGet, "/api/history?wallet="+from+query, "")
+		if err := json.Unmarshal(rec.Body.Bytes(), &transactions); err != nil {
+			t.Fatalf("GET /api/history%s = %d %s", query, rec.Code, rec.Body)
+		}
+		return transactions
+	}
+	if got := history(""); len(got) != 2 {
+		t.Errorf("history = %d transactions, want the initial balance and the transfer", len(got))
+	}
+	if got := history("&archived=true"); len(got) != 3 || got[2]["amount"] != 7.0 {
+		t.Errorf("history with archived months = %v, want the archived transaction last", got)
+	}
+	if got := history("&archived=true&limit=2"); len(got) != 2 {
+		t.Errorf("history with archived months and limit 2 = %d transactions", len(got))
+	}
+
+	body = `{"to":"` + to + `","amount":2.5,"reason":"bonus"}`
+	if rec := serve(e, http.MethodPost, "/api/admin/mint", body); rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/admin/mint = %d: %s", rec.Code, rec.Body)
+	}
+	body = `{"from":"` + to + `","amount":0.5}`
+	if rec := serve(e, http.MethodPost, "/api/admin/burn", body); rec.Code != http.StatusBadRequest {
+		t.Errorf("POST /api/admin/burn without reason = %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body)
+	}
+	body = `{"from":"` + to + `","amount":0.5,"reason":"chargeback"}`
+	if rec := serve(e, http.MethodPost, "/api/admin/burn", body); rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/admin/burn = %d: %s", rec.Code, rec.Body)
+	}
+	rec = serve(e, http.MethodGet, "/api/admin/supply", "")
+	want := `{"issued":12.5,"burned":0.5,"circulating":12,"wallet_total":12,"balanced":true}`
+	if got := strings.TrimSpace(rec.Body.String()); got != want {
+		t.Errorf("GET /api/admin/supply = %s, want %s", got, want)
+	}
+
+	if rec := serve(e, http.MethodPost, "/api/admin/keys", `{"scopes":["admin"]}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("POST /api/admin/keys without a name = %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body)
+	}
+
+	testChain(t, e, cfg)
+	testReceipts(t, e, i, cfg, sent.Receipt, from, to)
+	testRisk(t, e, i, cfg, from)
+	testSanctions(t, e, from, to)
+	testLimits(t, e, wallets[2].ID.String(), wallets[3].ID.String())
+	testStatus(t, e, wallets[4].ID.String(), wallets[5].ID.String())
+}
+
+func testChain(t *testing.T, e *echo.Echo, cfg config.Config) {
+	verify := func() usecase.ChainVerificationDTO {
+		t.Helper()
+		var report usecase.ChainVerificationDTO
+		rec := serve(e, http.MethodGet, "/api/admin/chain/verify", "")
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("GET /api/admin/chain/verify = %d %s", rec.Code, rec.Body)
+		}
+		return report
+	}
+	// 10 initial balances, a transfer, a mint and a burn.
+	if report := verify(); !report.Intact || report.Checked != 13 || report.Head.Count != 13 {
+		t.Errorf("verification = %+v, want 13 transactions in an intact chain", report)
+	}
+
+	var checkpoint usecase.CheckpointDTO
+	rec := serve(e, http.MethodPost, "/api/admin/chain/checkpoints", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &checkpoint); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/admin/chain/checkpoints = %d %s", rec.Code, rec.Body)
+	}
+	key, err := signing.LoadKey(cfg.SigningKeyFile)
+	if err != nil {
+		t.Fatalf("LoadKey: %v", err)
+	}
+	createdAt, _ := time.Parse(time.RFC3339Nano, checkpoint.CreatedAt)
+	signature, _ := base64.StdEncoding.DecodeString(checkpoint.Signature)
+	payload := model.Checkpoint{
+		ChainHead: model.ChainHead{Seq: checkpoint.Seq, Hash: checkpoint.Hash, Count: checkpoint.Count},
+		CreatedAt: createdAt, KeyID: checkpoint.KeyID,
+	}.SignedPayload()
+	if checkpoint.KeyID != key.KeyID() || !ed25519.Verify(key.PublicKey(), payload, signature) {
+		t.Errorf("checkpoint %+v is not signed by the configured key", checkpoint)
+	}
+
+	var checkpoints []usecase.CheckpointDTO
+	rec = serve(e, http.MethodGet, "/api/admin/chain/checkpoints", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &checkpoints); err != nil || len(checkpoints) != 1 || checkpoints[0] != checkpoint {
+		t.Errorf("GET /api/admin/chain/checkpoints = %d %s, want the checkpoint just made", rec.Code, rec.Body)
+	}
+	if report := verify(); !report.Intact || report.Checkpoints != 1 {
+		t.Errorf("verification after a checkpoint = %+v, want it compared with the checkpoint", report)
+	}
+
+	if cfg.DBDriver != interactor.DriverSQLite {
+		return
+	}
+	// Editing a transaction behind the service's back breaks its link.
+	db, err := sqlx.Open("sqlite", cfg.DBDSN)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`UPDATE transactions SET amount = amount + 1 WHERE seq = 11`); err != nil {
+		t.Fatalf("failed to tamper with a transaction: %v", err)
+	}
+	if report := verify(); report.Intact || report.Broken == nil || report.Broken.Seq != 11 {
+		t.Errorf("verification after tampering = %+v, want the link of seq 11 broken", report)
+	}
+	if _, err := db.Exec(`UPDATE transactions SET amount = amount - 1 WHERE seq = 11`); err != nil {
+		t.Fatalf("failed to restore the transaction: %v", err)
+	}
+
+	// Archiving the oldest transaction leaves the log linking to its hash.
+	_, err = db.Exec(`INSERT INTO archived_chain (month, seq, hash, count)
+        SELECT '2024-01-01', seq, hash, 1 FROM transactions WHERE seq = 1`)
+	if err != nil {
+		t.Fatalf("failed to record the archived chain: %v", err)
+	}
+	if _, err := db.Exec(`DELETE FROM transactions WHERE seq = 1`); err != nil {
+		t.Fatalf("failed to archive a transaction: %v", err)
+	}
+	if report := verify(); !report.Intact || report.Checked != 12 {
+		t.Errorf("verification after archiving = %+v, want 12 transactions in an intact chain", report)
+	}
+	if _, err := db.Exec(`UPDATE archived_chain SET count = 2`); err != nil {
+		t.Fatalf("failed to tamper with the archived count: %v", err)
+	}
+	if report := verify(); report.Intact || report.Broken == nil || report.Broken.Seq != report.Head.Seq {
+		t.Errorf("verification with a miscounted archive = %+v, want the head count broken", report)
+	}
+	if _, err := db.Exec(`UPDATE archived_chain SET count = 1, hash = 'forged'`); err != nil {
+		t.Fatalf("failed to tamper with the archived hash: %v", err)
+	}
+	if report := verify(); report.Intact || report.Broken == nil || report.Broken.Seq != 2 {
+		t.Errorf("verification with a forged archived hash = %+v, want the link of seq 2 broken", report)
+	}
+}
+
+// testReceipts verifies the receipt of the first transfer offline, then rotates the
+// signing key and checks receipts of both keys still verify.
+func testReceipts(t *testing.T, e *echo.Echo, i interactor.Interactor, cfg config.Config, first json.RawMessage, from, to string) {
+	keySet := func() receipt.KeySet {
+		t.Helper()
+		rec := serve(e, http.MethodGet, "/api/keys", "")
+		keys, err := receipt.ParseKeySet(rec.Body.Bytes())
+		if err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("GET /api/keys = %d %s", rec.Code, rec.Body)
+		}
+		return keys
+	}
+	firstReceipt, err := receipt.Parse(first)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	keys := keySet()
+	if len(keys.Keys) != 1 || keys.Keys[0].Status != usecase.SigningKeyCurrent {
+		t.Fatalf("GET /api/keys = %+v, want the configured key", keys)
+	}
+	if err := keys.Verify(firstReceipt); err != nil {
+		t.Errorf("Verify of the transfer receipt: %v", err)
+	}
+	if firstReceipt.AmountCents != 40 || firstReceipt.Seq != 11 {
+		t.Errorf("receipt = %+v, want the transfer of 40 cents with seq 11", firstReceipt)
+	}
+	altered := firstReceipt
+	altered.To = from
+	if err := keys.Verify(altered); !errors.Is(err, receipt.ErrInvalidSignature) {
+		t.Errorf("Verify of an altered receipt = %v, want ErrInvalidSignature", err)
+	}
+
+	// Rotate: the new key goes first and the old one is kept after it.
+	old, err := os.ReadFile(cfg.SigningKeyFile)
+	if err != nil {
+		t.Fatalf("failed to read signing key: %v", err)
+	}
+	if err := os.WriteFile(cfg.SigningKeyFile, append(signingKeyPEM(t), old...), 0o600); err != nil {
+		t.Fatalf("failed to write signing key: %v", err)
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go i.NewKeyReloader().Run(ctx)
+	deadline := time.Now().Add(5 * time.Second)
+	for keys = keySet(); len(keys.Keys) != 2; keys = keySet() {
+		if time.Now().After(deadline) {
+			t.Fatalf("GET /api/keys = %+v, want the rotated key set", keys)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if keys.Keys[1].ID != firstReceipt.KeyID || keys.Keys[1].Status != usecase.SigningKeyRetired {
+		t.Errorf("GET /api/keys = %+v, want the old key retired", keys)
+	}
+	if err := keys.Verify(firstReceipt); err != nil {
+		t.Errorf("Verify of a receipt signed before the rotation: %v", err)
+	}
+
+	body := `{"from":"` + to + `","to":"` + from + `","amount":0.1}`
+	rec := serve(e, http.MethodPost, "/api/send", body)
+	var sent struct {
+		Receipt receipt.Receipt `json:"receipt"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &sent); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/send = %d: %s", rec.Code, rec.Body)
+	}
+	if sent.Receipt.KeyID != keys.Keys[0].ID {
+		t.Errorf("receipt after the rotation is signed with %s, want the new key %s", sent.Receipt.KeyID, keys.Keys[0].ID)
+	}
+	if err := keys.Verify(sent.Receipt); err != nil {
+		t.Errorf("Verify of a receipt signed after the rotation: %v", err)
+	}
+}
+
+// blockedWallet is denied by riskRules. No such wallet exists, so the transfers
+// the rules let through fail with 404.
+const blockedWallet = "5b0f4c9e-3d2a-4e71-8c6b-9a1d2e3f4a5b"
+
+// riskRules flags the first transfer of the test for review and denies transfers
+// to blockedWallet.
+const riskRules = `
+rule "large" {
+  type       = "amount"
+  action     = "review"
+  min_amount = 0.3
+}
+
+rule "blocked" {
+  type    = "blocklist"
+  action  = "deny"
+  wallets = ["` + blockedWallet + `"]
+}
+`
+
+func testRisk(t *testing.T, e *echo.Echo, i interactor.Interactor, cfg config.Config, from string) {
+	body := `{"from":"` + from + `","to":"` + blockedWallet + `","amount":0.1}`
+	rec := serve(e, http.MethodPost, "/api/send", body)
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "blocked") {
+		t.Fatalf("POST /api/send to a blocklisted wallet = %d %s, want 422 naming the rule", rec.Code, rec.Body)
+	}
+
+	decisions := func(query string) []usecase.RiskDecisionDTO {
+		t.Helper()
+		var decisions []usecase.RiskDecisionDTO
+		rec := serve(e, http.MethodGet, "/api/admin/risk/decisions"+query, "")
+		if err := json.Unmarshal(rec.Body.Bytes(), &decisions); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("GET /api/admin/risk/decisions%s = %d %s", query, rec.Code, rec.Body)
+		}
+		return decisions
+	}
+	denied := decisions("?action=deny")
+	if len(denied) != 1 || denied[0].To != blockedWallet || len(denied[0].Fired) != 1 || denied[0].Fired[0].Rule != "blocked" {
+		t.Fatalf("denied decisions = %+v, want the transfer to the blocklisted wallet", denied)
+	}
+	if !strings.Contains(rec.Body.String(), denied[0].ID) {
+		t.Errorf("POST /api/send to a blocklisted wallet = %s, want the decision ID %s", rec.Body, denied[0].ID)
+	}
+	// The first transfer and the overdraft after it were both at least 0.3.
+	if reviewed := decisions("?action=review&wallet=" + from); len(reviewed) != 2 || reviewed[1].Amount != 0.4 {
+		t.Errorf("reviewed decisions of %s = %+v, want the first transfer and the overdraft", from, reviewed)
+	}
+	if rec := serve(e, http.MethodGet, "/api/admin/risk/decisions?action=block", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("GET /api/admin/risk/decisions with an unknown action = %d, want 400", rec.Code)
+	}
+
+	// Without the blocklist rule the transfer gets as far as looking up the wallet.
+	if err := os.WriteFile(cfg.RiskRulesFile, []byte(riskRules[:strings.Index(riskRules, `rule "blocked"`)]), 0o600); err != nil {
+		t.Fatalf("failed to write risk rules: %v", err)
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go i.NewRiskRulesReloader().Run(ctx)
+	deadline := time.Now().Add(5 * time.Second)
+	for rec := serve(e, http.MethodPost, "/api/send", body); rec.Code != http.StatusNotFound; rec = serve(e, http.MethodPost, "/api/send", body) {
+		if time.Now().After(deadline) {
+			t.Fatalf("POST /api/send after removing the blocklist = %d %s, want 404", rec.Code, rec.Body)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// sanctionedName is listed by the watchlist of the test.
+const sanctionedName = "Ivan Petrov"
+
+func testSanctions(t *testing.T, e *echo.Echo, from, to string) {
+	if rec := serve(e, http.MethodPut, "/api/admin/wallets/"+to+"/owner", `{"owner_id":"auth0|5f1c2a","owner_name":"`+sanctionedName+`"}`); rec.Code != http.StatusOK {
+		t.Fatalf("PUT owner = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(e, http.MethodGet, "/api/wallets", ""); strings.Contains(rec.Body.String(), sanctionedName) {
+		t.Errorf("GET /api/wallets = %s, want the owner name left out", rec.Body)
+	}
+	var owner usecase.WalletOwnerDTO
+	if rec := serve(e, http.MethodGet, "/api/admin/wallets/"+to+"/owner", ""); json.Unmarshal(rec.Body.Bytes(), &owner) != nil ||
+		owner.OwnerID != "auth0|5f1c2a" || owner.OwnerName != sanctionedName {
+		t.Errorf("GET owner = %d %s, want auth0|5f1c2a named %s", rec.Code, rec.Body, sanctionedName)
+	}
+	body := `{"from":"` + from + `","to":"` + to + `","amount":0.1}`
+	rec := serve(e, http.MethodPost, "/api/send", body)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("POST /api/send to a sanctioned owner = %d %s, want 422", rec.Code, rec.Body)
+	}
+
+	var cases []usecase.SanctionsCaseDTO
+	if rec := serve(e, http.MethodGet, "/api/admin/sanctions/cases?status=open&wallet="+to, ""); json.Unmarshal(rec.Body.Bytes(), &cases) != nil {
+		t.Fatalf("GET /api/admin/sanctions/cases = %d %s", rec.Code, rec.Body)
+	}
+	if len(cases) != 1 || len(cases[0].Hits) != 1 || cases[0].Hits[0].Entry != sanctionedName || cases[0].Hits[0].Party != model.PartyReceiver {
+		t.Fatalf("open cases = %+v, want the blocked transfer matching %q", cases, sanctionedName)
+	}
+	if !strings.Contains(rec.Body.String(), cases[0].ID) {
+		t.Errorf("POST /api/send to a sanctioned owner = %s, want the case ID %s", rec.Body, cases[0].ID)
+	}
+
+	resolve := "/api/admin/sanctions/cases/" + cases[0].ID + "/resolve"
+	for _, tt := range []struct {
+		body string
+		code int
+	}{
+		{`{"status":"false_positive"}`, http.StatusBadRequest},
+		{`{"status":"open","note":"checked"}`, http.StatusBadRequest},
+		{`{"status":"false_positive","note":"a different person"}`, http.StatusOK},
+		{`{"status":"confirmed","note":"changed my mind"}`, http.StatusConflict},
+	} {
+		if rec := serve(e, http.MethodPost, resolve, tt.body); rec.Code != tt.code {
+			t.Errorf("POST %s %s = %d %s, want %d", resolve, tt.body, rec.Code, rec.Body, tt.code)
+		}
+	}
+	if rec := serve(e, http.MethodPost, "/api/admin/sanctions/cases/"+uuid.NewString()+"/resolve", `{"status":"confirmed","note":"x"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("POST resolve of an unknown case = %d, want 404", rec.Code)
+	}
+
+	var whitelist []usecase.WhitelistEntryDTO
+	if rec := serve(e, http.MethodGet, "/api/admin/sanctions/whitelist?wallet="+to, ""); json.Unmarshal(rec.Body.Bytes(), &whitelist) != nil {
+		t.Fatalf("GET /api/admin/sanctions/whitelist = %d %s", rec.Code, rec.Body)
+	}
+	if len(whitelist) != 1 || whitelist[0].Entry != sanctionedName || whitelist[0].CaseID != cases[0].ID {
+		t.Errorf("whitelist = %+v, want the false positive", whitelist)
+	}
+
+	// The false positive no longer blocks the owner.
+	if rec := serve(e, http.MethodPost, "/api/send", body); rec.Code != http.StatusOK {
+		t.Errorf("POST /api/send after whitelisting = %d: %s", rec.Code, rec.Body)
+	}
+
+	// Administrators change the whitelist by hand, always with a reason.
+	entry := `{"wallet_id":"` + to + `","entry":"` + sanctionedName + `"`
+	for _, tt := range []struct {
+		method string
+		body   string
+		code   int
+	}{
+		{http.MethodDelete, entry + `}`, http.StatusBadRequest},
+		{http.MethodDelete, entry + `,"reason":"passport expired"}`, http.StatusOK},
+		{http.MethodDelete, entry + `,"reason":"passport expired"}`, http.StatusNotFound},
+		{http.MethodPost, entry + `}`, http.StatusBadRequest},
+		{http.MethodPost, `{"wallet_id":"` + to + `","reason":"new passport"}`, http.StatusBadRequest},
+	} {
+		if rec := serve(e, tt.method, "/api/admin/sanctions/whitelist", tt.body); rec.Code != tt.code {
+			t.Errorf("%s /api/admin/sanctions/whitelist %s = %d %s, want %d", tt.method, tt.body, rec.Code, rec.Body, tt.code)
+		}
+	}
+	if rec := serve(e, http.MethodPost, "/api/send", body); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("POST /api/send after removing the whitelist entry = %d, want 422", rec.Code)
+	}
+	var added usecase.WhitelistEntryDTO
+	rec = serve(e, http.MethodPost, "/api/admin/sanctions/whitelist", entry+`,"reason":"new passport checked"}`)
+	if err := json.Unmarshal(rec.Body.Bytes(), &added); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/admin/sanctions/whitelist = %d %s", rec.Code, rec.Body)
+	}
+	if added.Entry != sanctionedName || added.CaseID != "" || added.Reason != "new passport checked" {
+		t.Errorf("whitelist entry added by hand = %+v", added)
+	}
+	if rec := serve(e, http.MethodPost, "/api/send", body); rec.Code != http.StatusOK {
+		t.Errorf("POST /api/send after whitelisting by hand = %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func testLimits(t *testing.T, e *echo.Echo, from, to string) {
+	limits := "/api/admin/wallets/" + from + "/limits"
+	if rec := serve(e, http.MethodPut, limits, `{"tier":"gold"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("PUT %s with an unknown tier = %d, want 404", limits, rec.Code)
+	}
+	if rec := serve(e, http.MethodPut, "/api/admin/limits/tiers/gold", `{"max_transfer":-1}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("PUT a tier with a negative limit = %d, want 400", rec.Code)
+	}
+	if rec := serve(e, http.MethodPut, "/api/admin/limits/tiers/gold", `{"max_transfer":0.5,"daily_total":0.3}`); rec.Code != http.StatusOK {
+		t.Fatalf("PUT /api/admin/limits/tiers/gold = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(e, http.MethodPut, limits, `{"tier":"gold","limits":{"hourly_count":2}}`); rec.Code != http.StatusOK {
+		t.Fatalf("PUT %s = %d: %s", limits, rec.Code, rec.Body)
+	}
+
+	// The tier caps the day at 0.3 and the wallet overrides it with two transfers an hour.
+	for _, tt := range []struct {
+		amount string
+		code   int
+	}{
+		{"0.6", http.StatusUnprocessableEntity},
+		{"0.4", http.StatusUnprocessableEntity},
+		{"0.2", http.StatusOK},
+		{"0.2", http.StatusUnprocessableEntity},
+		{"0.1", http.StatusOK},
+		{"0.01", http.StatusUnprocessableEntity},
+	} {
+		body := `{"from":"` + from + `","to":"` + to + `","amount":` + tt.amount + `}`
+		if rec := serve(e, http.MethodPost, "/api/send", body); rec.Code != tt.code {
+			t.Errorf("POST /api/send of %s = %d %s, want %d", tt.amount, rec.Code, rec.Body, tt.code)
+		}
+	}
+
+	var allowance usecase.AllowanceDTO
+	if rec := serve(e, http.MethodGet, "/api/wallet/"+from+"/limits", ""); json.Unmarshal(rec.Body.Bytes(), &allowance) != nil {
+		t.Fatalf("GET /api/wallet/%s/limits = %d %s", from, rec.Code, rec.Body)
+	}
+	remaining := allowance.Remaining
+	if allowance.Tier != "gold" || allowance.Used.DailyTotal != 0.3 || allowance.Used.HourlyCount != 2 ||
+		remaining.MaxTransfer == nil || *remaining.MaxTransfer != 0 || remaining.MonthlyTotal != nil {
+		t.Errorf("allowance = %+v, want the gold tier used up", allowance)
+	}
+	// Limits apply to SendMoney only.
+	if rec := serve(e, http.MethodPost, "/api/admin/burn", `{"from":"`+from+`","amount":0.1,"reason":"chargeback"}`); rec.Code != http.StatusOK {
+		t.Errorf("POST /api/admin/burn over the limits = %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func testStatus(t *testing.T, e *echo.Echo, id, other string) {
+	status := "/api/admin/wallets/" + id + "/status"
+	transfer := func(from, to string, code int) {
+		t.Helper()
+		body := `{"from":"` + from + `","to":"` + to + `","amount":0.1}`
+		if rec := serve(e, http.MethodPost, "/api/send", body); rec.Code != code {
+			t.Errorf("POST /api/send from %s to %s = %d %s, want %d", from, to, rec.Code, rec.Body, code)
+		}
+	}
+	for _, tt := range []struct {
+		body string
+		code int
+	}{
+		{`{"status":"frozen"}`, http.StatusBadRequest},
+		{`{"status":"paused","reason":"x"}`, http.StatusBadRequest},
+		{`{"status":"frozen","reason":"court order"}`, http.StatusOK},
+		{`{"status":"frozen","reason":"again"}`, http.StatusConflict},
+	} {
+		if rec := serve(e, http.MethodPut, status, tt.body); rec.Code != tt.code {
+			t.Errorf("PUT %s %s = %d %s, want %d", status, tt.body, rec.Code, rec.Body, tt.code)
+		}
+	}
+	// A frozen wallet neither sends nor, unlike a debit-blocked one, receives.
+	transfer(id, other, http.StatusUnprocessableEntity)
+	transfer(other, id, http.StatusUnprocessableEntity)
+	if rec := serve(e, http.MethodPost, "/api/admin/mint", `{"to":"`+id+`","amount":0.5,"reason":"refund"}`); rec.Code != http.StatusOK {
+		t.Errorf("POST /api/admin/mint into a frozen wallet = %d: %s", rec.Code, rec.Body)
+	}
+
+	if rec := serve(e, http.MethodPut, status, `{"status":"debit_blocked","reason":"partial release"}`); rec.Code != http.StatusOK {
+		t.Fatalf("PUT %s debit_blocked = %d: %s", status, rec.Code, rec.Body)
+	}
+	transfer(id, other, http.StatusUnprocessableEntity)
+	transfer(other, id, http.StatusOK)
+
+	closed := `{"status":"closed","reason":"customer request"}`
+	if rec := serve(e, http.MethodPut, status, closed); rec.Code != http.StatusConflict {
+		t.Errorf("PUT %s closed with money left = %d, want 409", status, rec.Code)
+	}
+	if rec := serve(e, http.MethodPost, "/api/admin/burn", `{"from":"`+id+`","amount":1.6,"reason":"payout"}`); rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/admin/burn of the whole balance = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(e, http.MethodPut, status, closed); rec.Code != http.StatusOK {
+		t.Fatalf("PUT %s closed = %d: %s", status, rec.Code, rec.Body)
+	}
+	if rec := serve(e, http.MethodPut, status, `{"status":"active","reason":"reopen"}`); rec.Code != http.StatusConflict {
+		t.Errorf("PUT %s active after closing = %d, want 409", status, rec.Code)
+	}
+	transfer(other, id, http.StatusUnprocessableEntity)
+	if rec := serve(e, http.MethodPost, "/api/admin/mint", `{"to":"`+id+`","amount":0.5,"reason":"refund"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("POST /api/admin/mint into a closed wallet = %d, want 422", rec.Code)
+	}
+
+	var wallets []model.Wallet
+	if rec := serve(e, http.MethodGet, "/api/wallets", ""); json.Unmarshal(rec.Body.Bytes(), &wallets) != nil {
+		t.Fatalf("GET /api/wallets = %d %s", rec.Code, rec.Body)
+	}
+	for _, w := range wallets {
+		if w.ID.String() == id && w.Status != model.WalletClosed {
+			t.Errorf("GET /api/wallets lists %s as %q, want closed", id, w.Status)
+		}
+	}
+
+	var history []usecase.StatusChangeDTO
+	if rec := serve(e, http.MethodGet, status+"/history", ""); json.Unmarshal(rec.Body.Bytes(), &history) != nil {
+		t.Fatalf("GET %s/history = %d %s", status, rec.Code, rec.Body)
+	}
+	var got []string
+	for _, change := range history {
+		got = append(got, change.From+">"+change.To+": "+change.Reason)
+	}
+	want := []string{"active>frozen: court order", "frozen>debit_blocked: partial release", "debit_blocked>closed: customer request"}
+	if !slices.Equal(got, want) {
+		t.Errorf("status history = %q, want %q", got, want)
+	}
+	if rec := serve(e, http.MethodGet, "/api/admin/wallets/"+uuid.NewString()+"/status/history", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("GET the status history of an unknown wallet = %d, want 404", rec.Code)
+	}
+}
+
+// writeWatchlist writes a watchlist listing sanctionedName and returns its path.
+func writeWatchlist(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sdn.xml")
+	list := `<watchlist><entry kind="name" reference="SDN-1">` + sanctionedName + `</entry></watchlist>`
+	if err := os.WriteFile(path, []byte(list), 0o600); err != nil {
+		t.Fatalf("failed to write watchlist: %v", err)
+	}
+	return path
+}
+
+// writeRiskRules writes a risk rules file and returns its path.
+func writeRiskRules(t *testing.T, rules string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "risk_rules.hcl")
+	if err := os.WriteFile(path, []byte(rules), 0o600); err != nil {
+		t.Fatalf("failed to write risk rules: %v", err)
+	}
+	return path
+}
+
+// writeSigningKey writes a new Ed25519 private key to a PEM file and returns its path.
+func writeSigningKey(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	if err := os.WriteFile(path, signingKeyPEM(t), 0o600); err != nil {
+		t.Fatalf("failed to write signing key: %v", err)
+	}
+	return path
+}
+
+// signingKeyPEM returns a new Ed25519 private key in PEM.
+func signingKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func serve(e *echo.Echo, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,143 @@
+package interactor
+
+import (
+	"errors"
+	"fmt"
+	"transaction-service/config"
+	"transaction-service/internal/domain/repository"
+	"transaction-service/internal/domain/service"
+	"transaction-service/internal/infrastructure/datastore"
+	"transaction-service/internal/infrastructure/memstore"
+	"transaction-service/internal/infrastructure/migrator"
+	"transaction-service/internal/infrastructure/sqlitestore"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Storage drivers selectable with config.Config.DBDriver.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+// storage is the set of repositories of the configured database driver.
+type storage struct {
+	db       *sqlx.DB            // Connection pool, nil for the memory driver
+	replicas *datastore.Replicas // Read replicas of a Postgres database, nil for none
+
+	wallets      repository.WalletRepository
+	transactions repository.TransactionRepository
+	apiKeys      repository.APIKeyRepository
+	chain        repository.ChainRepository
+	risk         repository.RiskRepository
+	sanctions    repository.SanctionsRepository
+	limits       repository.LimitRepository
+	statuses     repository.StatusRepository
+	health       repository.HealthRepository
+	transactor   repository.Transactor
+	listener     func(broker service.TransactionBroker) Worker
+	peers        *datastore.WalletNotifier          // Wallet caches of other replicas, nil unless the driver is postgres
+	migrator     func() (*migrator.Migrator, error) // nil when there is no schema to migrate
+}
+
+// openStorage connects to the storage selected by cfg.DBDriver. schemaVersion is
+// reported by storage without migrations.
+func openStorage(cfg config.Config, schemaVersion int64) (*storage, error) {
+	if len(cfg.DBReplicaDSNs) > 0 && cfg.DBDriver != DriverPostgres {
+		return nil, fmt.Errorf("database replicas need the %s driver, not %s", DriverPostgres, cfg.DBDriver)
+	}
+	if cfg.TransactionRetentionMonths > 0 && cfg.DBDriver != DriverPostgres {
+		return nil, fmt.Errorf("transaction retention needs the %s driver, not %s", DriverPostgres, cfg.DBDriver)
+	}
+
+	switch cfg.DBDriver {
+	case DriverPostgres:
+		db, err := sqlx.Connect("postgres", cfg.DSN())
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		var replicas *datastore.Replicas
+		if len(cfg.DBReplicaDSNs) > 0 {
+			if replicas, err = datastore.OpenReplicas(cfg.DBReplicaDSNs, cfg.DBReplicaCheckInterval); err != nil {
+				db.Close()
+				return nil, err
+			}
+		}
+		return &storage{
+			db:           db,
+			replicas:     replicas,
+			wallets:      datastore.NewWalletRepositoryImpl(db, replicas),
+			transactions: datastore.NewTransactionRepository(db, replicas),
+			apiKeys:      datastore.NewAPIKeyRepository(db),
+			chain:        datastore.NewChainRepository(db),
+			risk:         datastore.NewRiskRepository(db),
+			sanctions:    datastore.NewSanctionsRepository(db),
+			limits:       datastore.NewLimitRepository(db),
+			statuses:     datastore.NewStatusRepository(db),
+			health:       datastore.NewHealthRepository(db),
+			transactor:   datastore.NewTransactor(db),
+			listener: func(broker service.TransactionBroker) Worker {
+				return datastore.NewTransactionListener(db, cfg.DSN(), broker)
+			},
+			peers: datastore.NewWalletNotifier(db, cfg.DSN()),
+			migrator: func() (*migrator.Migrator, error) {
+				return migrator.NewPostgres(db.DB)
+			},
+		}, nil
+	case DriverSQLite:
+		db, err := sqlitestore.Open(cfg.DSN())
+		if err != nil {
+			return nil, err
+		}
+		return &storage{
+			db:           db.DB,
+			wallets:      sqlitestore.NewWalletRepository(db),
+			transactions: sqlitestore.NewTransactionRepository(db),
+			apiKeys:      sqlitestore.NewAPIKeyRepository(db),
+			chain:        sqlitestore.NewChainRepository(db),
+			risk:         sqlitestore.NewRiskRepository(db),
+			sanctions:    sqlitestore.NewSanctionsRepository(db),
+			limits:       sqlitestore.NewLimitRepository(db),
+			statuses:     sqlitestore.NewStatusRepository(db),
+			health:       sqlitestore.NewHealthRepository(db),
+			transactor:   sqlitestore.NewTransactor(db),
+			listener: func(broker service.TransactionBroker) Worker {
+				return sqlitestore.NewTransactionListener(db, broker)
+			},
+			migrator: func() (*migrator.Migrator, error) {
+				return migrator.NewSQLite(db.DB.DB)
+			},
+		}, nil
+	case DriverMemory:
+		store := memstore.New()
+		return &storage{
+			wallets:      memstore.NewWalletRepository(store),
+			transactions: memstore.NewTransactionRepository(store),
+			apiKeys:      memstore.NewAPIKeyRepository(store),
+			chain:        memstore.NewChainRepository(store),
+			risk:         memstore.NewRiskRepository(store),
+			sanctions:    memstore.NewSanctionsRepository(store),
+			limits:       memstore.NewLimitRepository(store),
+			statuses:     memstore.NewStatusRepository(store),
+			health:       memstore.NewHealthRepository(schemaVersion),
+			transactor:   memstore.NewTransactor(store),
+			listener: func(broker service.TransactionBroker) Worker {
+				return memstore.NewTransactionListener(store, broker)
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.DBDriver)
+	}
+}
+
+func (s *storage) close() error {
+	var errs []error
+	if s.replicas != nil {
+		errs = append(errs, s.replicas.Close())
+	}
+	if s.db != nil {
+		errs = append(errs, s.db.Close())
+	}
+	return errors.Join(errs...)
+}
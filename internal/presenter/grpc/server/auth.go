@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/presenter/grpc/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataAPIKey carries the API key of the caller, as the X-API-Key header does
+// for the REST API. End users send a bearer token in the authorization metadata
+// instead.
+const MetadataAPIKey = "x-api-key"
+
+const metadataAuthorization = "authorization"
+
+// methodScopes is the scope each RPC requires, mirroring the REST routes. Calls to
+// methods missing here are refused.
+var methodScopes = map[string]model.Scope{
+	pb.TransactionService_SendMoney_FullMethodName:         model.ScopeTransfersWrite,
+	pb.TransactionService_GetBalance_FullMethodName:        model.ScopeWalletsRead,
+	pb.TransactionService_ListWallets_FullMethodName:       model.ScopeWalletsRead,
+	pb.TransactionService_ListTransactions_FullMethodName:  model.ScopeWalletsRead,
+	pb.TransactionService_WatchTransactions_FullMethodName: model.ScopeWalletsRead,
+}
+
+// Authenticator resolves API credentials to the caller they belong to.
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (*model.Principal, error)
+}
+
+// Auth authenticates gRPC calls and checks the scope of the method, like the
+// Auth and RequireScope middleware of the REST API.
+type Auth struct {
+	apiKeys   Authenticator
+	tokens    Authenticator
+	anonymous *model.Principal
+}
+
+// NewAuth rejects calls without valid credentials and stores the caller's
+// principal in the call context. Services authenticate with an API key in the
+// x-api-key metadata, end users with a bearer token; tokens may be nil to accept
+// API keys only.
+func NewAuth(apiKeys, tokens Authenticator) *Auth {
+	return &Auth{apiKeys: apiKeys, tokens: tokens}
+}
+
+// NewAnonymousAuth lets every call through as an unrestricted admin. It is used
+// when authentication is disabled in the config.
+func NewAnonymousAuth() *Auth {
+	return &Auth{anonymous: &model.Principal{Subject: "anonymous", Scopes: []model.Scope{model.ScopeAdmin}, AllWallets: true}}
+}
+
+// Unary is the interceptor of unary calls.
+func (a *Auth) Unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// Stream is the interceptor of streaming calls.
+func (a *Auth) Stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+func (a *Auth) authenticate(ctx context.Context, method string) (context.Context, error) {
+	scope, ok := methodScopes[method]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "method %s is not allowed", method)
+	}
+
+	principal := a.anonymous
+	if principal == nil {
+		authenticator, credential, ok := a.credential(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "missing credentials")
+		}
+		var err error
+		principal, err = authenticator.Authenticate(ctx, credential)
+		if errors.Is(err, model.ErrUnauthenticated) {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	if !principal.HasScope(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "missing scope %s", scope)
+	}
+	return model.WithPrincipal(ctx, principal), nil
+}
+
+// credential picks the API key, or else the bearer token, of the call.
+func (a *Auth) credential(ctx context.Context) (Authenticator, string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keys := md.Get(MetadataAPIKey); len(keys) > 0 && keys[0] != "" {
+		return a.apiKeys, keys[0], true
+	}
+	for _, value := range md.Get(metadataAuthorization) {
+		if token, ok := strings.CutPrefix(value, "Bearer "); ok && a.tokens != nil {
+			return a.tokens, strings.TrimSpace(token), true
+		}
+	}
+	return nil, "", false
+}
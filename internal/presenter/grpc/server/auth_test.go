@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/presenter/grpc/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// staticAuthenticator knows a single credential.
+type staticAuthenticator struct {
+	credential string
+	principal  *model.Principal
+}
+
+func (a staticAuthenticator) Authenticate(_ context.Context, credential string) (*model.Principal, error) {
+	if credential != a.credential {
+		return nil, model.ErrUnauthenticated
+	}
+	return a.principal, nil
+}
+
+func TestAuthUnary(t *testing.T) {
+	reader := &model.Principal{Subject: "reader", Scopes: []model.Scope{model.ScopeWalletsRead}}
+	user := &model.Principal{Subject: "user", Scopes: []model.Scope{model.ScopeWalletsRead, model.ScopeTransfersWrite}}
+	auth := NewAuth(staticAuthenticator{"key", reader}, staticAuthenticator{"token", user})
+
+	tests := []struct {
+		name     string
+		auth     *Auth
+		method   string
+		metadata metadata.MD
+		want     codes.Code
+		subject  string
+	}{
+		{"no credentials", auth, pb.TransactionService_GetBalance_FullMethodName, nil, codes.Unauthenticated, ""},
+		{"unknown key", auth, pb.TransactionService_GetBalance_FullMethodName, metadata.Pairs(MetadataAPIKey, "other"), codes.Unauthenticated, ""},
+		{"api key", auth, pb.TransactionService_GetBalance_FullMethodName, metadata.Pairs(MetadataAPIKey, "key"), codes.OK, "reader"},
+		{"missing scope", auth, pb.TransactionService_SendMoney_FullMethodName, metadata.Pairs(MetadataAPIKey, "key"), codes.PermissionDenied, ""},
+		{"bearer token", auth, pb.TransactionService_SendMoney_FullMethodName, metadata.Pairs("authorization", "Bearer token"), codes.OK, "user"},
+		{"unknown method", auth, "/transaction.v1.TransactionService/Unknown", metadata.Pairs(MetadataAPIKey, "key"), codes.PermissionDenied, ""},
+		{"anonymous", NewAnonymousAuth(), pb.TransactionService_SendMoney_FullMethodName, nil, codes.OK, "anonymous"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.metadata)
+			var subject string
+			_, err := tt.auth.Unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, _ any) (any, error) {
+				if p, ok := model.PrincipalFromContext(ctx); ok {
+					subject = p.Subject
+				}
+				return nil, nil
+			})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("code = %s, want %s (%v)", got, tt.want, err)
+			}
+			if subject != tt.subject {
+				t.Errorf("principal = %q, want %q", subject, tt.subject)
+			}
+		})
+	}
+}
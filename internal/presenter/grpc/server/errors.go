@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"transaction-service/internal/domain/model"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus maps domain errors to gRPC status codes. Anything unrecognised is
+// reported as Internal.
+func toStatus(err error) error {
+	var code codes.Code
+	switch {
+	case errors.Is(err, model.ErrInvalidWalletID),
+		errors.Is(err, model.ErrInvalidAmount),
+		errors.Is(err, model.ErrSameWallet),
+		errors.Is(err, model.ErrInvalidCursor),
+		errors.Is(err, model.ErrInvalidFilter),
+		errors.Is(err, model.ErrSystemWallet),
+		errors.Is(err, model.ErrReasonRequired),
+		errors.Is(err, model.ErrInvalidStatus):
+		code = codes.InvalidArgument
+	case errors.Is(err, model.ErrUnauthenticated):
+		code = codes.Unauthenticated
+	case errors.Is(err, model.ErrForbidden):
+		code = codes.PermissionDenied
+	case errors.Is(err, model.ErrWalletNotFound):
+		code = codes.NotFound
+	case errors.Is(err, model.ErrInsufficientFunds),
+		errors.Is(err, model.ErrWalletNotEmpty),
+		errors.Is(err, model.ErrTransferDenied),
+		errors.Is(err, model.ErrSanctionsHit),
+		errors.Is(err, model.ErrLimitExceeded),
+		errors.Is(err, model.ErrWalletStatus),
+		errors.Is(err, model.ErrStatusTransition):
+		code = codes.FailedPrecondition
+	case errors.Is(err, model.ErrConflict):
+		code = codes.Aborted
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	default:
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
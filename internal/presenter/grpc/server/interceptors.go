@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"time"
+	"transaction-service/internal/domain/model"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataRequestID carries the request ID, accepted from clients and returned in the response header.
+const MetadataRequestID = "x-request-id"
+
+// MetadataConsistentRead set to true makes the call read from the primary database,
+// as the X-Consistent-Read header does for the REST API.
+const MetadataConsistentRead = "x-consistent-read"
+
+// validRequestID limits client-supplied IDs to something safe to log and store,
+// the same as the X-Request-ID header of the REST API.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// UnaryRequestID stores the client's request ID, or a generated one, in the call
+// context, returns it as response metadata and logs the call. A consistent read
+// requested in the metadata is stored in the context too.
+func UnaryRequestID(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx = withConsistentRead(withRequestID(ctx))
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logCall(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+// StreamRequestID is UnaryRequestID for streaming calls.
+func StreamRequestID(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := withConsistentRead(withRequestID(ss.Context()))
+	start := time.Now()
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	logCall(ctx, info.FullMethod, start, err)
+	return err
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataRequestID); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if !validRequestID.MatchString(id) {
+		id = uuid.NewString()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataRequestID, id))
+	return model.WithRequestID(ctx, id)
+}
+
+func withConsistentRead(ctx context.Context) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataConsistentRead); len(values) > 0 {
+			if consistent, _ := strconv.ParseBool(values[0]); consistent {
+				return model.WithConsistentRead(ctx)
+			}
+		}
+	}
+	return ctx
+}
+
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	level := slog.LevelInfo
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss:
+		level = slog.LevelError
+	}
+	slog.LogAttrs(ctx, level, "rpc served", attrs...)
+}
+
+// contextStream overrides the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,145 @@
+// Package server implements the gRPC API on top of the application usecases.
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"math"
+	"strconv"
+	"transaction-service/internal/presenter/grpc/pb"
+	"transaction-service/internal/usecase"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type transactionServer struct {
+	pb.UnimplementedTransactionServiceServer
+
+	WalletUsecase      usecase.WalletUsecase
+	TransactionUsecase usecase.TransactionUsecase
+}
+
+// NewServer creates the gRPC TransactionService backed by the given usecases.
+func NewServer(walletUsecase usecase.WalletUsecase, transactionUsecase usecase.TransactionUsecase) pb.TransactionServiceServer {
+	return &transactionServer{
+		WalletUsecase:      walletUsecase,
+		TransactionUsecase: transactionUsecase,
+	}
+}
+
+func (s *transactionServer) SendMoney(ctx context.Context, req *pb.SendMoneyRequest) (*pb.SendMoneyResponse, error) {
+	if req.GetAmount() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must be greater than zero")
+	}
+
+	transfer, err := s.WalletUsecase.SendMoney(ctx, req.GetFrom(), req.GetTo(), fromCents(req.GetAmount()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.SendMoneyResponse{Transaction: toProtoTransaction(transfer.Transaction)}
+	if transfer.Receipt != nil {
+		resp.Receipt = toProtoReceipt(*transfer.Receipt)
+	}
+	return resp, nil
+}
+
+func (s *transactionServer) GetBalance(ctx context.Context, req *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error) {
+	balance, err := s.WalletUsecase.GetBalance(ctx, req.GetWalletId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.GetBalanceResponse{Balance: toCents(balance)}, nil
+}
+
+func (s *transactionServer) ListWallets(ctx context.Context, _ *pb.ListWalletsRequest) (*pb.ListWalletsResponse, error) {
+	wallets, err := s.WalletUsecase.GetAllWallets(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.ListWalletsResponse{Wallets: make([]*pb.Wallet, len(wallets))}
+	for i, w := range wallets {
+		resp.Wallets[i] = &pb.Wallet{Id: w.ID.String(), Balance: int64(w.Amount), Status: string(w.Status)}
+	}
+	return resp, nil
+}
+
+func (s *transactionServer) ListTransactions(ctx context.Context, req *pb.ListTransactionsRequest) (*pb.ListTransactionsResponse, error) {
+	if req.GetCount() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "count must be greater than zero")
+	}
+
+	transactions, err := s.TransactionUsecase.GetLastTransactions(ctx, int(req.GetCount()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.ListTransactionsResponse{Transactions: make([]*pb.Transaction, len(transactions))}
+	for i, t := range transactions {
+		resp.Transactions[i] = toProtoTransaction(t)
+	}
+	return resp, nil
+}
+
+func (s *transactionServer) WatchTransactions(req *pb.WatchTransactionsRequest, stream grpc.ServerStreamingServer[pb.Transaction]) error {
+	var lastEventID string
+	if req.AfterSeq != nil {
+		lastEventID = strconv.FormatInt(req.GetAfterSeq(), 10)
+	}
+
+	transactions, err := s.TransactionUsecase.StreamTransactions(stream.Context(), req.GetWalletId(), lastEventID)
+	if err != nil {
+		return toStatus(err)
+	}
+
+	for t := range transactions {
+		if err := stream.Send(toProtoTransaction(t)); err != nil {
+			return err
+		}
+	}
+	return stream.Context().Err()
+}
+
+func toProtoTransaction(t usecase.TransactionDTO) *pb.Transaction {
+	return &pb.Transaction{
+		Id:        t.ID,
+		From:      t.From,
+		To:        t.To,
+		Amount:    toCents(t.Amount),
+		CreatedAt: t.CreatedAt,
+		Seq:       t.Seq,
+		RequestId: t.RequestID,
+		Reason:    t.Reason,
+		PrevHash:  t.PrevHash,
+		Hash:      t.Hash,
+	}
+}
+
+func toProtoReceipt(r usecase.ReceiptDTO) *pb.Receipt {
+	// The usecase encodes the signature for JSON; it is always valid base64.
+	signature, _ := base64.StdEncoding.DecodeString(r.Signature)
+	return &pb.Receipt{
+		TransactionId: r.TransactionID,
+		From:          r.From,
+		To:            r.To,
+		AmountCents:   int64(r.AmountCents),
+		CreatedAt:     r.CreatedAt,
+		Seq:           r.Seq,
+		Hash:          r.Hash,
+		IssuedAt:      r.IssuedAt,
+		KeyId:         r.KeyID,
+		Signature:     signature,
+	}
+}
+
+// The usecases speak in whole currency units while the gRPC contract uses cents.
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
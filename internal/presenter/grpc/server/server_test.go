@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/presenter/grpc/pb"
+	"transaction-service/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+// walletLister serves GetAllWallets; the other methods are not called.
+type walletLister struct {
+	usecase.WalletUsecase
+	wallets []*model.Wallet
+}
+
+func (l walletLister) GetAllWallets(context.Context) ([]*model.Wallet, error) {
+	return l.wallets, nil
+}
+
+func TestListWalletsStatus(t *testing.T) {
+	frozen := &model.Wallet{ID: uuid.New(), Amount: 150, Status: model.WalletFrozen}
+	server := NewServer(walletLister{wallets: []*model.Wallet{frozen}}, nil)
+
+	resp, err := server.ListWallets(context.Background(), &pb.ListWalletsRequest{})
+	if err != nil {
+		t.Fatalf("ListWallets: %v", err)
+	}
+	if len(resp.GetWallets()) != 1 {
+		t.Fatalf("ListWallets = %v, want one wallet", resp.GetWallets())
+	}
+	if got := resp.GetWallets()[0]; got.GetId() != frozen.ID.String() || got.GetBalance() != 150 || got.GetStatus() != "frozen" {
+		t.Errorf("wallet = %v, want %s with 150 cents, frozen", got, frozen.ID)
+	}
+}
+
+// transactionLister serves GetLastTransactions; the other methods are not called.
+type transactionLister struct {
+	usecase.TransactionUsecase
+	transactions []usecase.TransactionDTO
+}
+
+func (l transactionLister) GetLastTransactions(context.Context, int) ([]usecase.TransactionDTO, error) {
+	return l.transactions, nil
+}
+
+func TestListTransactionsChain(t *testing.T) {
+	burn := usecase.TransactionDTO{ID: uuid.NewString(), Amount: 2.5, Seq: 7, Reason: "chargeback", PrevHash: "ab12", Hash: "cd34"}
+	server := NewServer(nil, transactionLister{transactions: []usecase.TransactionDTO{burn}})
+
+	resp, err := server.ListTransactions(context.Background(), &pb.ListTransactionsRequest{Count: 1})
+	if err != nil {
+		t.Fatalf("ListTransactions: %v", err)
+	}
+	if len(resp.GetTransactions()) != 1 {
+		t.Fatalf("ListTransactions = %v, want one transaction", resp.GetTransactions())
+	}
+	if got := resp.GetTransactions()[0]; got.GetAmount() != 250 || got.GetReason() != "chargeback" || got.GetPrevHash() != "ab12" || got.GetHash() != "cd34" {
+		t.Errorf("transaction = %v, want 250 cents, the reason and both hashes", got)
+	}
+}
@@ -0,0 +1,35 @@
+// Package docs generates the OpenAPI specification of the HTTP API from its routes
+// and serves it with a Swagger UI.
+package docs
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/labstack/echo"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// Prefix is the path the documentation is served under.
+const Prefix = "/api/docs"
+
+//go:embed index.html
+var indexHTML []byte
+
+// NewDocsRoutes registers the Swagger UI at Prefix and the specification at
+// Prefix/openapi.yaml.
+func NewDocsRoutes(e *echo.Echo, spec []byte) {
+	assets := http.StripPrefix(Prefix+"/", http.FileServer(http.FS(swaggerFiles.FS)))
+
+	index := func(c echo.Context) error {
+		return c.HTMLBlob(http.StatusOK, indexHTML)
+	}
+
+	g := e.Group(Prefix)
+	g.GET("", index)
+	g.GET("/index.html", index)
+	g.GET("/openapi.yaml", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "application/yaml", spec)
+	})
+	g.GET("/*", echo.WrapHandler(assets))
+}
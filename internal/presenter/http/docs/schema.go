@@ -0,0 +1,210 @@
+package docs
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema is an OpenAPI schema object.
+type Schema struct {
+	Ref                  string            `yaml:"$ref,omitempty"`
+	AllOf                []*Schema         `yaml:"allOf,omitempty"`
+	Type                 string            `yaml:"type,omitempty"`
+	Format               string            `yaml:"format,omitempty"`
+	Description          string            `yaml:"description,omitempty"`
+	Enum                 []string          `yaml:"enum,omitempty"`
+	Nullable             bool              `yaml:"nullable,omitempty"`
+	Minimum              *float64          `yaml:"minimum,omitempty"`
+	ExclusiveMinimum     bool              `yaml:"exclusiveMinimum,omitempty"`
+	Maximum              *float64          `yaml:"maximum,omitempty"`
+	Default              any               `yaml:"default,omitempty"`
+	Example              any               `yaml:"example,omitempty"`
+	Items                *Schema           `yaml:"items,omitempty"`
+	Required             []string          `yaml:"required,omitempty"`
+	Properties           *ordered[*Schema] `yaml:"properties,omitempty"`
+	AdditionalProperties *Schema           `yaml:"additionalProperties,omitempty"`
+}
+
+var (
+	uuidType = reflect.TypeFor[uuid.UUID]()
+	timeType = reflect.TypeFor[time.Time]()
+)
+
+// schemaOf returns the schema of values of type t. Named struct types are added to
+// the components, without their DTO suffix, and referenced.
+func (s *Spec) schemaOf(t reflect.Type) *Schema {
+	switch t {
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return s.schemaOf(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.schemaOf(t.Elem())}
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			panic(fmt.Sprintf("docs: map key of %s is not a string", t))
+		}
+		return &Schema{Type: "object", AdditionalProperties: s.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.objectOf(t)
+		}
+		name := strings.TrimSuffix(t.Name(), "DTO")
+		if known, ok := s.types[name]; ok {
+			if known != t {
+				panic(fmt.Sprintf("docs: %s and %s are both documented as %s", known, t, name))
+			}
+		} else {
+			s.types[name] = t
+			s.schemas.set(name, nil) // Keeps the order of first use while the fields are reflected
+			s.schemas.set(name, s.objectOf(t))
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	panic(fmt.Sprintf("docs: cannot document values of type %s", t))
+}
+
+// objectOf returns the object schema of a struct type. Fields are named and left
+// out like encoding/json does, and are required unless omitempty. Embedded
+// structs without a name add their fields.
+func (s *Spec) objectOf(t reflect.Type) *Schema {
+	object := &Schema{Type: "object", Properties: &ordered[*Schema]{}}
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || len(field.Index) > 1 {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inner := s.objectOf(embedded)
+				object.Required = append(object.Required, inner.Required...)
+				for _, key := range inner.Properties.keys {
+					object.Properties.set(key, inner.Properties.values[key])
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		omitEmpty := strings.Contains(options, "omitempty")
+		schema := s.schemaOf(field.Type)
+		if field.Type.Kind() == reflect.Pointer && !omitEmpty {
+			schema = described(schema)
+			schema.Nullable = true
+		}
+		schema = annotate(schema, field)
+		object.Properties.set(name, schema)
+		if !omitEmpty {
+			object.Required = append(object.Required, name)
+		}
+	}
+	return object
+}
+
+// annotate applies the doc, format, enums, example, default, minimum,
+// exclusiveMinimum and maximum tags of a field to its schema.
+func annotate(schema *Schema, field reflect.StructField) *Schema {
+	tag := field.Tag
+	var has bool
+	for _, key := range []string{"doc", "format", "enums", "example", "default", "minimum", "exclusiveMinimum", "maximum"} {
+		if _, ok := tag.Lookup(key); ok {
+			has = true
+		}
+	}
+	if !has {
+		return schema
+	}
+
+	schema = described(schema)
+	target := schema
+	if schema.Type == "array" {
+		target = schema.Items // Formats and values describe the items of a list
+	}
+	schema.Description = tag.Get("doc")
+	if format, ok := tag.Lookup("format"); ok {
+		target.Format = format
+	}
+	if enums, ok := tag.Lookup("enums"); ok {
+		target.Enum = strings.Split(enums, ",")
+	}
+	if example, ok := tag.Lookup("example"); ok {
+		schema.Example = value(schema, field, example)
+	}
+	if def, ok := tag.Lookup("default"); ok {
+		schema.Default = value(schema, field, def)
+	}
+	for key, dest := range map[string]**float64{"minimum": &schema.Minimum, "maximum": &schema.Maximum} {
+		if bound, ok := tag.Lookup(key); ok {
+			n, err := strconv.ParseFloat(bound, 64)
+			if err != nil {
+				panic(fmt.Sprintf("docs: %s of %s: %v", key, field.Name, err))
+			}
+			*dest = &n
+		}
+	}
+	schema.ExclusiveMinimum = tag.Get("exclusiveMinimum") == "true"
+	return schema
+}
+
+// described returns a schema that may carry a description next to the given one.
+// OpenAPI 3.0 ignores everything beside a $ref, so references are wrapped in allOf.
+func described(schema *Schema) *Schema {
+	if schema.Ref == "" {
+		return schema
+	}
+	return &Schema{AllOf: []*Schema{schema}}
+}
+
+// value parses a tag value as the type of the schema.
+func value(schema *Schema, field reflect.StructField, s string) any {
+	var (
+		v   any
+		err error
+	)
+	switch schema.Type {
+	case "integer":
+		v, err = strconv.ParseInt(s, 10, 64)
+	case "number":
+		v, err = strconv.ParseFloat(s, 64)
+	case "boolean":
+		v, err = strconv.ParseBool(s)
+	default:
+		v = s
+	}
+	if err != nil {
+		panic(fmt.Sprintf("docs: value of %s: %v", field.Name, err))
+	}
+	return v
+}
@@ -0,0 +1,369 @@
+package docs
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Operation documents a route. Request and response bodies are given as values of
+// their Go types; their schemas are generated from the json struct tags and the
+// doc, format, enums, example, default, minimum, exclusiveMinimum and maximum tags.
+type Operation struct {
+	ID          string // operationId, unique across the API
+	Tag         string
+	Summary     string
+	Description string
+	Public      bool // Served without credentials
+	Params      []Param
+	Body        any // Request body, nil for none
+	Responses   []Response
+}
+
+// Param documents a path, query or header parameter.
+type Param struct {
+	Name        string
+	In          string // path, query or header
+	Description string
+	Required    bool // Implied for path parameters
+	Schema      *Schema
+}
+
+// Response documents a response status.
+type Response struct {
+	Status      int
+	Description string
+	Body        any    // nil for none
+	ContentType string // application/json unless set
+	Example     string // Example of a body that is not JSON
+	Headers     map[string]string
+
+	ref string // Name of a response shared through the components
+}
+
+// ErrorResponse is the body of every error response.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// sharedResponses are the error responses Errors refers to, by status.
+var sharedResponses = map[int]struct {
+	name        string
+	description string
+}{
+	http.StatusBadRequest:          {"BadRequest", "The request is malformed."},
+	http.StatusUnauthorized:        {"Unauthorized", "The API key or bearer token is missing, invalid or revoked."},
+	http.StatusForbidden:           {"Forbidden", "The caller lacks the required scope or may not access the wallet."},
+	http.StatusNotFound:            {"NotFound", "The wallet, key, case or limit tier does not exist."},
+	http.StatusConflict:            {"Conflict", "Concurrent transfers kept modifying the same wallets and the retry budget ran out. Nothing was debited; the request can be retried."},
+	http.StatusUnprocessableEntity: {"InsufficientFunds", "The sender's balance is too low, the transfer would exceed its spending limits, the status of a wallet does not allow it, or the risk rules or sanctions screening denied it."},
+	http.StatusTooManyRequests:     {"TooManyRequests", "The caller exceeded its rate limit. Read and write routes have separate token buckets, counted per API key, IP address or wallet depending on the configuration."},
+	http.StatusInternalServerError: {"InternalError", "The operation failed."},
+}
+
+// Errors returns the shared error responses with the given statuses. It panics on
+// a status without a shared response.
+func Errors(statuses ...int) []Response {
+	responses := make([]Response, len(statuses))
+	for i, status := range statuses {
+		shared, ok := sharedResponses[status]
+		if !ok {
+			panic(fmt.Sprintf("docs: no shared response for status %d", status))
+		}
+		responses[i] = Response{Status: status, ref: shared.name}
+	}
+	return responses
+}
+
+// Spec generates the OpenAPI 3 specification of the routes added to it.
+type Spec struct {
+	paths   ordered[*ordered[*operationObject]] // By path, then method
+	schemas ordered[*Schema]
+	types   map[string]reflect.Type // By schema name
+	tags    []string
+	ids     map[string]bool
+}
+
+// NewSpec creates an empty specification.
+func NewSpec() *Spec {
+	return &Spec{types: make(map[string]reflect.Type), ids: make(map[string]bool)}
+}
+
+var pathParam = regexp.MustCompile(`:([^/]+)`)
+
+// Add documents the route. The path is an Echo path, with :name parameters. It
+// panics when the operation ID is missing or taken, a path parameter is not
+// documented or a body type cannot be documented.
+func (s *Spec) Add(method, path string, op Operation) {
+	if op.ID == "" || s.ids[op.ID] {
+		panic(fmt.Sprintf("docs: %s %s needs a unique operation ID, got %q", method, path, op.ID))
+	}
+	s.ids[op.ID] = true
+
+	operation := &operationObject{
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: op.ID,
+	}
+	if op.Tag != "" {
+		operation.Tags = []string{op.Tag}
+		if !slices.Contains(s.tags, op.Tag) {
+			s.tags = append(s.tags, op.Tag)
+		}
+	}
+	if op.Public {
+		operation.Security = &[]map[string][]string{}
+	}
+
+	documented := make(map[string]bool)
+	for _, param := range op.Params {
+		if param.In == "path" {
+			param.Required = true
+			documented[param.Name] = true
+		}
+		operation.Parameters = append(operation.Parameters, parameterObject{
+			Name:        param.Name,
+			In:          param.In,
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      param.Schema,
+		})
+	}
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		if !documented[match[1]] {
+			panic(fmt.Sprintf("docs: path parameter %s of %s %s is not documented", match[1], method, path))
+		}
+	}
+
+	if op.Body != nil {
+		operation.RequestBody = &requestBodyObject{
+			Required: true,
+			Content:  map[string]mediaTypeObject{"application/json": {Schema: s.schemaOf(reflect.TypeOf(op.Body))}},
+		}
+	}
+	responses := slices.Clone(op.Responses)
+	slices.SortStableFunc(responses, func(a, b Response) int { return a.Status - b.Status })
+	for _, response := range responses {
+		operation.Responses.set(strconv.Itoa(response.Status), s.responseOf(response))
+	}
+
+	path = pathParam.ReplaceAllString(path, "{$1}")
+	operations, ok := s.paths.values[path]
+	if !ok {
+		operations = &ordered[*operationObject]{}
+		s.paths.set(path, operations)
+	}
+	operations.set(strings.ToLower(method), operation)
+}
+
+func (s *Spec) responseOf(response Response) responseObject {
+	if response.ref != "" {
+		return responseObject{Ref: "#/components/responses/" + response.ref}
+	}
+	object := responseObject{Description: response.Description}
+	for name, description := range response.Headers {
+		if object.Headers == nil {
+			object.Headers = make(map[string]headerObject)
+		}
+		object.Headers[name] = headerObject{Description: description, Schema: &Schema{Type: "string"}}
+	}
+	if response.Body == nil && response.Example == "" {
+		return object
+	}
+	contentType := response.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	media := mediaTypeObject{Example: response.Example}
+	if response.Body != nil {
+		media.Schema = s.schemaOf(reflect.TypeOf(response.Body))
+	} else {
+		media.Schema = &Schema{Type: "string"}
+	}
+	object.Content = map[string]mediaTypeObject{contentType: media}
+	return object
+}
+
+// YAML returns the specification as YAML.
+func (s *Spec) YAML() ([]byte, error) {
+	doc := document{
+		OpenAPI: "3.0.3",
+		Info: infoObject{
+			Title: "Transaction service API",
+			Description: "Wallet balances, transfers between wallets and the transaction log. " +
+				"Every request may carry an `X-Request-ID` header (up to 128 characters of letters, digits and `._:-`); " +
+				"otherwise one is generated. The ID is echoed in the `X-Request-ID` response header, logged with " +
+				"everything the request does and stored on the transactions it creates.",
+			Version: "1.0.0",
+		},
+		Servers:  []serverObject{{URL: "/"}},
+		Security: []map[string][]string{{"ApiKeyAuth": {}}, {"BearerAuth": {}}},
+		Paths:    s.paths,
+		Components: componentsObject{
+			SecuritySchemes: securitySchemes,
+		},
+	}
+	for _, tag := range s.tags {
+		doc.Tags = append(doc.Tags, tagObject{Name: tag})
+	}
+
+	errorSchema := s.schemaOf(reflect.TypeFor[ErrorResponse]())
+	statuses := make([]int, 0, len(sharedResponses))
+	for status := range sharedResponses {
+		statuses = append(statuses, status)
+	}
+	slices.Sort(statuses)
+	for _, status := range statuses {
+		shared := sharedResponses[status]
+		response := responseObject{
+			Description: shared.description,
+			Content:     map[string]mediaTypeObject{"application/json": {Schema: errorSchema}},
+		}
+		if status == http.StatusTooManyRequests {
+			response.Headers = rateLimitHeaders
+		}
+		doc.Components.Responses.set(shared.name, response)
+	}
+	doc.Components.Schemas = s.schemas
+	return yaml.Marshal(doc)
+}
+
+var securitySchemes = map[string]securitySchemeObject{
+	"ApiKeyAuth": {
+		Type: "apiKey",
+		In:   "header",
+		Name: "X-API-Key",
+		Description: "Key issued through /api/admin/keys or the apikey CLI. Scopes per route: `wallets:read` for " +
+			"reads, `transfers:write` for /api/send and `admin` for key management. Keys bound to wallets " +
+			"only see and debit those wallets.",
+	},
+	"BearerAuth": {
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+		Description: "End-user token signed by a key from the configured JWKS file. Grants `wallets:read` and " +
+			"`transfers:write` (or the scopes in its `scope` claim) on the wallets owned by its `sub`. " +
+			"Tokens with `\"admin\": true` may access every wallet.",
+	},
+}
+
+var rateLimitHeaders = map[string]headerObject{
+	"RateLimit-Limit":     {Description: "Number of requests the bucket holds when full.", Schema: &Schema{Type: "integer"}},
+	"RateLimit-Remaining": {Description: "Requests left in the bucket.", Schema: &Schema{Type: "integer"}},
+	"RateLimit-Reset":     {Description: "Seconds until the bucket is full again.", Schema: &Schema{Type: "integer"}},
+	"Retry-After":         {Description: "Seconds until the next request will be accepted.", Schema: &Schema{Type: "integer"}},
+}
+
+type document struct {
+	OpenAPI    string                              `yaml:"openapi"`
+	Info       infoObject                          `yaml:"info"`
+	Servers    []serverObject                      `yaml:"servers"`
+	Security   []map[string][]string               `yaml:"security"`
+	Tags       []tagObject                         `yaml:"tags"`
+	Paths      ordered[*ordered[*operationObject]] `yaml:"paths"`
+	Components componentsObject                    `yaml:"components"`
+}
+
+type infoObject struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Version     string `yaml:"version"`
+}
+
+type serverObject struct {
+	URL string `yaml:"url"`
+}
+
+type tagObject struct {
+	Name string `yaml:"name"`
+}
+
+type operationObject struct {
+	Tags        []string                `yaml:"tags,omitempty"`
+	Summary     string                  `yaml:"summary,omitempty"`
+	Description string                  `yaml:"description,omitempty"`
+	OperationID string                  `yaml:"operationId"`
+	Security    *[]map[string][]string  `yaml:"security,omitempty"`
+	Parameters  []parameterObject       `yaml:"parameters,omitempty"`
+	RequestBody *requestBodyObject      `yaml:"requestBody,omitempty"`
+	Responses   ordered[responseObject] `yaml:"responses"`
+}
+
+type parameterObject struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description,omitempty"`
+	Required    bool    `yaml:"required,omitempty"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+type requestBodyObject struct {
+	Required bool                       `yaml:"required"`
+	Content  map[string]mediaTypeObject `yaml:"content"`
+}
+
+type responseObject struct {
+	Ref         string                     `yaml:"$ref,omitempty"`
+	Description string                     `yaml:"description,omitempty"`
+	Headers     map[string]headerObject    `yaml:"headers,omitempty"`
+	Content     map[string]mediaTypeObject `yaml:"content,omitempty"`
+}
+
+type headerObject struct {
+	Description string  `yaml:"description"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+type mediaTypeObject struct {
+	Schema  *Schema `yaml:"schema"`
+	Example string  `yaml:"example,omitempty"`
+}
+
+type securitySchemeObject struct {
+	Type         string `yaml:"type"`
+	In           string `yaml:"in,omitempty"`
+	Name         string `yaml:"name,omitempty"`
+	Scheme       string `yaml:"scheme,omitempty"`
+	BearerFormat string `yaml:"bearerFormat,omitempty"`
+	Description  string `yaml:"description"`
+}
+
+type componentsObject struct {
+	SecuritySchemes map[string]securitySchemeObject `yaml:"securitySchemes"`
+	Schemas         ordered[*Schema]                `yaml:"schemas"`
+	Responses       ordered[responseObject]         `yaml:"responses"`
+}
+
+// ordered is a YAML mapping that keeps its keys in the order they were first set.
+type ordered[V any] struct {
+	keys   []string
+	values map[string]V
+}
+
+func (m *ordered[V]) set(key string, value V) {
+	if m.values == nil {
+		m.values = make(map[string]V)
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m ordered[V]) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range m.keys {
+		var value yaml.Node
+		if err := value.Encode(m.values[key]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &value)
+	}
+	return node, nil
+}
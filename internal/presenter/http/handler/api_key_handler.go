@@ -0,0 +1,75 @@
+// Package handler implements HTTP handlers for API key administration.
+package handler
+
+import (
+	"net/http"
+	"transaction-service/internal/usecase"
+
+	"github.com/labstack/echo"
+)
+
+// APIKeyHandler defines HTTP endpoints for managing API keys.
+type APIKeyHandler interface {
+	// IssueAPIKey handles the request to create a new API key.
+	IssueAPIKey(c echo.Context) error
+
+	// ListAPIKeys handles the request to list every API key.
+	ListAPIKeys(c echo.Context) error
+
+	// RotateAPIKey handles the request to replace the secret of an API key.
+	RotateAPIKey(c echo.Context) error
+
+	// RevokeAPIKey handles the request to disable an API key.
+	RevokeAPIKey(c echo.Context) error
+}
+
+// IssueAPIKeyRequest is the body of a key issue.
+type IssueAPIKeyRequest struct {
+	Name      string   `json:"name" doc:"Label of the client the key is issued to."`
+	Scopes    []string `json:"scopes" enums:"wallets:read,transfers:write,admin"`
+	WalletIDs []string `json:"wallet_ids,omitempty" format:"uuid" doc:"Wallets the key is bound to. Omit to allow every wallet."`
+}
+
+type apiKeyHandlerImpl struct {
+	APIKeyUsecase usecase.APIKeyUsecase
+}
+
+func NewAPIKeyHandler(apiKeyUsecase usecase.APIKeyUsecase) APIKeyHandler {
+	return &apiKeyHandlerImpl{APIKeyUsecase: apiKeyUsecase}
+}
+
+func (h *apiKeyHandlerImpl) IssueAPIKey(c echo.Context) error {
+	var request IssueAPIKeyRequest
+	if err := c.Bind(&request); err != nil || request.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	key, err := h.APIKeyUsecase.IssueKey(c.Request().Context(), request.Name, request.Scopes, request.WalletIDs)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, key)
+}
+
+func (h *apiKeyHandlerImpl) ListAPIKeys(c echo.Context) error {
+	keys, err := h.APIKeyUsecase.ListKeys(c.Request().Context())
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, keys)
+}
+
+func (h *apiKeyHandlerImpl) RotateAPIKey(c echo.Context) error {
+	key, err := h.APIKeyUsecase.RotateKey(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, key)
+}
+
+func (h *apiKeyHandlerImpl) RevokeAPIKey(c echo.Context) error {
+	if err := h.APIKeyUsecase.RevokeKey(c.Request().Context(), c.Param("id")); err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, StatusResponse{Status: "revoked"})
+}
@@ -3,4 +3,13 @@ package handler
 type AppHandler interface {
 	WalletHandler
 	TransactionHandler
+	APIKeyHandler
+	HealthHandler
+	ChainHandler
+	ReceiptHandler
+	RiskHandler
+	SanctionsHandler
+	LimitHandler
+	StatusHandler
+	MetricsHandler
 }
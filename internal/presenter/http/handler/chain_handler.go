@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"transaction-service/internal/usecase"
+
+	"github.com/labstack/echo"
+)
+
+// ChainHandler defines HTTP endpoints for the hash chain of the transaction log.
+type ChainHandler interface {
+	// VerifyChain handles the request to verify the chain.
+	VerifyChain(c echo.Context) error
+
+	// CreateCheckpoint handles the request to sign a checkpoint of the chain head.
+	CreateCheckpoint(c echo.Context) error
+
+	// GetCheckpoints handles the request to export the signed checkpoints.
+	GetCheckpoints(c echo.Context) error
+}
+
+type chainHandlerImpl struct {
+	ChainUsecase usecase.ChainUsecase
+}
+
+func NewChainHandler(chainUsecase usecase.ChainUsecase) ChainHandler {
+	return &chainHandlerImpl{ChainUsecase: chainUsecase}
+}
+
+// VerifyChain answers 200 whether or not the chain is intact; the body tells.
+func (h *chainHandlerImpl) VerifyChain(c echo.Context) error {
+	report, err := h.ChainUsecase.VerifyChain(c.Request().Context())
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+func (h *chainHandlerImpl) CreateCheckpoint(c echo.Context) error {
+	checkpoint, err := h.ChainUsecase.CreateCheckpoint(c.Request().Context())
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, checkpoint)
+}
+
+func (h *chainHandlerImpl) GetCheckpoints(c echo.Context) error {
+	limit := 0
+	if value := c.QueryParam("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid limit parameter"})
+		}
+	}
+	checkpoints, err := h.ChainUsecase.GetCheckpoints(c.Request().Context(), limit)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, checkpoints)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"transaction-service/internal/domain/model"
+
+	"github.com/labstack/echo"
+)
+
+// errorStatus maps domain errors to HTTP status codes. Anything unrecognised is
+// reported as an internal error.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, model.ErrInvalidWalletID),
+		errors.Is(err, model.ErrInvalidAmount),
+		errors.Is(err, model.ErrSameWallet),
+		errors.Is(err, model.ErrInvalidScope),
+		errors.Is(err, model.ErrInvalidCursor),
+		errors.Is(err, model.ErrInvalidFilter),
+		errors.Is(err, model.ErrSystemWallet),
+		errors.Is(err, model.ErrReasonRequired),
+		errors.Is(err, model.ErrNameRequired),
+		errors.Is(err, model.ErrInvalidResolution),
+		errors.Is(err, model.ErrEntryRequired),
+		errors.Is(err, model.ErrInvalidLimits),
+		errors.Is(err, model.ErrInvalidStatus):
+		return http.StatusBadRequest
+	case errors.Is(err, model.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, model.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, model.ErrWalletNotFound),
+		errors.Is(err, model.ErrAPIKeyNotFound),
+		errors.Is(err, model.ErrCaseNotFound),
+		errors.Is(err, model.ErrNotWhitelisted),
+		errors.Is(err, model.ErrTierNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, model.ErrInsufficientFunds),
+		errors.Is(err, model.ErrTransferDenied),
+		errors.Is(err, model.ErrSanctionsHit),
+		errors.Is(err, model.ErrLimitExceeded),
+		errors.Is(err, model.ErrWalletStatus):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, model.ErrConflict),
+		errors.Is(err, model.ErrWalletNotEmpty),
+		errors.Is(err, model.ErrCaseResolved),
+		errors.Is(err, model.ErrStatusTransition):
+		return http.StatusConflict
+	case errors.Is(err, model.ErrSigningDisabled):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// StatusResponse is the body of a request that returns nothing else.
+type StatusResponse struct {
+	Status string `json:"status" example:"success"`
+}
+
+// errorResponse writes err as a JSON error body with the matching status code.
+func errorResponse(c echo.Context, err error) error {
+	return c.JSON(errorStatus(err), map[string]string{"error": err.Error()})
+}
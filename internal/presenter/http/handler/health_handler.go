@@ -0,0 +1,47 @@
+// Package handler implements HTTP handlers for liveness and readiness probes.
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+	"transaction-service/internal/usecase"
+
+	"github.com/labstack/echo"
+)
+
+// readinessTimeout bounds the readiness checks so a hung database fails the probe
+// instead of timing it out.
+const readinessTimeout = 2 * time.Second
+
+// HealthHandler defines the HTTP endpoints probed by the orchestrator.
+type HealthHandler interface {
+	// Healthz reports that the process is up and serving requests.
+	Healthz(c echo.Context) error
+
+	// Readyz reports whether the service can take traffic.
+	Readyz(c echo.Context) error
+}
+
+type healthHandlerImpl struct {
+	HealthUsecase usecase.HealthUsecase
+}
+
+func NewHealthHandler(healthUsecase usecase.HealthUsecase) HealthHandler {
+	return &healthHandlerImpl{HealthUsecase: healthUsecase}
+}
+
+func (h *healthHandlerImpl) Healthz(c echo.Context) error {
+	return c.JSON(http.StatusOK, StatusResponse{Status: "ok"})
+}
+
+func (h *healthHandlerImpl) Readyz(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), readinessTimeout)
+	defer cancel()
+
+	readiness := h.HealthUsecase.Readiness(ctx)
+	if readiness.Status != usecase.StatusReady {
+		return c.JSON(http.StatusServiceUnavailable, readiness)
+	}
+	return c.JSON(http.StatusOK, readiness)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"transaction-service/internal/usecase"
+
+	"github.com/labstack/echo"
+)
+
+// LimitHandler defines HTTP endpoints for the spending limits of wallets.
+type LimitHandler interface {
+	// GetLimits handles the request to show what a wallet may still send.
+	GetLimits(c echo.Context) error
+
+	// GetTiers handles the request to list the limit tiers.
+	GetTiers(c echo.Context) error
+
+	// SetTier handles the request to create a tier or replace its limits.
+	SetTier(c echo.Context) error
+
+	// GetWalletLimits handles the request to show the tier and overrides of a wallet.
+	GetWalletLimits(c echo.Context) error
+
+	// SetWalletLimits handles the request to assign a wallet to a tier and
+	// override its limits.
+	SetWalletLimits(c echo.Context) error
+}
+
+// SetWalletLimitsRequest is the body of a wallet limits change.
+type SetWalletLimitsRequest struct {
+	Tier   string            `json:"tier,omitempty" doc:"Tier to assign the wallet to. Omit for the default tier."`
+	Limits usecase.LimitsDTO `json:"limits,omitempty"`
+}
+
+type limitHandlerImpl struct {
+	LimitUsecase usecase.LimitUsecase
+}
+
+func NewLimitHandler(limitUsecase usecase.LimitUsecase) LimitHandler {
+	return &limitHandlerImpl{LimitUsecase: limitUsecase}
+}
+
+func (h *limitHandlerImpl) GetLimits(c echo.Context) error {
+	allowance, err := h.LimitUsecase.GetAllowance(c.Request().Context(), c.Param("address"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, allowance)
+}
+
+func (h *limitHandlerImpl) GetTiers(c echo.Context) error {
+	tiers, err := h.LimitUsecase.GetTiers(c.Request().Context())
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, tiers)
+}
+
+func (h *limitHandlerImpl) SetTier(c echo.Context) error {
+	var request usecase.LimitsDTO
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	tier, err := h.LimitUsecase.SetTier(c.Request().Context(), c.Param("name"), request)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, tier)
+}
+
+func (h *limitHandlerImpl) GetWalletLimits(c echo.Context) error {
+	limits, err := h.LimitUsecase.GetWalletLimits(c.Request().Context(), c.Param("address"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, limits)
+}
+
+func (h *limitHandlerImpl) SetWalletLimits(c echo.Context) error {
+	var request SetWalletLimitsRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	limits, err := h.LimitUsecase.SetWalletLimits(c.Request().Context(), c.Param("address"), request.Tier, request.Limits)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, limits)
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+)
+
+// MetricsHandler defines the HTTP endpoint scraped by Prometheus.
+type MetricsHandler interface {
+	// Metrics exposes the service metrics in the Prometheus text format.
+	Metrics(c echo.Context) error
+}
+
+type metricsHandlerImpl struct {
+	exposition http.Handler
+}
+
+func NewMetricsHandler(exposition http.Handler) MetricsHandler {
+	return &metricsHandlerImpl{exposition: exposition}
+}
+
+func (h *metricsHandlerImpl) Metrics(c echo.Context) error {
+	h.exposition.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+	"transaction-service/internal/usecase"
+
+	"github.com/labstack/echo"
+)
+
+// ReceiptHandler defines HTTP endpoints for verifying transfer receipts.
+type ReceiptHandler interface {
+	// GetSigningKeys handles the request to publish the public signing keys.
+	GetSigningKeys(c echo.Context) error
+}
+
+type receiptHandlerImpl struct {
+	ReceiptUsecase usecase.ReceiptUsecase
+}
+
+func NewReceiptHandler(receiptUsecase usecase.ReceiptUsecase) ReceiptHandler {
+	return &receiptHandlerImpl{ReceiptUsecase: receiptUsecase}
+}
+
+func (h *receiptHandlerImpl) GetSigningKeys(c echo.Context) error {
+	keys, err := h.ReceiptUsecase.GetSigningKeys(c.Request().Context())
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, keys)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"transaction-service/internal/usecase"
+
+	"github.com/labstack/echo"
+)
+
+// RiskHandler defines HTTP endpoints for the risk decisions made on transfers.
+type RiskHandler interface {
+	// GetDecisions handles the request to search the risk decisions.
+	GetDecisions(c echo.Context) error
+}
+
+type riskHandlerImpl struct {
+	RiskUsecase usecase.RiskUsecase
+}
+
+func NewRiskHandler(riskUsecase usecase.RiskUsecase) RiskHandler {
+	return &riskHandlerImpl{RiskUsecase: riskUsecase}
+}
+
+func (h *riskHandlerImpl) GetDecisions(c echo.Context) error {
+	query, err := parseDecisionQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	decisions, err := h.RiskUsecase.GetDecisions(c.Request().Context(), query)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, decisions)
+}
+
+// parseDecisionQuery reads the decision query parameters. Values are validated
+// further by the usecase.
+func parseDecisionQuery(c echo.Context) (usecase.DecisionQuery, error) {
+	query := usecase.DecisionQuery{WalletID: c.QueryParam("wallet"), Action: c.QueryParam("action")}
+	for name, dest := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		if value := c.QueryParam(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return usecase.DecisionQuery{}, fmt.Errorf("invalid %s parameter, want an RFC 3339 time", name)
+			}
+			*dest = t
+		}
+	}
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return usecase.DecisionQuery{}, fmt.Errorf("invalid limit parameter")
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
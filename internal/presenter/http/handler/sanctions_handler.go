@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"transaction-service/internal/usecase"
+
+	"github.com/labstack/echo"
+)
+
+// SanctionsHandler defines HTTP endpoints for the cases opened by sanctions
+// screening.
+type SanctionsHandler interface {
+	// GetCases handles the request to search the sanctions cases.
+	GetCases(c echo.Context) error
+
+	// ResolveCase handles the request to close a sanctions case.
+	ResolveCase(c echo.Context) error
+
+	// GetWhitelist handles the request to list the whitelisted false positives.
+	GetWhitelist(c echo.Context) error
+
+	// AddToWhitelist handles the request to whitelist an entry for a wallet by hand.
+	AddToWhitelist(c echo.Context) error
+
+	// RemoveFromWhitelist handles the request to screen a wallet for an entry again.
+	RemoveFromWhitelist(c echo.Context) error
+}
+
+// ResolveCaseRequest is the body of a case resolution.
+type ResolveCaseRequest struct {
+	Status string `json:"status" enums:"confirmed,false_positive"`
+	Note   string `json:"note"`
+}
+
+// WhitelistRequest is the body of a whitelist change.
+type WhitelistRequest struct {
+	WalletID string `json:"wallet_id" format:"uuid"`
+	Entry    string `json:"entry" doc:"The watchlist entry, a listed name or wallet ID, exactly as the screening hit reports it."`
+	Reason   string `json:"reason" doc:"Why the entry is added or removed."`
+}
+
+type sanctionsHandlerImpl struct {
+	SanctionsUsecase usecase.SanctionsUsecase
+}
+
+func NewSanctionsHandler(sanctionsUsecase usecase.SanctionsUsecase) SanctionsHandler {
+	return &sanctionsHandlerImpl{SanctionsUsecase: sanctionsUsecase}
+}
+
+func (h *sanctionsHandlerImpl) GetCases(c echo.Context) error {
+	query := usecase.CaseQuery{WalletID: c.QueryParam("wallet"), Status: c.QueryParam("status")}
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid limit parameter"})
+		}
+		query.Limit = limit
+	}
+
+	cases, err := h.SanctionsUsecase.GetCases(c.Request().Context(), query)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, cases)
+}
+
+func (h *sanctionsHandlerImpl) ResolveCase(c echo.Context) error {
+	var request ResolveCaseRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	resolved, err := h.SanctionsUsecase.ResolveCase(c.Request().Context(), c.Param("id"), request.Status, request.Note)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, resolved)
+}
+
+func (h *sanctionsHandlerImpl) GetWhitelist(c echo.Context) error {
+	entries, err := h.SanctionsUsecase.GetWhitelist(c.Request().Context(), c.QueryParam("wallet"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, entries)
+}
+
+func (h *sanctionsHandlerImpl) AddToWhitelist(c echo.Context) error {
+	var request WhitelistRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	added, err := h.SanctionsUsecase.AddToWhitelist(c.Request().Context(), request.WalletID, request.Entry, request.Reason)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, added)
+}
+
+func (h *sanctionsHandlerImpl) RemoveFromWhitelist(c echo.Context) error {
+	var request WhitelistRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	if err := h.SanctionsUsecase.RemoveFromWhitelist(c.Request().Context(), request.WalletID, request.Entry, request.Reason); err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, StatusResponse{Status: "success"})
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"transaction-service/internal/usecase"
+
+	"github.com/labstack/echo"
+)
+
+// StatusHandler defines HTTP endpoints for the status of wallets.
+type StatusHandler interface {
+	// SetWalletStatus handles the request to change the status of a wallet.
+	SetWalletStatus(c echo.Context) error
+
+	// GetStatusHistory handles the request to list the status changes of a wallet.
+	GetStatusHistory(c echo.Context) error
+}
+
+// SetWalletStatusRequest is the body of a status change.
+type SetWalletStatusRequest struct {
+	Status string `json:"status" enums:"active,frozen,debit_blocked,closed"`
+	Reason string `json:"reason" doc:"Why the status changes, recorded in the history."`
+}
+
+type statusHandlerImpl struct {
+	StatusUsecase usecase.StatusUsecase
+}
+
+func NewStatusHandler(statusUsecase usecase.StatusUsecase) StatusHandler {
+	return &statusHandlerImpl{StatusUsecase: statusUsecase}
+}
+
+func (h *statusHandlerImpl) SetWalletStatus(c echo.Context) error {
+	var request SetWalletStatusRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	change, err := h.StatusUsecase.SetWalletStatus(c.Request().Context(), c.Param("address"), request.Status, request.Reason)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, change)
+}
+
+func (h *statusHandlerImpl) GetStatusHistory(c echo.Context) error {
+	changes, err := h.StatusUsecase.GetStatusHistory(c.Request().Context(), c.Param("address"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, changes)
+}
@@ -2,8 +2,10 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 	"transaction-service/internal/usecase"
 
 	"github.com/labstack/echo"
@@ -13,6 +15,12 @@ import (
 type TransactionHandler interface {
 	// GetLastTransactions handles the request to retrieve recent transactions.
 	GetLastTransactions(c echo.Context) error
+
+	// GetHistory handles the request to search the transaction history.
+	GetHistory(c echo.Context) error
+
+	// StreamTransactions streams newly committed transactions over SSE or WebSocket.
+	StreamTransactions(c echo.Context) error
 }
 
 type transactionHandlerImpl struct {
@@ -36,7 +44,66 @@ func (h *transactionHandlerImpl) GetLastTransactions(c echo.Context) error {
 
 	transactions, err := h.TransactionUsecase.GetLastTransactions(c.Request().Context(), count)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return errorResponse(c, err)
 	}
 	return c.JSON(http.StatusOK, transactions)
 }
+
+func (h *transactionHandlerImpl) GetHistory(c echo.Context) error {
+	query, err := parseHistoryQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	transactions, err := h.TransactionUsecase.GetHistory(c.Request().Context(), query)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, transactions)
+}
+
+// parseHistoryQuery reads the history query parameters. Values are validated
+// further by the usecase.
+func parseHistoryQuery(c echo.Context) (usecase.HistoryQuery, error) {
+	query := usecase.HistoryQuery{WalletID: c.QueryParam("wallet")}
+	for name, dest := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		if value := c.QueryParam(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return usecase.HistoryQuery{}, fmt.Errorf("invalid %s parameter, want an RFC 3339 time", name)
+			}
+			*dest = t
+		}
+	}
+	for name, dest := range map[string]*float64{"min_amount": &query.MinAmount, "max_amount": &query.MaxAmount} {
+		if value := c.QueryParam(name); value != "" {
+			amount, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return usecase.HistoryQuery{}, fmt.Errorf("invalid %s parameter", name)
+			}
+			*dest = amount
+		}
+	}
+	if value := c.QueryParam("before"); value != "" {
+		seq, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return usecase.HistoryQuery{}, fmt.Errorf("invalid before parameter")
+		}
+		query.BeforeSeq = seq
+	}
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return usecase.HistoryQuery{}, fmt.Errorf("invalid limit parameter")
+		}
+		query.Limit = limit
+	}
+	if value := c.QueryParam("archived"); value != "" {
+		archived, err := strconv.ParseBool(value)
+		if err != nil {
+			return usecase.HistoryQuery{}, fmt.Errorf("invalid archived parameter")
+		}
+		query.IncludeArchived = archived
+	}
+	return query, nil
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"transaction-service/internal/usecase"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo"
+)
+
+// streamHeartbeatInterval keeps idle stream connections open through proxies.
+const streamHeartbeatInterval = 15 * time.Second
+
+var upgrader = websocket.Upgrader{
+	// CORS is wide open for the REST API as well, see middleware.NewMiddleware.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func (h *transactionHandlerImpl) StreamTransactions(c echo.Context) error {
+	req := c.Request()
+
+	lastEventID := req.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("last_event_id")
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	transactions, err := h.TransactionUsecase.StreamTransactions(ctx, c.QueryParam("wallet"), lastEventID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	if websocket.IsWebSocketUpgrade(req) {
+		return streamWebSocket(ctx, cancel, c, transactions)
+	}
+	return streamSSE(ctx, c, transactions)
+}
+
+func streamSSE(ctx context.Context, c echo.Context, transactions <-chan usecase.TransactionDTO) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+		case transaction, ok := <-transactions:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(transaction)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(res, "id: %d\nevent: transaction\ndata: %s\n\n", transaction.Seq, data); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
+
+func streamWebSocket(ctx context.Context, cancel context.CancelFunc, c echo.Context, transactions <-chan usecase.TransactionDTO) error {
+	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return nil // Upgrade has already replied to the client.
+	}
+	defer conn.Close()
+
+	// The stream is one-way; reading only detects when the client goes away.
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			deadline := time.Now().Add(streamHeartbeatInterval)
+			if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				return nil
+			}
+		case transaction, ok := <-transactions:
+			if !ok {
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "stream closed"))
+				return nil
+			}
+			if err := conn.WriteJSON(transaction); err != nil {
+				return nil
+			}
+		}
+	}
+}
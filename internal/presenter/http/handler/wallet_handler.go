@@ -19,6 +19,21 @@ type WalletHandler interface {
 
 	// GetAllWallets handles the request to return all of the wallets
 	GetAllWallets(c echo.Context) error
+
+	// SetWalletOwner handles the request to assign a wallet to an end user.
+	SetWalletOwner(c echo.Context) error
+
+	// GetWalletOwner handles the request to read who a wallet is assigned to.
+	GetWalletOwner(c echo.Context) error
+
+	// MintMoney handles the request to issue money into a wallet.
+	MintMoney(c echo.Context) error
+
+	// BurnMoney handles the request to destroy money held by a wallet.
+	BurnMoney(c echo.Context) error
+
+	// GetSupply handles the request to report the money supply.
+	GetSupply(c echo.Context) error
 }
 
 type walletHandlerImpl struct {
@@ -29,21 +44,55 @@ func NewWalletHandler(walletUsecase usecase.WalletUsecase) WalletHandler {
 	return &walletHandlerImpl{WalletUsecase: walletUsecase}
 }
 
+// SendMoneyRequest is the body of a transfer.
+type SendMoneyRequest struct {
+	From   string  `json:"from" format:"uuid" doc:"Wallet to debit."`
+	To     string  `json:"to" format:"uuid" doc:"Wallet to credit."`
+	Amount float64 `json:"amount" minimum:"0" exclusiveMinimum:"true" maximum:"100000" doc:"Amount in currency units, with cents as the fractional part. The spending limits of the sender may cap it lower."`
+}
+
+// TransferResponse is the body of a committed transfer.
+type TransferResponse struct {
+	Status string `json:"status" example:"success"`
+	*usecase.TransferDTO
+}
+
+// BalanceResponse is the body of a balance.
+type BalanceResponse struct {
+	Balance float64 `json:"balance" doc:"Balance in currency units."`
+}
+
+// SetWalletOwnerRequest is the body of an owner assignment.
+type SetWalletOwnerRequest struct {
+	OwnerID   string `json:"owner_id" doc:"Token subject of the new owner. An empty string unassigns the wallet."`
+	OwnerName string `json:"owner_name,omitempty" doc:"Legal name of the owner, screened against the sanctions lists."`
+}
+
+// MintRequest is the body of a mint.
+type MintRequest struct {
+	To     string  `json:"to" format:"uuid" doc:"Wallet to credit."`
+	Amount float64 `json:"amount" minimum:"0" exclusiveMinimum:"true" doc:"Amount in currency units, with cents as the fractional part."`
+	Reason string  `json:"reason" doc:"Why the money is issued, recorded on the transaction."`
+}
+
+// BurnRequest is the body of a burn.
+type BurnRequest struct {
+	From   string  `json:"from" format:"uuid" doc:"Wallet to debit."`
+	Amount float64 `json:"amount" minimum:"0" exclusiveMinimum:"true" doc:"Amount in currency units, with cents as the fractional part."`
+	Reason string  `json:"reason" doc:"Why the money is burned, recorded on the transaction."`
+}
+
 func (h *walletHandlerImpl) GetBalance(c echo.Context) error {
 	address := c.Param("address")
 	balance, err := h.WalletUsecase.GetBalance(c.Request().Context(), address)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return errorResponse(c, err)
 	}
-	return c.JSON(http.StatusOK, map[string]interface{}{"balance": balance})
+	return c.JSON(http.StatusOK, BalanceResponse{Balance: balance})
 }
 
 func (h *walletHandlerImpl) SendMoney(c echo.Context) error {
-	var request struct {
-		From   string  `json:"from"`
-		To     string  `json:"to"`
-		Amount float64 `json:"amount"`
-	}
+	var request SendMoneyRequest
 	if err := c.Bind(&request); err != nil || request.Amount <= 0 {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
@@ -58,17 +107,69 @@ func (h *walletHandlerImpl) SendMoney(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid 'to' UUID"})
 	}
 
-	err = h.WalletUsecase.SendMoney(c.Request().Context(), fromUUID.String(), toUUID.String(), request.Amount)
+	transfer, err := h.WalletUsecase.SendMoney(c.Request().Context(), fromUUID.String(), toUUID.String(), request.Amount)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return errorResponse(c, err)
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "success"})
+	return c.JSON(http.StatusOK, TransferResponse{Status: "success", TransferDTO: transfer})
 }
 
 func (h *walletHandlerImpl) GetAllWallets(c echo.Context) error {
 	wallets, err := h.WalletUsecase.GetAllWallets(c.Request().Context())
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return errorResponse(c, err)
 	}
 	return c.JSON(http.StatusOK, wallets)
 }
+
+func (h *walletHandlerImpl) SetWalletOwner(c echo.Context) error {
+	var request SetWalletOwnerRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	if err := h.WalletUsecase.SetWalletOwner(c.Request().Context(), c.Param("address"), request.OwnerID, request.OwnerName); err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, StatusResponse{Status: "success"})
+}
+
+func (h *walletHandlerImpl) GetWalletOwner(c echo.Context) error {
+	owner, err := h.WalletUsecase.GetWalletOwner(c.Request().Context(), c.Param("address"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, owner)
+}
+
+func (h *walletHandlerImpl) MintMoney(c echo.Context) error {
+	var request MintRequest
+	if err := c.Bind(&request); err != nil || request.Amount <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	if err := h.WalletUsecase.Mint(c.Request().Context(), request.To, request.Amount, request.Reason); err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, StatusResponse{Status: "success"})
+}
+
+func (h *walletHandlerImpl) BurnMoney(c echo.Context) error {
+	var request BurnRequest
+	if err := c.Bind(&request); err != nil || request.Amount <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	if err := h.WalletUsecase.Burn(c.Request().Context(), request.From, request.Amount, request.Reason); err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, StatusResponse{Status: "success"})
+}
+
+func (h *walletHandlerImpl) GetSupply(c echo.Context) error {
+	supply, err := h.WalletUsecase.GetSupply(c.Request().Context())
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, supply)
+}
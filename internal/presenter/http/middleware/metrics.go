@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// RequestObserver records served HTTP requests.
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, d time.Duration)
+}
+
+// unmatchedRoute labels requests that matched no route, keeping label cardinality bounded.
+const unmatchedRoute = "unmatched"
+
+// NewMetrics reports the latency and status of every request, labelled by route pattern.
+func NewMetrics(observer RequestObserver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			status := responseStatus(c, err)
+
+			// The router reports misses with its sentinel errors and may leave the raw path behind.
+			route := c.Path()
+			if route == "" || err == echo.ErrNotFound || err == echo.ErrMethodNotAllowed {
+				route = unmatchedRoute
+			}
+			observer.ObserveRequest(c.Request().Method, route, status, time.Since(start))
+			return err
+		}
+	}
+}
+
+// responseStatus returns the status code the client receives. Errors returned by
+// handlers are written by the error handler after every middleware has run.
+func responseStatus(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+	if he, ok := err.(*echo.HTTPError); ok {
+		return he.Code
+	}
+	return http.StatusInternalServerError
+}
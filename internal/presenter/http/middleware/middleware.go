@@ -6,10 +6,14 @@ import (
 )
 
 func NewMiddleware(e *echo.Echo) {
-	e.Use(middleware.Logger())
+	e.Use(NewRequestID())
+	e.Use(NewConsistentRead())
+	e.Use(NewAccessLog())
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{echo.GET, echo.HEAD, echo.PUT, echo.PATCH, echo.POST, echo.DELETE},
+		AllowOrigins:  []string{"*"},
+		AllowMethods:  []string{echo.GET, echo.HEAD, echo.PUT, echo.PATCH, echo.POST, echo.DELETE},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, HeaderAPIKey, HeaderTraceparent, HeaderRequestID, HeaderConsistentRead},
+		ExposeHeaders: []string{HeaderRateLimitLimit, HeaderRateLimitRemaining, HeaderRateLimitReset, HeaderRetryAfter, HeaderTraceparent, HeaderRequestID},
 	}))
 }
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/infrastructure/ratelimit"
+
+	"github.com/labstack/echo"
+)
+
+// Rate limit response headers, draft-ietf-httpapi-ratelimit-headers.
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+// maxPeekedBody caps how much of a request body is read to find the wallet to limit by.
+const maxPeekedBody = 1 << 20
+
+// RateLimitKeyFunc derives the bucket a request is counted against.
+type RateLimitKeyFunc func(c echo.Context) string
+
+// RateLimitByIP counts requests per client IP address.
+func RateLimitByIP(c echo.Context) string {
+	return "ip:" + c.RealIP()
+}
+
+// RateLimitByClient counts requests per authenticated caller (API key or token
+// subject), falling back to the client IP.
+func RateLimitByClient(c echo.Context) string {
+	if p, ok := model.PrincipalFromContext(c.Request().Context()); ok {
+		return "client:" + p.Subject
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitByWallet counts requests per wallet: the wallet in the path or query,
+// or the debited wallet of a transfer. Requests without a wallet are counted per client.
+func RateLimitByWallet(c echo.Context) string {
+	if wallet := c.Param("address"); wallet != "" {
+		return "wallet:" + wallet
+	}
+	if wallet := c.QueryParam("wallet"); wallet != "" {
+		return "wallet:" + wallet
+	}
+	if wallet := peekSender(c); wallet != "" {
+		return "wallet:" + wallet
+	}
+	return RateLimitByClient(c)
+}
+
+// RateLimitKeyByName returns the key function configured by name: api_key, ip or wallet.
+func RateLimitKeyByName(name string) (RateLimitKeyFunc, error) {
+	switch name {
+	case "api_key":
+		return RateLimitByClient, nil
+	case "ip":
+		return RateLimitByIP, nil
+	case "wallet":
+		return RateLimitByWallet, nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit key %q", name)
+	}
+}
+
+// NewRateLimit limits requests with token buckets from store. class separates the
+// buckets of routes limited differently, e.g. reads and writes. Requests are let
+// through if the store fails.
+func NewRateLimit(store ratelimit.Store, class string, limit ratelimit.Limit, key RateLimitKeyFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			res, err := store.Take(c.Request().Context(), class+":"+key(c), limit)
+			if err != nil {
+				slog.ErrorContext(c.Request().Context(), "rate limit check failed", "error", err)
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set(HeaderRateLimitLimit, strconv.Itoa(res.Limit))
+			header.Set(HeaderRateLimitRemaining, strconv.Itoa(int(math.Max(0, math.Floor(res.Remaining)))))
+			header.Set(HeaderRateLimitReset, strconv.Itoa(int(res.ResetAfter().Seconds())))
+
+			if !res.Allowed {
+				header.Set(HeaderRetryAfter, strconv.Itoa(int(res.RetryAfter().Seconds())))
+				return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "rate limit exceeded"})
+			}
+			return next(c)
+		}
+	}
+}
+
+// peekSender returns the "from" wallet of a JSON request body and leaves the body
+// readable for the handler.
+func peekSender(c echo.Context) string {
+	req := c.Request()
+	if req.Body == nil || req.Method != http.MethodPost {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxPeekedBody))
+	if err != nil {
+		return ""
+	}
+	req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), req.Body))
+
+	var request struct {
+		From string `json:"from"`
+	}
+	if json.Unmarshal(body, &request) != nil {
+		return ""
+	}
+	return request.From
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+	"transaction-service/internal/domain/model"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo"
+)
+
+// HeaderRequestID carries the request ID, accepted from clients and echoed in responses.
+const HeaderRequestID = "X-Request-ID"
+
+// HeaderConsistentRead set to true makes the request read from the primary database,
+// so it sees the writes of earlier requests even when read replicas lag.
+const HeaderConsistentRead = "X-Consistent-Read"
+
+// validRequestID limits client-supplied IDs to something safe to log and store.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// NewRequestID accepts the client's X-Request-ID or generates one, stores it in the
+// request context and echoes it in the response.
+func NewRequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := req.Header.Get(HeaderRequestID)
+			if !validRequestID.MatchString(id) {
+				id = uuid.NewString()
+			}
+
+			c.Response().Header().Set(HeaderRequestID, id)
+			c.SetRequest(req.WithContext(model.WithRequestID(req.Context(), id)))
+			return next(c)
+		}
+	}
+}
+
+// NewConsistentRead marks the context of requests sending X-Consistent-Read: true.
+func NewConsistentRead() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if consistent, _ := strconv.ParseBool(req.Header.Get(HeaderConsistentRead)); consistent {
+				c.SetRequest(req.WithContext(model.WithConsistentRead(req.Context())))
+			}
+			return next(c)
+		}
+	}
+}
+
+// NewAccessLog logs every served request.
+func NewAccessLog() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			status := responseStatus(c, err)
+
+			req := c.Request()
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("route", c.Path()),
+				slog.String("path", req.URL.Path),
+				slog.Int("status", status),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("remote_ip", c.RealIP()),
+				slog.Int64("bytes_out", c.Response().Size),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			slog.LogAttrs(req.Context(), level, "request served", attrs...)
+			return err
+		}
+	}
+}
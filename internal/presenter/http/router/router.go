@@ -2,17 +2,62 @@
 package router
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/labstack/echo"
+	"transaction-service/internal/presenter/http/docs"
 	"transaction-service/internal/presenter/http/handler"
 )
 
-// NewRouter sets up routes for the transaction service.
-func NewRouter(e *echo.Echo, h handler.AppHandler) {
-	api := e.Group("/api")
-	{
-		api.POST("/send", h.SendMoney)
-		api.GET("/transactions", h.GetLastTransactions)
-		api.GET("/wallets", h.GetAllWallets)
-		api.GET("/wallet/:address/balance", h.GetBalance)
+// Middleware is the per-route middleware NewRouter attaches to API routes.
+type Middleware struct {
+	// IPLimit rate limits every request by client IP before Auth runs, so floods
+	// without valid credentials are throttled before their key is looked up.
+	IPLimit echo.MiddlewareFunc
+
+	// Auth authenticates every API request; each route then requires its own scope.
+	Auth echo.MiddlewareFunc
+
+	// ReadLimit and WriteLimit rate limit read-only and mutating routes, after
+	// Auth, so they may count requests per caller or wallet.
+	ReadLimit  echo.MiddlewareFunc
+	WriteLimit echo.MiddlewareFunc
+}
+
+// NewRouter sets up routes for the transaction service and serves the OpenAPI
+// specification generated from them. Routes needing credentials live under /api,
+// behind the Auth middleware; public routes are registered without it. Every
+// route is limited by IP first.
+func NewRouter(e *echo.Echo, h handler.AppHandler, m Middleware) {
+	api := e.Group("/api", chain(m.IPLimit, m.Auth)...)
+	spec := docs.NewSpec()
+	for _, r := range routes(h, m) {
+		switch {
+		case r.doc.Public:
+			e.Add(r.method, r.path, r.handler, append(chain(m.IPLimit), r.middleware...)...)
+		case strings.HasPrefix(r.path, "/api/"):
+			api.Add(r.method, strings.TrimPrefix(r.path, "/api"), r.handler, r.middleware...)
+		default:
+			panic(fmt.Sprintf("router: %s %s needs credentials outside /api", r.method, r.path))
+		}
+		spec.Add(r.method, r.path, r.doc)
+	}
+
+	yaml, err := spec.YAML()
+	if err != nil {
+		panic(fmt.Sprintf("router: failed to generate the OpenAPI specification: %v", err))
+	}
+	docs.NewDocsRoutes(e, yaml)
+}
+
+// chain drops the middleware that is not configured.
+func chain(m ...echo.MiddlewareFunc) []echo.MiddlewareFunc {
+	result := make([]echo.MiddlewareFunc, 0, len(m))
+	for _, mw := range m {
+		if mw != nil {
+			result = append(result, mw)
+		}
 	}
+	return result
 }
@@ -0,0 +1,199 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"transaction-service/internal/infrastructure/ratelimit"
+	"transaction-service/internal/presenter/http/docs"
+	"transaction-service/internal/presenter/http/handler"
+	"transaction-service/internal/presenter/http/middleware"
+
+	"github.com/labstack/echo"
+	"gopkg.in/yaml.v3"
+)
+
+type testAppHandler struct {
+	handler.WalletHandler
+	handler.TransactionHandler
+	handler.APIKeyHandler
+	handler.HealthHandler
+	handler.ChainHandler
+	handler.ReceiptHandler
+	handler.RiskHandler
+	handler.SanctionsHandler
+	handler.LimitHandler
+	handler.StatusHandler
+	handler.MetricsHandler
+}
+
+func newTestRouter() *echo.Echo {
+	return newTestRouterWith(Middleware{Auth: middleware.NewAnonymousAuth()})
+}
+
+func newTestRouterWith(m Middleware) *echo.Echo {
+	e := echo.New()
+	NewRouter(e, &testAppHandler{
+		WalletHandler:      handler.NewWalletHandler(nil),
+		TransactionHandler: handler.NewTransactionHandler(nil),
+		APIKeyHandler:      handler.NewAPIKeyHandler(nil),
+		HealthHandler:      handler.NewHealthHandler(nil),
+		ChainHandler:       handler.NewChainHandler(nil),
+		ReceiptHandler:     handler.NewReceiptHandler(nil),
+		RiskHandler:        handler.NewRiskHandler(nil),
+		SanctionsHandler:   handler.NewSanctionsHandler(nil),
+		LimitHandler:       handler.NewLimitHandler(nil),
+		StatusHandler:      handler.NewStatusHandler(nil),
+		MetricsHandler:     handler.NewMetricsHandler(http.NotFoundHandler()),
+	}, m)
+	return e
+}
+
+type openAPISpec struct {
+	OpenAPI string                               `yaml:"openapi"`
+	Paths   map[string]map[string]map[string]any `yaml:"paths"`
+}
+
+var pathParam = regexp.MustCompile(`:([^/]+)`)
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	e := newTestRouter()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, docs.Prefix+"/openapi.yaml", nil))
+	var spec openAPISpec
+	if err := yaml.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("failed to parse openapi.yaml: %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Fatalf("expected an OpenAPI 3 document, got version %q", spec.OpenAPI)
+	}
+
+	routes := e.Routes()
+	documented := make(map[string]bool)
+	for _, r := range routes {
+		// Echo registers catch-all routes for every group; only application routes need docs.
+		if strings.HasPrefix(r.Name, "github.com/labstack/echo.") || strings.HasPrefix(r.Path, docs.Prefix) {
+			continue
+		}
+		path := pathParam.ReplaceAllString(r.Path, "{$1}")
+		method := strings.ToLower(r.Method)
+		documented[method+" "+path] = true
+
+		operation, ok := spec.Paths[path][method]
+		if !ok {
+			t.Errorf("route %s %s has no entry in openapi.yaml", r.Method, path)
+			continue
+		}
+		if _, ok := operation["responses"]; !ok {
+			t.Errorf("route %s %s documents no responses", r.Method, path)
+		}
+	}
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if !documented[method+" "+path] {
+				t.Errorf("openapi.yaml documents %s %s, which is not routed", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestDocsAreServed(t *testing.T) {
+	e := newTestRouter()
+
+	for _, path := range []string{docs.Prefix, docs.Prefix + "/openapi.yaml", docs.Prefix + "/swagger-ui-bundle.js"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s: expected status 200, got %d", path, rec.Code)
+		}
+	}
+}
+
+// failingStore fails every take, as an unreachable Postgres store would.
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store down")
+}
+
+func (failingStore) Close() error {
+	return nil
+}
+
+func TestRateLimitByIPBeforeAuth(t *testing.T) {
+	var authenticated int
+	rejectAll := func(echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authenticated++
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid API key"})
+		}
+	}
+	// Slow enough that nothing refills during the test.
+	limit := ratelimit.Limit{Rate: 0.001, Burst: 2}
+	e := newTestRouterWith(Middleware{
+		IPLimit: middleware.NewRateLimit(ratelimit.NewMemoryStore(), "ip", limit, middleware.RateLimitByIP),
+		Auth:    rejectAll,
+	})
+	serve := func(path, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(echo.HeaderXRealIP, ip)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for n, want := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		rec := serve("/api/wallets", "10.0.0.1")
+		if rec.Code != want {
+			t.Fatalf("request %d = %d, want %d", n+1, rec.Code, want)
+		}
+		if got := rec.Header().Get(middleware.HeaderRateLimitLimit); got != "2" {
+			t.Errorf("request %d: %s = %q, want 2", n+1, middleware.HeaderRateLimitLimit, got)
+		}
+	}
+	if authenticated != 2 {
+		t.Errorf("Auth ran %d times, want 2: the limited request must not reach it", authenticated)
+	}
+
+	rec := serve("/api/wallets", "10.0.0.1")
+	for header, want := range map[string]string{
+		middleware.HeaderRateLimitRemaining: "0",
+		middleware.HeaderRateLimitReset:     "2000",
+		middleware.HeaderRetryAfter:         "1000",
+	} {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("limited response: %s = %q, want %q", header, got, want)
+		}
+	}
+
+	if rec := serve("/api/wallets", "10.0.0.2"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("request from another IP = %d, want 401", rec.Code)
+	}
+	for n, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		if rec := serve("/healthz", "10.0.0.3"); rec.Code != want {
+			t.Errorf("public request %d = %d, want %d", n+1, rec.Code, want)
+		}
+	}
+}
+
+func TestRateLimitFailsOpen(t *testing.T) {
+	e := newTestRouterWith(Middleware{
+		IPLimit: middleware.NewRateLimit(failingStore{}, "ip", ratelimit.Limit{Rate: 1, Burst: 1}, middleware.RateLimitByIP),
+		Auth:    middleware.NewAnonymousAuth(),
+	})
+	for n := range 3 {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("request %d with the store down = %d, want 200", n+1, rec.Code)
+		}
+		if got := rec.Header().Get(middleware.HeaderRateLimitLimit); got != "" {
+			t.Errorf("request %d with the store down has %s %q", n+1, middleware.HeaderRateLimitLimit, got)
+		}
+	}
+}
@@ -0,0 +1,458 @@
+package router
+
+import (
+	"net/http"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/presenter/http/docs"
+	"transaction-service/internal/presenter/http/handler"
+	"transaction-service/internal/presenter/http/middleware"
+	"transaction-service/internal/usecase"
+
+	"github.com/labstack/echo"
+)
+
+// route is an entry of the route table. NewRouter registers it and adds its doc
+// to the OpenAPI specification, so every route is documented and nothing else is.
+type route struct {
+	method     string
+	path       string
+	handler    echo.HandlerFunc
+	middleware []echo.MiddlewareFunc
+	doc        docs.Operation
+}
+
+// Parameters shared by several routes.
+var (
+	walletAddress = docs.Param{
+		Name:        "address",
+		In:          "path",
+		Description: "Wallet ID.",
+		Schema:      &docs.Schema{Type: "string", Format: "uuid"},
+	}
+	apiKeyID = docs.Param{
+		Name:        "id",
+		In:          "path",
+		Description: "API key ID.",
+		Schema:      &docs.Schema{Type: "string", Format: "uuid"},
+	}
+	consistentRead = docs.Param{
+		Name: "X-Consistent-Read",
+		In:   "header",
+		Description: "When true, the request reads from the primary database and sees every committed " +
+			"transfer, e.g. a balance right after /api/send. Otherwise reads may be served by a " +
+			"read replica and lag behind by the replication delay.",
+		Schema: &docs.Schema{Type: "boolean"},
+	}
+)
+
+// walletFilter documents the query parameter restricting a listing to a wallet.
+func walletFilter(description string) docs.Param {
+	return docs.Param{Name: "wallet", In: "query", Description: description, Schema: &docs.Schema{Type: "string", Format: "uuid"}}
+}
+
+// timeFilter documents a date-time query parameter.
+func timeFilter(name, description string) docs.Param {
+	return docs.Param{Name: name, In: "query", Description: description, Schema: &docs.Schema{Type: "string", Format: "date-time"}}
+}
+
+// limitParam documents the limit query parameter of a listing capped at 1000
+// entries, 100 by default.
+func limitParam(description string) docs.Param {
+	return docs.Param{Name: "limit", In: "query", Description: description, Schema: &docs.Schema{
+		Type:    "integer",
+		Minimum: bound(1),
+		Maximum: bound(1000),
+		Default: 100,
+	}}
+}
+
+func bound(n float64) *float64 {
+	return &n
+}
+
+// ok documents a 200 response with the given body.
+func ok(description string, body any) docs.Response {
+	return docs.Response{Status: http.StatusOK, Description: description, Body: body}
+}
+
+// responses joins a success response with shared error responses.
+func responses(success docs.Response, errors ...docs.Response) []docs.Response {
+	return append([]docs.Response{success}, errors...)
+}
+
+// routes returns the route table of the service.
+func routes(h handler.AppHandler, m Middleware) []route {
+	read := chain(middleware.RequireScope(model.ScopeWalletsRead), m.ReadLimit)
+	write := chain(middleware.RequireScope(model.ScopeTransfersWrite), m.WriteLimit)
+	adminRead := chain(middleware.RequireScope(model.ScopeAdmin), m.ReadLimit)
+	adminWrite := chain(middleware.RequireScope(model.ScopeAdmin), m.WriteLimit)
+
+	return []route{
+		{http.MethodPost, "/api/send", h.SendMoney, write, docs.Operation{
+			ID:      "sendMoney",
+			Tag:     "wallets",
+			Summary: "Transfer money between two wallets",
+			Body:    handler.SendMoneyRequest{},
+			Responses: responses(ok("The transfer was committed. The response carries the transaction and, when a signing key "+
+				"is configured, a receipt anyone can verify offline with the keys at `/api/keys`.", handler.TransferResponse{}),
+				docs.Errors(400, 401, 403, 404, 409, 422, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/transactions", h.GetLastTransactions, read, docs.Operation{
+			ID:      "getLastTransactions",
+			Tag:     "transactions",
+			Summary: "List the most recent transactions",
+			Params: []docs.Param{
+				{Name: "count", In: "query", Required: true, Description: "Number of transactions to return, newest first.",
+					Schema: &docs.Schema{Type: "integer", Minimum: bound(1)}},
+				consistentRead,
+			},
+			Responses: responses(ok("Transactions, newest first.", []usecase.TransactionDTO{}),
+				docs.Errors(400, 401, 403, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/history", h.GetHistory, read, docs.Operation{
+			ID:      "getHistory",
+			Tag:     "transactions",
+			Summary: "Search the transaction history",
+			Description: "Returns the transactions matching every given filter, newest first. Page through " +
+				"the results by passing the seq of the last transaction as before. With archived, " +
+				"months moved out of the transaction log by the retention policy are searched once " +
+				"the log runs out.",
+			Params: []docs.Param{
+				walletFilter("Only transactions sent from or to this wallet."),
+				timeFilter("since", "Only transactions created at or after this time."),
+				timeFilter("until", "Only transactions created before this time."),
+				{Name: "min_amount", In: "query", Description: "Only transactions of at least this amount.",
+					Schema: &docs.Schema{Type: "number", Minimum: bound(0)}},
+				{Name: "max_amount", In: "query", Description: "Only transactions of at most this amount.",
+					Schema: &docs.Schema{Type: "number", Minimum: bound(0)}},
+				{Name: "before", In: "query", Description: "Only transactions older than this sequence number, for the next page.",
+					Schema: &docs.Schema{Type: "integer", Format: "int64", Minimum: bound(0)}},
+				limitParam("Maximum number of transactions returned."),
+				{Name: "archived", In: "query", Description: "Also search the archived months.",
+					Schema: &docs.Schema{Type: "boolean", Default: false}},
+				consistentRead,
+			},
+			Responses: responses(ok("Transactions, newest first.", []usecase.TransactionDTO{}),
+				docs.Errors(400, 401, 403, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/wallets", h.GetAllWallets, read, docs.Operation{
+			ID:        "getAllWallets",
+			Tag:       "wallets",
+			Summary:   "List all wallets",
+			Params:    []docs.Param{consistentRead},
+			Responses: responses(ok("Every wallet with its balance.", []*model.Wallet{}), docs.Errors(401, 403, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/wallet/:address/balance", h.GetBalance, read, docs.Operation{
+			ID:        "getBalance",
+			Tag:       "wallets",
+			Summary:   "Get the balance of a wallet",
+			Params:    []docs.Param{walletAddress, consistentRead},
+			Responses: responses(ok("The current balance.", handler.BalanceResponse{}), docs.Errors(400, 401, 403, 404, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/wallet/:address/limits", h.GetLimits, read, docs.Operation{
+			ID:      "getLimits",
+			Tag:     "wallets",
+			Summary: "Get the spending limits of a wallet and what is left of them",
+			Description: "Daily and monthly totals count transfers sent since midnight and the first of the month in UTC; " +
+				"the hourly count covers the last hour. `remaining.max_transfer` is the largest transfer the " +
+				"wallet may send now.",
+			Params:    []docs.Param{walletAddress},
+			Responses: responses(ok("The limits and remaining allowance.", usecase.AllowanceDTO{}), docs.Errors(400, 401, 403, 404, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/stream/transactions", h.StreamTransactions, read, docs.Operation{
+			ID:      "streamTransactions",
+			Tag:     "transactions",
+			Summary: "Stream newly committed transactions",
+			Description: "Streams transactions as Server-Sent Events. Each event has the transaction's `seq` as its " +
+				"`id` and a Transaction object as its data. Sending the same request as a WebSocket upgrade " +
+				"streams the same Transaction objects as JSON text messages instead.",
+			Params: []docs.Param{
+				walletFilter("Only stream transactions sent from or to this wallet."),
+				{Name: "Last-Event-ID", In: "header", Description: "Replay transactions committed after this `seq` before streaming live ones.",
+					Schema: &docs.Schema{Type: "integer", Format: "int64"}},
+				{Name: "last_event_id", In: "query", Description: "Same as the Last-Event-ID header, for clients that cannot set headers.",
+					Schema: &docs.Schema{Type: "integer", Format: "int64"}},
+			},
+			Responses: responses(docs.Response{
+				Status:      http.StatusOK,
+				Description: "An open event stream.",
+				ContentType: "text/event-stream",
+				Example: "id: 42\nevent: transaction\n" +
+					`data: {"id":"0d9b7c1e-3f0a-4c53-9d3e-3b5c9e1c2a11","from":"...","to":"...","amount":1.5,"created_at":"2025-01-23 10:19:04","seq":42}` + "\n",
+			}, docs.Errors(400, 401, 403, 429)...),
+		}},
+
+		{http.MethodPost, "/api/admin/keys", h.IssueAPIKey, adminWrite, docs.Operation{
+			ID:          "issueAPIKey",
+			Tag:         "admin",
+			Summary:     "Issue an API key",
+			Description: "Requires the `admin` scope. The plaintext key is only returned once.",
+			Body:        handler.IssueAPIKeyRequest{},
+			Responses: responses(docs.Response{Status: http.StatusCreated, Description: "The key was issued.", Body: usecase.IssuedAPIKeyDTO{}},
+				docs.Errors(400, 401, 403, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/admin/keys", h.ListAPIKeys, adminRead, docs.Operation{
+			ID:          "listAPIKeys",
+			Tag:         "admin",
+			Summary:     "List API keys",
+			Description: "Requires the `admin` scope. Secrets are never returned.",
+			Responses: responses(ok("Every key, newest first, including revoked ones.", []usecase.APIKeyDTO{}),
+				docs.Errors(401, 403, 429, 500)...),
+		}},
+		{http.MethodPost, "/api/admin/keys/:id/rotate", h.RotateAPIKey, adminWrite, docs.Operation{
+			ID:          "rotateAPIKey",
+			Tag:         "admin",
+			Summary:     "Rotate the secret of an API key",
+			Description: "Requires the `admin` scope. The old secret stops working immediately.",
+			Params:      []docs.Param{apiKeyID},
+			Responses:   responses(ok("The new plaintext key.", usecase.IssuedAPIKeyDTO{}), docs.Errors(401, 403, 404, 429, 500)...),
+		}},
+		{http.MethodDelete, "/api/admin/keys/:id", h.RevokeAPIKey, adminWrite, docs.Operation{
+			ID:          "revokeAPIKey",
+			Tag:         "admin",
+			Summary:     "Revoke an API key",
+			Description: "Requires the `admin` scope.",
+			Params:      []docs.Param{apiKeyID},
+			Responses:   responses(ok("The key was revoked.", handler.StatusResponse{}), docs.Errors(401, 403, 404, 429, 500)...),
+		}},
+		{http.MethodPut, "/api/admin/wallets/:address/owner", h.SetWalletOwner, adminWrite, docs.Operation{
+			ID:          "setWalletOwner",
+			Tag:         "admin",
+			Summary:     "Assign a wallet to an end user",
+			Description: "Requires the `admin` scope. Bearer tokens whose subject matches the owner may use the wallet. Transfers screen the owner name against the sanctions lists.",
+			Params:      []docs.Param{walletAddress},
+			Body:        handler.SetWalletOwnerRequest{},
+			Responses:   responses(ok("The owner was assigned.", handler.StatusResponse{}), docs.Errors(400, 401, 403, 404, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/admin/wallets/:address/owner", h.GetWalletOwner, adminRead, docs.Operation{
+			ID:          "getWalletOwner",
+			Tag:         "admin",
+			Summary:     "Get the owner of a wallet",
+			Description: "Requires the `admin` scope. Wallet listings leave the owner out, as its legal name is personal data.",
+			Params:      []docs.Param{walletAddress},
+			Responses:   responses(ok("The owner of the wallet.", usecase.WalletOwnerDTO{}), docs.Errors(400, 401, 403, 404, 429, 500)...),
+		}},
+		{http.MethodPost, "/api/admin/mint", h.MintMoney, adminWrite, docs.Operation{
+			ID:          "mintMoney",
+			Tag:         "admin",
+			Summary:     "Issue money into a wallet",
+			Description: "Requires the `admin` scope. The money is transferred from the mint wallet, which may go negative.",
+			Body:        handler.MintRequest{},
+			Responses:   responses(ok("The money was issued.", handler.StatusResponse{}), docs.Errors(400, 401, 403, 404, 409, 429, 500)...),
+		}},
+		{http.MethodPost, "/api/admin/burn", h.BurnMoney, adminWrite, docs.Operation{
+			ID:          "burnMoney",
+			Tag:         "admin",
+			Summary:     "Destroy money held by a wallet",
+			Description: "Requires the `admin` scope. The money is transferred back to the mint wallet.",
+			Body:        handler.BurnRequest{},
+			Responses:   responses(ok("The money was burned.", handler.StatusResponse{}), docs.Errors(400, 401, 403, 404, 409, 422, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/admin/supply", h.GetSupply, adminRead, docs.Operation{
+			ID:          "getSupply",
+			Tag:         "admin",
+			Summary:     "Report the money supply",
+			Description: "Requires the `admin` scope. Money in circulation is issued minus burned and should equal the sum of user wallet balances.",
+			Responses:   responses(ok("The money supply.", usecase.SupplyDTO{}), docs.Errors(401, 403, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/admin/chain/verify", h.VerifyChain, adminRead, docs.Operation{
+			ID:      "verifyChain",
+			Tag:     "admin",
+			Summary: "Verify the transaction hash chain",
+			Description: "Requires the `admin` scope. Walks the hash chain of the transaction log up to its head, " +
+				"recomputing every hash and comparing it with the stored checkpoints, and reports the " +
+				"first broken link. The status is 200 whether or not the chain is intact.",
+			Responses: responses(ok("The result of the walk.", usecase.ChainVerificationDTO{}), docs.Errors(401, 403, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/admin/chain/checkpoints", h.GetCheckpoints, adminRead, docs.Operation{
+			ID:          "getCheckpoints",
+			Tag:         "admin",
+			Summary:     "Export the signed checkpoints",
+			Description: "Requires the `admin` scope. Checkpoints are listed newest first.",
+			Params:      []docs.Param{limitParam("Maximum number of checkpoints.")},
+			Responses:   responses(ok("The checkpoints.", []usecase.CheckpointDTO{}), docs.Errors(400, 401, 403, 429, 500)...),
+		}},
+		{http.MethodPost, "/api/admin/chain/checkpoints", h.CreateCheckpoint, adminWrite, docs.Operation{
+			ID:      "createCheckpoint",
+			Tag:     "admin",
+			Summary: "Sign a checkpoint of the chain head",
+			Description: "Requires the `admin` scope. Signs and stores the current head of the chain. When the " +
+				"head has not moved since the last checkpoint, that checkpoint is returned.",
+			Responses: append(responses(ok("The checkpoint of the current head.", usecase.CheckpointDTO{}), docs.Errors(401, 403, 429, 500)...),
+				docs.Response{Status: http.StatusNotImplemented, Description: "No signing key is configured.", Body: docs.ErrorResponse{}}),
+		}},
+		{http.MethodGet, "/api/admin/risk/decisions", h.GetDecisions, adminRead, docs.Operation{
+			ID:      "getRiskDecisions",
+			Tag:     "admin",
+			Summary: "Search the risk decisions made on transfers",
+			Description: "Requires the `admin` scope. Every transfer checked against the risk rules leaves a decision " +
+				"listing the rules that fired, so a denied or flagged transfer can be explained. The decision ID " +
+				"is also in the error of a denied transfer. Decisions are listed newest first.",
+			Params: []docs.Param{
+				walletFilter("Only transfers from or to this wallet."),
+				{Name: "action", In: "query", Description: "Only decisions with this outcome.",
+					Schema: &docs.Schema{Type: "string", Enum: []string{string(model.RiskAllow), string(model.RiskReview), string(model.RiskDeny)}}},
+				timeFilter("since", "Only decisions made at or after this time."),
+				timeFilter("until", "Only decisions made before this time."),
+				limitParam("Maximum number of decisions returned."),
+			},
+			Responses: responses(ok("Decisions, newest first.", []usecase.RiskDecisionDTO{}), docs.Errors(400, 401, 403, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/admin/sanctions/cases", h.GetCases, adminRead, docs.Operation{
+			ID:      "getSanctionsCases",
+			Tag:     "admin",
+			Summary: "Search the cases opened by sanctions screening",
+			Description: "Requires the `admin` scope. A transfer whose sender or receiver matches a watchlist entry is " +
+				"blocked and opens a case listing the hits. The case ID is also in the error of the blocked " +
+				"transfer. Cases are listed newest first.",
+			Params: []docs.Param{
+				walletFilter("Only transfers from or to this wallet."),
+				{Name: "status", In: "query", Description: "Only cases with this status.",
+					Schema: &docs.Schema{Type: "string", Enum: []string{string(model.CaseOpen), string(model.CaseConfirmed), string(model.CaseFalsePositive)}}},
+				limitParam("Maximum number of cases returned."),
+			},
+			Responses: responses(ok("Cases, newest first.", []usecase.SanctionsCaseDTO{}), docs.Errors(400, 401, 403, 429, 500)...),
+		}},
+		{http.MethodPost, "/api/admin/sanctions/cases/:id/resolve", h.ResolveCase, adminWrite, docs.Operation{
+			ID:      "resolveSanctionsCase",
+			Tag:     "admin",
+			Summary: "Resolve a sanctions case",
+			Description: "Requires the `admin` scope. Closes an open case as confirmed or as a false positive. A false " +
+				"positive whitelists every wallet of the case for the entry it matched, so the entry no longer " +
+				"blocks its transfers. The blocked transfer itself is not replayed.",
+			Params: []docs.Param{{Name: "id", In: "path", Description: "ID of the case.", Schema: &docs.Schema{Type: "string", Format: "uuid"}}},
+			Body:   handler.ResolveCaseRequest{},
+			Responses: append(responses(ok("The resolved case.", usecase.SanctionsCaseDTO{}), docs.Errors(400, 401, 403, 404, 429, 500)...),
+				docs.Response{Status: http.StatusConflict, Description: "The case is already resolved.", Body: docs.ErrorResponse{}}),
+		}},
+		{http.MethodGet, "/api/admin/sanctions/whitelist", h.GetWhitelist, adminRead, docs.Operation{
+			ID:          "getSanctionsWhitelist",
+			Tag:         "admin",
+			Summary:     "List the false positives cleared for wallets",
+			Description: "Requires the `admin` scope. Entries are listed oldest first.",
+			Params:      []docs.Param{walletFilter("Only entries cleared for this wallet.")},
+			Responses:   responses(ok("Whitelist entries.", []usecase.WhitelistEntryDTO{}), docs.Errors(400, 401, 403, 429, 500)...),
+		}},
+		{http.MethodPost, "/api/admin/sanctions/whitelist", h.AddToWhitelist, adminWrite, docs.Operation{
+			ID:      "addToSanctionsWhitelist",
+			Tag:     "admin",
+			Summary: "Whitelist a watchlist entry for a wallet",
+			Description: "Requires the `admin` scope. Clears the wallet of the entry without a case, so the entry no " +
+				"longer blocks its transfers. The reason is required and kept with the entry. An entry already " +
+				"whitelisted is returned as it is.",
+			Body:      handler.WhitelistRequest{},
+			Responses: responses(ok("The whitelist entry.", usecase.WhitelistEntryDTO{}), docs.Errors(400, 401, 403, 429, 500)...),
+		}},
+		{http.MethodDelete, "/api/admin/sanctions/whitelist", h.RemoveFromWhitelist, adminWrite, docs.Operation{
+			ID:      "removeFromSanctionsWhitelist",
+			Tag:     "admin",
+			Summary: "Remove a watchlist entry from the whitelist of a wallet",
+			Description: "Requires the `admin` scope. The entry blocks the transfers of the wallet again. The reason " +
+				"is required and logged.",
+			Body:      handler.WhitelistRequest{},
+			Responses: responses(ok("The entry was removed.", handler.StatusResponse{}), docs.Errors(400, 401, 403, 404, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/admin/limits/tiers", h.GetTiers, adminRead, docs.Operation{
+			ID:          "getLimitTiers",
+			Tag:         "admin",
+			Summary:     "List the limit tiers",
+			Description: "Requires the `admin` scope. Tiers are listed by name.",
+			Responses:   responses(ok("Limit tiers.", []usecase.TierLimitsDTO{}), docs.Errors(401, 403, 429, 500)...),
+		}},
+		{http.MethodPut, "/api/admin/limits/tiers/:name", h.SetTier, adminWrite, docs.Operation{
+			ID:      "setLimitTier",
+			Tag:     "admin",
+			Summary: "Create a limit tier or replace its limits",
+			Description: "Requires the `admin` scope. The `default` tier applies to wallets not assigned to another tier; " +
+				"until it is saved, such wallets have no tier limits.",
+			Params:    []docs.Param{{Name: "name", In: "path", Description: "Name of the tier.", Schema: &docs.Schema{Type: "string"}}},
+			Body:      usecase.LimitsDTO{},
+			Responses: responses(ok("The saved tier.", usecase.TierLimitsDTO{}), docs.Errors(400, 401, 403, 429, 500)...),
+		}},
+		{http.MethodGet, "/api/admin/wallets/:address/limits", h.GetWalletLimits, adminRead, docs.Operation{
+			ID:          "getWalletLimits",
+			Tag:         "admin",
+			Summary:     "Get the limit tier of a wallet and the limits it overrides",
+			Description: "Requires the `admin` scope.",
+			Params:      []docs.Param{walletAddress},
+			Responses:   responses(ok("The tier and overrides of the wallet.", usecase.WalletLimitsDTO{}), docs.Errors(400, 401, 403, 404, 429, 500)...),
+		}},
+		{http.MethodPut, "/api/admin/wallets/:address/limits", h.SetWalletLimits, adminWrite, docs.Operation{
+			ID:          "setWalletLimits",
+			Tag:         "admin",
+			Summary:     "Assign a wallet to a limit tier and override its limits",
+			Description: "Requires the `admin` scope. Replaces every override of the wallet.",
+			Params:      []docs.Param{walletAddress},
+			Body:        handler.SetWalletLimitsRequest{},
+			Responses:   responses(ok("The saved tier and overrides.", usecase.WalletLimitsDTO{}), docs.Errors(400, 401, 403, 404, 429, 500)...),
+		}},
+		{http.MethodPut, "/api/admin/wallets/:address/status", h.SetWalletStatus, adminWrite, docs.Operation{
+			ID:      "setWalletStatus",
+			Tag:     "admin",
+			Summary: "Change the status of a wallet",
+			Description: "Requires the `admin` scope. Frozen wallets neither send nor receive transfers, so their " +
+				"balance stays put during a review; debit-blocked wallets only receive, and closed wallets do neither. " +
+				"Mints and burns only stop at closed wallets. " +
+				"Any status but `closed` may change to any other; closing is final and needs a zero balance. " +
+				"Every change is recorded in the status history of the wallet.",
+			Params: []docs.Param{walletAddress},
+			Body:   handler.SetWalletStatusRequest{},
+			Responses: append(responses(ok("The recorded status change.", usecase.StatusChangeDTO{}), docs.Errors(400, 401, 403, 404, 429, 500)...),
+				docs.Response{Status: http.StatusConflict, Body: docs.ErrorResponse{}, Description: "The wallet cannot change to this status: " +
+					"it already has it, it is closed, it is not empty or a transfer changed it meanwhile."}),
+		}},
+		{http.MethodGet, "/api/admin/wallets/:address/status/history", h.GetStatusHistory, adminRead, docs.Operation{
+			ID:          "getWalletStatusHistory",
+			Tag:         "admin",
+			Summary:     "List the status changes of a wallet",
+			Description: "Requires the `admin` scope. Changes are listed oldest first.",
+			Params:      []docs.Param{walletAddress},
+			Responses:   responses(ok("Status changes.", []usecase.StatusChangeDTO{}), docs.Errors(400, 401, 403, 404, 429, 500)...),
+		}},
+
+		// Anyone holding a receipt may verify it, so the keys need no credentials.
+		{http.MethodGet, "/api/keys", h.GetSigningKeys, chain(m.ReadLimit), docs.Operation{
+			ID:      "getSigningKeys",
+			Tag:     "wallets",
+			Summary: "List the keys receipts and checkpoints are verified with",
+			Description: "Lists the public signing keys, the current one first. Retired keys no longer sign but are kept, " +
+				"so what they signed still verifies. Empty when no signing key is configured. Needs no credentials.",
+			Public:    true,
+			Responses: responses(ok("The public signing keys.", usecase.SigningKeysDTO{}), docs.Errors(429)...),
+		}},
+
+		// Probes and metrics are answered without credentials and only limited by IP.
+		{http.MethodGet, "/healthz", h.Healthz, nil, docs.Operation{
+			ID:          "healthz",
+			Tag:         "health",
+			Summary:     "Liveness probe",
+			Description: "Succeeds while the process is up. Needs no credentials.",
+			Public:      true,
+			Responses:   responses(ok("The process is up.", handler.StatusResponse{}), docs.Errors(429)...),
+		}},
+		{http.MethodGet, "/readyz", h.Readyz, nil, docs.Operation{
+			ID:      "readyz",
+			Tag:     "health",
+			Summary: "Readiness probe",
+			Description: "Succeeds when the database answers, its schema is at the version this build expects, " +
+				"startup initialization has finished and the service is not shutting down. Needs no credentials.",
+			Public: true,
+			Responses: append(responses(ok("The service can take traffic.", usecase.ReadinessDTO{}), docs.Errors(429)...),
+				docs.Response{Status: http.StatusServiceUnavailable, Description: "The service cannot take traffic; failing checks carry a detail.", Body: usecase.ReadinessDTO{}}),
+		}},
+		{http.MethodGet, "/metrics", h.Metrics, nil, docs.Operation{
+			ID:          "metrics",
+			Tag:         "health",
+			Summary:     "Prometheus metrics",
+			Description: "Exposes request, transfer, cache and database pool metrics in the Prometheus text format. Needs no credentials.",
+			Public:      true,
+			Responses: responses(docs.Response{
+				Status:      http.StatusOK,
+				Description: "The current metrics.",
+				ContentType: "text/plain",
+				Example: "# HELP transaction_service_http_requests_total HTTP requests by method, route pattern and status code.\n" +
+					"# TYPE transaction_service_http_requests_total counter\n",
+			}, docs.Errors(429)...),
+		}},
+	}
+}
@@ -0,0 +1,133 @@
+// Package usecase implements application-specific logic for API keys.
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// APIKeyUsecase defines application-level logic for managing API keys.
+type APIKeyUsecase interface {
+	// IssueKey creates a key and returns it with its plaintext value.
+	IssueKey(ctx context.Context, name string, scopes []string, walletIDs []string) (*IssuedAPIKeyDTO, error)
+
+	// RotateKey replaces the secret of a key and returns the new plaintext value.
+	RotateKey(ctx context.Context, id string) (*IssuedAPIKeyDTO, error)
+
+	// RevokeKey disables a key.
+	RevokeKey(ctx context.Context, id string) error
+
+	// ListKeys returns every key without secrets.
+	ListKeys(ctx context.Context) ([]APIKeyDTO, error)
+}
+
+type apiKeyUsecase struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyUsecase(apiKeyService service.APIKeyService) APIKeyUsecase {
+	return &apiKeyUsecase{apiKeyService: apiKeyService}
+}
+
+func (u *apiKeyUsecase) IssueKey(ctx context.Context, name string, scopes []string, walletIDs []string) (*IssuedAPIKeyDTO, error) {
+	parsedScopes := make([]model.Scope, len(scopes))
+	for i, s := range scopes {
+		scope, ok := model.ParseScope(s)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", model.ErrInvalidScope, s)
+		}
+		parsedScopes[i] = scope
+	}
+
+	parsedWallets := make([]uuid.UUID, len(walletIDs))
+	for i, w := range walletIDs {
+		id, err := uuid.Parse(w)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+		}
+		parsedWallets[i] = id
+	}
+
+	key, plaintext, err := u.apiKeyService.Issue(ctx, name, parsedScopes, parsedWallets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue api key: %w", err)
+	}
+	return &IssuedAPIKeyDTO{APIKeyDTO: newAPIKeyDTO(key), Key: plaintext}, nil
+}
+
+func (u *apiKeyUsecase) RotateKey(ctx context.Context, id string) (*IssuedAPIKeyDTO, error) {
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid id: %v", model.ErrAPIKeyNotFound, err)
+	}
+
+	plaintext, err := u.apiKeyService.Rotate(ctx, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate api key: %w", err)
+	}
+	return &IssuedAPIKeyDTO{APIKeyDTO: APIKeyDTO{ID: keyID.String()}, Key: plaintext}, nil
+}
+
+func (u *apiKeyUsecase) RevokeKey(ctx context.Context, id string) error {
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("%w: invalid id: %v", model.ErrAPIKeyNotFound, err)
+	}
+
+	if err := u.apiKeyService.Revoke(ctx, keyID); err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+func (u *apiKeyUsecase) ListKeys(ctx context.Context) ([]APIKeyDTO, error) {
+	keys, err := u.apiKeyService.FetchAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return lo.Map(keys, func(key *model.APIKey, _ int) APIKeyDTO {
+		return newAPIKeyDTO(key)
+	}), nil
+}
+
+// APIKeyDTO represents an API key without its secret.
+type APIKeyDTO struct {
+	ID        string   `json:"id" format:"uuid"`
+	Name      string   `json:"name,omitempty"`
+	Scopes    []string `json:"scopes,omitempty" enums:"wallets:read,transfers:write,admin"`
+	WalletIDs []string `json:"wallet_ids,omitempty" format:"uuid"`
+	CreatedAt string   `json:"created_at,omitempty"`
+	RotatedAt string   `json:"rotated_at,omitempty"`
+	RevokedAt string   `json:"revoked_at,omitempty"`
+}
+
+// IssuedAPIKeyDTO is an API key together with its plaintext value, returned only once.
+type IssuedAPIKeyDTO struct {
+	APIKeyDTO
+	Key string `json:"key" doc:"Plaintext key to send in the X-API-Key header." example:"tsk_0d9b7c1e3f0a4c539d3e3b5c9e1c2a11_..."`
+}
+
+func newAPIKeyDTO(key *model.APIKey) APIKeyDTO {
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("2006-01-02 15:04:05")
+	}
+
+	return APIKeyDTO{
+		ID:        key.ID.String(),
+		Name:      key.Name,
+		Scopes:    lo.Map(key.Scopes, func(s model.Scope, _ int) string { return string(s) }),
+		WalletIDs: lo.Map(key.WalletIDs, func(id uuid.UUID, _ int) string { return id.String() }),
+		CreatedAt: formatTime(&key.CreatedAt),
+		RotatedAt: formatTime(key.RotatedAt),
+		RevokedAt: formatTime(key.RevokedAt),
+	}
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// ChainUsecase defines application-level logic for the hash chain of the
+// transaction log.
+type ChainUsecase interface {
+	// VerifyChain walks the chain and reports the first broken link, if any.
+	VerifyChain(ctx context.Context) (*ChainVerificationDTO, error)
+
+	// CreateCheckpoint signs the current head of the chain.
+	CreateCheckpoint(ctx context.Context) (*CheckpointDTO, error)
+
+	// GetCheckpoints returns up to limit checkpoints, newest first.
+	GetCheckpoints(ctx context.Context, limit int) ([]CheckpointDTO, error)
+}
+
+// Checkpoint page sizes accepted by GetCheckpoints.
+const (
+	DefaultCheckpointLimit = 100
+	MaxCheckpointLimit     = 1000
+)
+
+type chainUsecase struct {
+	chainService service.ChainService
+}
+
+func NewChainUsecase(chainService service.ChainService) ChainUsecase {
+	return &chainUsecase{chainService: chainService}
+}
+
+func (u *chainUsecase) VerifyChain(ctx context.Context) (_ *ChainVerificationDTO, err error) {
+	ctx, span := tracer.Start(ctx, "ChainUsecase.VerifyChain")
+	defer func() { endSpan(span, err) }()
+
+	report, err := u.chainService.Verify(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify chain: %w", err)
+	}
+	result := &ChainVerificationDTO{
+		Intact:      report.Broken == nil,
+		Checked:     report.Checked,
+		Unchained:   report.Unchained,
+		Checkpoints: report.Checkpoints,
+		Head:        newChainHeadDTO(report.Head),
+	}
+	if b := report.Broken; b != nil {
+		result.Broken = &BrokenLinkDTO{Seq: b.Seq, Problem: b.Problem, Expected: b.Expected, Actual: b.Actual}
+		if b.TransactionID != uuid.Nil {
+			result.Broken.TransactionID = b.TransactionID.String()
+		}
+	}
+	return result, nil
+}
+
+func (u *chainUsecase) CreateCheckpoint(ctx context.Context) (_ *CheckpointDTO, err error) {
+	ctx, span := tracer.Start(ctx, "ChainUsecase.CreateCheckpoint")
+	defer func() { endSpan(span, err) }()
+
+	checkpoint, err := u.chainService.Checkpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+	result := newCheckpointDTO(checkpoint)
+	return &result, nil
+}
+
+func (u *chainUsecase) GetCheckpoints(ctx context.Context, limit int) (_ []CheckpointDTO, err error) {
+	ctx, span := tracer.Start(ctx, "ChainUsecase.GetCheckpoints")
+	defer func() { endSpan(span, err) }()
+
+	if limit < 0 || limit > MaxCheckpointLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", model.ErrInvalidFilter, MaxCheckpointLimit)
+	}
+	if limit == 0 {
+		limit = DefaultCheckpointLimit
+	}
+	checkpoints, err := u.chainService.Checkpoints(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkpoints: %w", err)
+	}
+	return lo.Map(checkpoints, func(c model.Checkpoint, _ int) CheckpointDTO {
+		return newCheckpointDTO(c)
+	}), nil
+}
+
+// ChainVerificationDTO represents the result of a chain verification.
+type ChainVerificationDTO struct {
+	Intact      bool           `json:"intact"`
+	Checked     int            `json:"checked" doc:"Chained transactions verified."`
+	Unchained   int            `json:"unchained" doc:"Transactions created before the chain began, not verified."`
+	Checkpoints int            `json:"checkpoints" doc:"Checkpoints the chain was compared with."`
+	Head        ChainHeadDTO   `json:"head"`
+	Broken      *BrokenLinkDTO `json:"broken,omitempty" doc:"First broken link, absent when the chain is intact."`
+}
+
+// ChainHeadDTO represents the last link of the chain.
+type ChainHeadDTO struct {
+	Seq   int64  `json:"seq" doc:"Sequence number of the last chained transaction."`
+	Hash  string `json:"hash" doc:"Hash of that transaction."`
+	Count int64  `json:"count" doc:"Number of transactions chained so far."`
+}
+
+func newChainHeadDTO(head model.ChainHead) ChainHeadDTO {
+	return ChainHeadDTO{Seq: head.Seq, Hash: head.Hash, Count: head.Count}
+}
+
+// BrokenLinkDTO represents the first transaction whose hashes do not add up.
+type BrokenLinkDTO struct {
+	Seq           int64  `json:"seq"`
+	TransactionID string `json:"transaction_id,omitempty" format:"uuid"`
+	Problem       string `json:"problem" example:"content does not match its hash"`
+	Expected      string `json:"expected"`
+	Actual        string `json:"actual"`
+}
+
+// CheckpointDTO represents a signed checkpoint. The signature, in base64, covers
+// the payload described by model.Checkpoint.SignedPayload.
+type CheckpointDTO struct {
+	ChainHeadDTO
+	CreatedAt string `json:"created_at" format:"date-time"`
+	KeyID     string `json:"key_id" doc:"ID of the Ed25519 key the checkpoint is signed with."`
+	Signature string `json:"signature" format:"byte" doc:"Base64 Ed25519 signature of the JSON object {\"seq\":…,\"count\":…,\"hash\":\"…\",\"created_at\":\"…\",\"key_id\":\"…\"}, with the fields in this order and created_at in RFC 3339 with nanoseconds, in UTC."`
+}
+
+func newCheckpointDTO(c model.Checkpoint) CheckpointDTO {
+	return CheckpointDTO{
+		ChainHeadDTO: newChainHeadDTO(c.ChainHead),
+		CreatedAt:    c.CreatedAt.UTC().Format(time.RFC3339Nano),
+		KeyID:        c.KeyID,
+		Signature:    base64.StdEncoding.EncodeToString(c.Signature),
+	}
+}
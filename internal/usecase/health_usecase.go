@@ -0,0 +1,56 @@
+// Package usecase implements application-specific logic for health probes.
+package usecase
+
+import (
+	"context"
+	"transaction-service/internal/domain/service"
+)
+
+// Readiness statuses reported in ReadinessDTO.
+const (
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// HealthUsecase defines application-level logic for health probes.
+type HealthUsecase interface {
+	// Readiness reports whether the service can take traffic and why not.
+	Readiness(ctx context.Context) ReadinessDTO
+}
+
+type healthUsecase struct {
+	healthService service.HealthService
+}
+
+func NewHealthUsecase(healthService service.HealthService) HealthUsecase {
+	return &healthUsecase{healthService: healthService}
+}
+
+func (u *healthUsecase) Readiness(ctx context.Context) ReadinessDTO {
+	ready, checks := u.healthService.Ready(ctx)
+
+	dto := ReadinessDTO{Status: StatusNotReady, Checks: make(map[string]HealthCheckDTO, len(checks))}
+	if ready {
+		dto.Status = StatusReady
+	}
+	for _, c := range checks {
+		status := StatusReady
+		if !c.Healthy {
+			status = StatusNotReady
+		}
+		dto.Checks[c.Name] = HealthCheckDTO{Status: status, Detail: c.Detail}
+	}
+	return dto
+}
+
+// ReadinessDTO represents the result of the readiness probe.
+type ReadinessDTO struct {
+	Status string                    `json:"status" enums:"ready,not_ready"`
+	Checks map[string]HealthCheckDTO `json:"checks" doc:"Checks by name: database, migrations, initialization and shutdown."`
+}
+
+// HealthCheckDTO represents a single readiness check.
+type HealthCheckDTO struct {
+	Status string `json:"status" enums:"ready,not_ready"`
+	Detail string `json:"detail,omitempty" example:"schema version 20250218100000, expected 20250218100000"`
+}
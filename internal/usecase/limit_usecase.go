@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// LimitUsecase defines application-level logic for the spending limits of tiers
+// and wallets.
+type LimitUsecase interface {
+	// GetAllowance returns the limits of a wallet and what is left of them.
+	GetAllowance(ctx context.Context, walletID string) (AllowanceDTO, error)
+
+	// GetTiers returns every tier, by name.
+	GetTiers(ctx context.Context) ([]TierLimitsDTO, error)
+
+	// SetTier creates a tier or replaces its limits.
+	SetTier(ctx context.Context, name string, limits LimitsDTO) (TierLimitsDTO, error)
+
+	// GetWalletLimits returns the tier of a wallet and the limits it overrides.
+	GetWalletLimits(ctx context.Context, walletID string) (WalletLimitsDTO, error)
+
+	// SetWalletLimits assigns a wallet to a tier, the default one when empty, and
+	// replaces the limits it overrides.
+	SetWalletLimits(ctx context.Context, walletID, tier string, limits LimitsDTO) (WalletLimitsDTO, error)
+}
+
+type limitUsecase struct {
+	limitService service.LimitService
+}
+
+func NewLimitUsecase(limitService service.LimitService) LimitUsecase {
+	return &limitUsecase{limitService: limitService}
+}
+
+func (u *limitUsecase) GetAllowance(ctx context.Context, walletID string) (_ AllowanceDTO, err error) {
+	ctx, span := tracer.Start(ctx, "LimitUsecase.GetAllowance")
+	defer func() { endSpan(span, err) }()
+
+	walletUUID, err := uuid.Parse(walletID)
+	if err != nil {
+		return AllowanceDTO{}, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+	}
+	p, err := principalOf(ctx)
+	if err != nil {
+		return AllowanceDTO{}, err
+	}
+	if !p.CanAccessWallet(walletUUID) {
+		return AllowanceDTO{}, fmt.Errorf("%w: cannot read wallet %s", model.ErrForbidden, walletUUID)
+	}
+
+	allowance, err := u.limitService.Allowance(ctx, walletUUID)
+	if err != nil {
+		return AllowanceDTO{}, fmt.Errorf("failed to get allowance: %w", err)
+	}
+	return AllowanceDTO{
+		WalletID: allowance.WalletID.String(),
+		Tier:     allowance.Tier,
+		Limits:   newLimitsDTO(allowance.Limits),
+		Used: UsageDTO{
+			DailyTotal:   float64(allowance.Used.DailyTotal) / 100,
+			MonthlyTotal: float64(allowance.Used.MonthlyTotal) / 100,
+			HourlyCount:  allowance.Used.HourlyCount,
+		},
+		Remaining: newLimitsDTO(allowance.Remaining()),
+	}, nil
+}
+
+func (u *limitUsecase) GetTiers(ctx context.Context) (_ []TierLimitsDTO, err error) {
+	ctx, span := tracer.Start(ctx, "LimitUsecase.GetTiers")
+	defer func() { endSpan(span, err) }()
+
+	tiers, err := u.limitService.Tiers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get limit tiers: %w", err)
+	}
+	return lo.Map(tiers, func(tier model.TierLimits, _ int) TierLimitsDTO {
+		return newTierLimitsDTO(tier)
+	}), nil
+}
+
+func (u *limitUsecase) SetTier(ctx context.Context, name string, limits LimitsDTO) (_ TierLimitsDTO, err error) {
+	ctx, span := tracer.Start(ctx, "LimitUsecase.SetTier")
+	defer func() { endSpan(span, err) }()
+
+	tier, err := u.limitService.SetTier(ctx, name, limits.toModel())
+	if err != nil {
+		return TierLimitsDTO{}, fmt.Errorf("failed to set limit tier: %w", err)
+	}
+	return newTierLimitsDTO(tier), nil
+}
+
+func (u *limitUsecase) GetWalletLimits(ctx context.Context, walletID string) (_ WalletLimitsDTO, err error) {
+	ctx, span := tracer.Start(ctx, "LimitUsecase.GetWalletLimits")
+	defer func() { endSpan(span, err) }()
+
+	walletUUID, err := uuid.Parse(walletID)
+	if err != nil {
+		return WalletLimitsDTO{}, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+	}
+	limits, err := u.limitService.WalletLimits(ctx, walletUUID)
+	if err != nil {
+		return WalletLimitsDTO{}, fmt.Errorf("failed to get wallet limits: %w", err)
+	}
+	return newWalletLimitsDTO(limits), nil
+}
+
+func (u *limitUsecase) SetWalletLimits(ctx context.Context, walletID, tier string, limits LimitsDTO) (_ WalletLimitsDTO, err error) {
+	ctx, span := tracer.Start(ctx, "LimitUsecase.SetWalletLimits")
+	defer func() { endSpan(span, err) }()
+
+	walletUUID, err := uuid.Parse(walletID)
+	if err != nil {
+		return WalletLimitsDTO{}, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+	}
+	saved, err := u.limitService.SetWalletLimits(ctx, walletUUID, tier, limits.toModel())
+	if err != nil {
+		return WalletLimitsDTO{}, fmt.Errorf("failed to set wallet limits: %w", err)
+	}
+	return newWalletLimitsDTO(saved), nil
+}
+
+// LimitsDTO represents spending limits, amounts in currency units. A null limit
+// caps nothing, or takes the limit of the tier when overridden for a wallet.
+type LimitsDTO struct {
+	MaxTransfer  *float64 `json:"max_transfer" minimum:"0" doc:"Largest single transfer."`
+	DailyTotal   *float64 `json:"daily_total" minimum:"0" doc:"Total sent per calendar day in UTC."`
+	MonthlyTotal *float64 `json:"monthly_total" minimum:"0" doc:"Total sent per calendar month in UTC."`
+	HourlyCount  *int     `json:"hourly_count" minimum:"0" doc:"Transfers sent within the last hour."`
+}
+
+// TierLimitsDTO represents the limits of a tier.
+type TierLimitsDTO struct {
+	Name      string    `json:"name" example:"default"`
+	Limits    LimitsDTO `json:"limits"`
+	UpdatedAt string    `json:"updated_at" format:"date-time"`
+}
+
+// WalletLimitsDTO represents the tier of a wallet and the limits it overrides.
+type WalletLimitsDTO struct {
+	WalletID  string    `json:"wallet_id" format:"uuid"`
+	Tier      string    `json:"tier" example:"default"`
+	Limits    LimitsDTO `json:"limits"`
+	UpdatedAt string    `json:"updated_at,omitempty" format:"date-time" doc:"Absent until limits are set for the wallet."`
+}
+
+// AllowanceDTO represents the limits of a wallet, what it has sent against them
+// and what is left.
+type AllowanceDTO struct {
+	WalletID  string    `json:"wallet_id" format:"uuid"`
+	Tier      string    `json:"tier"`
+	Limits    LimitsDTO `json:"limits"`
+	Used      UsageDTO  `json:"used"`
+	Remaining LimitsDTO `json:"remaining"`
+}
+
+// UsageDTO represents what a wallet has sent today, this month and within the
+// last hour.
+type UsageDTO struct {
+	DailyTotal   float64 `json:"daily_total" doc:"Sent today."`
+	MonthlyTotal float64 `json:"monthly_total" doc:"Sent this month."`
+	HourlyCount  int     `json:"hourly_count" doc:"Transfers sent within the last hour."`
+}
+
+func (l LimitsDTO) toModel() model.Limits {
+	cents := func(units *float64) *int {
+		if units == nil {
+			return nil
+		}
+		c := int(math.Round(*units * 100))
+		return &c
+	}
+	return model.Limits{
+		MaxTransfer:  cents(l.MaxTransfer),
+		DailyTotal:   cents(l.DailyTotal),
+		MonthlyTotal: cents(l.MonthlyTotal),
+		HourlyCount:  l.HourlyCount,
+	}
+}
+
+func newLimitsDTO(l model.Limits) LimitsDTO {
+	units := func(cents *int) *float64 {
+		if cents == nil {
+			return nil
+		}
+		u := float64(*cents) / 100
+		return &u
+	}
+	return LimitsDTO{
+		MaxTransfer:  units(l.MaxTransfer),
+		DailyTotal:   units(l.DailyTotal),
+		MonthlyTotal: units(l.MonthlyTotal),
+		HourlyCount:  l.HourlyCount,
+	}
+}
+
+func newTierLimitsDTO(tier model.TierLimits) TierLimitsDTO {
+	return TierLimitsDTO{
+		Name:      tier.Name,
+		Limits:    newLimitsDTO(tier.Limits),
+		UpdatedAt: tier.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+func newWalletLimitsDTO(limits model.WalletLimits) WalletLimitsDTO {
+	dto := WalletLimitsDTO{
+		WalletID: limits.WalletID.String(),
+		Tier:     limits.TierName(),
+		Limits:   newLimitsDTO(limits.Limits),
+	}
+	if !limits.UpdatedAt.IsZero() {
+		dto.UpdatedAt = limits.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return dto
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"transaction-service/internal/domain/model"
+)
+
+// principalOf returns the caller of a call scoped to wallets. The APIs authenticate
+// their callers and the CLI marks itself trusted, so a call without a principal
+// is refused rather than given access to every wallet.
+func principalOf(ctx context.Context) (*model.Principal, error) {
+	p, ok := model.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: no caller in the call context", model.ErrUnauthenticated)
+	}
+	return p, nil
+}
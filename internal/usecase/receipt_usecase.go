@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/service"
+
+	"github.com/samber/lo"
+)
+
+// ReceiptUsecase defines application-level logic for transfer receipts.
+type ReceiptUsecase interface {
+	// GetSigningKeys returns the public keys receipts and checkpoints are verified with.
+	GetSigningKeys(ctx context.Context) (*SigningKeysDTO, error)
+}
+
+type receiptUsecase struct {
+	receiptService service.ReceiptService
+}
+
+func NewReceiptUsecase(receiptService service.ReceiptService) ReceiptUsecase {
+	return &receiptUsecase{receiptService: receiptService}
+}
+
+func (u *receiptUsecase) GetSigningKeys(ctx context.Context) (_ *SigningKeysDTO, err error) {
+	ctx, span := tracer.Start(ctx, "ReceiptUsecase.GetSigningKeys")
+	defer func() { endSpan(span, err) }()
+
+	keys := u.receiptService.PublicKeys(ctx)
+	return &SigningKeysDTO{Keys: lo.Map(keys, func(k model.PublicKey, _ int) SigningKeyDTO {
+		status := SigningKeyRetired
+		if k.Current {
+			status = SigningKeyCurrent
+		}
+		return SigningKeyDTO{
+			KeyID:     k.ID,
+			Algorithm: "Ed25519",
+			PublicKey: base64.StdEncoding.EncodeToString(k.Key),
+			Status:    status,
+		}
+	})}, nil
+}
+
+// TransferDTO represents a committed transfer.
+type TransferDTO struct {
+	Transaction TransactionDTO `json:"transaction"`
+	Receipt     *ReceiptDTO    `json:"receipt,omitempty" doc:"Signed proof of the transfer. Absent when no signing key is configured."`
+}
+
+// ReceiptDTO represents a signed transfer receipt. The signature, in base64, covers
+// the other fields as described by model.Receipt.SignedPayload.
+type ReceiptDTO struct {
+	TransactionID string `json:"transaction_id" format:"uuid"`
+	From          string `json:"from" format:"uuid"`
+	To            string `json:"to" format:"uuid"`
+	AmountCents   int    `json:"amount_cents" example:"150"`
+	CreatedAt     string `json:"created_at" format:"date-time"`
+	Seq           int64  `json:"seq"`
+	Hash          string `json:"hash" doc:"Hash linking the transaction into the transaction log."`
+	IssuedAt      string `json:"issued_at" format:"date-time"`
+	KeyID         string `json:"key_id" doc:"ID of the Ed25519 key the receipt is signed with."`
+	Signature     string `json:"signature" format:"byte" doc:"Base64 Ed25519 signature of the JSON object of the other fields, in the order listed here, with both times as served."`
+}
+
+func newReceiptDTO(r model.Receipt) ReceiptDTO {
+	return ReceiptDTO{
+		TransactionID: r.TransactionID.String(),
+		From:          r.From,
+		To:            r.To,
+		AmountCents:   r.Amount,
+		CreatedAt:     r.CreatedAt.UTC().Format(time.RFC3339Nano),
+		Seq:           r.Seq,
+		Hash:          r.Hash,
+		IssuedAt:      r.IssuedAt.UTC().Format(time.RFC3339Nano),
+		KeyID:         r.KeyID,
+		Signature:     base64.StdEncoding.EncodeToString(r.Signature),
+	}
+}
+
+// Statuses of a SigningKeyDTO.
+const (
+	SigningKeyCurrent = "current" // Signs new receipts and checkpoints
+	SigningKeyRetired = "retired" // Only verifies what it signed before
+)
+
+// SigningKeysDTO represents the published signing keys.
+type SigningKeysDTO struct {
+	Keys []SigningKeyDTO `json:"keys"`
+}
+
+// SigningKeyDTO represents a public key. The key is the raw 32-byte Ed25519 key in
+// base64.
+type SigningKeyDTO struct {
+	KeyID     string `json:"key_id" doc:"Hex of the first 8 bytes of the SHA-256 hash of the public key."`
+	Algorithm string `json:"algorithm" enums:"Ed25519"`
+	PublicKey string `json:"public_key" format:"byte" doc:"The raw 32-byte public key in base64."`
+	Status    string `json:"status" enums:"current,retired"`
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// RiskUsecase defines application-level logic for the risk decisions made on
+// transfers.
+type RiskUsecase interface {
+	// GetDecisions returns the decisions matching the query, newest first.
+	GetDecisions(ctx context.Context, query DecisionQuery) ([]RiskDecisionDTO, error)
+}
+
+// Decision page sizes accepted by GetDecisions.
+const (
+	DefaultDecisionLimit = 100
+	MaxDecisionLimit     = 1000
+)
+
+// DecisionQuery selects risk decisions for GetDecisions. Zero fields match
+// everything.
+type DecisionQuery struct {
+	WalletID string
+	Action   string // allow, review or deny
+	Since    time.Time
+	Until    time.Time
+	Limit    int // DefaultDecisionLimit when zero, at most MaxDecisionLimit
+}
+
+type riskUsecase struct {
+	riskService service.RiskService
+}
+
+func NewRiskUsecase(riskService service.RiskService) RiskUsecase {
+	return &riskUsecase{riskService: riskService}
+}
+
+func (u *riskUsecase) GetDecisions(ctx context.Context, query DecisionQuery) (_ []RiskDecisionDTO, err error) {
+	ctx, span := tracer.Start(ctx, "RiskUsecase.GetDecisions")
+	defer func() { endSpan(span, err) }()
+
+	filter, err := query.toFilter()
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := u.riskService.Decisions(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk decisions: %w", err)
+	}
+	return lo.Map(decisions, func(d model.RiskDecision, _ int) RiskDecisionDTO {
+		return newRiskDecisionDTO(d)
+	}), nil
+}
+
+// toFilter validates the query and converts it to a decision filter.
+func (q DecisionQuery) toFilter() (model.RiskDecisionFilter, error) {
+	filter := model.RiskDecisionFilter{
+		Action: model.RiskAction(q.Action),
+		Since:  q.Since,
+		Until:  q.Until,
+		Limit:  q.Limit,
+	}
+	if q.WalletID != "" {
+		walletUUID, err := uuid.Parse(q.WalletID)
+		if err != nil {
+			return model.RiskDecisionFilter{}, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+		}
+		filter.WalletID = walletUUID.String()
+	}
+
+	switch {
+	case q.Action != "" && !filter.Action.Valid():
+		return model.RiskDecisionFilter{}, fmt.Errorf("%w: unknown action %q", model.ErrInvalidFilter, q.Action)
+	case !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until):
+		return model.RiskDecisionFilter{}, fmt.Errorf("%w: since must be before until", model.ErrInvalidFilter)
+	case q.Limit < 0 || q.Limit > MaxDecisionLimit:
+		return model.RiskDecisionFilter{}, fmt.Errorf("%w: limit must be between 1 and %d", model.ErrInvalidFilter, MaxDecisionLimit)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultDecisionLimit
+	}
+	return filter, nil
+}
+
+// RiskDecisionDTO represents a risk decision and the rules behind it.
+type RiskDecisionDTO struct {
+	ID        string         `json:"id" format:"uuid"`
+	CreatedAt string         `json:"created_at" format:"date-time"`
+	From      string         `json:"from" format:"uuid"`
+	To        string         `json:"to" format:"uuid"`
+	Amount    float64        `json:"amount"`
+	Action    string         `json:"action" enums:"allow,review,deny"`
+	Fired     []FiredRuleDTO `json:"fired" doc:"Rules that fired; the decision is the most severe of their actions."`
+	RequestID string         `json:"request_id,omitempty" doc:"ID of the request that asked for the transfer."`
+}
+
+// FiredRuleDTO represents a rule that fired for a transfer.
+type FiredRuleDTO struct {
+	Rule   string `json:"rule" doc:"Name of the rule in the rules file."`
+	Action string `json:"action" enums:"allow,review,deny"`
+	Reason string `json:"reason"`
+}
+
+func newRiskDecisionDTO(d model.RiskDecision) RiskDecisionDTO {
+	return RiskDecisionDTO{
+		ID:        d.ID.String(),
+		CreatedAt: d.CreatedAt.UTC().Format(time.RFC3339Nano),
+		From:      d.From,
+		To:        d.To,
+		Amount:    float64(d.Amount) / 100,
+		Action:    string(d.Action),
+		Fired: lo.Map(d.Fired, func(f model.FiredRule, _ int) FiredRuleDTO {
+			return FiredRuleDTO{Rule: f.Rule, Action: string(f.Action), Reason: f.Reason}
+		}),
+		RequestID: d.RequestID,
+	}
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// SanctionsUsecase defines application-level logic for the cases opened by
+// sanctions screening and the whitelist of cleared false positives.
+type SanctionsUsecase interface {
+	// GetCases returns the cases matching the query, newest first.
+	GetCases(ctx context.Context, query CaseQuery) ([]SanctionsCaseDTO, error)
+
+	// ResolveCase closes an open case as confirmed or as a false positive. The
+	// note is required.
+	ResolveCase(ctx context.Context, caseID, status, note string) (SanctionsCaseDTO, error)
+
+	// GetWhitelist returns the whitelist entries of a wallet, or of every wallet
+	// when walletID is empty.
+	GetWhitelist(ctx context.Context, walletID string) ([]WhitelistEntryDTO, error)
+
+	// AddToWhitelist clears a wallet of a watchlist entry without a case. The
+	// reason is required.
+	AddToWhitelist(ctx context.Context, walletID, entry, reason string) (WhitelistEntryDTO, error)
+
+	// RemoveFromWhitelist screens a wallet for a whitelisted entry again. The
+	// reason is required.
+	RemoveFromWhitelist(ctx context.Context, walletID, entry, reason string) error
+}
+
+// Case page sizes accepted by GetCases.
+const (
+	DefaultCaseLimit = 100
+	MaxCaseLimit     = 1000
+)
+
+// CaseQuery selects sanctions cases for GetCases. Zero fields match everything.
+type CaseQuery struct {
+	WalletID string
+	Status   string // open, confirmed or false_positive
+	Limit    int    // DefaultCaseLimit when zero, at most MaxCaseLimit
+}
+
+type sanctionsUsecase struct {
+	sanctionsService service.SanctionsService
+}
+
+func NewSanctionsUsecase(sanctionsService service.SanctionsService) SanctionsUsecase {
+	return &sanctionsUsecase{sanctionsService: sanctionsService}
+}
+
+func (u *sanctionsUsecase) GetCases(ctx context.Context, query CaseQuery) (_ []SanctionsCaseDTO, err error) {
+	ctx, span := tracer.Start(ctx, "SanctionsUsecase.GetCases")
+	defer func() { endSpan(span, err) }()
+
+	filter, err := query.toFilter()
+	if err != nil {
+		return nil, err
+	}
+	cases, err := u.sanctionsService.Cases(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sanctions cases: %w", err)
+	}
+	return lo.Map(cases, func(c model.SanctionsCase, _ int) SanctionsCaseDTO {
+		return newSanctionsCaseDTO(c)
+	}), nil
+}
+
+func (u *sanctionsUsecase) ResolveCase(ctx context.Context, caseID, status, note string) (_ SanctionsCaseDTO, err error) {
+	ctx, span := tracer.Start(ctx, "SanctionsUsecase.ResolveCase")
+	defer func() { endSpan(span, err) }()
+
+	id, err := uuid.Parse(caseID)
+	if err != nil {
+		return SanctionsCaseDTO{}, fmt.Errorf("%w: %v", model.ErrCaseNotFound, err)
+	}
+	c, err := u.sanctionsService.ResolveCase(ctx, id, model.CaseStatus(status), note)
+	if err != nil {
+		return SanctionsCaseDTO{}, fmt.Errorf("failed to resolve sanctions case: %w", err)
+	}
+	return newSanctionsCaseDTO(c), nil
+}
+
+func (u *sanctionsUsecase) GetWhitelist(ctx context.Context, walletID string) (_ []WhitelistEntryDTO, err error) {
+	ctx, span := tracer.Start(ctx, "SanctionsUsecase.GetWhitelist")
+	defer func() { endSpan(span, err) }()
+
+	if walletID != "" {
+		walletUUID, err := uuid.Parse(walletID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+		}
+		walletID = walletUUID.String()
+	}
+	entries, err := u.sanctionsService.Whitelist(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get whitelist: %w", err)
+	}
+	return lo.Map(entries, func(e model.WhitelistEntry, _ int) WhitelistEntryDTO {
+		return newWhitelistEntryDTO(e)
+	}), nil
+}
+
+func (u *sanctionsUsecase) AddToWhitelist(ctx context.Context, walletID, entry, reason string) (_ WhitelistEntryDTO, err error) {
+	ctx, span := tracer.Start(ctx, "SanctionsUsecase.AddToWhitelist")
+	defer func() { endSpan(span, err) }()
+
+	walletUUID, err := uuid.Parse(walletID)
+	if err != nil {
+		return WhitelistEntryDTO{}, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+	}
+	added, err := u.sanctionsService.AddToWhitelist(ctx, walletUUID, entry, reason)
+	if err != nil {
+		return WhitelistEntryDTO{}, fmt.Errorf("failed to whitelist: %w", err)
+	}
+	return newWhitelistEntryDTO(added), nil
+}
+
+func (u *sanctionsUsecase) RemoveFromWhitelist(ctx context.Context, walletID, entry, reason string) (err error) {
+	ctx, span := tracer.Start(ctx, "SanctionsUsecase.RemoveFromWhitelist")
+	defer func() { endSpan(span, err) }()
+
+	walletUUID, err := uuid.Parse(walletID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+	}
+	if err := u.sanctionsService.RemoveFromWhitelist(ctx, walletUUID, entry, reason); err != nil {
+		return fmt.Errorf("failed to remove from whitelist: %w", err)
+	}
+	return nil
+}
+
+// toFilter validates the query and converts it to a case filter.
+func (q CaseQuery) toFilter() (model.CaseFilter, error) {
+	filter := model.CaseFilter{Status: model.CaseStatus(q.Status), Limit: q.Limit}
+	if q.WalletID != "" {
+		walletUUID, err := uuid.Parse(q.WalletID)
+		if err != nil {
+			return model.CaseFilter{}, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+		}
+		filter.WalletID = walletUUID.String()
+	}
+
+	switch {
+	case q.Status != "" && !filter.Status.Valid():
+		return model.CaseFilter{}, fmt.Errorf("%w: unknown status %q", model.ErrInvalidFilter, q.Status)
+	case q.Limit < 0 || q.Limit > MaxCaseLimit:
+		return model.CaseFilter{}, fmt.Errorf("%w: limit must be between 1 and %d", model.ErrInvalidFilter, MaxCaseLimit)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultCaseLimit
+	}
+	return filter, nil
+}
+
+// SanctionsCaseDTO represents a transfer blocked by sanctions screening and the
+// entries it matched.
+type SanctionsCaseDTO struct {
+	ID         string               `json:"id" format:"uuid"`
+	CreatedAt  string               `json:"created_at" format:"date-time"`
+	From       string               `json:"from" format:"uuid"`
+	To         string               `json:"to" format:"uuid"`
+	Amount     float64              `json:"amount"`
+	Hits       []model.ScreeningHit `json:"hits"`
+	Status     string               `json:"status" enums:"open,confirmed,false_positive"`
+	Note       string               `json:"note,omitempty" doc:"Why the case was resolved as it was."`
+	ResolvedAt string               `json:"resolved_at,omitempty" format:"date-time"`
+	RequestID  string               `json:"request_id,omitempty" doc:"ID of the request that asked for the transfer."`
+}
+
+// WhitelistEntryDTO represents a watchlist entry cleared for a wallet.
+type WhitelistEntryDTO struct {
+	WalletID  string `json:"wallet_id" format:"uuid"`
+	Entry     string `json:"entry" doc:"The watchlist entry no longer screened for this wallet."`
+	CaseID    string `json:"case_id,omitempty" format:"uuid" doc:"Case resolved as a false positive, absent for entries added by hand."`
+	Reason    string `json:"reason,omitempty" doc:"Why the entry was added by hand."`
+	Actor     string `json:"actor,omitempty" doc:"Subject of the caller that added the entry by hand, walletctl for entries added with the CLI."`
+	CreatedAt string `json:"created_at" format:"date-time"`
+}
+
+func newWhitelistEntryDTO(e model.WhitelistEntry) WhitelistEntryDTO {
+	dto := WhitelistEntryDTO{
+		WalletID:  e.WalletID,
+		Entry:     e.Entry,
+		Reason:    e.Reason,
+		Actor:     e.Actor,
+		CreatedAt: e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if e.CaseID != uuid.Nil {
+		dto.CaseID = e.CaseID.String()
+	}
+	return dto
+}
+
+func newSanctionsCaseDTO(c model.SanctionsCase) SanctionsCaseDTO {
+	dto := SanctionsCaseDTO{
+		ID:        c.ID.String(),
+		CreatedAt: c.CreatedAt.UTC().Format(time.RFC3339Nano),
+		From:      c.From,
+		To:        c.To,
+		Amount:    float64(c.Amount) / 100,
+		Hits:      c.Hits,
+		Status:    string(c.Status),
+		Note:      c.Note,
+		RequestID: c.RequestID,
+	}
+	if !c.ResolvedAt.IsZero() {
+		dto.ResolvedAt = c.ResolvedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return dto
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"transaction-service/internal/domain/model"
+	"transaction-service/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// StatusUsecase defines application-level logic for the status of wallets.
+type StatusUsecase interface {
+	// SetWalletStatus changes the status of a wallet; reason is mandatory.
+	SetWalletStatus(ctx context.Context, walletID, status, reason string) (StatusChangeDTO, error)
+
+	// GetStatusHistory returns the status changes of a wallet, oldest first.
+	GetStatusHistory(ctx context.Context, walletID string) ([]StatusChangeDTO, error)
+}
+
+type statusUsecase struct {
+	statusService service.StatusService
+}
+
+func NewStatusUsecase(statusService service.StatusService) StatusUsecase {
+	return &statusUsecase{statusService: statusService}
+}
+
+func (u *statusUsecase) SetWalletStatus(ctx context.Context, walletID, status, reason string) (_ StatusChangeDTO, err error) {
+	ctx, span := tracer.Start(ctx, "StatusUsecase.SetWalletStatus")
+	defer func() { endSpan(span, err) }()
+
+	walletUUID, err := uuid.Parse(walletID)
+	if err != nil {
+		return StatusChangeDTO{}, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+	}
+	walletStatus, err := model.ParseWalletStatus(status)
+	if err != nil {
+		return StatusChangeDTO{}, err
+	}
+
+	change, err := u.statusService.SetStatus(ctx, walletUUID, walletStatus, reason)
+	if err != nil {
+		return StatusChangeDTO{}, fmt.Errorf("failed to set wallet status: %w", err)
+	}
+	return newStatusChangeDTO(change), nil
+}
+
+func (u *statusUsecase) GetStatusHistory(ctx context.Context, walletID string) (_ []StatusChangeDTO, err error) {
+	ctx, span := tracer.Start(ctx, "StatusUsecase.GetStatusHistory")
+	defer func() { endSpan(span, err) }()
+
+	walletUUID, err := uuid.Parse(walletID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+	}
+
+	changes, err := u.statusService.History(ctx, walletUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status history: %w", err)
+	}
+	return lo.Map(changes, func(change model.StatusChange, _ int) StatusChangeDTO {
+		return newStatusChangeDTO(change)
+	}), nil
+}
+
+// StatusChangeDTO represents a change of the status of a wallet.
+type StatusChangeDTO struct {
+	ID        string `json:"id" format:"uuid"`
+	WalletID  string `json:"wallet_id" format:"uuid"`
+	From      string `json:"from" enums:"active,frozen,debit_blocked,closed"`
+	To        string `json:"to" enums:"active,frozen,debit_blocked,closed"`
+	Reason    string `json:"reason"`
+	Actor     string `json:"actor,omitempty" doc:"Subject of the caller that made the change, walletctl for changes made with the CLI."`
+	RequestID string `json:"request_id,omitempty" doc:"X-Request-ID of the request that made the change."`
+	CreatedAt string `json:"created_at" format:"date-time"`
+}
+
+func newStatusChangeDTO(change model.StatusChange) StatusChangeDTO {
+	return StatusChangeDTO{
+		ID:        change.ID.String(),
+		WalletID:  change.WalletID.String(),
+		From:      string(change.From),
+		To:        string(change.To),
+		Reason:    change.Reason,
+		Actor:     change.Actor,
+		RequestID: change.RequestID,
+		CreatedAt: change.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
@@ -4,38 +4,186 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"transaction-service/internal/domain/model"
 	"transaction-service/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
 )
 
 // TransactionUsecase defines application-level logic for transactions.
 type TransactionUsecase interface {
 	// GetLastTransactions retrieves the last N transactions as DTOs.
 	GetLastTransactions(ctx context.Context, count int) ([]TransactionDTO, error)
+
+	// StreamTransactions streams newly committed transactions as DTOs, optionally
+	// limited to one wallet and resumed after the given event ID.
+	StreamTransactions(ctx context.Context, walletID string, lastEventID string) (<-chan TransactionDTO, error)
+
+	// GetHistory retrieves the transactions matching the query as DTOs, newest first.
+	GetHistory(ctx context.Context, query HistoryQuery) ([]TransactionDTO, error)
+}
+
+// History page sizes accepted by GetHistory.
+const (
+	DefaultHistoryLimit = 100
+	MaxHistoryLimit     = 1000
+)
+
+// HistoryQuery selects transactions for GetHistory. Zero fields match everything;
+// amounts are in units, as in SendMoney.
+type HistoryQuery struct {
+	WalletID  string
+	Since     time.Time
+	Until     time.Time
+	MinAmount float64
+	MaxAmount float64
+	BeforeSeq int64 // Sequence number of the last transaction of the previous page
+	Limit     int   // DefaultHistoryLimit when zero, at most MaxHistoryLimit
+
+	IncludeArchived bool // Also search the months moved to the archive
 }
 
 type transactionUsecase struct {
 	transactionService service.TransactionService
 }
 
-func (u *transactionUsecase) GetLastTransactions(ctx context.Context, count int) ([]TransactionDTO, error) {
-	transactions, err := u.transactionService.GetNTransactions(ctx, count)
+func (u *transactionUsecase) GetLastTransactions(ctx context.Context, count int) (_ []TransactionDTO, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionUsecase.GetLastTransactions")
+	defer func() { endSpan(span, err) }()
+
+	p, err := principalOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := u.transactionService.GetNTransactions(ctx, count, p.VisibleWalletIDs())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions: %w", err)
 	}
 
 	transactionDTOs := make([]TransactionDTO, len(transactions))
 	for i, t := range transactions {
-		transactionDTOs[i] = TransactionDTO{
-			ID:        t.ID.String(),
-			From:      t.From,
-			To:        t.To,
-			Amount:    float64(t.Amount) / 100,
-			CreatedAt: t.CreatedAt.Format("2006-01-02 15:04:05"),
-		}
+		transactionDTOs[i] = newTransactionDTO(t)
 	}
 	return transactionDTOs, nil
 }
 
+func (u *transactionUsecase) StreamTransactions(ctx context.Context, walletID string, lastEventID string) (<-chan TransactionDTO, error) {
+	principal, err := principalOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := model.TransactionFilter{}
+	if walletID != "" {
+		walletUUID, err := uuid.Parse(walletID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+		}
+		if !principal.CanAccessWallet(walletUUID) {
+			return nil, fmt.Errorf("%w: cannot read wallet %s", model.ErrForbidden, walletUUID)
+		}
+		filter.WalletID = walletUUID.String()
+	}
+
+	var cursor *int64
+	if lastEventID != "" {
+		seq, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || seq < 0 {
+			return nil, fmt.Errorf("%w: last event ID %q", model.ErrInvalidCursor, lastEventID)
+		}
+		cursor = &seq
+	}
+
+	transactions, err := u.transactionService.WatchTransactions(ctx, filter, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch transactions: %w", err)
+	}
+
+	out := make(chan TransactionDTO)
+	go func() {
+		defer close(out)
+		for t := range transactions {
+			if !principal.CanSeeTransaction(t) {
+				continue
+			}
+			select {
+			case out <- newTransactionDTO(t):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (u *transactionUsecase) GetHistory(ctx context.Context, query HistoryQuery) (_ []TransactionDTO, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionUsecase.GetHistory")
+	defer func() { endSpan(span, err) }()
+
+	filter, err := query.toFilter()
+	if err != nil {
+		return nil, err
+	}
+	principal, err := principalOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if filter.WalletID != "" && !principal.CanAccessWallet(uuid.MustParse(filter.WalletID)) {
+		return nil, fmt.Errorf("%w: cannot read wallet %s", model.ErrForbidden, filter.WalletID)
+	}
+	filter.WalletIDs = principal.VisibleWalletIDs()
+
+	transactions, err := u.transactionService.History(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+	return lo.Map(transactions, func(t model.Transaction, _ int) TransactionDTO {
+		return newTransactionDTO(t)
+	}), nil
+}
+
+// toFilter validates the query and converts it to a history filter in cents.
+func (q HistoryQuery) toFilter() (model.HistoryFilter, error) {
+	filter := model.HistoryFilter{
+		Since:     q.Since,
+		Until:     q.Until,
+		MinAmount: int(math.Round(q.MinAmount * 100)),
+		MaxAmount: int(math.Round(q.MaxAmount * 100)),
+		BeforeSeq: q.BeforeSeq,
+		Limit:     q.Limit,
+
+		IncludeArchived: q.IncludeArchived,
+	}
+	if q.WalletID != "" {
+		walletUUID, err := uuid.Parse(q.WalletID)
+		if err != nil {
+			return model.HistoryFilter{}, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+		}
+		filter.WalletID = walletUUID.String()
+	}
+
+	switch {
+	case q.MinAmount < 0 || q.MaxAmount < 0:
+		return model.HistoryFilter{}, fmt.Errorf("%w: amounts cannot be negative", model.ErrInvalidFilter)
+	case filter.MaxAmount > 0 && filter.MinAmount > filter.MaxAmount:
+		return model.HistoryFilter{}, fmt.Errorf("%w: minimum amount is above the maximum", model.ErrInvalidFilter)
+	case !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until):
+		return model.HistoryFilter{}, fmt.Errorf("%w: since must be before until", model.ErrInvalidFilter)
+	case q.BeforeSeq < 0:
+		return model.HistoryFilter{}, fmt.Errorf("%w: sequence number %d", model.ErrInvalidCursor, q.BeforeSeq)
+	case q.Limit < 0 || q.Limit > MaxHistoryLimit:
+		return model.HistoryFilter{}, fmt.Errorf("%w: limit must be between 1 and %d", model.ErrInvalidFilter, MaxHistoryLimit)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultHistoryLimit
+	}
+	return filter, nil
+}
+
 func NewTransactionUsecase(transactionService service.TransactionService) TransactionUsecase {
 	return &transactionUsecase{
 		transactionService: transactionService,
@@ -44,9 +192,29 @@ func NewTransactionUsecase(transactionService service.TransactionService) Transa
 
 // TransactionDTO represents a data transfer object for transactions.
 type TransactionDTO struct {
-	ID        string  `json:"id"`
-	From      string  `json:"from"`
-	To        string  `json:"to"`
-	Amount    float64 `json:"amount"`
-	CreatedAt string  `json:"created_at"`
+	ID        string  `json:"id" format:"uuid"`
+	From      string  `json:"from" format:"uuid"`
+	To        string  `json:"to" format:"uuid"`
+	Amount    float64 `json:"amount" doc:"Amount in currency units."`
+	CreatedAt string  `json:"created_at" example:"2025-01-23 10:19:04"`
+	Seq       int64   `json:"seq" doc:"Position in the transaction log, usable as a stream cursor."`
+	RequestID string  `json:"request_id,omitempty" doc:"X-Request-ID of the request that created the transaction, if any."`
+	Reason    string  `json:"reason,omitempty" doc:"Reason given for a mint or burn, absent on ordinary transfers."`
+	PrevHash  string  `json:"prev_hash,omitempty" doc:"Hash of the previous transaction in the hash chain, absent on the first one."`
+	Hash      string  `json:"hash,omitempty" doc:"Hex SHA-256 linking the transaction into the hash chain, computed over prev_hash and the other fields. Absent on transactions created before the chain began."`
+}
+
+func newTransactionDTO(t model.Transaction) TransactionDTO {
+	return TransactionDTO{
+		ID:        t.ID.String(),
+		From:      t.From,
+		To:        t.To,
+		Amount:    float64(t.Amount) / 100,
+		CreatedAt: t.CreatedAt.Format("2006-01-02 15:04:05"),
+		Seq:       t.Seq,
+		RequestID: t.RequestID,
+		Reason:    t.Reason,
+		PrevHash:  t.PrevHash,
+		Hash:      t.Hash,
+	}
 }
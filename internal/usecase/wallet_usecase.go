@@ -3,60 +3,129 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"transaction-service/internal/domain/model"
 	"transaction-service/internal/domain/service"
 
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 )
 
 // WalletUsecase defines application-level logic for wallets.
 type WalletUsecase interface {
-	// SendMoney transfers funds between wallets.
-	SendMoney(ctx context.Context, fromID, toID string, amount float64) error
+	// SendMoney transfers funds between wallets and returns the transaction with
+	// its signed receipt.
+	SendMoney(ctx context.Context, fromID, toID string, amount float64) (*TransferDTO, error)
 
 	// GetBalance retrieves the balance of a wallet by its string ID.
 	GetBalance(ctx context.Context, walletID string) (float64, error)
 
 	GetAllWallets(ctx context.Context) ([]*model.Wallet, error)
+
+	// SetWalletOwner assigns a wallet to the end user with the given subject and
+	// legal name. The name is what sanctions screening matches.
+	SetWalletOwner(ctx context.Context, walletID, ownerID, ownerName string) error
+
+	// GetWalletOwner retrieves the owner of a wallet, including the legal name
+	// left out of wallet listings.
+	GetWalletOwner(ctx context.Context, walletID string) (*WalletOwnerDTO, error)
+
+	// CreateWallet opens a new wallet.
+	CreateWallet(ctx context.Context) (*model.Wallet, error)
+
+	// GetWallet retrieves a wallet by its string ID.
+	GetWallet(ctx context.Context, walletID string) (*model.Wallet, error)
+
+	// Reconcile checks every wallet balance against the transaction log.
+	Reconcile(ctx context.Context) (*ReconciliationDTO, error)
+
+	// Mint issues amount into a wallet, recording the reason on the transaction.
+	Mint(ctx context.Context, toID string, amount float64, reason string) error
+
+	// Burn destroys amount held by a wallet, recording the reason on the transaction.
+	Burn(ctx context.Context, fromID string, amount float64, reason string) error
+
+	// GetSupply reports the money issued, burned and in circulation.
+	GetSupply(ctx context.Context) (*SupplyDTO, error)
 }
 
 type walletUsecase struct {
-	walletService service.WalletService
+	walletService  service.WalletService
+	receiptService service.ReceiptService
 }
 
-func NewWalletUsecase(walletService service.WalletService) WalletUsecase {
+func NewWalletUsecase(walletService service.WalletService, receiptService service.ReceiptService) WalletUsecase {
 	return &walletUsecase{
-		walletService: walletService,
+		walletService:  walletService,
+		receiptService: receiptService,
 	}
 }
 
-func (u *walletUsecase) SendMoney(ctx context.Context, fromID, toID string, amount float64) error {
+func (u *walletUsecase) SendMoney(ctx context.Context, fromID, toID string, amount float64) (_ *TransferDTO, err error) {
+	ctx, span := tracer.Start(ctx, "WalletUsecase.SendMoney")
+	defer func() { endSpan(span, err) }()
+
+	slog.DebugContext(ctx, "transfer requested", "from_wallet", fromID, "to_wallet", toID, "amount", amount)
+
 	if amount <= 0 {
-		return fmt.Errorf("amount must be greater than zero")
+		return nil, fmt.Errorf("%w: amount must be greater than zero", model.ErrInvalidAmount)
 	}
 
 	fromUUID, err := uuid.Parse(fromID)
 	if err != nil {
-		return fmt.Errorf("invalid 'from' wallet ID: %w", err)
+		return nil, fmt.Errorf("%w 'from': %v", model.ErrInvalidWalletID, err)
 	}
 
 	toUUID, err := uuid.Parse(toID)
 	if err != nil {
-		return fmt.Errorf("invalid 'to' wallet ID: %w", err)
+		return nil, fmt.Errorf("%w 'to': %v", model.ErrInvalidWalletID, err)
 	}
 
-	amountInCents := int(amount * 100)
-	if err := u.walletService.SendMoney(ctx, fromUUID, toUUID, amountInCents); err != nil {
-		return fmt.Errorf("failed to send money: %w", err)
+	p, err := principalOf(ctx)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	if !p.CanAccessWallet(fromUUID) {
+		return nil, fmt.Errorf("%w: cannot debit wallet %s", model.ErrForbidden, fromUUID)
+	}
+
+	// Rounding rather than truncating keeps amounts like 0.29 from losing a cent.
+	amountInCents := int(math.Round(amount * 100))
+	transaction, err := u.walletService.SendMoney(ctx, fromUUID, toUUID, amountInCents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send money: %w", err)
+	}
+
+	// The transfer is committed, so failing to sign its receipt must not fail the
+	// request: the client would retry and send the money twice.
+	result := &TransferDTO{Transaction: newTransactionDTO(transaction)}
+	receipt, err := u.receiptService.Issue(ctx, transaction)
+	switch {
+	case err == nil:
+		result.Receipt = lo.ToPtr(newReceiptDTO(receipt))
+	case !errors.Is(err, model.ErrSigningDisabled):
+		slog.ErrorContext(ctx, "failed to sign transfer receipt", "transaction_id", transaction.ID, "error", err)
+	}
+	return result, nil
 }
 
-func (u *walletUsecase) GetBalance(ctx context.Context, walletID string) (float64, error) {
+func (u *walletUsecase) GetBalance(ctx context.Context, walletID string) (_ float64, err error) {
+	ctx, span := tracer.Start(ctx, "WalletUsecase.GetBalance")
+	defer func() { endSpan(span, err) }()
+
 	walletUUID, err := uuid.Parse(walletID)
 	if err != nil {
-		return 0, fmt.Errorf("invalid wallet ID: %w", err)
+		return 0, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+	}
+	p, err := principalOf(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !p.CanAccessWallet(walletUUID) {
+		return 0, fmt.Errorf("%w: cannot read wallet %s", model.ErrForbidden, walletUUID)
 	}
 
 	amount, err := u.walletService.GetBalance(ctx, walletUUID)
@@ -67,10 +136,192 @@ func (u *walletUsecase) GetBalance(ctx context.Context, walletID string) (float6
 	return float64(amount) / 100, nil
 }
 
-func (u *walletUsecase) GetAllWallets(ctx context.Context) ([]*model.Wallet, error) {
+func (u *walletUsecase) GetAllWallets(ctx context.Context) (_ []*model.Wallet, err error) {
+	ctx, span := tracer.Start(ctx, "WalletUsecase.GetAllWallets")
+	defer func() { endSpan(span, err) }()
+
+	p, err := principalOf(ctx)
+	if err != nil {
+		return nil, err
+	}
 	wallets, err := u.walletService.FetchAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch wallets: %w", err)
 	}
-	return wallets, nil
+	return lo.Filter(wallets, func(w *model.Wallet, _ int) bool {
+		return p.CanAccessWallet(w.ID)
+	}), nil
+}
+
+func (u *walletUsecase) SetWalletOwner(ctx context.Context, walletID, ownerID, ownerName string) (err error) {
+	ctx, span := tracer.Start(ctx, "WalletUsecase.SetWalletOwner")
+	defer func() { endSpan(span, err) }()
+
+	walletUUID, err := uuid.Parse(walletID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+	}
+
+	if err := u.walletService.SetOwner(ctx, walletUUID, ownerID, ownerName); err != nil {
+		return fmt.Errorf("failed to set wallet owner: %w", err)
+	}
+	slog.InfoContext(ctx, "wallet owner changed", "wallet_id", walletUUID, "owner_id", ownerID)
+	return nil
+}
+
+func (u *walletUsecase) GetWalletOwner(ctx context.Context, walletID string) (_ *WalletOwnerDTO, err error) {
+	ctx, span := tracer.Start(ctx, "WalletUsecase.GetWalletOwner")
+	defer func() { endSpan(span, err) }()
+
+	walletUUID, err := uuid.Parse(walletID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+	}
+
+	wallet, err := u.walletService.FetchByID(ctx, walletUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	return &WalletOwnerDTO{WalletID: wallet.ID.String(), OwnerID: wallet.OwnerID, OwnerName: wallet.OwnerName}, nil
+}
+
+func (u *walletUsecase) CreateWallet(ctx context.Context) (_ *model.Wallet, err error) {
+	ctx, span := tracer.Start(ctx, "WalletUsecase.CreateWallet")
+	defer func() { endSpan(span, err) }()
+
+	wallet, err := u.walletService.CreateWallet(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wallet: %w", err)
+	}
+	return wallet, nil
+}
+
+func (u *walletUsecase) GetWallet(ctx context.Context, walletID string) (_ *model.Wallet, err error) {
+	ctx, span := tracer.Start(ctx, "WalletUsecase.GetWallet")
+	defer func() { endSpan(span, err) }()
+
+	walletUUID, err := uuid.Parse(walletID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidWalletID, err)
+	}
+	p, err := principalOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !p.CanAccessWallet(walletUUID) {
+		return nil, fmt.Errorf("%w: cannot read wallet %s", model.ErrForbidden, walletUUID)
+	}
+
+	wallet, err := u.walletService.FetchByID(ctx, walletUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	return wallet, nil
+}
+
+func (u *walletUsecase) Reconcile(ctx context.Context) (_ *ReconciliationDTO, err error) {
+	ctx, span := tracer.Start(ctx, "WalletUsecase.Reconcile")
+	defer func() { endSpan(span, err) }()
+
+	report, err := u.walletService.Reconcile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile wallets: %w", err)
+	}
+	return &ReconciliationDTO{
+		Wallets:      report.Wallets,
+		Transactions: report.Transactions,
+		TotalAmount:  float64(report.TotalAmount) / 100,
+		Mismatches: lo.Map(report.Mismatches, func(m model.BalanceMismatch, _ int) BalanceMismatchDTO {
+			return BalanceMismatchDTO{
+				WalletID: m.WalletID.String(),
+				Balance:  float64(m.Balance) / 100,
+				Expected: float64(m.Expected) / 100,
+			}
+		}),
+	}, nil
+}
+
+func (u *walletUsecase) Mint(ctx context.Context, toID string, amount float64, reason string) (err error) {
+	ctx, span := tracer.Start(ctx, "WalletUsecase.Mint")
+	defer func() { endSpan(span, err) }()
+
+	if amount <= 0 {
+		return fmt.Errorf("%w: amount must be greater than zero", model.ErrInvalidAmount)
+	}
+	toUUID, err := uuid.Parse(toID)
+	if err != nil {
+		return fmt.Errorf("%w 'to': %v", model.ErrInvalidWalletID, err)
+	}
+
+	if err := u.walletService.Mint(ctx, toUUID, int(math.Round(amount*100)), reason); err != nil {
+		return fmt.Errorf("failed to mint money: %w", err)
+	}
+	return nil
+}
+
+func (u *walletUsecase) Burn(ctx context.Context, fromID string, amount float64, reason string) (err error) {
+	ctx, span := tracer.Start(ctx, "WalletUsecase.Burn")
+	defer func() { endSpan(span, err) }()
+
+	if amount <= 0 {
+		return fmt.Errorf("%w: amount must be greater than zero", model.ErrInvalidAmount)
+	}
+	fromUUID, err := uuid.Parse(fromID)
+	if err != nil {
+		return fmt.Errorf("%w 'from': %v", model.ErrInvalidWalletID, err)
+	}
+
+	if err := u.walletService.Burn(ctx, fromUUID, int(math.Round(amount*100)), reason); err != nil {
+		return fmt.Errorf("failed to burn money: %w", err)
+	}
+	return nil
+}
+
+func (u *walletUsecase) GetSupply(ctx context.Context) (_ *SupplyDTO, err error) {
+	ctx, span := tracer.Start(ctx, "WalletUsecase.GetSupply")
+	defer func() { endSpan(span, err) }()
+
+	supply, err := u.walletService.Supply(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supply: %w", err)
+	}
+	return &SupplyDTO{
+		Issued:      float64(supply.Issued) / 100,
+		Burned:      float64(supply.Burned) / 100,
+		Circulating: float64(supply.Circulating()) / 100,
+		WalletTotal: float64(supply.WalletTotal) / 100,
+		Balanced:    supply.Circulating() == supply.WalletTotal,
+	}, nil
+}
+
+// ReconciliationDTO represents the result of a reconciliation.
+type ReconciliationDTO struct {
+	Wallets      int                  `json:"wallets"`
+	Transactions int                  `json:"transactions"`
+	TotalAmount  float64              `json:"total_amount"`
+	Mismatches   []BalanceMismatchDTO `json:"mismatches"`
+}
+
+// BalanceMismatchDTO represents a wallet whose balance disagrees with its transactions.
+type BalanceMismatchDTO struct {
+	WalletID string  `json:"wallet_id"`
+	Balance  float64 `json:"balance"`
+	Expected float64 `json:"expected"`
+}
+
+// WalletOwnerDTO represents the end user a wallet is assigned to.
+type WalletOwnerDTO struct {
+	WalletID  string `json:"wallet_id" format:"uuid"`
+	OwnerID   string `json:"owner_id" doc:"Token subject of the owner, empty if unassigned."`
+	OwnerName string `json:"owner_name" doc:"Legal name of the owner, screened against the sanctions lists."`
+}
+
+// SupplyDTO represents the money supply. Balanced reports whether the money in
+// circulation equals the sum of user wallet balances.
+type SupplyDTO struct {
+	Issued      float64 `json:"issued" doc:"Money ever minted, in currency units."`
+	Burned      float64 `json:"burned" doc:"Money ever burned, in currency units."`
+	Circulating float64 `json:"circulating" doc:"Issued minus burned."`
+	WalletTotal float64 `json:"wallet_total" doc:"Sum of user wallet balances."`
+	Balanced    bool    `json:"balanced" doc:"Whether circulating equals wallet_total."`
 }
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transactions ADD COLUMN seq BIGSERIAL;
+CREATE UNIQUE INDEX transactions_seq_idx ON transactions (seq);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_transaction_created() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('transactions_created', NEW.seq::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER transactions_notify_created
+    AFTER INSERT ON transactions
+    FOR EACH ROW EXECUTE FUNCTION notify_transaction_created();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS transactions_notify_created ON transactions;
+DROP FUNCTION IF EXISTS notify_transaction_created();
+DROP INDEX IF EXISTS transactions_seq_idx;
+ALTER TABLE transactions DROP COLUMN IF EXISTS seq;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE api_keys (
+                          id UUID PRIMARY KEY,
+                          name TEXT NOT NULL,
+                          secret_hash BYTEA NOT NULL,
+                          scopes TEXT[] NOT NULL,
+                          wallet_ids UUID[] NOT NULL DEFAULT '{}',
+                          created_at TIMESTAMP NOT NULL,
+                          rotated_at TIMESTAMP,
+                          revoked_at TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE wallets ADD COLUMN owner_id TEXT;
+CREATE INDEX wallets_owner_id_idx ON wallets (owner_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS wallets_owner_id_idx;
+ALTER TABLE wallets DROP COLUMN IF EXISTS owner_id;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE UNLOGGED TABLE rate_limit_buckets (
+                                             key TEXT PRIMARY KEY,
+                                             tokens DOUBLE PRECISION NOT NULL,
+                                             allowed BOOLEAN NOT NULL,
+                                             updated_at TIMESTAMPTZ NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS rate_limit_buckets;
+-- +goose StatementEnd
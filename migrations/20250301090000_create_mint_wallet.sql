@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE wallets ADD COLUMN system BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE transactions ADD COLUMN reason TEXT;
+-- +goose StatementEnd
+
+-- Every wallet so far was created holding 100 cents. Record that money as issued by
+-- the mint wallet, so the supply it reports covers it and the log still adds up to
+-- every balance. Closed wallets are only left in the log; their money is now held
+-- by other wallets, so it is issued too.
+-- +goose StatementBegin
+INSERT INTO wallets (id, amount, system) VALUES ('00000000-0000-0000-0000-000000000001', 0, true);
+
+INSERT INTO transactions (id, "from", "to", amount, created_at, reason)
+SELECT gen_random_uuid(), '00000000-0000-0000-0000-000000000001', id, 100, NOW(), 'opening balance'
+FROM (
+    SELECT id::text AS id FROM wallets WHERE NOT system
+    UNION SELECT "from" FROM transactions
+    UNION SELECT "to" FROM transactions
+) AS funded
+ORDER BY id;
+
+UPDATE wallets SET amount = -(SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE "from" = '00000000-0000-0000-0000-000000000001')
+WHERE id = '00000000-0000-0000-0000-000000000001';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM transactions WHERE "from" = '00000000-0000-0000-0000-000000000001' OR "to" = '00000000-0000-0000-0000-000000000001';
+DELETE FROM wallets WHERE system;
+ALTER TABLE transactions DROP COLUMN IF EXISTS reason;
+ALTER TABLE wallets DROP COLUMN IF EXISTS system;
+-- +goose StatementEnd
@@ -0,0 +1,96 @@
+-- +goose Up
+-- Rebuild transactions as a table partitioned by month of created_at. The primary
+-- key must include the partition key, and seq is no longer unique by constraint,
+-- though the sequence still hands out unique values. Months without a partition
+-- of their own land in transactions_default; the service creates partitions ahead
+-- of time, so it stays empty.
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS transactions_notify_created ON transactions;
+ALTER TABLE transactions RENAME TO transactions_unpartitioned;
+ALTER TABLE transactions_unpartitioned RENAME CONSTRAINT transactions_pkey TO transactions_unpartitioned_pkey;
+ALTER INDEX transactions_seq_idx RENAME TO transactions_unpartitioned_seq_idx;
+
+CREATE TABLE transactions (
+                              id UUID NOT NULL,
+                              "from" TEXT NOT NULL,
+                              "to" TEXT NOT NULL,
+                              amount INT NOT NULL,
+                              created_at TIMESTAMP NOT NULL,
+                              seq BIGINT NOT NULL DEFAULT nextval('transactions_seq_seq'),
+                              request_id TEXT,
+                              reason TEXT,
+                              PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+ALTER SEQUENCE transactions_seq_seq OWNED BY transactions.seq;
+CREATE INDEX transactions_seq_idx ON transactions (seq);
+CREATE INDEX transactions_created_at_idx ON transactions (created_at);
+CREATE TABLE transactions_default PARTITION OF transactions DEFAULT;
+-- +goose StatementEnd
+
+-- One partition per month from the oldest transaction to three months ahead.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    first_day TIMESTAMP;
+BEGIN
+    FOR first_day IN
+        SELECT generate_series(
+            date_trunc('month', LEAST((SELECT MIN(created_at) FROM transactions_unpartitioned), NOW()::timestamp)),
+            date_trunc('month', NOW()::timestamp) + INTERVAL '3 months',
+            INTERVAL '1 month')
+    LOOP
+        EXECUTE format('CREATE TABLE %I PARTITION OF transactions FOR VALUES FROM (%L) TO (%L)',
+                       'transactions_' || to_char(first_day, '"y"YYYY"m"MM'), first_day, first_day + INTERVAL '1 month');
+    END LOOP;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO transactions (id, "from", "to", amount, created_at, seq, request_id, reason)
+SELECT id, "from", "to", amount, created_at, seq, request_id, reason FROM transactions_unpartitioned;
+DROP TABLE transactions_unpartitioned;
+
+CREATE TRIGGER transactions_notify_created
+    AFTER INSERT ON transactions
+    FOR EACH ROW EXECUTE FUNCTION notify_transaction_created();
+-- +goose StatementEnd
+
+-- Transfers of archived months, summed by wallet, so balances and the supply still
+-- add up once their partitions are detached.
+-- +goose StatementBegin
+CREATE TABLE archived_transfer_totals (
+                                          wallet_id TEXT PRIMARY KEY,
+                                          sent BIGINT NOT NULL,
+                                          received BIGINT NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- Only the attached partitions are restored; archived months stay in the archive.
+-- +goose StatementBegin
+DROP TABLE IF EXISTS archived_transfer_totals;
+DROP TRIGGER IF EXISTS transactions_notify_created ON transactions;
+ALTER TABLE transactions RENAME TO transactions_partitioned;
+ALTER TABLE transactions_partitioned RENAME CONSTRAINT transactions_pkey TO transactions_partitioned_pkey;
+
+CREATE TABLE transactions (
+                              id UUID PRIMARY KEY,
+                              "from" TEXT NOT NULL,
+                              "to" TEXT NOT NULL,
+                              amount INT NOT NULL,
+                              created_at TIMESTAMP NOT NULL,
+                              seq BIGINT NOT NULL DEFAULT nextval('transactions_seq_seq'),
+                              request_id TEXT,
+                              reason TEXT
+);
+ALTER SEQUENCE transactions_seq_seq OWNED BY transactions.seq;
+INSERT INTO transactions SELECT id, "from", "to", amount, created_at, seq, request_id, reason FROM transactions_partitioned;
+DROP TABLE transactions_partitioned;
+DROP INDEX IF EXISTS transactions_seq_idx;
+CREATE UNIQUE INDEX transactions_seq_idx ON transactions (seq);
+
+CREATE TRIGGER transactions_notify_created
+    AFTER INSERT ON transactions
+    FOR EACH ROW EXECUTE FUNCTION notify_transaction_created();
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Transactions created before this migration are left unchained.
+ALTER TABLE transactions ADD COLUMN prev_hash TEXT;
+ALTER TABLE transactions ADD COLUMN hash TEXT;
+
+-- transaction_chain_head holds the one last link of the chain. Creating a
+-- transaction locks it, which orders the chain like seq.
+CREATE TABLE transaction_chain_head (
+                                        id INT PRIMARY KEY CHECK (id = 1),
+                                        seq BIGINT NOT NULL,
+                                        hash TEXT NOT NULL,
+                                        count BIGINT NOT NULL
+);
+INSERT INTO transaction_chain_head (id, seq, hash, count) VALUES (1, 0, '', 0);
+
+CREATE TABLE chain_checkpoints (
+                                   seq BIGINT PRIMARY KEY,
+                                   count BIGINT NOT NULL,
+                                   hash TEXT NOT NULL,
+                                   created_at TIMESTAMP NOT NULL,
+                                   key_id TEXT NOT NULL,
+                                   signature BYTEA NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS chain_checkpoints;
+DROP TABLE IF EXISTS transaction_chain_head;
+ALTER TABLE transactions DROP COLUMN IF EXISTS hash;
+ALTER TABLE transactions DROP COLUMN IF EXISTS prev_hash;
+-- +goose StatementEnd
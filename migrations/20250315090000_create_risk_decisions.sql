@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- risk_decisions logs the evaluation of every transfer against the risk rules,
+-- with the rules that fired as a JSON array.
+CREATE TABLE risk_decisions (
+                                id UUID PRIMARY KEY,
+                                created_at TIMESTAMP NOT NULL,
+                                "from" TEXT NOT NULL,
+                                "to" TEXT NOT NULL,
+                                amount BIGINT NOT NULL,
+                                action TEXT NOT NULL,
+                                fired JSONB NOT NULL,
+                                request_id TEXT
+);
+CREATE INDEX risk_decisions_created_at_idx ON risk_decisions (created_at);
+CREATE INDEX risk_decisions_from_idx ON risk_decisions ("from", created_at);
+CREATE INDEX risk_decisions_to_idx ON risk_decisions ("to", created_at);
+
+-- Velocity rules sum the recent transfers sent from a wallet.
+CREATE INDEX transactions_from_created_at_idx ON transactions ("from", created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS transactions_from_created_at_idx;
+DROP TABLE IF EXISTS risk_decisions;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- sanctions_cases holds the transfers blocked by sanctions screening, with the
+-- watchlist hits as a JSON array, until a reviewer resolves them.
+CREATE TABLE sanctions_cases (
+                                 id UUID PRIMARY KEY,
+                                 created_at TIMESTAMP NOT NULL,
+                                 "from" TEXT NOT NULL,
+                                 "to" TEXT NOT NULL,
+                                 amount BIGINT NOT NULL,
+                                 hits JSONB NOT NULL,
+                                 status TEXT NOT NULL,
+                                 note TEXT NOT NULL DEFAULT '',
+                                 resolved_at TIMESTAMP,
+                                 request_id TEXT
+);
+CREATE INDEX sanctions_cases_created_at_idx ON sanctions_cases (created_at);
+CREATE INDEX sanctions_cases_status_idx ON sanctions_cases (status, created_at);
+
+-- sanctions_whitelist clears wallets of watchlist entries they were wrongly
+-- matched with.
+CREATE TABLE sanctions_whitelist (
+                                     wallet_id TEXT NOT NULL,
+                                     entry TEXT NOT NULL,
+                                     case_id UUID NOT NULL REFERENCES sanctions_cases (id),
+                                     created_at TIMESTAMP NOT NULL,
+                                     PRIMARY KEY (wallet_id, entry)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sanctions_whitelist;
+DROP TABLE IF EXISTS sanctions_cases;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- limit_tiers holds the spending limits shared by the wallets of a tier. A NULL
+-- limit caps nothing; amounts are in cents.
+CREATE TABLE limit_tiers (
+                             name TEXT PRIMARY KEY,
+                             max_transfer BIGINT,
+                             daily_total BIGINT,
+                             monthly_total BIGINT,
+                             hourly_count INT,
+                             updated_at TIMESTAMP NOT NULL
+);
+
+-- wallet_limits assigns wallets to tiers, the default tier when tier is NULL, and
+-- overrides limits of the tier; a NULL limit takes the one of the tier.
+CREATE TABLE wallet_limits (
+                               wallet_id UUID PRIMARY KEY REFERENCES wallets (id) ON DELETE CASCADE,
+                               tier TEXT REFERENCES limit_tiers (name),
+                               max_transfer BIGINT,
+                               daily_total BIGINT,
+                               monthly_total BIGINT,
+                               hourly_count INT,
+                               updated_at TIMESTAMP NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS wallet_limits;
+DROP TABLE IF EXISTS limit_tiers;
+-- +goose StatementEnd
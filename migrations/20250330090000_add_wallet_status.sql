@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE wallets ADD COLUMN status TEXT NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'frozen', 'debit_blocked', 'closed'));
+
+-- wallet_status_changes records every change of the status of a wallet, with the
+-- subject of the principal that made it when there was one.
+CREATE TABLE wallet_status_changes (
+                                       id UUID PRIMARY KEY,
+                                       wallet_id UUID NOT NULL REFERENCES wallets (id) ON DELETE CASCADE,
+                                       from_status TEXT NOT NULL,
+                                       to_status TEXT NOT NULL,
+                                       reason TEXT NOT NULL,
+                                       actor TEXT,
+                                       request_id TEXT,
+                                       created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX wallet_status_changes_wallet_id_idx ON wallet_status_changes (wallet_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS wallet_status_changes;
+ALTER TABLE wallets DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- owner_name is the legal name of the owner of a wallet. Sanctions screening
+-- matches it against the name entries of the watchlists.
+ALTER TABLE wallets ADD COLUMN owner_name TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wallets DROP COLUMN IF EXISTS owner_name;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Administrators whitelist entries by hand as well as by resolving a case as a
+-- false positive. Such entries have no case and record why they were added.
+ALTER TABLE sanctions_whitelist ALTER COLUMN case_id DROP NOT NULL;
+ALTER TABLE sanctions_whitelist ADD COLUMN reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE sanctions_whitelist ADD COLUMN actor TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM sanctions_whitelist WHERE case_id IS NULL;
+ALTER TABLE sanctions_whitelist DROP COLUMN IF EXISTS actor;
+ALTER TABLE sanctions_whitelist DROP COLUMN IF EXISTS reason;
+ALTER TABLE sanctions_whitelist ALTER COLUMN case_id SET NOT NULL;
+-- +goose StatementEnd
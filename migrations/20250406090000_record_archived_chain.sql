@@ -0,0 +1,37 @@
+-- +goose Up
+-- The last link and the number of chained transactions of every archived month,
+-- so the chain is verified from where the archive leaves off. Months archived
+-- before are recorded from their detached partitions; a partition dropped already
+-- leaves its month unrecorded, which verification then reports.
+-- +goose StatementBegin
+CREATE TABLE archived_chain (
+                                month DATE PRIMARY KEY,
+                                seq BIGINT NOT NULL,
+                                hash TEXT NOT NULL,
+                                count BIGINT NOT NULL
+);
+
+DO $$
+DECLARE
+    part RECORD;
+BEGIN
+    FOR part IN
+        SELECT c.relname FROM pg_class c
+        WHERE c.relkind = 'r' AND c.relname ~ '^transactions_y[0-9]{4}m[0-9]{2}$'
+          AND NOT EXISTS (SELECT 1 FROM pg_inherits i WHERE i.inhrelid = c.oid)
+    LOOP
+        EXECUTE format($q$
+            INSERT INTO archived_chain (month, seq, hash, count)
+            SELECT to_date(%L, 'YYYY"m"MM'),
+                   COALESCE((SELECT seq FROM %I WHERE hash IS NOT NULL ORDER BY seq DESC LIMIT 1), 0),
+                   COALESCE((SELECT hash FROM %I WHERE hash IS NOT NULL ORDER BY seq DESC LIMIT 1), ''),
+                   (SELECT COUNT(*) FROM %I WHERE hash IS NOT NULL)
+        $q$, substring(part.relname FROM 15), part.relname, part.relname, part.relname);
+    END LOOP;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS archived_chain;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Every sender and receiver pair of archived months, so a transfer to a known
+-- counterparty is still recognised once its partition is detached. Months archived
+-- before are recorded from their detached partitions.
+-- +goose StatementBegin
+CREATE TABLE archived_transfer_pairs (
+                                         from_id TEXT NOT NULL,
+                                         to_id TEXT NOT NULL,
+                                         PRIMARY KEY (from_id, to_id)
+);
+
+DO $$
+DECLARE
+    part RECORD;
+BEGIN
+    FOR part IN
+        SELECT c.relname FROM pg_class c
+        WHERE c.relkind = 'r' AND c.relname ~ '^transactions_y[0-9]{4}m[0-9]{2}$'
+          AND NOT EXISTS (SELECT 1 FROM pg_inherits i WHERE i.inhrelid = c.oid)
+    LOOP
+        EXECUTE format($q$
+            INSERT INTO archived_transfer_pairs (from_id, to_id)
+            SELECT DISTINCT "from", "to" FROM %I
+            ON CONFLICT DO NOTHING
+        $q$, part.relname);
+    END LOOP;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS archived_transfer_pairs;
+-- +goose StatementEnd
@@ -0,0 +1,67 @@
+// Package migrations holds the goose SQL migrations of the service.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FS contains every migration file, named <version>_<description>.sql.
+//
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// SQLite returns the migrations of the SQLite driver. Each one is the equivalent of
+// the Postgres migration with the same version, so both drivers report the same
+// schema version.
+func SQLite() fs.FS {
+	sub, err := fs.Sub(sqliteFS, "sqlite")
+	if err != nil {
+		// The directory is embedded above, so this cannot happen.
+		panic(err)
+	}
+	return sub
+}
+
+// LatestVersion returns the version of the newest migration, which is the schema
+// version this build of the service expects.
+func LatestVersion() (int64, error) {
+	versions, err := Versions(FS)
+	if err != nil {
+		return 0, err
+	}
+	if len(versions) == 0 {
+		return 0, nil
+	}
+	return versions[len(versions)-1], nil
+}
+
+// Versions returns the versions of the migrations in fsys, in ascending order.
+func Versions(fsys fs.FS) ([]int64, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	versions := make([]int64, 0, len(files))
+	for _, name := range files {
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s has no version prefix", name)
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version: %w", name, err)
+		}
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	return versions, nil
+}
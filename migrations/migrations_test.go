@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestSQLiteMatchesPostgres keeps the two migration sets in step: every schema
+// change needs an equivalent for each driver.
+func TestSQLiteMatchesPostgres(t *testing.T) {
+	postgres, err := Versions(FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlite, err := Versions(SQLite())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(postgres, sqlite) {
+		t.Errorf("migration versions differ:\npostgres %v\nsqlite   %v", postgres, sqlite)
+	}
+}
@@ -0,0 +1,8 @@
+-- +goose Up
+CREATE TABLE wallets (
+    id TEXT PRIMARY KEY,
+    amount INTEGER NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS wallets;
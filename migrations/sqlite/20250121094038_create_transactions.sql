@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE transactions (
+    id TEXT PRIMARY KEY,
+    "from" TEXT NOT NULL,
+    "to" TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS transactions;
@@ -0,0 +1,8 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS service_state (
+    key TEXT PRIMARY KEY,
+    value TEXT
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS service_state;
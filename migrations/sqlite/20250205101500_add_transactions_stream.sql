@@ -0,0 +1,10 @@
+-- +goose Up
+-- SQLite cannot add an autoincrement column, so the repository assigns seq on
+-- insert. Writes are serialized, which keeps it gapless.
+ALTER TABLE transactions ADD COLUMN seq INTEGER;
+UPDATE transactions SET seq = rowid;
+CREATE UNIQUE INDEX transactions_seq_idx ON transactions (seq);
+
+-- +goose Down
+DROP INDEX IF EXISTS transactions_seq_idx;
+ALTER TABLE transactions DROP COLUMN seq;
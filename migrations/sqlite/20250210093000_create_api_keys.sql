@@ -0,0 +1,15 @@
+-- +goose Up
+-- scopes and wallet_ids hold JSON arrays.
+CREATE TABLE api_keys (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    secret_hash BLOB NOT NULL,
+    scopes TEXT NOT NULL,
+    wallet_ids TEXT NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL,
+    rotated_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS api_keys;
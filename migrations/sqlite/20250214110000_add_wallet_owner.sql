@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE wallets ADD COLUMN owner_id TEXT;
+CREATE INDEX wallets_owner_id_idx ON wallets (owner_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS wallets_owner_id_idx;
+ALTER TABLE wallets DROP COLUMN owner_id;
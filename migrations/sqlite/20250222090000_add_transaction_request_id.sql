@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE transactions ADD COLUMN request_id TEXT;
+
+-- +goose Down
+ALTER TABLE transactions DROP COLUMN request_id;
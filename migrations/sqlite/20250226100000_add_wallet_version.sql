@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE wallets ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE wallets DROP COLUMN version;
@@ -0,0 +1,27 @@
+-- +goose Up
+ALTER TABLE wallets ADD COLUMN system INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN reason TEXT;
+
+-- Every wallet so far was created holding 100 cents, closed ones included. Record
+-- that money as issued by the mint wallet, as in the Postgres migration. SQLite has no UUID function, so
+-- IDs are formatted from random bytes as version 4 UUIDs.
+INSERT INTO wallets (id, amount, system) VALUES ('00000000-0000-0000-0000-000000000001', 0, 1);
+
+INSERT INTO transactions (id, "from", "to", amount, created_at, seq, reason)
+SELECT lower(substr(h, 1, 8) || '-' || substr(h, 9, 4) || '-4' || substr(h, 14, 3) || '-8' || substr(h, 18, 3) || '-' || substr(h, 21, 12)),
+       '00000000-0000-0000-0000-000000000001', id, 100, strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'),
+       (SELECT COALESCE(MAX(seq), 0) FROM transactions) + row_number() OVER (ORDER BY id),
+       'opening balance'
+FROM (
+    SELECT id, hex(randomblob(16)) AS h
+    FROM (SELECT id FROM wallets WHERE NOT system UNION SELECT "from" FROM transactions UNION SELECT "to" FROM transactions)
+);
+
+UPDATE wallets SET amount = -(SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE "from" = '00000000-0000-0000-0000-000000000001')
+WHERE id = '00000000-0000-0000-0000-000000000001';
+
+-- +goose Down
+DELETE FROM transactions WHERE "from" = '00000000-0000-0000-0000-000000000001' OR "to" = '00000000-0000-0000-0000-000000000001';
+DELETE FROM wallets WHERE system;
+ALTER TABLE transactions DROP COLUMN reason;
+ALTER TABLE wallets DROP COLUMN system;
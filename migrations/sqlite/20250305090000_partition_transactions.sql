@@ -0,0 +1,7 @@
+-- +goose Up
+-- SQLite has no table partitioning and its transactions are never archived; the
+-- index serves the newest-first listing the Postgres partitions are indexed for.
+CREATE INDEX transactions_created_at_idx ON transactions (created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS transactions_created_at_idx;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Transactions created before this migration are left unchained.
+ALTER TABLE transactions ADD COLUMN prev_hash TEXT;
+ALTER TABLE transactions ADD COLUMN hash TEXT;
+
+CREATE TABLE transaction_chain_head (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    seq INTEGER NOT NULL,
+    hash TEXT NOT NULL,
+    count INTEGER NOT NULL
+);
+INSERT INTO transaction_chain_head (id, seq, hash, count) VALUES (1, 0, '', 0);
+
+CREATE TABLE chain_checkpoints (
+    seq INTEGER PRIMARY KEY,
+    count INTEGER NOT NULL,
+    hash TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    key_id TEXT NOT NULL,
+    signature BLOB NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS chain_checkpoints;
+DROP TABLE IF EXISTS transaction_chain_head;
+ALTER TABLE transactions DROP COLUMN hash;
+ALTER TABLE transactions DROP COLUMN prev_hash;
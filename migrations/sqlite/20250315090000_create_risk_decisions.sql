@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE risk_decisions (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    "from" TEXT NOT NULL,
+    "to" TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    fired TEXT NOT NULL,
+    request_id TEXT
+);
+CREATE INDEX risk_decisions_created_at_idx ON risk_decisions (created_at);
+CREATE INDEX risk_decisions_from_idx ON risk_decisions ("from", created_at);
+CREATE INDEX risk_decisions_to_idx ON risk_decisions ("to", created_at);
+
+-- Velocity rules sum the recent transfers sent from a wallet.
+CREATE INDEX transactions_from_created_at_idx ON transactions ("from", created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS transactions_from_created_at_idx;
+DROP TABLE IF EXISTS risk_decisions;
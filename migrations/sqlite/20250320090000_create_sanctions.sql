@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE sanctions_cases (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    "from" TEXT NOT NULL,
+    "to" TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    hits TEXT NOT NULL,
+    status TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    resolved_at TIMESTAMP,
+    request_id TEXT
+);
+CREATE INDEX sanctions_cases_created_at_idx ON sanctions_cases (created_at);
+CREATE INDEX sanctions_cases_status_idx ON sanctions_cases (status, created_at);
+
+CREATE TABLE sanctions_whitelist (
+    wallet_id TEXT NOT NULL,
+    entry TEXT NOT NULL,
+    case_id TEXT NOT NULL REFERENCES sanctions_cases (id),
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (wallet_id, entry)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS sanctions_whitelist;
+DROP TABLE IF EXISTS sanctions_cases;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE limit_tiers (
+    name TEXT PRIMARY KEY,
+    max_transfer INTEGER,
+    daily_total INTEGER,
+    monthly_total INTEGER,
+    hourly_count INTEGER,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE wallet_limits (
+    wallet_id TEXT PRIMARY KEY REFERENCES wallets (id) ON DELETE CASCADE,
+    tier TEXT REFERENCES limit_tiers (name),
+    max_transfer INTEGER,
+    daily_total INTEGER,
+    monthly_total INTEGER,
+    hourly_count INTEGER,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS wallet_limits;
+DROP TABLE IF EXISTS limit_tiers;
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE wallets ADD COLUMN status TEXT NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'frozen', 'debit_blocked', 'closed'));
+
+CREATE TABLE wallet_status_changes (
+    id TEXT PRIMARY KEY,
+    wallet_id TEXT NOT NULL REFERENCES wallets (id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    actor TEXT,
+    request_id TEXT,
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX wallet_status_changes_wallet_id_idx ON wallet_status_changes (wallet_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS wallet_status_changes;
+ALTER TABLE wallets DROP COLUMN status;
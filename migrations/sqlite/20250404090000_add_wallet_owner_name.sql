@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE wallets ADD COLUMN owner_name TEXT;
+
+-- +goose Down
+ALTER TABLE wallets DROP COLUMN owner_name;
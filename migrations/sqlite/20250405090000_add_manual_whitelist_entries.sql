@@ -0,0 +1,28 @@
+-- +goose Up
+-- SQLite cannot drop a NOT NULL constraint, so the table is rebuilt.
+CREATE TABLE sanctions_whitelist_new (
+    wallet_id TEXT NOT NULL,
+    entry TEXT NOT NULL,
+    case_id TEXT REFERENCES sanctions_cases (id),
+    reason TEXT NOT NULL DEFAULT '',
+    actor TEXT,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (wallet_id, entry)
+);
+INSERT INTO sanctions_whitelist_new (wallet_id, entry, case_id, created_at)
+    SELECT wallet_id, entry, case_id, created_at FROM sanctions_whitelist;
+DROP TABLE sanctions_whitelist;
+ALTER TABLE sanctions_whitelist_new RENAME TO sanctions_whitelist;
+
+-- +goose Down
+CREATE TABLE sanctions_whitelist_old (
+    wallet_id TEXT NOT NULL,
+    entry TEXT NOT NULL,
+    case_id TEXT NOT NULL REFERENCES sanctions_cases (id),
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (wallet_id, entry)
+);
+INSERT INTO sanctions_whitelist_old (wallet_id, entry, case_id, created_at)
+    SELECT wallet_id, entry, case_id, created_at FROM sanctions_whitelist WHERE case_id IS NOT NULL;
+DROP TABLE sanctions_whitelist;
+ALTER TABLE sanctions_whitelist_old RENAME TO sanctions_whitelist;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQLite transactions are never archived, so the table stays empty.
+CREATE TABLE archived_chain (
+    month DATE PRIMARY KEY,
+    seq INTEGER NOT NULL,
+    hash TEXT NOT NULL,
+    count INTEGER NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS archived_chain;
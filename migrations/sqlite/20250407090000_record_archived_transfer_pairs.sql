@@ -0,0 +1,5 @@
+-- +goose Up
+-- SQLite transactions are never archived, so the log alone records which wallets
+-- have transferred to each other. The version is kept in step with Postgres.
+
+-- +goose Down
@@ -0,0 +1,134 @@
+// Package receipt verifies transfer receipts of the transaction service without
+// calling it. A receipt is returned by POST /api/send; the keys it is verified with
+// are published at GET /api/keys and can be fetched once and kept:
+//
+//	keys, err := receipt.ParseKeySet(keysJSON)
+//	r, err := receipt.Parse(receiptJSON)
+//	err = keys.Verify(r)
+//
+// The package depends on the standard library only.
+package receipt
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnknownKey is returned for a receipt signed with a key missing from the key set.
+	ErrUnknownKey = errors.New("receipt is signed with an unknown key")
+
+	// ErrInvalidSignature is returned for a receipt that was altered or not signed
+	// by the service.
+	ErrInvalidSignature = errors.New("receipt signature is invalid")
+)
+
+// Receipt is a signed transfer receipt as served by the service. Times are kept as
+// served, in RFC 3339 with nanoseconds, as the signature covers them verbatim.
+type Receipt struct {
+	TransactionID string `json:"transaction_id"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	AmountCents   int64  `json:"amount_cents"`
+	CreatedAt     string `json:"created_at"`
+	Seq           int64  `json:"seq"`
+	Hash          string `json:"hash"` // Hash linking the transaction into the log
+	IssuedAt      string `json:"issued_at"`
+	KeyID         string `json:"key_id"`
+	Signature     string `json:"signature"` // Base64 Ed25519 signature of Payload
+}
+
+// Parse decodes a receipt from its JSON form.
+func Parse(data []byte) (Receipt, error) {
+	var r Receipt
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Receipt{}, fmt.Errorf("failed to parse receipt: %w", err)
+	}
+	return r, nil
+}
+
+// Payload returns the bytes the signature covers: the receipt without its
+// signature, as a JSON object with the fields in the order of Receipt.
+func (r Receipt) Payload() []byte {
+	payload, _ := json.Marshal(struct {
+		TransactionID string `json:"transaction_id"`
+		From          string `json:"from"`
+		To            string `json:"to"`
+		AmountCents   int64  `json:"amount_cents"`
+		CreatedAt     string `json:"created_at"`
+		Seq           int64  `json:"seq"`
+		Hash          string `json:"hash"`
+		IssuedAt      string `json:"issued_at"`
+		KeyID         string `json:"key_id"`
+	}{r.TransactionID, r.From, r.To, r.AmountCents, r.CreatedAt, r.Seq, r.Hash, r.IssuedAt, r.KeyID})
+	return payload
+}
+
+// Key is a public key of the service.
+type Key struct {
+	ID        string `json:"key_id"`
+	Algorithm string `json:"algorithm"`  // Always Ed25519
+	PublicKey string `json:"public_key"` // Base64 of the raw 32-byte key
+	Status    string `json:"status"`     // current or retired; both verify
+}
+
+// KeySet is the document served at /api/keys.
+type KeySet struct {
+	Keys []Key `json:"keys"`
+}
+
+// ParseKeySet decodes the document served at /api/keys.
+func ParseKeySet(data []byte) (KeySet, error) {
+	var set KeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return KeySet{}, fmt.Errorf("failed to parse key set: %w", err)
+	}
+	return set, nil
+}
+
+// Verify checks that r was signed by one of the keys. Retired keys are accepted:
+// a receipt stays valid after the key that signed it is rotated out.
+func (s KeySet) Verify(r Receipt) error {
+	for _, key := range s.Keys {
+		if key.ID != r.KeyID {
+			continue
+		}
+		public, err := key.decode()
+		if err != nil {
+			return err
+		}
+		signature, err := base64.StdEncoding.DecodeString(r.Signature)
+		if err != nil || !ed25519.Verify(public, r.Payload(), signature) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+	return fmt.Errorf("%w %q", ErrUnknownKey, r.KeyID)
+}
+
+// decode returns the Ed25519 public key, checking it has the ID it is listed under.
+func (k Key) decode() (ed25519.PublicKey, error) {
+	if k.Algorithm != "Ed25519" {
+		return nil, fmt.Errorf("key %s uses unsupported algorithm %q", k.ID, k.Algorithm)
+	}
+	public, err := base64.StdEncoding.DecodeString(k.PublicKey)
+	if err != nil || len(public) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("key %s is not an Ed25519 public key", k.ID)
+	}
+	if KeyID(public) != k.ID {
+		return nil, fmt.Errorf("key %s does not match its ID", k.ID)
+	}
+	return public, nil
+}
+
+// KeyID returns the ID the service gives a public key: the hex of the first 8
+// bytes of its SHA-256 hash.
+func KeyID(public ed25519.PublicKey) string {
+	sum := sha256.Sum256(public)
+	return hex.EncodeToString(sum[:8])
+}
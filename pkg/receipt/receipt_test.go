@@ -0,0 +1,55 @@
+package receipt_test
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"transaction-service/pkg/receipt"
+)
+
+func TestVerify(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	keys := receipt.KeySet{Keys: []receipt.Key{{
+		ID: receipt.KeyID(public), Algorithm: "Ed25519",
+		PublicKey: base64.StdEncoding.EncodeToString(public), Status: "retired",
+	}}}
+	signed := receipt.Receipt{
+		TransactionID: "5d2c7a52-8a43-4d41-9a59-2b9c0d6f0f47",
+		From:          "1e7d3c40-2b43-4cf2-9d4e-8b1f0a6a1d11",
+		To:            "9b0a2f6e-51c4-4f6b-a7a2-0c3d5e8f9a22",
+		AmountCents:   150,
+		CreatedAt:     "2025-03-10T09:00:00.123456Z",
+		Seq:           42,
+		Hash:          "ab12",
+		IssuedAt:      "2025-03-10T09:00:00.2Z",
+		KeyID:         receipt.KeyID(public),
+	}
+	signed.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(private, signed.Payload()))
+
+	if err := keys.Verify(signed); err != nil {
+		t.Errorf("Verify of a receipt signed by a retired key: %v", err)
+	}
+
+	altered := signed
+	altered.AmountCents = 15000
+	if err := keys.Verify(altered); !errors.Is(err, receipt.ErrInvalidSignature) {
+		t.Errorf("Verify of an altered receipt = %v, want ErrInvalidSignature", err)
+	}
+
+	unknown := signed
+	unknown.KeyID = "0000000000000000"
+	if err := keys.Verify(unknown); !errors.Is(err, receipt.ErrUnknownKey) {
+		t.Errorf("Verify of a receipt with an unknown key = %v, want ErrUnknownKey", err)
+	}
+
+	forged := keys
+	forged.Keys = []receipt.Key{keys.Keys[0]}
+	forged.Keys[0].ID = "0000000000000000"
+	if err := forged.Verify(unknown); err == nil || errors.Is(err, receipt.ErrInvalidSignature) {
+		t.Errorf("Verify with a key listed under another ID = %v, want it rejected", err)
+	}
+}